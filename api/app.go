@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -231,6 +232,53 @@ contextsLoop:
 	return filter
 }
 
+// readableApps lists the apps matching the filter that the token is allowed
+// to read. Apps allowed only by conditional roles are checked one by one.
+func readableApps(ctx stdContext.Context, t auth.Token, filter *app.Filter) ([]*appTypes.App, error) {
+	perms, err := t.Permissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var apps []*appTypes.App
+	contexts := permission.ContextsFromListForPermission(perms, permission.PermAppRead)
+	contexts = append(contexts, permission.ContextsFromListForPermission(perms, permission.PermAppReadInfo)...)
+	if len(contexts) > 0 {
+		contextFilter := *filter
+		apps, err = app.List(ctx, appFilterByContext(contexts, &contextFilter))
+		if err != nil {
+			return nil, err
+		}
+	}
+	conditionalContexts := permission.ConditionalContextsFromListForPermission(perms, permission.PermAppRead)
+	conditionalContexts = append(conditionalContexts, permission.ConditionalContextsFromListForPermission(perms, permission.PermAppReadInfo)...)
+	if len(conditionalContexts) == 0 {
+		return apps, nil
+	}
+	contextFilter := *filter
+	candidates, err := app.List(ctx, appFilterByContext(conditionalContexts, &contextFilter))
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[string]bool, len(apps))
+	for _, a := range apps {
+		listed[a.Name] = true
+	}
+	for _, a := range candidates {
+		if listed[a.Name] {
+			continue
+		}
+		appContexts := contextsForApp(a)
+		if permission.CheckFromPermList(perms, permission.PermAppRead, appContexts...) ||
+			permission.CheckFromPermList(perms, permission.PermAppReadInfo, appContexts...) {
+			apps = append(apps, a)
+		}
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Name < apps[j].Name
+	})
+	return apps, nil
+}
+
 // title: app list
 // path: /apps
 // method: GET
@@ -268,13 +316,7 @@ func appList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if tags, ok := r.URL.Query()["tag"]; ok {
 		filter.Tags = tags
 	}
-	contexts := permission.ContextsForPermission(ctx, t, permission.PermAppRead)
-	contexts = append(contexts, permission.ContextsForPermission(ctx, t, permission.PermAppReadInfo)...)
-	if len(contexts) == 0 {
-		w.WriteHeader(http.StatusNoContent)
-		return nil
-	}
-	apps, err := app.List(ctx, appFilterByContext(contexts, filter))
+	apps, err := readableApps(ctx, t, filter)
 	if err != nil {
 		return err
 	}
@@ -1526,6 +1568,10 @@ func bindServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token) (
 		}
 		return err
 	}
+	err = instance.CheckPool(a.Pool)
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
 	evt, err := event.New(ctx, &event.Opts{
 		Target: appTarget(appName),
 		ExtraTargets: []eventTypes.ExtraTarget{
@@ -2193,8 +2239,20 @@ func unsetCertIssuer(w http.ResponseWriter, r *http.Request, t auth.Token) error
 }
 
 func contextsForApp(a *appTypes.App) []permTypes.PermissionContext {
-	return append(permission.Contexts(permTypes.CtxTeam, a.Teams),
+	contexts := append(permission.Contexts(permTypes.CtxTeam, a.Teams),
 		permission.Context(permTypes.CtxApp, a.Name),
 		permission.Context(permTypes.CtxPool, a.Pool),
 	)
+	contexts = append(contexts, permission.Contexts(permTypes.CtxTag, a.Tags)...)
+	return append(contexts, labelContexts(a.Metadata.Labels)...)
+}
+
+// labelContexts exposes metadata labels as "name=value" tags, allowing role
+// conditions to match them as well.
+func labelContexts(labels []appTypes.MetadataItem) []permTypes.PermissionContext {
+	contexts := make([]permTypes.PermissionContext, len(labels))
+	for i, label := range labels {
+		contexts[i] = permission.Context(permTypes.CtxTag, label.Name+"="+label.Value)
+	}
+	return contexts
 }
@@ -91,6 +91,81 @@ func (s *S) TestAppListFilteringByPlatform(c *check.C) {
 	}
 }
 
+func (s *S) TestAppListConditionalGlobalRole(c *check.C) {
+	app1 := appTypes.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name, Tags: []string{"tier=dev"}}
+	err := app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	app2 := appTypes.App{Name: "app2", Platform: "zend", TeamOwner: s.team.Name, Tags: []string{"tier=prod"}}
+	err = app.CreateApp(context.TODO(), &app2, s.user)
+	c.Assert(err, check.IsNil)
+	user, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "conditional", permTypes.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	c.Assert(user.Roles, check.HasLen, 1)
+	role, err := permission.FindRole(context.TODO(), user.Roles[0].Name)
+	c.Assert(err, check.IsNil)
+	err = role.SetCondition(context.TODO(), permTypes.Condition{Permission: "app.read", Tags: []string{"tier=dev"}})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/apps?simplified=true", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	apps := []appTypes.AppResume{}
+	err = json.Unmarshal(recorder.Body.Bytes(), &apps)
+	c.Assert(err, check.IsNil)
+	c.Assert(apps, check.HasLen, 1)
+	c.Assert(apps[0].Name, check.Equals, "app1")
+}
+
+func (s *S) TestAppListConditionalRoleFollowsEditedTags(c *check.C) {
+	a := appTypes.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name, Tags: []string{"tier=prod"}}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "conditional", permTypes.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	}, permTypes.Permission{
+		Scheme:  permission.PermAppUpdateTags,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	role, err := permission.FindRole(context.TODO(), "conditional"+permission.PermAppRead.FullName())
+	c.Assert(err, check.IsNil)
+	err = role.SetCondition(context.TODO(), permTypes.Condition{Permission: "app.read", Tags: []string{"tier=dev"}})
+	c.Assert(err, check.IsNil)
+	listApps := func() []appTypes.AppResume {
+		request, err := http.NewRequest("GET", "/apps?simplified=true", nil)
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Authorization", "b "+token.GetValue())
+		recorder := httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, request)
+		if recorder.Code == http.StatusNoContent {
+			return nil
+		}
+		c.Assert(recorder.Code, check.Equals, http.StatusOK)
+		apps := []appTypes.AppResume{}
+		err = json.Unmarshal(recorder.Body.Bytes(), &apps)
+		c.Assert(err, check.IsNil)
+		return apps
+	}
+	c.Assert(listApps(), check.HasLen, 0)
+	// Conditions are evaluated against the current tags, so users allowed to
+	// edit them can satisfy a tag condition on their own.
+	body := strings.NewReader("tag=tier%3Ddev")
+	request, err := http.NewRequest("PUT", "/apps/app1", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	apps := listApps()
+	c.Assert(apps, check.HasLen, 1)
+	c.Assert(apps[0].Name, check.Equals, "app1")
+}
+
 func (s *S) TestAppListFilteringByTeamOwner(c *check.C) {
 	ctx := context.Background()
 	team := authTypes.Team{Name: "angra"}
@@ -5126,6 +5201,35 @@ func (s *S) TestBindHandlerEndpointIsDown(c *check.C) {
 	}, eventtest.HasEvent)
 }
 
+func (s *S) TestBindHandlerPoolDoesNotMatch(c *check.C) {
+	srvc := service.Service{Name: "mysql", Endpoint: map[string]string{"production": "http://localhost:1234"}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := service.Create(context.TODO(), srvc)
+	c.Assert(err, check.IsNil)
+	instance := service.ServiceInstance{
+		Name:        "my-mysql",
+		ServiceName: "mysql",
+		Teams:       []string{s.team.Name},
+		Pool:        "other-pool",
+		SharedPools: []string{"another-pool"},
+	}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(context.TODO(), instance)
+	c.Assert(err, check.IsNil)
+	a := appTypes.App{Name: "painkiller", Platform: "zend", TeamOwner: s.team.Name}
+	err = app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	u := fmt.Sprintf("/services/%s/instances/%s/%s", instance.ServiceName, instance.Name, a.Name)
+	request, err := http.NewRequest("PUT", u, strings.NewReader("noRestart=true"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, service.ErrMultiClusterPoolDoesNotMatch.Error()+"\n")
+}
+
 func (s *S) TestBindHandler(c *check.C) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(`{"DATABASE_USER":"root","DATABASE_PASSWORD":"s3cr3t"}`))
@@ -8,9 +8,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"reflect"
 	"runtime"
+	"time"
 
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/app"
@@ -30,14 +32,25 @@ import (
 
 const (
 	nonManagedSchemeMsg = "Authentication scheme does not allow this operation."
+	nonMFASchemeMsg     = "Authentication scheme does not support multi-factor authentication."
+	nonSessionSchemeMsg = "Authentication scheme does not support session management."
 	createDisabledMsg   = "User registration is disabled for non-admin users."
 )
 
 var createDisabledErr = &errors.HTTP{Code: http.StatusUnauthorized, Message: createDisabledMsg}
 
 func handleAuthError(err error) error {
-	if err == authTypes.ErrUserNotFound {
+	switch err {
+	case authTypes.ErrUserNotFound:
 		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	case authTypes.ErrMFARequired, authTypes.ErrInvalidMFACode:
+		return &errors.HTTP{Code: http.StatusUnauthorized, Message: err.Error()}
+	case authTypes.ErrMFANotEnrolled:
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	case authTypes.ErrMFAAlreadyEnabled:
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	case authTypes.ErrMFALocked:
+		return &errors.HTTP{Code: http.StatusTooManyRequests, Message: err.Error()}
 	}
 	switch err.(type) {
 	case *errors.ValidationError:
@@ -138,6 +151,8 @@ func login(w http.ResponseWriter, r *http.Request) (err error) {
 	for key, values := range fields {
 		params[key] = values[0]
 	}
+	params[auth.SessionClientIPParam] = clientIP(r)
+	params[auth.SessionUserAgentParam] = r.UserAgent()
 
 	if userScheme, ok := app.AuthScheme.(auth.UserScheme); ok {
 		token, err := userScheme.Login(ctx, params)
@@ -166,6 +181,123 @@ func logout(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	return nil
 }
 
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// title: list sessions
+// path: /users/sessions
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: Ok
+//	204: No content
+//	400: Invalid data
+//	401: Unauthorized
+func listSessions(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	sessionScheme, ok := app.AuthScheme.(auth.SessionScheme)
+	if !ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: nonSessionSchemeMsg}
+	}
+	if !permission.Check(ctx, t, permission.PermUserReadSessions, permission.Context(permTypes.CtxUser, t.GetUserName())) {
+		return permission.ErrUnauthorized
+	}
+	sessions, err := sessionScheme.ListSessions(ctx, t.GetUserName())
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	currentID := auth.SessionID(t.GetValue())
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(sessions)
+}
+
+// title: revoke session
+// path: /users/sessions/{id}
+// method: DELETE
+// responses:
+//
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Session not found
+func revokeSession(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	sessionScheme, ok := app.AuthScheme.(auth.SessionScheme)
+	if !ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: nonSessionSchemeMsg}
+	}
+	email := t.GetUserName()
+	if !permission.Check(ctx, t, permission.PermUserUpdateSessions, permission.Context(permTypes.CtxUser, email)) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     userTarget(email),
+		Kind:       permission.PermUserUpdateSessions,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, email)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	err = sessionScheme.RevokeSession(ctx, email, r.URL.Query().Get(":id"))
+	if err == authTypes.ErrSessionNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return err
+}
+
+// title: revoke all user sessions
+// path: /users/{email}/sessions
+// method: DELETE
+// responses:
+//
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+//	404: User not found
+func revokeUserSessions(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	sessionScheme, ok := app.AuthScheme.(auth.SessionScheme)
+	if !ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: nonSessionSchemeMsg}
+	}
+	email := r.URL.Query().Get(":email")
+	if !permission.Check(ctx, t, permission.PermUserUpdateSessions, permission.Context(permTypes.CtxUser, email)) {
+		return permission.ErrUnauthorized
+	}
+	if _, err = auth.GetUserByEmail(ctx, email); err != nil {
+		return handleAuthError(err)
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     userTarget(email),
+		Kind:       permission.PermUserUpdateSessions,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, email)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	return sessionScheme.RevokeAllSessions(ctx, email)
+}
+
 // title: change password
 // path: /users/password
 // method: PUT
@@ -209,6 +341,17 @@ func changePassword(w http.ResponseWriter, r *http.Request, t auth.Token) (err e
 			Message: "New password and password confirmation didn't match.",
 		}
 	}
+	if mfaScheme, ok := app.AuthScheme.(auth.MFAScheme); ok {
+		var u *auth.User
+		u, err = auth.ConvertNewUser(t.User(ctx))
+		if err != nil {
+			return err
+		}
+		err = mfaScheme.VerifyMFA(ctx, u, InputValue(r, "otp"))
+		if err != nil {
+			return handleAuthError(err)
+		}
+	}
 	err = managed.ChangePassword(ctx, t, oldPassword, newPassword)
 	if err != nil {
 		return handleAuthError(err)
@@ -216,6 +359,165 @@ func changePassword(w http.ResponseWriter, r *http.Request, t auth.Token) (err e
 	return nil
 }
 
+// title: enroll multi-factor authentication
+// path: /users/mfa
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/json
+// responses:
+//
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+//	409: Already enabled
+func enrollMFA(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	mfaScheme, ok := app.AuthScheme.(auth.MFAScheme)
+	if !ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: nonMFASchemeMsg}
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     userTarget(t.GetUserName()),
+		Kind:       permission.PermUserUpdateMfa,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, t.GetUserName())),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	u, err := auth.ConvertNewUser(t.User(ctx))
+	if err != nil {
+		return err
+	}
+	password := InputValue(r, "password")
+	if password == "" {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "The current password is required."}
+	}
+	enrollment, err := mfaScheme.EnrollMFA(ctx, u, password)
+	if err != nil {
+		return handleAuthError(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(enrollment)
+}
+
+// title: confirm multi-factor authentication
+// path: /users/mfa/confirm
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/json
+// responses:
+//
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+//	409: Already enabled
+func confirmMFA(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	mfaScheme, ok := app.AuthScheme.(auth.MFAScheme)
+	if !ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: nonMFASchemeMsg}
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     userTarget(t.GetUserName()),
+		Kind:       permission.PermUserUpdateMfa,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, t.GetUserName())),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	u, err := auth.ConvertNewUser(t.User(ctx))
+	if err != nil {
+		return err
+	}
+	recoveryCodes, err := mfaScheme.ConfirmMFA(ctx, u, InputValue(r, "otp"))
+	if err != nil {
+		return handleAuthError(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string][]string{"recoveryCodes": recoveryCodes})
+}
+
+// title: disable multi-factor authentication
+// path: /users/mfa
+// method: DELETE
+// responses:
+//
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+func disableMFA(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	mfaScheme, ok := app.AuthScheme.(auth.MFAScheme)
+	if !ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: nonMFASchemeMsg}
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     userTarget(t.GetUserName()),
+		Kind:       permission.PermUserUpdateMfa,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, t.GetUserName())),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	u, err := auth.ConvertNewUser(t.User(ctx))
+	if err != nil {
+		return err
+	}
+	return handleAuthError(mfaScheme.DisableMFA(ctx, u, InputValue(r, "otp")))
+}
+
+// title: reset user multi-factor authentication
+// path: /users/{email}/mfa
+// method: DELETE
+// responses:
+//
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+//	403: Forbidden
+//	404: User not found
+func resetUserMFA(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	if _, ok := app.AuthScheme.(auth.MFAScheme); !ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: nonMFASchemeMsg}
+	}
+	email := r.URL.Query().Get(":email")
+	// every user has the permission in its own context, resetting is only
+	// allowed with the global permission and never to the own user.
+	if !permission.Check(ctx, t, permission.PermUserUpdateMfa) {
+		return permission.ErrUnauthorized
+	}
+	if email == t.GetUserName() {
+		return &errors.HTTP{Code: http.StatusForbidden, Message: "You can't reset your own multi-factor authentication, disable it using a recovery code instead."}
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     userTarget(email),
+		Kind:       permission.PermUserUpdateMfa,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, email)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	u, err := auth.GetUserByEmail(ctx, email)
+	if err != nil {
+		return handleAuthError(err)
+	}
+	u.MFA = nil
+	return u.Update(ctx)
+}
+
 // title: reset password
 // path: /users/{email}/password
 // method: POST
@@ -750,7 +1052,9 @@ type rolePermissionData struct {
 	Name         string
 	ContextType  string
 	ContextValue string
-	Group        string `json:",omitempty"`
+	Group        string     `json:",omitempty"`
+	ExpiresAt    *time.Time `json:",omitempty"`
+	ExpiresIn    string     `json:",omitempty"`
 }
 
 type apiUser struct {
@@ -805,6 +1109,10 @@ func createAPIUser(ctx context.Context, perms []permTypes.Permission, user *auth
 }
 
 func expandRoleData(ctx context.Context, perms []permTypes.Permission, userRole authTypes.RoleInstance, user *apiUser, roleMap map[string]*permission.Role, includeAll bool, group string) (bool, error) {
+	now := time.Now()
+	if userRole.Expired(now) {
+		return true, nil
+	}
 	role := roleMap[userRole.Name]
 	if role == nil {
 		r, err := permission.FindRole(ctx, userRole.Name)
@@ -835,12 +1143,17 @@ func expandRoleData(ctx context.Context, perms []permTypes.Permission, userRole
 	if !allPermsMatch {
 		return true, nil
 	}
-	user.Roles = append(user.Roles, rolePermissionData{
+	roleData := rolePermissionData{
 		Name:         userRole.Name,
 		ContextType:  string(role.ContextType),
 		ContextValue: userRole.ContextValue,
 		Group:        group,
-	})
+	}
+	if userRole.ExpiresAt != nil {
+		roleData.ExpiresAt = userRole.ExpiresAt
+		roleData.ExpiresIn = userRole.ExpiresAt.Sub(now).Truncate(time.Second).String()
+	}
+	user.Roles = append(user.Roles, roleData)
 	user.Permissions = append(user.Permissions, rolePerms...)
 	return role.ContextType == permTypes.CtxGlobal, nil
 }
@@ -1833,3 +1833,272 @@ func (s *AuthSuite) TestTeamGroupsListNoTeam(c *check.C) {
 	err = teamGroupList(recorder, request, token)
 	c.Assert(err, check.DeepEquals, &errors.HTTP{Code: http.StatusNotFound, Message: "team not found"})
 }
+
+func (s *AuthSuite) TestEnrollMFA(c *check.C) {
+	u := &auth.User{Email: "me@globo.com.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodPost, "/users/mfa", strings.NewReader("password=123456"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var enrollment authTypes.MFAEnrollment
+	err = json.Unmarshal(recorder.Body.Bytes(), &enrollment)
+	c.Assert(err, check.IsNil)
+	c.Assert(enrollment.Secret, check.Not(check.Equals), "")
+	c.Assert(enrollment.URL, check.Matches, "otpauth://totp/.*")
+	dbUser, err := auth.GetUserByEmail(context.TODO(), u.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.MFA.Secret, check.Equals, enrollment.Secret)
+	c.Assert(dbUser.MFAEnabled(), check.Equals, false)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(u.Email),
+		Owner:  u.Email,
+		Kind:   "user.update.mfa",
+	}, eventtest.HasEvent)
+}
+
+func (s *AuthSuite) TestEnrollMFARequiresPassword(c *check.C) {
+	u := &auth.User{Email: "me@globo.com.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	for _, tt := range []struct {
+		body string
+		code int
+	}{
+		{body: "", code: http.StatusBadRequest},
+		{body: "password=wrong-password", code: http.StatusUnauthorized},
+	} {
+		request, err := http.NewRequest(http.MethodPost, "/users/mfa", strings.NewReader(tt.body))
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.Header.Set("Authorization", "bearer "+token.GetValue())
+		recorder := httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, request)
+		c.Assert(recorder.Code, check.Equals, tt.code)
+	}
+	dbUser, err := auth.GetUserByEmail(context.TODO(), u.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.MFA, check.IsNil)
+}
+
+func (s *AuthSuite) TestConfirmMFAInvalidCode(c *check.C) {
+	u := &auth.User{Email: "me@globo.com.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	_, err = nativeScheme.EnrollMFA(context.TODO(), u, "123456")
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodPost, "/users/mfa/confirm", strings.NewReader("otp=000000"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+	c.Assert(recorder.Body.String(), check.Equals, authTypes.ErrInvalidMFACode.Error()+"\n")
+}
+
+func (s *AuthSuite) TestLoginWithMFAEnabledRequiresOTP(c *check.C) {
+	u := &auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	u.MFA = &authTypes.MFA{Secret: "JBSWY3DPEHPK3PXP", Enabled: true}
+	err = u.Update(context.TODO())
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodPost, "/users/nobody@globo.com/tokens", strings.NewReader("password=123456"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+	c.Assert(recorder.Body.String(), check.Equals, authTypes.ErrMFARequired.Error()+"\n")
+}
+
+func (s *AuthSuite) TestResetUserMFA(c *check.C) {
+	u := &auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	u.MFA = &authTypes.MFA{Secret: "JBSWY3DPEHPK3PXP", Enabled: true}
+	err = u.Update(context.TODO())
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodDelete, "/users/nobody@globo.com/mfa", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	dbUser, err := auth.GetUserByEmail(context.TODO(), u.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.MFA, check.IsNil)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(u.Email),
+		Owner:  s.token.GetUserName(),
+		Kind:   "user.update.mfa",
+	}, eventtest.HasEvent)
+}
+
+func (s *AuthSuite) TestResetUserMFAWithoutPermission(c *check.C) {
+	u := &auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodDelete, "/users/other@globo.com/mfa", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *AuthSuite) TestResetUserMFAOwnUser(c *check.C) {
+	s.user.MFA = &authTypes.MFA{Secret: "JBSWY3DPEHPK3PXP", Enabled: true}
+	err := s.user.Update(context.TODO())
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodDelete, "/users/"+s.user.Email+"/mfa", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	dbUser, err := auth.GetUserByEmail(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.MFAEnabled(), check.Equals, true)
+}
+
+func (s *AuthSuite) TestLoginMFALockedAfterInvalidCodes(c *check.C) {
+	u := &auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	u.MFA = &authTypes.MFA{Secret: "JBSWY3DPEHPK3PXP", Enabled: true}
+	err = u.Update(context.TODO())
+	c.Assert(err, check.IsNil)
+	var recorder *httptest.ResponseRecorder
+	for i := 0; i < 5; i++ {
+		request, err := http.NewRequest(http.MethodPost, "/users/nobody@globo.com/tokens", strings.NewReader("password=123456&otp=000000"))
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder = httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, request)
+	}
+	c.Assert(recorder.Code, check.Equals, http.StatusTooManyRequests)
+	c.Assert(recorder.Body.String(), check.Equals, authTypes.ErrMFALocked.Error()+"\n")
+	dbUser, err := auth.GetUserByEmail(context.TODO(), u.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.MFA.LockedUntil.After(time.Now()), check.Equals, true)
+}
+
+func (s *AuthSuite) TestListSessions(c *check.C) {
+	u := &auth.User{Email: "me@globo.com.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	otherToken, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodPost, "/users/me@globo.com.com/tokens", strings.NewReader("password=123456"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("User-Agent", "tsuru-client/1.0")
+	request.RemoteAddr = "10.0.0.1:34567"
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var loginData map[string]string
+	err = json.Unmarshal(recorder.Body.Bytes(), &loginData)
+	c.Assert(err, check.IsNil)
+	request, err = http.NewRequest(http.MethodGet, "/users/sessions", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+loginData["token"])
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var sessions []authTypes.Session
+	err = json.Unmarshal(recorder.Body.Bytes(), &sessions)
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 2)
+	c.Assert(sessions[0].ID, check.Equals, auth.SessionID(loginData["token"]))
+	c.Assert(sessions[0].Current, check.Equals, true)
+	c.Assert(sessions[0].ClientIP, check.Equals, "10.0.0.1")
+	c.Assert(sessions[0].UserAgent, check.Equals, "tsuru-client/1.0")
+	c.Assert(sessions[0].LastUsed, check.NotNil)
+	c.Assert(sessions[1].ID, check.Equals, auth.SessionID(otherToken.GetValue()))
+	c.Assert(sessions[1].Current, check.Equals, false)
+}
+
+func (s *AuthSuite) TestRevokeSession(c *check.C) {
+	u := &auth.User{Email: "me@globo.com.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	otherToken, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodDelete, "/users/sessions/"+auth.SessionID(otherToken.GetValue()), nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	_, err = nativeScheme.Auth(context.TODO(), "bearer "+otherToken.GetValue())
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(u.Email),
+		Owner:  u.Email,
+		Kind:   "user.update.sessions",
+	}, eventtest.HasEvent)
+}
+
+func (s *AuthSuite) TestRevokeSessionNotFound(c *check.C) {
+	request, err := http.NewRequest(http.MethodDelete, "/users/sessions/abcdef", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(recorder.Body.String(), check.Equals, authTypes.ErrSessionNotFound.Error()+"\n")
+}
+
+func (s *AuthSuite) TestRevokeUserSessions(c *check.C) {
+	u := &auth.User{Email: "me@globo.com.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodDelete, "/users/me@globo.com.com/sessions", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	_, err = nativeScheme.Auth(context.TODO(), "bearer "+token.GetValue())
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(u.Email),
+		Owner:  s.token.GetUserName(),
+		Kind:   "user.update.sessions",
+	}, eventtest.HasEvent)
+}
+
+func (s *AuthSuite) TestRevokeUserSessionsWithoutPermission(c *check.C) {
+	u := &auth.User{Email: "me@globo.com.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodDelete, "/users/"+s.user.Email+"/sessions", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
@@ -0,0 +1,219 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/dependency"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/service"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+// readableDependencies restricts the dependency graph to the apps, jobs and
+// service instances the token is allowed to read.
+func readableDependencies(ctx context.Context, t auth.Token) (*dependency.Filter, error) {
+	perms, err := t.Permissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &dependency.Filter{
+		App: func(a *appTypes.App) bool {
+			return permission.CheckFromPermList(perms, permission.PermAppRead, contextsForApp(a)...)
+		},
+		Job: func(j *jobTypes.Job) bool {
+			return permission.CheckFromPermList(perms, permission.PermJobRead, contextsForJob(j)...)
+		},
+		ServiceInstance: func(si *service.ServiceInstance) bool {
+			return permission.CheckFromPermList(perms, permission.PermServiceInstanceRead, contextsForServiceInstance(si, si.ServiceName)...)
+		},
+	}, nil
+}
+
+// title: app dependencies
+// path: /apps/{app}/dependencies
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	401: Unauthorized
+//	404: App not found
+func appDependencies(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	a, err := getAppFromContext(r.URL.Query().Get(":app"), r)
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermAppRead, contextsForApp(a)...) {
+		return permission.ErrUnauthorized
+	}
+	graph, err := dependency.ForApp(ctx, a)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(graph)
+}
+
+// title: app deletion plan
+// path: /apps/{app}/deletion-plan
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	401: Unauthorized
+//	404: App not found
+func appDeletionPlan(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	a, err := getAppFromContext(r.URL.Query().Get(":app"), r)
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermAppRead, contextsForApp(a)...) {
+		return permission.ErrUnauthorized
+	}
+	plan, err := dependency.AppDeletionPlan(ctx, a)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(plan)
+}
+
+// title: job dependencies
+// path: /jobs/{name}/dependencies
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	401: Unauthorized
+//	404: Job not found
+func jobDependencies(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	j, err := getJob(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobRead, contextsForJob(j)...) {
+		return permission.ErrUnauthorized
+	}
+	graph, err := dependency.ForJob(ctx, j)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(graph)
+}
+
+// title: team dependencies
+// path: /teams/{name}/dependencies
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	401: Unauthorized
+//	404: Team not found
+func teamDependencies(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	teamName := r.URL.Query().Get(":name")
+	if !permission.Check(ctx, t, permission.PermTeamRead, permission.Context(permTypes.CtxTeam, teamName)) {
+		return permission.ErrUnauthorized
+	}
+	_, err := servicemanager.Team.FindByName(ctx, teamName)
+	if err == authTypes.ErrTeamNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	filter, err := readableDependencies(ctx, t)
+	if err != nil {
+		return err
+	}
+	graph, err := dependency.ForTeam(ctx, teamName, filter)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(graph)
+}
+
+// title: pool dependencies
+// path: /pools/{name}/dependencies
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	401: Unauthorized
+//	404: Pool not found
+func poolDependencies(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	poolName := r.URL.Query().Get(":name")
+	if !permission.Check(ctx, t, permission.PermPoolRead, permission.Context(permTypes.CtxPool, poolName)) {
+		return permission.ErrUnauthorized
+	}
+	_, err := pool.GetPoolByName(ctx, poolName)
+	if err == pool.ErrPoolNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	filter, err := readableDependencies(ctx, t)
+	if err != nil {
+		return err
+	}
+	graph, err := dependency.ForPool(ctx, poolName, filter)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(graph)
+}
+
+// title: service instance deletion plan
+// path: /services/{service}/instances/{instance}/deletion-plan
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	401: Unauthorized
+//	404: Service instance not found
+func serviceInstanceDeletionPlan(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	serviceName := r.URL.Query().Get(":service")
+	instanceName := r.URL.Query().Get(":instance")
+	si, err := getServiceInstanceOrError(ctx, serviceName, instanceName)
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermServiceInstanceRead, contextsForServiceInstance(si, serviceName)...) {
+		return permission.ErrUnauthorized
+	}
+	unbindAll, _ := strconv.ParseBool(r.URL.Query().Get("unbindall"))
+	plan, err := dependency.ServiceInstanceDeletionPlan(ctx, si, unbindAll)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(plan)
+}
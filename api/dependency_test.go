@@ -0,0 +1,144 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/dependency"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/service"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestAppDependencies(c *check.C) {
+	a := appTypes.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	collection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = collection.InsertOne(context.TODO(), service.ServiceInstance{Name: "db", ServiceName: "mysql", Apps: []string{"myapp"}})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/apps/myapp/dependencies", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var graph dependency.Graph
+	err = json.Unmarshal(recorder.Body.Bytes(), &graph)
+	c.Assert(err, check.IsNil)
+	c.Assert(graph.Edges, check.DeepEquals, []dependency.Edge{
+		{From: "app:myapp", To: "router:fake"},
+		{From: "app:myapp", To: "service-instance:mysql/db"},
+	})
+}
+
+func (s *S) TestAppDeletionPlan(c *check.C) {
+	a := appTypes.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/apps/myapp/deletion-plan", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var plan dependency.CascadePlan
+	err = json.Unmarshal(recorder.Body.Bytes(), &plan)
+	c.Assert(err, check.IsNil)
+	c.Assert(plan.Target.ID, check.Equals, "app:myapp")
+	c.Assert(plan.Steps, check.HasLen, 2)
+	c.Assert(plan.Steps[0].Action, check.Equals, dependency.ActionRemoveBackend)
+	c.Assert(plan.Steps[1].Action, check.Equals, dependency.ActionRemove)
+}
+
+func (s *S) TestAppDependenciesUnauthorized(c *check.C) {
+	a := appTypes.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxApp, "otherapp"),
+	})
+	request, err := http.NewRequest("GET", "/apps/myapp/dependencies", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestServiceInstanceDeletionPlan(c *check.C) {
+	a := appTypes.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	collection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = collection.InsertOne(context.TODO(), service.ServiceInstance{Name: "db", ServiceName: "mysql", Apps: []string{"myapp"}, Teams: []string{s.team.Name}})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/services/mysql/instances/db/deletion-plan", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var plan dependency.CascadePlan
+	err = json.Unmarshal(recorder.Body.Bytes(), &plan)
+	c.Assert(err, check.IsNil)
+	c.Assert(plan.BlockedBy, check.Equals, service.ErrServiceInstanceBound.Error())
+	c.Assert(plan.Steps, check.HasLen, 3)
+	request, err = http.NewRequest("GET", "/services/mysql/instances/db/deletion-plan?unbindall=true", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	err = json.Unmarshal(recorder.Body.Bytes(), &plan)
+	c.Assert(err, check.IsNil)
+	c.Assert(plan.BlockedBy, check.Equals, "")
+}
+
+func (s *S) TestTeamDependenciesOnlyReadableResources(c *check.C) {
+	a := appTypes.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	collection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = collection.InsertOne(context.TODO(), service.ServiceInstance{Name: "db", ServiceName: "mysql", Apps: []string{"myapp"}, Teams: []string{s.team.Name}})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermTeamRead,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	}, permTypes.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxApp, "myapp"),
+	})
+	request, err := http.NewRequest("GET", "/1.24/teams/"+s.team.Name+"/dependencies", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var graph dependency.Graph
+	err = json.Unmarshal(recorder.Body.Bytes(), &graph)
+	c.Assert(err, check.IsNil)
+	var ids []string
+	for _, n := range graph.Nodes {
+		ids = append(ids, n.ID)
+	}
+	c.Assert(ids, check.DeepEquals, []string{"app:myapp", "router:fake"})
+	c.Assert(graph.Edges, check.DeepEquals, []dependency.Edge{
+		{From: "app:myapp", To: "router:fake"},
+	})
+}
@@ -10,12 +10,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
 	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/event"
@@ -53,6 +56,23 @@ type inputJob struct {
 	Trigger               bool                   `json:"trigger"` // Trigger means the client wants to forcefully run a job
 	ActiveDeadlineSeconds *int64                 `json:"activeDeadlineSeconds,omitempty"`
 	ConcurrencyPolicy     *string                `json:"concurrencyPolicy,omitempty"`
+
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	StartingDeadlineSeconds    *int64 `json:"startingDeadlineSeconds,omitempty"`
+	TimeZone                   string `json:"timeZone,omitempty"`
+	SuccessfulJobsHistoryLimit *int32 `json:"successfulJobsHistoryLimit,omitempty"`
+	FailedJobsHistoryLimit     *int32 `json:"failedJobsHistoryLimit,omitempty"`
+
+	Alerts *jobTypes.AlertRules `json:"alerts,omitempty"`
+
+	InitContainers []jobTypes.Container `json:"initContainers,omitempty"`
+	Sidecars       []jobTypes.Container `json:"sidecars,omitempty"`
+
+	// Template creates the job from a job template, replacing its
+	// placeholders by Params.
+	Template string            `json:"template,omitempty"`
+	Params   map[string]string `json:"params,omitempty"`
 }
 
 func getJob(ctx stdContext.Context, name string) (*jobTypes.Job, error) {
@@ -94,6 +114,45 @@ func filterExtraIn(f *jobTypes.Filter, name string, value string) {
 	f.Extra[name] = append(f.Extra[name], value)
 }
 
+// readableJobs lists the jobs matching the filter that the token is allowed
+// to read. Jobs allowed only by conditional roles are checked one by one.
+func readableJobs(ctx stdContext.Context, t auth.Token, filter *jobTypes.Filter) ([]jobTypes.Job, error) {
+	perms, err := t.Permissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var jobs []jobTypes.Job
+	if contexts := permission.ContextsFromListForPermission(perms, permission.PermJobRead); len(contexts) > 0 {
+		contextFilter := *filter
+		jobs, err = servicemanager.Job.List(ctx, jobFilterByContext(contexts, &contextFilter))
+		if err != nil {
+			return nil, err
+		}
+	}
+	conditionalContexts := permission.ConditionalContextsFromListForPermission(perms, permission.PermJobRead)
+	if len(conditionalContexts) == 0 {
+		return jobs, nil
+	}
+	contextFilter := *filter
+	candidates, err := servicemanager.Job.List(ctx, jobFilterByContext(conditionalContexts, &contextFilter))
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[string]bool, len(jobs))
+	for _, j := range jobs {
+		listed[j.Name] = true
+	}
+	for i := range candidates {
+		if !listed[candidates[i].Name] && permission.CheckFromPermList(perms, permission.PermJobRead, contextsForJob(&candidates[i])...) {
+			jobs = append(jobs, candidates[i])
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Name < jobs[j].Name
+	})
+	return jobs, nil
+}
+
 // title: job list
 // path: /jobs/list
 // method: GET
@@ -118,13 +177,10 @@ func jobList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if pool := r.URL.Query().Get("pool"); pool != "" {
 		filter.Pool = pool
 	}
-	contexts := permission.ContextsForPermission(ctx, t, permission.PermJobRead)
-	contexts = append(contexts, permission.ContextsForPermission(ctx, t, permission.PermJobRead)...)
-	if len(contexts) == 0 {
-		w.WriteHeader(http.StatusNoContent)
-		return nil
+	if template := r.URL.Query().Get("template"); template != "" {
+		filter.Template = template
 	}
-	jobs, err := servicemanager.Job.List(ctx, jobFilterByContext(contexts, filter))
+	jobs, err := readableJobs(ctx, t, filter)
 	if err != nil {
 		return err
 	}
@@ -136,12 +192,14 @@ func jobList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 }
 
 // title: job trigger
-// path: /job/trigger/{name}
-// method: PUT
+// path: /jobs/{name}/trigger
+// method: POST
+// consume: application/x-www-form-urlencoded, application/json
 // produce: application/json
 // responses:
 //
 //	200: OK
+//	400: Invalid data
 //	401: Unauthorized
 //	404: Not found
 func jobTrigger(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
@@ -157,18 +215,32 @@ func jobTrigger(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 	if !canRun {
 		return permission.ErrUnauthorized
 	}
+	var opts jobTypes.TriggerOptions
+	err = ParseInput(r, &opts)
+	if err != nil {
+		return err
+	}
+	if !opts.IsEmpty() {
+		canOverride := permission.Check(ctx, t, permission.PermJobTriggerParameters,
+			contextsForJob(j)...,
+		)
+		if !canOverride {
+			return permission.ErrUnauthorized
+		}
+	}
+	opts.TriggeredBy = t.GetUserName()
 	evt, err := event.New(ctx, &event.Opts{
 		Target:     jobTarget(j.Name),
 		Kind:       permission.PermJobTrigger,
 		Owner:      t,
-		CustomData: event.FormToCustomData(InputFields(r)),
+		CustomData: event.FormToCustomData(triggerInputFields(r, opts)),
 		Allowed:    event.Allowed(permission.PermJobReadEvents, contextsForJob(j)...),
 	})
 	if err != nil {
 		return err
 	}
 	defer func() { evt.Done(ctx, err) }()
-	err = servicemanager.Job.Trigger(ctx, j)
+	err = servicemanager.Job.Trigger(ctx, j, opts)
 	if err != nil {
 		return err
 	}
@@ -185,6 +257,24 @@ func jobTrigger(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 	return nil
 }
 
+// triggerInputFields returns the trigger input fields with the values of
+// non-public envs suppressed, so they are not stored in the event.
+func triggerInputFields(r *http.Request, opts jobTypes.TriggerOptions) url.Values {
+	fields := InputFields(r)
+	for key := range fields {
+		parts := strings.Split(strings.ToLower(key), ".")
+		if len(parts) != 3 || parts[0] != "envs" || parts[2] != "value" {
+			continue
+		}
+		i, err := strconv.Atoi(parts[1])
+		if err == nil && i >= 0 && i < len(opts.Envs) && opts.Envs[i].Public {
+			continue
+		}
+		fields[key] = []string{app.SuppressedEnv}
+	}
+	return fields
+}
+
 // title: job info
 // path: /jobs
 // method: GET
@@ -227,10 +317,16 @@ func jobInfo(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 		})
 	}
 
+	volumeBinds, err := servicemanager.Volume.BindsForJob(ctx, nil, j.Name)
+	if err != nil {
+		return err
+	}
+
 	jobInfo := &jobTypes.JobInfo{
 		Job:                  j,
 		Units:                units,
 		ServiceInstanceBinds: binds,
+		VolumeBinds:          volumeBinds,
 	}
 
 	cluster, err := servicemanager.Cluster.FindByPool(ctx, provision.DefaultProvisioner, j.Pool)
@@ -330,6 +426,135 @@ func killJob(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	return err
 }
 
+// title: job suspend
+// path: /jobs/{name}/suspend
+// method: POST
+// responses:
+//
+//	200: Job suspended
+//	400: Manual jobs can't be suspended
+//	401: Unauthorized
+//	404: Job not found
+func suspendJob(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	return setJobSuspend(r, t, true)
+}
+
+// title: job resume
+// path: /jobs/{name}/resume
+// method: POST
+// responses:
+//
+//	200: Job resumed
+//	400: Manual jobs can't be resumed
+//	401: Unauthorized
+//	404: Job not found
+func resumeJob(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	return setJobSuspend(r, t, false)
+}
+
+func setJobSuspend(r *http.Request, t auth.Token, suspend bool) (err error) {
+	ctx := r.Context()
+	j, err := getJob(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	perm := permission.PermJobUpdateResume
+	if suspend {
+		perm = permission.PermJobUpdateSuspend
+	}
+	if !permission.Check(ctx, t, perm, contextsForJob(j)...) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     jobTarget(j.Name),
+		Kind:       perm,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermJobReadEvents, contextsForJob(j)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	if suspend {
+		return servicemanager.Job.Suspend(ctx, j)
+	}
+	return servicemanager.Job.Resume(ctx, j)
+}
+
+// title: job set autoscale
+// path: /jobs/{name}/autoscale
+// method: POST
+// consume: application/json
+// responses:
+//
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Job not found
+func setJobAutoScale(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	j, err := getJob(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobUpdateAutoscaleAdd, contextsForJob(j)...) {
+		return permission.ErrUnauthorized
+	}
+	var spec jobTypes.AutoScaleSpec
+	err = ParseInput(r, &spec)
+	if err != nil {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("unable to parse autoscale spec: %v", err),
+		}
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     jobTarget(j.Name),
+		Kind:       permission.PermJobUpdateAutoscaleAdd,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermJobReadEvents, contextsForJob(j)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	return servicemanager.Job.SetAutoScale(ctx, j, spec)
+}
+
+// title: job remove autoscale
+// path: /jobs/{name}/autoscale
+// method: DELETE
+// responses:
+//
+//	200: Ok
+//	401: Unauthorized
+//	404: Job not found
+func removeJobAutoScale(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	j, err := getJob(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobUpdateAutoscaleRemove, contextsForJob(j)...) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     jobTarget(j.Name),
+		Kind:       permission.PermJobUpdateAutoscaleRemove,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermJobReadEvents, contextsForJob(j)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	return servicemanager.Job.RemoveAutoScale(ctx, j)
+}
+
 // title: job update
 // path: /jobs
 // method: PUT
@@ -374,12 +599,20 @@ func updateJob(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 		Description: ij.Description,
 		Pool:        ij.Pool,
 		Metadata:    ij.Metadata,
+		Alerts:      ij.Alerts,
 		Spec: jobTypes.JobSpec{
-			ConcurrencyPolicy:     ij.ConcurrencyPolicy,
-			Schedule:              ij.Schedule,
-			Container:             ij.Container,
-			Manual:                ij.Manual,
-			ActiveDeadlineSeconds: ij.ActiveDeadlineSeconds,
+			ConcurrencyPolicy:          ij.ConcurrencyPolicy,
+			Schedule:                   ij.Schedule,
+			Container:                  ij.Container,
+			Manual:                     ij.Manual,
+			ActiveDeadlineSeconds:      ij.ActiveDeadlineSeconds,
+			TTLSecondsAfterFinished:    ij.TTLSecondsAfterFinished,
+			StartingDeadlineSeconds:    ij.StartingDeadlineSeconds,
+			TimeZone:                   ij.TimeZone,
+			SuccessfulJobsHistoryLimit: ij.SuccessfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     ij.FailedJobsHistoryLimit,
+			InitContainers:             ij.InitContainers,
+			Sidecars:                   ij.Sidecars,
 		},
 	}
 
@@ -453,11 +686,19 @@ func createJob(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 		Pool:          ij.Pool,
 		Metadata:      ij.Metadata,
 		DeployOptions: ij.DeployOptions,
+		Alerts:        ij.Alerts,
 		Spec: jobTypes.JobSpec{
-			ConcurrencyPolicy: ij.ConcurrencyPolicy,
-			Manual:            ij.Manual,
-			Schedule:          ij.Schedule,
-			Container:         ij.Container,
+			ConcurrencyPolicy:          ij.ConcurrencyPolicy,
+			Manual:                     ij.Manual,
+			Schedule:                   ij.Schedule,
+			Container:                  ij.Container,
+			TTLSecondsAfterFinished:    ij.TTLSecondsAfterFinished,
+			StartingDeadlineSeconds:    ij.StartingDeadlineSeconds,
+			TimeZone:                   ij.TimeZone,
+			SuccessfulJobsHistoryLimit: ij.SuccessfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     ij.FailedJobsHistoryLimit,
+			InitContainers:             ij.InitContainers,
+			Sidecars:                   ij.Sidecars,
 		},
 	}
 	if ij.ActiveDeadlineSeconds != nil && *ij.ActiveDeadlineSeconds >= 0 {
@@ -475,6 +716,11 @@ func createJob(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 	if !canCreate {
 		return permission.ErrUnauthorized
 	}
+	if ij.Template != "" {
+		if err = renderJobTemplate(ctx, j, ij.Template, ij.Params); err != nil {
+			return err
+		}
+	}
 	u, err := t.User(ctx)
 	if err != nil {
 		return err
@@ -528,6 +774,7 @@ func createJob(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 //	200: Job removed
 //	401: Unauthorized
 //	404: Not found
+//	409: Job is used by a workflow
 func deleteJob(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	ctx := r.Context()
 	name := r.URL.Query().Get(":name")
@@ -541,6 +788,17 @@ func deleteJob(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 	if !canDelete {
 		return permission.ErrUnauthorized
 	}
+	workflows, err := servicemanager.JobWorkflow.ListWorkflows(ctx, &jobTypes.WorkflowFilter{Job: j.Name})
+	if err != nil {
+		return err
+	}
+	if len(workflows) > 0 {
+		inUse := &jobTypes.ErrJobInWorkflows{Job: j.Name}
+		for _, wf := range workflows {
+			inUse.Workflows = append(inUse.Workflows, wf.Name)
+		}
+		return &errors.HTTP{Code: http.StatusConflict, Message: inUse.Error()}
+	}
 	evt, err := event.New(ctx, &event.Opts{
 		Target:     jobTarget(j.Name),
 		Kind:       permission.PermJobDelete,
@@ -615,6 +873,10 @@ func bindJobServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token
 		}
 		return err
 	}
+	err = instance.CheckPool(j.Pool)
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
 
 	evt, err := event.New(ctx, &event.Opts{
 		Target: jobTarget(j.Name),
@@ -1001,13 +1263,135 @@ func jobLog(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	return followLogs(tsuruNet.CancelableParentContext(r.Context()), j.Name, watcher, encoder)
 }
 
+// title: job runs
+// path: /jobs/{name}/runs
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	204: No content
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Not found
+func jobRuns(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	j, err := getJob(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobRead, contextsForJob(j)...) {
+		return permission.ErrUnauthorized
+	}
+	filter := jobTypes.RunFilter{Status: jobTypes.RunStatus(r.URL.Query().Get("status"))}
+	if l := r.URL.Query().Get("limit"); l != "" {
+		filter.Limit, err = strconv.Atoi(l)
+		if err != nil {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: `Parameter "limit" must be an integer.`}
+		}
+	}
+	runs, err := servicemanager.Job.ListRuns(ctx, j, filter)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(runs)
+}
+
+func getJobRun(ctx stdContext.Context, j *jobTypes.Job, runName string) (*jobTypes.Run, error) {
+	run, err := servicemanager.Job.GetRun(ctx, j, runName)
+	if err == jobTypes.ErrJobRunNotFound {
+		return nil, &errors.HTTP{Code: http.StatusNotFound, Message: fmt.Sprintf("Run %s of job %s not found.", runName, j.Name)}
+	}
+	return run, err
+}
+
+// title: job run info
+// path: /jobs/{name}/runs/{run}
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	401: Unauthorized
+//	404: Not found
+func jobRunInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	j, err := getJob(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobRead, contextsForJob(j)...) {
+		return permission.ErrUnauthorized
+	}
+	run, err := getJobRun(ctx, j, r.URL.Query().Get(":run"))
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(run)
+}
+
+// title: job run log
+// path: /jobs/{name}/runs/{run}/log
+// method: GET
+// produce: application/x-json-stream
+// responses:
+//
+//	200: OK
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Not found
+func jobRunLog(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	var err error
+	lines := 100
+	if l := r.URL.Query().Get("lines"); l != "" {
+		lines, err = strconv.Atoi(l)
+		if err != nil {
+			msg := `Parameter "lines" must be an integer.`
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: msg}
+		}
+	}
+	j, err := getJob(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobReadLogs, contextsForJob(j)...) {
+		return permission.ErrUnauthorized
+	}
+	run, err := getJobRun(ctx, j, r.URL.Query().Get(":run"))
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	logs := []appTypes.Applog{}
+	if len(run.Units) > 0 {
+		logs, err = servicemanager.LogService.List(ctx, appTypes.ListLogArgs{
+			Name:  j.Name,
+			Type:  log.LogTypeJob,
+			Units: run.Units,
+			Limit: lines,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return json.NewEncoder(w).Encode(logs)
+}
+
 func jobTarget(jobName string) eventTypes.Target {
 	return eventTypes.Target{Type: eventTypes.TargetTypeJob, Value: jobName}
 }
 
 func contextsForJob(job *jobTypes.Job) []permTypes.PermissionContext {
-	return append(permission.Contexts(permTypes.CtxTeam, job.Teams),
+	contexts := append(permission.Contexts(permTypes.CtxTeam, job.Teams),
 		permission.Context(permTypes.CtxJob, job.Name),
 		permission.Context(permTypes.CtxPool, job.Pool),
 	)
+	return append(contexts, labelContexts(job.Metadata.Labels)...)
 }
@@ -0,0 +1,307 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	stdContext "context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+type inputJobTemplate struct {
+	Name        string                   `json:"name"`
+	TeamOwner   string                   `json:"teamOwner"`
+	Description string                   `json:"description"`
+	Params      []jobTypes.TemplateParam `json:"params"`
+	Spec        jobTypes.TemplateSpec    `json:"spec"`
+}
+
+func jobTemplateTarget(name string) eventTypes.Target {
+	return eventTypes.Target{Type: eventTypes.TargetTypeJobTemplate, Value: name}
+}
+
+// contextsForJobTemplate returns no contexts for templates managed by
+// admins, so only global permissions match them.
+func contextsForJobTemplate(tmpl *jobTypes.Template) []permTypes.PermissionContext {
+	if tmpl.TeamOwner == "" {
+		return nil
+	}
+	return []permTypes.PermissionContext{permission.Context(permTypes.CtxTeam, tmpl.TeamOwner)}
+}
+
+// redactJobTemplate hides the values of the private envs of admin
+// templates from tokens without a global read permission, as they are
+// shared with every team.
+func redactJobTemplate(ctx stdContext.Context, t auth.Token, tmpl *jobTypes.Template) {
+	if tmpl.TeamOwner != "" || permission.Check(ctx, t, permission.PermJobTemplateRead) {
+		return
+	}
+	for i := range tmpl.Spec.Envs {
+		if !tmpl.Spec.Envs[i].Public {
+			tmpl.Spec.Envs[i].Value = app.SuppressedEnv
+		}
+	}
+}
+
+func getJobTemplate(ctx stdContext.Context, name string) (*jobTypes.Template, error) {
+	tmpl, err := servicemanager.JobTemplate.GetTemplate(ctx, name)
+	if err == jobTypes.ErrTemplateNotFound {
+		return nil, &errors.HTTP{Code: http.StatusNotFound, Message: fmt.Sprintf("Job template %s not found.", name)}
+	}
+	return tmpl, err
+}
+
+// renderJobTemplate fills the job being created with the template, team
+// templates are only available to the jobs of their team.
+func renderJobTemplate(ctx stdContext.Context, j *jobTypes.Job, name string, params map[string]string) error {
+	tmpl, err := servicemanager.JobTemplate.GetTemplate(ctx, name)
+	if err == jobTypes.ErrTemplateNotFound {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: fmt.Sprintf("Job template %s not found.", name)}
+	}
+	if err != nil {
+		return err
+	}
+	if tmpl.TeamOwner != "" && tmpl.TeamOwner != j.TeamOwner {
+		return &errors.HTTP{Code: http.StatusForbidden, Message: fmt.Sprintf("Job template %s is not available to team %s.", name, j.TeamOwner)}
+	}
+	return servicemanager.JobTemplate.RenderJob(ctx, tmpl, params, j)
+}
+
+// title: job template create
+// path: /job-templates
+// method: POST
+// consume: application/x-www-form-urlencoded, application/json
+// produce: application/json
+// responses:
+//
+//	201: Job template created
+//	400: Invalid data
+//	401: Unauthorized
+//	409: Job template already exists
+func createJobTemplate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	var it inputJobTemplate
+	if err = ParseInput(r, &it); err != nil {
+		return err
+	}
+	tmpl := &jobTypes.Template{
+		Name:        it.Name,
+		TeamOwner:   it.TeamOwner,
+		Owner:       t.GetUserName(),
+		Description: it.Description,
+		Params:      it.Params,
+		Spec:        it.Spec,
+	}
+	if !permission.Check(ctx, t, permission.PermJobTemplateCreate, contextsForJobTemplate(tmpl)...) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     jobTemplateTarget(tmpl.Name),
+		Kind:       permission.PermJobTemplateCreate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermJobTemplateRead, contextsForJobTemplate(tmpl)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	err = servicemanager.JobTemplate.CreateTemplate(ctx, tmpl)
+	if err == jobTypes.ErrTemplateAlreadyExists {
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(tmpl)
+}
+
+// title: job template update
+// path: /job-templates/{name}
+// method: PUT
+// consume: application/x-www-form-urlencoded, application/json
+// responses:
+//
+//	200: Job template updated
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Not found
+func updateJobTemplate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	tmpl, err := getJobTemplate(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobTemplateUpdate, contextsForJobTemplate(tmpl)...) {
+		return permission.ErrUnauthorized
+	}
+	var it inputJobTemplate
+	if err = ParseInput(r, &it); err != nil {
+		return err
+	}
+	if it.Description != "" {
+		tmpl.Description = it.Description
+	}
+	if it.Params != nil {
+		tmpl.Params = it.Params
+	}
+	if it.Spec.Image != "" {
+		tmpl.Spec = it.Spec
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     jobTemplateTarget(tmpl.Name),
+		Kind:       permission.PermJobTemplateUpdate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermJobTemplateRead, contextsForJobTemplate(tmpl)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	return servicemanager.JobTemplate.UpdateTemplate(ctx, tmpl)
+}
+
+// title: job template list
+// path: /job-templates
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: List job templates
+//	204: No content
+//	401: Unauthorized
+func jobTemplateList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	filter := &jobTypes.TemplateFilter{TeamOwners: []string{}}
+	for _, c := range permission.ContextsForPermission(ctx, t, permission.PermJobTemplateRead) {
+		if c.CtxType == permTypes.CtxGlobal {
+			filter.TeamOwners = nil
+			break
+		}
+		filter.TeamOwners = append(filter.TeamOwners, c.Value)
+	}
+	templates, err := servicemanager.JobTemplate.ListTemplates(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if len(templates) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	for i := range templates {
+		redactJobTemplate(ctx, t, &templates[i])
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(templates)
+}
+
+// title: job template info
+// path: /job-templates/{name}
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	401: Unauthorized
+//	404: Not found
+func jobTemplateInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	tmpl, err := getJobTemplate(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if tmpl.TeamOwner != "" && !permission.Check(ctx, t, permission.PermJobTemplateRead, contextsForJobTemplate(tmpl)...) {
+		return permission.ErrUnauthorized
+	}
+	redactJobTemplate(ctx, t, tmpl)
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(tmpl)
+}
+
+// title: job template delete
+// path: /job-templates/{name}
+// method: DELETE
+// responses:
+//
+//	200: Job template removed
+//	401: Unauthorized
+//	404: Not found
+func deleteJobTemplate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	tmpl, err := getJobTemplate(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobTemplateDelete, contextsForJobTemplate(tmpl)...) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     jobTemplateTarget(tmpl.Name),
+		Kind:       permission.PermJobTemplateDelete,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermJobTemplateRead, contextsForJobTemplate(tmpl)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	return servicemanager.JobTemplate.RemoveTemplate(ctx, tmpl)
+}
+
+// title: job template apply
+// path: /job-templates/{name}/apply
+// method: POST
+// produce: application/json
+// responses:
+//
+//	200: Jobs updated
+//	401: Unauthorized
+//	404: Not found
+func applyJobTemplate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	tmpl, err := getJobTemplate(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobTemplateUpdate, contextsForJobTemplate(tmpl)...) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     jobTemplateTarget(tmpl.Name),
+		Kind:       permission.PermJobTemplateUpdate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermJobTemplateRead, contextsForJobTemplate(tmpl)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	results, err := servicemanager.JobTemplate.ApplyTemplate(ctx, tmpl, t)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(results)
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	"github.com/tsuru/tsuru/servicemanager"
+	bindTypes "github.com/tsuru/tsuru/types/bind"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	"github.com/tsuru/tsuru/types/quota"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) createJobTemplate(c *check.C, teamOwner string) {
+	collection, err := storagev2.JobTemplatesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = collection.InsertOne(context.TODO(), &jobTypes.Template{
+		Name:      "db-backup",
+		TeamOwner: teamOwner,
+		Params:    []jobTypes.TemplateParam{{Name: "database", Required: true}},
+		Spec: jobTypes.TemplateSpec{
+			Image:    "postgres:15",
+			Command:  []string{"pg_dump", "{{.database}}"},
+			Schedule: "0 3 * * *",
+			Envs:     []bindTypes.EnvVar{{Name: "PGDATABASE", Value: "{{.database}}"}},
+		},
+	})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestCreateJobTemplate(c *check.C) {
+	body := `{"name":"db-backup","teamOwner":"` + s.team.Name + `","params":[{"name":"database","required":true}],"spec":{"image":"postgres:15","command":["pg_dump","{{.database}}"],"schedule":"0 3 * * *"}}`
+	request, err := http.NewRequest("POST", "/job-templates", strings.NewReader(body))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	tmpl, err := servicemanager.JobTemplate.GetTemplate(context.TODO(), "db-backup")
+	c.Assert(err, check.IsNil)
+	c.Assert(tmpl.Owner, check.Equals, s.token.GetUserName())
+	c.Assert(tmpl.Spec.Command, check.DeepEquals, []string{"pg_dump", "{{.database}}"})
+	c.Assert(eventtest.EventDesc{
+		Target: jobTemplateTarget("db-backup"),
+		Owner:  s.token.GetUserName(),
+		Kind:   "job.template.create",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestCreateAdminJobTemplateRequiresGlobalPermission(c *check.C) {
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermJobTemplateCreate,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	body := `{"name":"db-backup","spec":{"image":"postgres:15","schedule":"0 3 * * *"}}`
+	request, err := http.NewRequest("POST", "/job-templates", strings.NewReader(body))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestJobTemplateListSharesAdminTemplates(c *check.C) {
+	s.createJobTemplate(c, "")
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermJobCreate,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	request, err := http.NewRequest("GET", "/job-templates", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var templates []jobTypes.Template
+	err = json.Unmarshal(recorder.Body.Bytes(), &templates)
+	c.Assert(err, check.IsNil)
+	c.Assert(templates, check.HasLen, 1)
+	c.Assert(templates[0].Name, check.Equals, "db-backup")
+}
+
+func (s *S) TestJobTemplateInfoRedactsAdminTemplatePrivateEnvs(c *check.C) {
+	s.createJobTemplate(c, "")
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermJobCreate,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	request, err := http.NewRequest("GET", "/job-templates/db-backup", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var tmpl jobTypes.Template
+	err = json.Unmarshal(recorder.Body.Bytes(), &tmpl)
+	c.Assert(err, check.IsNil)
+	c.Assert(tmpl.Spec.Envs, check.DeepEquals, []bindTypes.EnvVar{{Name: "PGDATABASE", Value: app.SuppressedEnv}})
+	request, err = http.NewRequest("GET", "/job-templates/db-backup", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	err = json.Unmarshal(recorder.Body.Bytes(), &tmpl)
+	c.Assert(err, check.IsNil)
+	c.Assert(tmpl.Spec.Envs, check.DeepEquals, []bindTypes.EnvVar{{Name: "PGDATABASE", Value: "{{.database}}"}})
+}
+
+func (s *S) TestCreateJobFromTemplate(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "jobProv"
+	provision.Register("jobProv", func() (provision.Provisioner, error) {
+		return &provisiontest.JobProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}, nil
+	})
+	defer provision.Unregister("jobProv")
+	s.createJobTemplate(c, s.team.Name)
+	j := inputJob{
+		Name:      "orders-backup",
+		TeamOwner: s.team.Name,
+		Pool:      "test1",
+		Template:  "db-backup",
+		Params:    map[string]string{"database": "orders"},
+	}
+	var buffer bytes.Buffer
+	err := json.NewEncoder(&buffer).Encode(j)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/jobs", &buffer)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/json")
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermJobCreate,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	s.mockService.UserQuota.OnInc = func(item quota.QuotaItem, q int) error {
+		return nil
+	}
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	gotJob, err := servicemanager.Job.GetByName(context.TODO(), "orders-backup")
+	c.Assert(err, check.IsNil)
+	c.Assert(gotJob.Spec.Container.OriginalImageSrc, check.Equals, "postgres:15")
+	c.Assert(gotJob.Spec.Container.Command, check.DeepEquals, []string{"pg_dump", "orders"})
+	c.Assert(gotJob.Spec.Schedule, check.Equals, "0 3 * * *")
+	c.Assert(gotJob.Template, check.DeepEquals, &jobTypes.TemplateRef{Name: "db-backup", Params: map[string]string{"database": "orders"}})
+
+	request, err = http.NewRequest("GET", "/jobs?template=db-backup", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var jobs []jobTypes.Job
+	err = json.Unmarshal(recorder.Body.Bytes(), &jobs)
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.HasLen, 1)
+	c.Assert(jobs[0].Name, check.Equals, "orders-backup")
+}
+
+func (s *S) TestCreateJobFromOtherTeamTemplate(c *check.C) {
+	s.createJobTemplate(c, "other-team")
+	j := inputJob{
+		Name:      "orders-backup",
+		TeamOwner: s.team.Name,
+		Pool:      "test1",
+		Template:  "db-backup",
+		Params:    map[string]string{"database": "orders"},
+	}
+	var buffer bytes.Buffer
+	err := json.NewEncoder(&buffer).Encode(j)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/jobs", &buffer)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(recorder.Body.String(), check.Equals, "Job template db-backup is not available to team "+s.team.Name+".\n")
+}
+
+func (s *S) TestApplyJobTemplateNotFound(c *check.C) {
+	request, err := http.NewRequest("POST", "/job-templates/unknown/apply", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
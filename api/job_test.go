@@ -18,6 +18,7 @@ import (
 	"github.com/cezarsa/form"
 	"github.com/tsuru/config"
 
+	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/db/storagev2"
 	"github.com/tsuru/tsuru/event/eventtest"
@@ -601,6 +602,190 @@ func (s *S) TestKillJobUnitNotFound(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
 }
 
+func (s *S) TestSuspendAndResumeJob(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "jobProv"
+	provision.Register("jobProv", func() (provision.Provisioner, error) {
+		return &provisiontest.JobProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}, nil
+	})
+	defer provision.Unregister("jobProv")
+	j1 := jobTypes.Job{
+		TeamOwner: s.team.Name,
+		Pool:      "test1",
+		Name:      "job1",
+		Spec: jobTypes.JobSpec{
+			Schedule: "* * * * *",
+		},
+		DeployOptions: &jobTypes.DeployOptions{
+			Kind:  provTypes.DeployImage,
+			Image: "busybox:1.28",
+		},
+	}
+	user, _ := auth.ConvertOldUser(s.user, nil)
+	err := servicemanager.Job.CreateJob(context.TODO(), &j1, user)
+	c.Assert(err, check.IsNil)
+	for _, action := range []string{"suspend", "resume"} {
+		request, err := http.NewRequest("POST", "/jobs/job1/"+action, nil)
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Authorization", "b "+s.token.GetValue())
+		recorder := httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, request)
+		c.Assert(recorder.Code, check.Equals, http.StatusOK)
+		gotJob, err := servicemanager.Job.GetByName(context.TODO(), j1.Name)
+		c.Assert(err, check.IsNil)
+		c.Assert(gotJob.Spec.Suspend, check.Equals, action == "suspend")
+		c.Assert(eventtest.EventDesc{
+			Target: jobTarget("job1"),
+			Owner:  s.token.GetUserName(),
+			Kind:   "job.update." + action,
+		}, eventtest.HasEvent)
+	}
+}
+
+func (s *S) TestSuspendManualJob(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "jobProv"
+	provision.Register("jobProv", func() (provision.Provisioner, error) {
+		return &provisiontest.JobProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}, nil
+	})
+	defer provision.Unregister("jobProv")
+	j1 := jobTypes.Job{
+		TeamOwner: s.team.Name,
+		Pool:      "test1",
+		Name:      "job1",
+		Spec: jobTypes.JobSpec{
+			Manual: true,
+		},
+		DeployOptions: &jobTypes.DeployOptions{
+			Kind:  provTypes.DeployImage,
+			Image: "busybox:1.28",
+		},
+	}
+	user, _ := auth.ConvertOldUser(s.user, nil)
+	err := servicemanager.Job.CreateJob(context.TODO(), &j1, user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/jobs/job1/suspend", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, jobTypes.ErrSuspendManualJob.Error()+"\n")
+}
+
+func (s *S) TestSetAndRemoveJobAutoScale(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "jobProv"
+	provision.Register("jobProv", func() (provision.Provisioner, error) {
+		return &provisiontest.JobProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}, nil
+	})
+	defer provision.Unregister("jobProv")
+	j1 := jobTypes.Job{
+		TeamOwner: s.team.Name,
+		Pool:      "test1",
+		Name:      "job1",
+		Spec: jobTypes.JobSpec{
+			Manual: true,
+		},
+		DeployOptions: &jobTypes.DeployOptions{
+			Kind:  provTypes.DeployImage,
+			Image: "busybox:1.28",
+		},
+	}
+	user, _ := auth.ConvertOldUser(s.user, nil)
+	err := servicemanager.Job.CreateJob(context.TODO(), &j1, user)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader(`{"minParallelism":0,"maxParallelism":5,"prometheus":[{"name":"queue","query":"sum(queue_depth)","threshold":10}]}`)
+	request, err := http.NewRequest("POST", "/jobs/job1/autoscale", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %s", recorder.Body.String()))
+	gotJob, err := servicemanager.Job.GetByName(context.TODO(), j1.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(gotJob.Spec.AutoScale, check.DeepEquals, &jobTypes.AutoScaleSpec{
+		MaxParallelism: 5,
+		Prometheus: []provTypes.AutoScalePrometheus{
+			{Name: "queue", Query: "sum(queue_depth)", Threshold: 10},
+		},
+	})
+	c.Assert(eventtest.EventDesc{
+		Target: jobTarget("job1"),
+		Owner:  s.token.GetUserName(),
+		Kind:   "job.update.autoscale.add",
+	}, eventtest.HasEvent)
+	request, err = http.NewRequest("DELETE", "/jobs/job1/autoscale", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	gotJob, err = servicemanager.Job.GetByName(context.TODO(), j1.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(gotJob.Spec.AutoScale, check.IsNil)
+	c.Assert(eventtest.EventDesc{
+		Target: jobTarget("job1"),
+		Owner:  s.token.GetUserName(),
+		Kind:   "job.update.autoscale.remove",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestSetJobAutoScaleScheduledJob(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "jobProv"
+	provision.Register("jobProv", func() (provision.Provisioner, error) {
+		return &provisiontest.JobProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}, nil
+	})
+	defer provision.Unregister("jobProv")
+	j1 := jobTypes.Job{
+		TeamOwner: s.team.Name,
+		Pool:      "test1",
+		Name:      "job1",
+		Spec: jobTypes.JobSpec{
+			Schedule: "* * * * *",
+		},
+		DeployOptions: &jobTypes.DeployOptions{
+			Kind:  provTypes.DeployImage,
+			Image: "busybox:1.28",
+		},
+	}
+	user, _ := auth.ConvertOldUser(s.user, nil)
+	err := servicemanager.Job.CreateJob(context.TODO(), &j1, user)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader(`{"maxParallelism":5,"prometheus":[{"name":"queue","query":"sum(queue_depth)"}]}`)
+	request, err := http.NewRequest("POST", "/jobs/job1/autoscale", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, jobTypes.ErrAutoScaleScheduledJob.Error()+"\n")
+}
+
+func (s *S) TestSuspendJobWithoutPermission(c *check.C) {
+	jobsCollection, err := storagev2.JobsCollection()
+	c.Assert(err, check.IsNil)
+	_, err = jobsCollection.InsertOne(context.TODO(), jobTypes.Job{Name: "job1", Pool: "test1", TeamOwner: s.team.Name, Teams: []string{s.team.Name}})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermJobUpdateResume,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	request, err := http.NewRequest("POST", "/jobs/job1/suspend", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
 func (s *S) TestUpdateCronjobNotFound(c *check.C) {
 	oldProvisioner := provision.DefaultProvisioner
 	defer func() { provision.DefaultProvisioner = oldProvisioner }()
@@ -828,6 +1013,95 @@ func (s *S) TestTriggerCronjob(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, http.StatusOK)
 }
 
+func (s *S) TestTriggerCronjobWithParameters(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "jobProv"
+	provision.Register("jobProv", func() (provision.Provisioner, error) {
+		return &provisiontest.JobProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}, nil
+	})
+	defer provision.Unregister("jobProv")
+	j1 := jobTypes.Job{
+		TeamOwner: s.team.Name,
+		Pool:      "test1",
+		Name:      "manual-job",
+		Spec: jobTypes.JobSpec{
+			Manual: true,
+			Container: jobTypes.ContainerInfo{
+				OriginalImageSrc: "ubuntu:latest",
+				Command:          []string{"./reprocess"},
+			},
+		},
+	}
+	user, _ := auth.ConvertOldUser(s.user, nil)
+	err := servicemanager.Job.CreateJob(context.TODO(), &j1, user)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader(`{"envs":[{"name":"DATE","value":"2026-01-01","public":true},{"name":"TOKEN","value":"secret"}],"args":["--dry-run"],"command":["./reprocess","--verbose"]}`)
+	request, err := http.NewRequest("POST", fmt.Sprintf("/jobs/%s/trigger", j1.Name), body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(provisiontest.ProvisionerInstance.LastJobTrigger(j1.Name), check.DeepEquals, jobTypes.TriggerOptions{
+		Envs:        []bindTypes.EnvVar{{Name: "DATE", Value: "2026-01-01", Public: true}, {Name: "TOKEN", Value: "secret"}},
+		Args:        []string{"--dry-run"},
+		Command:     []string{"./reprocess", "--verbose"},
+		TriggeredBy: s.token.GetUserName(),
+	})
+	c.Assert(eventtest.EventDesc{
+		Target: jobTarget(j1.Name),
+		Owner:  s.token.GetUserName(),
+		Kind:   "job.trigger",
+		StartCustomData: []map[string]interface{}{
+			{"name": "envs.0.name", "value": "DATE"},
+			{"name": "envs.0.value", "value": "2026-01-01"},
+			{"name": "envs.1.name", "value": "TOKEN"},
+			{"name": "envs.1.value", "value": app.SuppressedEnv},
+			{"name": "args.0", "value": "--dry-run"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestTriggerCronjobWithParametersForbidden(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "jobProv"
+	provision.Register("jobProv", func() (provision.Provisioner, error) {
+		return &provisiontest.JobProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}, nil
+	})
+	defer provision.Unregister("jobProv")
+	j1 := jobTypes.Job{
+		TeamOwner: s.team.Name,
+		Pool:      "test1",
+		Name:      "manual-job",
+		Spec: jobTypes.JobSpec{
+			Manual: true,
+			Container: jobTypes.ContainerInfo{
+				OriginalImageSrc: "ubuntu:latest",
+				Command:          []string{"./reprocess"},
+			},
+		},
+	}
+	user, _ := auth.ConvertOldUser(s.user, nil)
+	err := servicemanager.Job.CreateJob(context.TODO(), &j1, user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermJobRun,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	body := strings.NewReader(`{"command":["sh","-c","env"]}`)
+	request, err := http.NewRequest("POST", fmt.Sprintf("/jobs/%s/trigger", j1.Name), body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(provisiontest.ProvisionerInstance.JobExecutions(j1.Name), check.Equals, 0)
+}
+
 func (s *S) TestJobList(c *check.C) {
 	oldProvisioner := provision.DefaultProvisioner
 	defer func() { provision.DefaultProvisioner = oldProvisioner }()
@@ -3292,6 +3566,105 @@ func (s *S) TestJobLogsList(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, http.StatusOK)
 }
 
+func (s *S) TestJobRuns(c *check.C) {
+	j := jobTypes.Job{Name: "myjob", Pool: "test1", TeamOwner: s.team.Name, Teams: []string{s.team.Name}}
+	jobsCollection, err := storagev2.JobsCollection()
+	c.Assert(err, check.IsNil)
+	_, err = jobsCollection.InsertOne(context.TODO(), j)
+	c.Assert(err, check.IsNil)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	exitCode := int32(1)
+	for _, run := range []jobTypes.Run{
+		{Name: "myjob-1", Job: j.Name, Status: jobTypes.RunStatusSucceeded, StartTime: now.Add(-time.Hour)},
+		{Name: "myjob-2", Job: j.Name, Status: jobTypes.RunStatusFailed, StartTime: now, ExitCode: &exitCode, Manual: true, TriggeredBy: "admin@example.com"},
+	} {
+		err = servicemanager.Job.RecordRun(context.TODO(), run)
+		c.Assert(err, check.IsNil)
+	}
+	request, err := http.NewRequest("GET", "/jobs/myjob/runs?status=failed", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var runs []jobTypes.Run
+	err = json.Unmarshal(recorder.Body.Bytes(), &runs)
+	c.Assert(err, check.IsNil)
+	c.Assert(runs, check.HasLen, 1)
+	c.Assert(runs[0].Name, check.Equals, "myjob-2")
+	c.Assert(*runs[0].ExitCode, check.Equals, int32(1))
+	c.Assert(runs[0].TriggeredBy, check.Equals, "admin@example.com")
+	request, err = http.NewRequest("GET", "/jobs/myjob/runs/myjob-1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var run jobTypes.Run
+	err = json.Unmarshal(recorder.Body.Bytes(), &run)
+	c.Assert(err, check.IsNil)
+	c.Assert(run.Status, check.Equals, jobTypes.RunStatusSucceeded)
+	request, err = http.NewRequest("GET", "/jobs/myjob/runs/myjob-3", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestJobRunsNoContent(c *check.C) {
+	j := jobTypes.Job{Name: "myjob", Pool: "test1", TeamOwner: s.team.Name, Teams: []string{s.team.Name}}
+	jobsCollection, err := storagev2.JobsCollection()
+	c.Assert(err, check.IsNil)
+	_, err = jobsCollection.InsertOne(context.TODO(), j)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/jobs/myjob/runs", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+}
+
+func (s *S) TestJobRunLog(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "jobProv"
+	provision.Register("jobProv", func() (provision.Provisioner, error) {
+		prov := provisiontest.ProvisionerInstance
+		prov.LogsEnabled = true
+		return &provisiontest.JobProvisioner{FakeProvisioner: prov}, nil
+	})
+	defer provision.Unregister("jobProv")
+	j := jobTypes.Job{
+		Name:      "lost1",
+		Pool:      s.Pool,
+		TeamOwner: s.team.Name,
+		Spec: jobTypes.JobSpec{
+			Schedule: "* * * * *",
+		},
+		DeployOptions: &jobTypes.DeployOptions{
+			Kind:  provTypes.DeployImage,
+			Image: "busybox:1.18",
+		},
+	}
+	user, _ := auth.ConvertOldUser(s.user, nil)
+	err := servicemanager.Job.CreateJob(context.TODO(), &j, user)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Job.RecordRun(context.TODO(), jobTypes.Run{Name: "lost1-1", Job: j.Name, StartTime: time.Now(), Units: []string{"lost1-1-abcde"}})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", fmt.Sprintf("/jobs/%s/runs/lost1-1/log?lines=10", j.Name), nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var logs []appTypes.Applog
+	err = json.Unmarshal(recorder.Body.Bytes(), &logs)
+	c.Assert(err, check.IsNil)
+	c.Assert(logs[0].Message, check.Equals, "Fake message from provisioner")
+}
+
 func (s *S) TestJobLogsWatch(c *check.C) {
 	oldProvisioner := provision.DefaultProvisioner
 	defer func() { provision.DefaultProvisioner = oldProvisioner }()
@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/auth"
@@ -20,9 +22,12 @@ import (
 	"github.com/tsuru/tsuru/router"
 	"github.com/tsuru/tsuru/service"
 	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
 	authTypes "github.com/tsuru/tsuru/types/auth"
 	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
 	permTypes "github.com/tsuru/tsuru/types/permission"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
 )
 
 // title: role create
@@ -285,6 +290,92 @@ func removePermissions(w http.ResponseWriter, r *http.Request, t auth.Token) (er
 	return role.RemovePermissions(ctx, permName)
 }
 
+// title: set permission condition
+// path: /roles/{name}/conditions
+// method: POST
+// consume: application/x-www-form-urlencoded
+// responses:
+//
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Role not found
+func setPermissionCondition(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	if !permission.Check(ctx, t, permission.PermRoleUpdatePermissionAdd) {
+		return permission.ErrUnauthorized
+	}
+	roleName := r.URL.Query().Get(":name")
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     eventTypes.Target{Type: eventTypes.TargetTypeRole, Value: roleName},
+		Kind:       permission.PermRoleUpdatePermissionAdd,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermRoleReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	role, err := getRoleReturnNotFound(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	tags, _ := InputValues(r, "tag")
+	pools, _ := InputValues(r, "pool")
+	err = role.SetCondition(ctx, permTypes.Condition{
+		Permission: InputValue(r, "permission"),
+		Tags:       tags,
+		Pools:      pools,
+	})
+	if err == permTypes.ErrEmptyCondition {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	if perr, ok := err.(*permTypes.ErrInvalidCondition); ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: perr.Error()}
+	}
+	if perr, ok := err.(*permTypes.ErrPermissionNotFound); ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: perr.Error()}
+	}
+	return err
+}
+
+// title: remove permission condition
+// path: /roles/{name}/conditions/{permission}
+// method: DELETE
+// responses:
+//
+//	200: Condition removed
+//	401: Unauthorized
+//	404: Role not found
+func removePermissionCondition(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	// Removing a condition widens what the role grants, so it requires the
+	// same permission as adding permissions to the role.
+	if !permission.Check(ctx, t, permission.PermRoleUpdatePermissionAdd) {
+		return permission.ErrUnauthorized
+	}
+	roleName := r.URL.Query().Get(":name")
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     eventTypes.Target{Type: eventTypes.TargetTypeRole, Value: roleName},
+		Kind:       permission.PermRoleUpdatePermissionAdd,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermRoleReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	role, err := getRoleReturnNotFound(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	return role.RemoveCondition(ctx, r.URL.Query().Get(":permission"))
+}
+
 func getRoleReturnNotFound(ctx context.Context, roleName string) (permission.Role, error) {
 	role, err := permission.FindRole(ctx, roleName)
 	if err != nil {
@@ -364,7 +455,31 @@ func assignRole(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 		return err
 	}
 
-	return user.AddRole(ctx, roleName, contextValue)
+	expiresAtRaw := InputValue(r, "expires_at")
+	if expiresAtRaw == "" {
+		return user.AddRole(ctx, roleName, contextValue)
+	}
+	expiresAt, err := time.Parse(time.RFC3339, expiresAtRaw)
+	if err != nil {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("invalid expires_at value %q, must be in RFC3339 format", expiresAtRaw),
+		}
+	}
+	if !expiresAt.After(time.Now()) {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: "expires_at must be in the future",
+		}
+	}
+	justification := InputValue(r, "justification")
+	if justification == "" {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: "justification is required for role assignments with expiration",
+		}
+	}
+	return user.AddRoleWithExpiration(ctx, roleName, contextValue, expiresAt, justification)
 }
 
 // title: dissociate role from user
@@ -449,6 +564,121 @@ func listPermissions(w http.ResponseWriter, r *http.Request, t auth.Token) error
 	return json.NewEncoder(w).Encode(permList)
 }
 
+// title: explain permission
+// path: /permissions/explain
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+//	404: User or context not found
+func explainPermission(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	permName := r.URL.Query().Get("permission")
+	scheme, err := permission.SafeGet(permName)
+	if permName == "" || err != nil {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: (&permTypes.ErrPermissionNotFound{Permission: permName}).Error(),
+		}
+	}
+	user, err := auth.ConvertNewUser(t.User(ctx))
+	if err != nil {
+		return err
+	}
+	email := r.URL.Query().Get("user")
+	if email != "" && email != user.Email {
+		if !permission.Check(ctx, t, permission.PermUserUpdate) {
+			return permission.ErrUnauthorized
+		}
+		user, err = auth.GetUserByEmail(ctx, email)
+		if err == authTypes.ErrUserNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	contexts, err := explainContexts(ctx, t, r.URL.Query().Get("context"))
+	if err != nil {
+		return err
+	}
+	explanation, err := user.ExplainPermission(ctx, scheme, contexts...)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(explanation)
+}
+
+// explainContexts expands a context in the "type:value" format into the
+// same list of contexts the API handlers use when checking permissions for
+// the referenced object. Objects the token can't read are reported as not
+// found, so their existence and contexts aren't disclosed.
+func explainContexts(ctx context.Context, t auth.Token, rawContext string) ([]permTypes.PermissionContext, error) {
+	if rawContext == "" {
+		return nil, nil
+	}
+	rawType, value, _ := strings.Cut(rawContext, ":")
+	ctxType, err := permission.ParseContext(rawType)
+	if err != nil {
+		return nil, &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	notFound := func(err error) error {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	switch ctxType {
+	case permTypes.CtxGlobal:
+		return nil, nil
+	case permTypes.CtxApp:
+		a, err := app.GetByName(ctx, value)
+		if err != nil {
+			return nil, notFound(err)
+		}
+		if !permission.Check(ctx, t, permission.PermAppRead, contextsForApp(a)...) {
+			return nil, notFound(appTypes.ErrAppNotFound)
+		}
+		return contextsForApp(a), nil
+	case permTypes.CtxJob:
+		j, err := servicemanager.Job.GetByName(ctx, value)
+		if err != nil {
+			return nil, notFound(err)
+		}
+		if !permission.Check(ctx, t, permission.PermJobRead, contextsForJob(j)...) {
+			return nil, notFound(jobTypes.ErrJobNotFound)
+		}
+		return contextsForJob(j), nil
+	case permTypes.CtxService:
+		s, err := service.Get(ctx, value)
+		if err != nil {
+			return nil, notFound(err)
+		}
+		return contextsForService(&s), nil
+	case permTypes.CtxServiceInstance:
+		serviceName, instanceName, _ := strings.Cut(value, "/")
+		si, err := service.GetServiceInstance(ctx, serviceName, instanceName)
+		if err != nil {
+			return nil, notFound(err)
+		}
+		if !permission.Check(ctx, t, permission.PermServiceInstanceRead, contextsForServiceInstance(si, serviceName)...) {
+			return nil, notFound(service.ErrServiceInstanceNotFound)
+		}
+		return contextsForServiceInstance(si, serviceName), nil
+	case permTypes.CtxVolume:
+		v, err := servicemanager.Volume.Get(ctx, value)
+		if err != nil {
+			return nil, notFound(err)
+		}
+		if !permission.Check(ctx, t, permission.PermVolumeRead, contextsForVolume(v)...) {
+			return nil, notFound(volumeTypes.ErrVolumeNotFound)
+		}
+		return contextsForVolume(v), nil
+	}
+	return []permTypes.PermissionContext{permission.Context(ctxType, value)}, nil
+}
+
 // title: add default role
 // path: /role/default
 // method: POST
@@ -11,8 +11,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tsuru/tsuru/app"
@@ -453,6 +455,79 @@ func (s *S) TestAssignRole(c *check.C) {
 	}, eventtest.HasEvent)
 }
 
+func (s *S) TestAssignRoleWithExpiration(c *check.C) {
+	ctx := context.TODO()
+
+	role, err := permission.NewRole(ctx, "test", "team", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions(ctx, "app.create")
+	c.Assert(err, check.IsNil)
+	_, emptyToken := permissiontest.CustomUserWithPermission(c, nativeScheme, "user2")
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	roleBody := bytes.NewBufferString(fmt.Sprintf("email=%s&context=myteam&expires_at=%s&justification=on-call", emptyToken.GetUserName(), expiresAt.Format(time.RFC3339)))
+	req, err := http.NewRequest(http.MethodPost, "/roles/test/user", roleBody)
+	c.Assert(err, check.IsNil)
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "user1", permTypes.Permission{
+		Scheme:  permission.PermRoleUpdateAssign,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	}, permTypes.Permission{
+		Scheme:  permission.PermAppCreate,
+		Context: permission.Context(permTypes.CtxTeam, "myteam"),
+	})
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	emptyUser, err := emptyToken.User(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(emptyUser.Roles, check.HasLen, 1)
+	c.Assert(emptyUser.Roles[0].ExpiresAt, check.NotNil)
+	c.Assert(emptyUser.Roles[0].ExpiresAt.Equal(expiresAt), check.Equals, true)
+	c.Assert(emptyUser.Roles[0].Justification, check.Equals, "on-call")
+}
+
+func (s *S) TestAssignRoleWithInvalidExpiration(c *check.C) {
+	ctx := context.TODO()
+
+	role, err := permission.NewRole(ctx, "test", "team", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions(ctx, "app.create")
+	c.Assert(err, check.IsNil)
+	_, emptyToken := permissiontest.CustomUserWithPermission(c, nativeScheme, "user2")
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "user1", permTypes.Permission{
+		Scheme:  permission.PermRoleUpdateAssign,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	}, permTypes.Permission{
+		Scheme:  permission.PermAppCreate,
+		Context: permission.Context(permTypes.CtxTeam, "myteam"),
+	})
+	tests := []struct {
+		body    string
+		message string
+	}{
+		{body: "expires_at=tomorrow&justification=x", message: "invalid expires_at value \"tomorrow\", must be in RFC3339 format\n"},
+		{body: "expires_at=" + url.QueryEscape(time.Now().Add(-time.Hour).Format(time.RFC3339)) + "&justification=x", message: "expires_at must be in the future\n"},
+		{body: "expires_at=" + url.QueryEscape(time.Now().Add(time.Hour).Format(time.RFC3339)), message: "justification is required for role assignments with expiration\n"},
+	}
+	for _, tt := range tests {
+		roleBody := bytes.NewBufferString(fmt.Sprintf("email=%s&context=myteam&%s", emptyToken.GetUserName(), tt.body))
+		req, err := http.NewRequest(http.MethodPost, "/roles/test/user", roleBody)
+		c.Assert(err, check.IsNil)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Authorization", "bearer "+token.GetValue())
+		recorder := httptest.NewRecorder()
+		server := RunServer(true)
+		server.ServeHTTP(recorder, req)
+		c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+		c.Assert(recorder.Body.String(), check.Equals, tt.message)
+	}
+	emptyUser, err := emptyToken.User(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(emptyUser.Roles, check.HasLen, 0)
+}
+
 func (s *S) TestAssignRoleNotFound(c *check.C) {
 	_, emptyToken := permissiontest.CustomUserWithPermission(c, nativeScheme, "user2")
 	roleBody := bytes.NewBufferString(fmt.Sprintf("email=%s&context=myteam", emptyToken.GetUserName()))
@@ -1007,6 +1082,144 @@ func (s *S) TestListPermissions(c *check.C) {
 	})
 }
 
+func (s *S) TestSetPermissionCondition(c *check.C) {
+	role, err := permission.NewRole(context.TODO(), "test", "team", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions(context.TODO(), "app.deploy")
+	c.Assert(err, check.IsNil)
+	rec := httptest.NewRecorder()
+	body := strings.NewReader("permission=app.deploy&pool=staging-*&tag=tier%3Ddev")
+	req, err := http.NewRequest(http.MethodPost, "/roles/test/conditions", body)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	server := RunServer(true)
+	server.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	role, err = permission.FindRole(context.TODO(), "test")
+	c.Assert(err, check.IsNil)
+	c.Assert(role.Conditions, check.DeepEquals, []permTypes.Condition{
+		{Permission: "app.deploy", Tags: []string{"tier=dev"}, Pools: []string{"staging-*"}},
+	})
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeRole, Value: "test"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "role.update.permission.add",
+		StartCustomData: []map[string]interface{}{
+			{"name": "permission", "value": "app.deploy"},
+			{"name": ":name", "value": "test"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestSetPermissionConditionInvalid(c *check.C) {
+	role, err := permission.NewRole(context.TODO(), "test", "team", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions(context.TODO(), "app.deploy")
+	c.Assert(err, check.IsNil)
+	rec := httptest.NewRecorder()
+	body := strings.NewReader("permission=app.update&tag=tier%3Ddev")
+	req, err := http.NewRequest(http.MethodPost, "/roles/test/conditions", body)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	server := RunServer(true)
+	server.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(rec.Body.String(), check.Equals, "invalid condition for permission \"app.update\": permission is not part of the role\n")
+}
+
+func (s *S) TestRemovePermissionCondition(c *check.C) {
+	role, err := permission.NewRole(context.TODO(), "test", "team", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions(context.TODO(), "app.deploy")
+	c.Assert(err, check.IsNil)
+	err = role.SetCondition(context.TODO(), permTypes.Condition{Permission: "app.deploy", Pools: []string{"staging-*"}})
+	c.Assert(err, check.IsNil)
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodDelete, "/roles/test/conditions/app.deploy", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	server := RunServer(true)
+	server.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	role, err = permission.FindRole(context.TODO(), "test")
+	c.Assert(err, check.IsNil)
+	c.Assert(role.Conditions, check.HasLen, 0)
+}
+
+func (s *S) TestExplainPermission(c *check.C) {
+	app1 := appTypes.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "explainer", permTypes.Permission{
+		Scheme:  permission.PermAppDeploy,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	}, permTypes.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/permissions/explain?permission=app.deploy&context=app:myapp", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	server := RunServer(true)
+	server.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	var explanation auth.PermissionExplanation
+	err = json.Unmarshal(rec.Body.Bytes(), &explanation)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Allowed, check.Equals, true)
+	c.Assert(explanation.Contexts, check.DeepEquals, []string{"team:" + s.team.Name, "app:myapp", "pool:" + app1.Pool})
+	c.Assert(explanation.GrantedBy, check.DeepEquals, &auth.RoleEvaluation{
+		Role:         "explainerapp.deploy" + s.team.Name,
+		ContextType:  "team",
+		ContextValue: s.team.Name,
+		Granted:      true,
+		Reason:       `permission "app.deploy" granted in context team:` + s.team.Name,
+	})
+}
+
+func (s *S) TestExplainPermissionContextWithoutReadPermission(c *check.C) {
+	app1 := appTypes.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "explainer", permTypes.Permission{
+		Scheme:  permission.PermAppDeploy,
+		Context: permission.Context(permTypes.CtxTeam, "otherteam"),
+	})
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/permissions/explain?permission=app.deploy&context=app:myapp", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	server := RunServer(true)
+	server.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusNotFound)
+	c.Assert(rec.Body.String(), check.Equals, appTypes.ErrAppNotFound.Error()+"\n")
+}
+
+func (s *S) TestExplainPermissionOtherUserRequiresPermission(c *check.C) {
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "explainer")
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/permissions/explain?permission=app.deploy&user="+s.user.Email, nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	server := RunServer(true)
+	server.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestExplainPermissionInvalidPermission(c *check.C) {
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/permissions/explain?permission=app.dance", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	server := RunServer(true)
+	server.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(rec.Body.String(), check.Equals, "permission named \"app.dance\" not found\n")
+}
+
 func (s *S) TestAddDefaultRole(c *check.C) {
 	_, err := permission.NewRole(context.TODO(), "r1", "team", "")
 	c.Assert(err, check.IsNil)
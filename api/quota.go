@@ -13,6 +13,7 @@ import (
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/job"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/servicemanager"
 	authTypes "github.com/tsuru/tsuru/types/auth"
@@ -185,6 +186,16 @@ func changeAppQuota(w http.ResponseWriter, r *http.Request, t auth.Token) (err e
 	return err
 }
 
+// teamQuota holds the quota of apps of the team along with its job quotas.
+// The job runs limit applies to each pool, JobRunsByPool holds the job units
+// currently running in each one of them.
+type teamQuota struct {
+	quota.Quota
+	Jobs          quota.Quota    `json:"jobs"`
+	JobRuns       quota.Quota    `json:"jobRuns"`
+	JobRunsByPool map[string]int `json:"jobRunsByPool"`
+}
+
 // title: team quota
 // path: /teams/{name}/quota
 // method: GET
@@ -211,8 +222,28 @@ func getTeamQuota(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if err != nil {
 		return err
 	}
+	jobQuota, err := servicemanager.TeamJobQuota.Get(ctx, team)
+	if err != nil {
+		return err
+	}
+	jobRunQuota, err := servicemanager.TeamJobRunQuota.Get(ctx, team)
+	if err != nil {
+		return err
+	}
+	runningJobUnits, err := job.RunningUnitsByPool(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	for _, running := range runningJobUnits {
+		jobRunQuota.InUse += running
+	}
 	w.Header().Set("Content-Type", "application/json")
-	return json.NewEncoder(w).Encode(team.Quota)
+	return json.NewEncoder(w).Encode(teamQuota{
+		Quota:         team.Quota,
+		Jobs:          *jobQuota,
+		JobRuns:       *jobRunQuota,
+		JobRunsByPool: runningJobUnits,
+	})
 }
 
 // title: update team quota
@@ -255,19 +286,45 @@ func changeTeamQuota(w http.ResponseWriter, r *http.Request, t auth.Token) (err
 		return err
 	}
 	defer func() { evt.Done(ctx, err) }()
-	limit, err := strconv.Atoi(InputValue(r, "limit"))
-	if err != nil {
-		return &errors.HTTP{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid limit",
+	type teamLimit struct {
+		field   string
+		name    string
+		service quota.QuotaService[*authTypes.Team]
+		value   int
+	}
+	var limits []teamLimit
+	for _, l := range []teamLimit{
+		{field: "limit", name: "limit", service: servicemanager.TeamQuota},
+		{field: "jobs", name: "jobs limit", service: servicemanager.TeamJobQuota},
+		{field: "jobRuns", name: "job runs limit", service: servicemanager.TeamJobRunQuota},
+	} {
+		if _, ok := InputValues(r, l.field); ok {
+			limits = append(limits, l)
 		}
 	}
-	err = servicemanager.TeamQuota.SetLimit(r.Context(), team, limit)
-	if err == quota.ErrLimitLowerThanAllocated {
-		return &errors.HTTP{
-			Code:    http.StatusForbidden,
-			Message: err.Error(),
+	if len(limits) == 0 {
+		limits = []teamLimit{{field: "limit", name: "limit", service: servicemanager.TeamQuota}}
+	}
+	for i := range limits {
+		limits[i].value, err = strconv.Atoi(InputValue(r, limits[i].field))
+		if err != nil {
+			return &errors.HTTP{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid " + limits[i].name,
+			}
 		}
 	}
-	return err
+	for _, l := range limits {
+		err = l.service.SetLimit(r.Context(), team, l.value)
+		if err == quota.ErrLimitLowerThanAllocated {
+			return &errors.HTTP{
+				Code:    http.StatusForbidden,
+				Message: err.Error(),
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
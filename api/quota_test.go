@@ -74,6 +74,12 @@ func (s *QuotaSuite) SetUpTest(c *check.C) {
 	c.Assert(err, check.IsNil)
 	app.AuthScheme = nativeScheme
 	servicemock.SetMockService(&s.mockService)
+	s.mockService.TeamJobQuota.OnGet = func(_ *authTypes.Team) (*quota.Quota, error) {
+		return &quota.UnlimitedQuota, nil
+	}
+	s.mockService.TeamJobRunQuota.OnGet = func(_ *authTypes.Team) (*quota.Quota, error) {
+		return &quota.UnlimitedQuota, nil
+	}
 }
 
 func (s *QuotaSuite) TearDownSuite(c *check.C) {
@@ -354,6 +360,91 @@ func (s *QuotaSuite) TestChangeTeamQuota(c *check.C) {
 	}, eventtest.HasEvent)
 }
 
+func (s *QuotaSuite) TestGetTeamQuotaWithJobQuotas(c *check.C) {
+	team := &authTypes.Team{
+		Name:         "avengers",
+		CreatingUser: "radio@gaga.com",
+		Quota:        quota.Quota{Limit: 4, InUse: 2},
+	}
+	s.mockService.Team.OnFindByName = func(s string) (*authTypes.Team, error) {
+		return team, nil
+	}
+	s.mockService.TeamJobQuota.OnGet = func(t *authTypes.Team) (*quota.Quota, error) {
+		c.Assert(t.Name, check.Equals, team.Name)
+		return &quota.Quota{Limit: 10, InUse: 3}, nil
+	}
+	s.mockService.TeamJobRunQuota.OnGet = func(t *authTypes.Team) (*quota.Quota, error) {
+		c.Assert(t.Name, check.Equals, team.Name)
+		return &quota.Quota{Limit: 5}, nil
+	}
+	request, err := http.NewRequest("GET", "/teams/avengers/quota", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var qt teamQuota
+	err = json.NewDecoder(recorder.Body).Decode(&qt)
+	c.Assert(err, check.IsNil)
+	c.Assert(qt, check.DeepEquals, teamQuota{
+		Quota:         team.Quota,
+		Jobs:          quota.Quota{Limit: 10, InUse: 3},
+		JobRuns:       quota.Quota{Limit: 5},
+		JobRunsByPool: map[string]int{},
+	})
+}
+
+func (s *QuotaSuite) TestChangeTeamJobQuotas(c *check.C) {
+	team := &authTypes.Team{
+		Name:         "avengers",
+		CreatingUser: "radio@gaga.com",
+	}
+	s.mockService.Team.OnFindByName = func(s string) (*authTypes.Team, error) {
+		return team, nil
+	}
+	s.mockService.TeamQuota.OnSetLimit = func(qi *authTypes.Team, i int) error {
+		c.Fatal("app quota must not be changed")
+		return nil
+	}
+	limits := map[string]int{}
+	s.mockService.TeamJobQuota.OnSetLimit = func(qi *authTypes.Team, i int) error {
+		c.Assert(qi.Name, check.Equals, team.Name)
+		limits["jobs"] = i
+		return nil
+	}
+	s.mockService.TeamJobRunQuota.OnSetLimit = func(qi *authTypes.Team, i int) error {
+		c.Assert(qi.Name, check.Equals, team.Name)
+		limits["jobRuns"] = i
+		return nil
+	}
+	body := bytes.NewBufferString("jobs=20&jobRuns=8")
+	request, _ := http.NewRequest("PUT", "/teams/avengers/quota", body)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(limits, check.DeepEquals, map[string]int{"jobs": 20, "jobRuns": 8})
+}
+
+func (s *QuotaSuite) TestChangeTeamJobQuotasInvalidLimit(c *check.C) {
+	s.mockService.Team.OnFindByName = func(s string) (*authTypes.Team, error) {
+		return &authTypes.Team{Name: "avengers"}, nil
+	}
+	s.mockService.TeamJobQuota.OnSetLimit = func(qi *authTypes.Team, i int) error {
+		c.Fatal("job quota must not be changed")
+		return nil
+	}
+	body := bytes.NewBufferString("jobs=20&jobRuns=many")
+	request, _ := http.NewRequest("PUT", "/teams/avengers/quota", body)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "Invalid job runs limit\n")
+}
+
 func (s *QuotaSuite) TestChangeTeamQuotaRequiresPermission(c *check.C) {
 	token := userWithPermission(c)
 	request, _ := http.NewRequest("PUT", "/teams/avengers/quota", nil)
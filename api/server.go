@@ -42,6 +42,7 @@ import (
 	_ "github.com/tsuru/tsuru/auth/native"
 	_ "github.com/tsuru/tsuru/auth/oauth"
 	_ "github.com/tsuru/tsuru/auth/oidc"
+	"github.com/tsuru/tsuru/auth/rolesweeper"
 	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/event/webhook"
 	"github.com/tsuru/tsuru/hc"
@@ -147,6 +148,14 @@ func setupServices() error {
 	if err != nil {
 		return errors.Wrapf(err, "could not initialize team quota service")
 	}
+	servicemanager.TeamJobQuota, err = auth.TeamJobQuotaService()
+	if err != nil {
+		return errors.Wrapf(err, "could not initialize team job quota service")
+	}
+	servicemanager.TeamJobRunQuota, err = auth.TeamJobRunQuotaService()
+	if err != nil {
+		return errors.Wrapf(err, "could not initialize team job run quota service")
+	}
 	servicemanager.Webhook, err = webhook.WebhookService()
 	if err != nil {
 		return errors.Wrapf(err, "could not initialize webhook service")
@@ -195,6 +204,14 @@ func setupServices() error {
 	if err != nil {
 		return errors.Wrapf(err, "could not initialize job service")
 	}
+	servicemanager.JobWorkflow, err = job.WorkflowService()
+	if err != nil {
+		return errors.Wrapf(err, "could not initialize job workflow service")
+	}
+	servicemanager.JobTemplate, err = job.TemplateService()
+	if err != nil {
+		return errors.Wrapf(err, "could not initialize job template service")
+	}
 	servicemanager.Tag, err = tag.TagService()
 	if err != nil {
 		return errors.Wrapf(err, "could not initialize tag service")
@@ -242,6 +259,11 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", http.MethodPut, "/services/{service}/instances/{instance}", AuthorizationRequiredHandler(updateServiceInstance))
 	m.Add("1.0", http.MethodDelete, "/services/{service}/instances/{instance}", AuthorizationRequiredHandler(removeServiceInstance))
 	m.Add("1.0", http.MethodGet, "/services/{service}/instances/{instance}/status", AuthorizationRequiredHandler(serviceInstanceStatus))
+	m.Add("1.0", http.MethodGet, "/services/{service}/instances/{instance}/status/history", AuthorizationRequiredHandler(serviceInstanceStatusHistory))
+	m.Add("1.0", http.MethodPost, "/services/{service}/instances/{instance}/rotate", AuthorizationRequiredHandler(serviceInstanceRotateCredentials))
+	m.Add("1.0", http.MethodGet, "/services/{service}/instances/{instance}/actions", AuthorizationRequiredHandler(serviceInstanceActions))
+	m.Add("1.0", http.MethodPost, "/services/{service}/instances/{instance}/actions/{action}", AuthorizationRequiredHandler(serviceInstanceExecuteAction))
+	m.Add("1.24", http.MethodGet, "/services/{service}/instances/{instance}/deletion-plan", AuthorizationRequiredHandler(serviceInstanceDeletionPlan))
 	m.Add("1.0", http.MethodPut, "/services/{service}/instances/{instance}/{app}", AuthorizationRequiredHandler(bindServiceInstance))
 	m.Add("1.0", http.MethodDelete, "/services/{service}/instances/{instance}/{app}", AuthorizationRequiredHandler(unbindServiceInstance))
 	m.Add("1.13", http.MethodPut, "/services/{service}/instances/{instance}/apps/{app}", AuthorizationRequiredHandler(bindServiceInstance))
@@ -273,6 +295,8 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", http.MethodGet, "/apps/{app}", AuthorizationRequiredHandler(appInfo))
 	m.Add("1.0", http.MethodDelete, "/apps/{app}", AuthorizationRequiredHandler(appDelete))
 	m.Add("1.0", http.MethodPut, "/apps/{app}", AuthorizationRequiredHandler(updateApp))
+	m.Add("1.24", http.MethodGet, "/apps/{app}/dependencies", AuthorizationRequiredHandler(appDependencies))
+	m.Add("1.24", http.MethodGet, "/apps/{app}/deletion-plan", AuthorizationRequiredHandler(appDeletionPlan))
 	m.Add("1.0", http.MethodPost, "/apps/{app}/cname", AuthorizationRequiredHandler(setCName))
 	m.Add("1.0", http.MethodDelete, "/apps/{app}/cname", AuthorizationRequiredHandler(unsetCName))
 	m.Add("1.0", http.MethodPost, "/apps/{app}/run", AuthorizationRequiredHandler(runCommand))
@@ -363,7 +387,14 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", http.MethodGet, "/users/{email}/quota", AuthorizationRequiredHandler(getUserQuota))
 	m.Add("1.0", http.MethodPut, "/users/{email}/quota", AuthorizationRequiredHandler(changeUserQuota))
 	m.Add("1.0", http.MethodDelete, "/users/tokens", AuthorizationRequiredHandler(logout))
+	m.Add("1.0", http.MethodGet, "/users/sessions", AuthorizationRequiredHandler(listSessions))
+	m.Add("1.0", http.MethodDelete, "/users/sessions/{id}", AuthorizationRequiredHandler(revokeSession))
+	m.Add("1.0", http.MethodDelete, "/users/{email}/sessions", AuthorizationRequiredHandler(revokeUserSessions))
 	m.Add("1.0", http.MethodPut, "/users/password", AuthorizationRequiredHandler(changePassword))
+	m.Add("1.0", http.MethodPost, "/users/mfa", AuthorizationRequiredHandler(enrollMFA))
+	m.Add("1.0", http.MethodPost, "/users/mfa/confirm", AuthorizationRequiredHandler(confirmMFA))
+	m.Add("1.0", http.MethodDelete, "/users/mfa", AuthorizationRequiredHandler(disableMFA))
+	m.Add("1.0", http.MethodDelete, "/users/{email}/mfa", AuthorizationRequiredHandler(resetUserMFA))
 	m.Add("1.0", http.MethodDelete, "/users", AuthorizationRequiredHandler(removeUser))
 	m.Add("1.0", http.MethodGet, "/users/api-key", AuthorizationRequiredHandler(showAPIToken))
 	m.Add("1.0", http.MethodPost, "/users/api-key", AuthorizationRequiredHandler(regenerateAPIToken))
@@ -377,6 +408,7 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.4", http.MethodGet, "/teams/{name}", AuthorizationRequiredHandler(teamInfo))
 	m.Add("1.12", http.MethodGet, "/teams/{name}/quota", AuthorizationRequiredHandler(getTeamQuota))
 	m.Add("1.12", http.MethodPut, "/teams/{name}/quota", AuthorizationRequiredHandler(changeTeamQuota))
+	m.Add("1.24", http.MethodGet, "/teams/{name}/dependencies", AuthorizationRequiredHandler(teamDependencies))
 	m.Add("1.17", http.MethodGet, "/teams/{name}/users", AuthorizationRequiredHandler(teamUserList))
 	m.Add("1.17", http.MethodGet, "/teams/{name}/groups", AuthorizationRequiredHandler(teamGroupList))
 
@@ -396,6 +428,7 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", http.MethodPost, "/pools/{name}/team", AuthorizationRequiredHandler(addTeamToPoolHandler))
 	m.Add("1.0", http.MethodDelete, "/pools/{name}/team", AuthorizationRequiredHandler(removeTeamToPoolHandler))
 	m.Add("1.8", http.MethodGet, "/pools/{name}", AuthorizationRequiredHandler(getPoolHandler))
+	m.Add("1.24", http.MethodGet, "/pools/{name}/dependencies", AuthorizationRequiredHandler(poolDependencies))
 
 	m.Add("1.3", http.MethodGet, "/constraints", AuthorizationRequiredHandler(poolConstraintList))
 	m.Add("1.3", http.MethodPut, "/constraints", AuthorizationRequiredHandler(poolConstraintSet))
@@ -407,12 +440,15 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", http.MethodDelete, "/roles/{name}", AuthorizationRequiredHandler(removeRole))
 	m.Add("1.0", http.MethodPost, "/roles/{name}/permissions", AuthorizationRequiredHandler(addPermissions))
 	m.Add("1.0", http.MethodDelete, "/roles/{name}/permissions/{permission}", AuthorizationRequiredHandler(removePermissions))
+	m.Add("1.0", http.MethodPost, "/roles/{name}/conditions", AuthorizationRequiredHandler(setPermissionCondition))
+	m.Add("1.0", http.MethodDelete, "/roles/{name}/conditions/{permission}", AuthorizationRequiredHandler(removePermissionCondition))
 	m.Add("1.0", http.MethodPost, "/roles/{name}/user", AuthorizationRequiredHandler(assignRole))
 	m.Add("1.0", http.MethodDelete, "/roles/{name}/user/{email}", AuthorizationRequiredHandler(dissociateRole))
 	m.Add("1.0", http.MethodGet, "/role/default", AuthorizationRequiredHandler(listDefaultRoles))
 	m.Add("1.0", http.MethodPost, "/role/default", AuthorizationRequiredHandler(addDefaultRole))
 	m.Add("1.0", http.MethodDelete, "/role/default", AuthorizationRequiredHandler(removeDefaultRole))
 	m.Add("1.0", http.MethodGet, "/permissions", AuthorizationRequiredHandler(listPermissions))
+	m.Add("1.0", http.MethodGet, "/permissions/explain", AuthorizationRequiredHandler(explainPermission))
 	m.Add("1.6", http.MethodPost, "/roles/{name}/token", AuthorizationRequiredHandler(assignRoleToToken))
 	m.Add("1.6", http.MethodDelete, "/roles/{name}/token/{token_id}", AuthorizationRequiredHandler(dissociateRoleFromToken))
 	m.Add("1.9", http.MethodPost, "/roles/{name}/group", AuthorizationRequiredHandler(assignRoleToGroup))
@@ -461,6 +497,7 @@ func RunServer(dry bool) http.Handler {
 
 	m.Add("1.7", http.MethodGet, "/brokers", AuthorizationRequiredHandler(serviceBrokerList))
 	m.Add("1.7", http.MethodPost, "/brokers", AuthorizationRequiredHandler(serviceBrokerAdd))
+	m.Add("1.7", http.MethodGet, "/brokers/{broker}", AuthorizationRequiredHandler(serviceBrokerInfo))
 	m.Add("1.7", http.MethodPut, "/brokers/{broker}", AuthorizationRequiredHandler(serviceBrokerUpdate))
 	m.Add("1.7", http.MethodDelete, "/brokers/{broker}", AuthorizationRequiredHandler(serviceBrokerDelete))
 
@@ -474,14 +511,40 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.13", http.MethodGet, "/jobs/{name}", AuthorizationRequiredHandler(jobInfo))
 	m.Add("1.13", http.MethodDelete, "/jobs/{name}", AuthorizationRequiredHandler(deleteJob))
 	m.Add("1.13", http.MethodPut, "/jobs/{name}", AuthorizationRequiredHandler(updateJob))
+	m.Add("1.24", http.MethodGet, "/jobs/{name}/dependencies", AuthorizationRequiredHandler(jobDependencies))
 	m.Add("1.13", http.MethodGet, "/jobs", AuthorizationRequiredHandler(jobList))
 	m.Add("1.16", http.MethodGet, "/jobs/{name}/env", AuthorizationRequiredHandler(getJobEnv))
 	m.Add("1.13", http.MethodPost, "/jobs/{name}/env", AuthorizationRequiredHandler(setJobEnv))
 	m.Add("1.13", http.MethodDelete, "/jobs/{name}/env", AuthorizationRequiredHandler(unsetJobEnv))
 	m.Add("1.13", http.MethodGet, "/jobs/{name}/log", AuthorizationRequiredHandler(jobLog))
+	m.Add("1.13", http.MethodGet, "/jobs/{name}/runs", AuthorizationRequiredHandler(jobRuns))
+	m.Add("1.13", http.MethodGet, "/jobs/{name}/runs/{run}", AuthorizationRequiredHandler(jobRunInfo))
+	m.Add("1.13", http.MethodGet, "/jobs/{name}/runs/{run}/log", AuthorizationRequiredHandler(jobRunLog))
 	m.Add("1.13", http.MethodDelete, "/jobs/{name}/units/{unit}", AuthorizationRequiredHandler(killJob))
+	m.Add("1.13", http.MethodPost, "/jobs/{name}/suspend", AuthorizationRequiredHandler(suspendJob))
+	m.Add("1.13", http.MethodPost, "/jobs/{name}/resume", AuthorizationRequiredHandler(resumeJob))
+	m.Add("1.13", http.MethodPost, "/jobs/{name}/autoscale", AuthorizationRequiredHandler(setJobAutoScale))
+	m.Add("1.13", http.MethodDelete, "/jobs/{name}/autoscale", AuthorizationRequiredHandler(removeJobAutoScale))
 	m.Add("1.23", http.MethodPost, "/jobs/{name}/deploy", AuthorizationRequiredHandler(jobDeploy))
 
+	m.Add("1.13", http.MethodPost, "/job-templates", AuthorizationRequiredHandler(createJobTemplate))
+	m.Add("1.13", http.MethodGet, "/job-templates", AuthorizationRequiredHandler(jobTemplateList))
+	m.Add("1.13", http.MethodGet, "/job-templates/{name}", AuthorizationRequiredHandler(jobTemplateInfo))
+	m.Add("1.13", http.MethodPut, "/job-templates/{name}", AuthorizationRequiredHandler(updateJobTemplate))
+	m.Add("1.13", http.MethodDelete, "/job-templates/{name}", AuthorizationRequiredHandler(deleteJobTemplate))
+	m.Add("1.13", http.MethodPost, "/job-templates/{name}/apply", AuthorizationRequiredHandler(applyJobTemplate))
+
+	m.Add("1.13", http.MethodPost, "/workflows", AuthorizationRequiredHandler(createWorkflow))
+	m.Add("1.13", http.MethodGet, "/workflows", AuthorizationRequiredHandler(workflowList))
+	m.Add("1.13", http.MethodGet, "/workflows/{name}", AuthorizationRequiredHandler(workflowInfo))
+	m.Add("1.13", http.MethodPut, "/workflows/{name}", AuthorizationRequiredHandler(updateWorkflow))
+	m.Add("1.13", http.MethodDelete, "/workflows/{name}", AuthorizationRequiredHandler(deleteWorkflow))
+	m.Add("1.13", http.MethodPost, "/workflows/{name}/run", AuthorizationRequiredHandler(runWorkflow))
+	m.Add("1.13", http.MethodGet, "/workflows/{name}/runs", AuthorizationRequiredHandler(workflowRuns))
+	m.Add("1.13", http.MethodGet, "/workflows/{name}/runs/{run}", AuthorizationRequiredHandler(workflowRunInfo))
+	m.Add("1.13", http.MethodPost, "/workflows/{name}/runs/{run}/cancel", AuthorizationRequiredHandler(cancelWorkflowRun))
+	m.Add("1.13", http.MethodPost, "/workflows/{name}/runs/{run}/rerun", AuthorizationRequiredHandler(rerunWorkflow))
+
 	n := negroni.New()
 	n.Use(negroni.NewRecovery())
 	if c := corsMiddleware(); c != nil {
@@ -597,6 +660,26 @@ func startServer(handler http.Handler) error {
 	if err != nil {
 		return errors.Wrap(err, "unable to initialize old image gc")
 	}
+	err = rolesweeper.Initialize()
+	if err != nil {
+		return errors.Wrap(err, "unable to initialize role expiration sweeper")
+	}
+	err = service.InitializeBrokerOperationPoller()
+	if err != nil {
+		return errors.Wrap(err, "unable to initialize broker operation poller")
+	}
+	err = service.InitializeCredentialsRevoker()
+	if err != nil {
+		return errors.Wrap(err, "unable to initialize credentials revoker")
+	}
+	err = service.InitializeStatusCollector()
+	if err != nil {
+		return errors.Wrap(err, "unable to initialize service instance status collector")
+	}
+	err = service.InitializeHealthProbe()
+	if err != nil {
+		return errors.Wrap(err, "unable to initialize service health probe")
+	}
 	fmt.Println("Checking components status:")
 	results := hc.Check(ctx, "all")
 	for _, result := range results {
@@ -82,19 +82,38 @@ type serviceInput struct {
 	Password  string            `json:"password" form:"password"`
 	Endpoints map[string]string `json:"endpoints" form:"endpoints"`
 	Endpoint  string            `json:"endpoint" form:"endpoint"`
+	Timeout   *int              `json:"timeout" form:"timeout"`
+
+	CircuitBreakerThreshold *int `json:"circuit_breaker_threshold" form:"circuit-breaker-threshold"`
+	CircuitBreakerCooldown  *int `json:"circuit_breaker_cooldown" form:"circuit-breaker-cooldown"`
+}
+
+// applyRequestOptions sets the timeout and circuit breaker of the service,
+// keeping the current values of the options omitted from the input.
+func (i *serviceInput) applyRequestOptions(s *service.Service) {
+	if i.Timeout != nil {
+		s.Timeout = *i.Timeout
+	}
+	if i.CircuitBreakerThreshold != nil {
+		s.CircuitBreaker.Threshold = *i.CircuitBreakerThreshold
+	}
+	if i.CircuitBreakerCooldown != nil {
+		s.CircuitBreaker.CooldownSeconds = *i.CircuitBreakerCooldown
+	}
 }
 
-func parseService(r *http.Request) (service.Service, error) {
+func parseService(r *http.Request) (service.Service, *serviceInput, error) {
 	var s service.Service
 
 	var inputSvc serviceInput
 	err := ParseInput(r, &inputSvc)
 	if err != nil {
-		return s, &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+		return s, nil, &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
 	}
 	s.Name = inputSvc.Name
 	s.Username = inputSvc.Username
 	s.Password = inputSvc.Password
+	inputSvc.applyRequestOptions(&s)
 	if len(inputSvc.Endpoints) != 0 {
 		s.Endpoint = inputSvc.Endpoints
 	} else if inputSvc.Endpoint != "" {
@@ -109,7 +128,7 @@ func parseService(r *http.Request) (service.Service, error) {
 	if team != "" {
 		s.OwnerTeams = []string{team}
 	}
-	return s, nil
+	return s, &inputSvc, nil
 }
 
 // title: service create
@@ -124,7 +143,7 @@ func parseService(r *http.Request) (service.Service, error) {
 //	409: Service already exists
 func serviceCreate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	ctx := r.Context()
-	s, err := parseService(r)
+	s, _, err := parseService(r)
 	if err != nil {
 		return err
 	}
@@ -186,7 +205,7 @@ func serviceCreate(w http.ResponseWriter, r *http.Request, t auth.Token) (err er
 //	403: Forbidden (team is not the owner)
 //	404: Service not found
 func serviceUpdate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
-	d, err := parseService(r)
+	d, input, err := parseService(r)
 	if err != nil {
 		return err
 	}
@@ -219,6 +238,7 @@ func serviceUpdate(w http.ResponseWriter, r *http.Request, t auth.Token) (err er
 	s.Endpoint = d.Endpoint
 	s.Password = d.Password
 	s.Username = d.Username
+	input.applyRequestOptions(&s)
 	if len(d.OwnerTeams) != 0 {
 		s.OwnerTeams = d.OwnerTeams
 	}
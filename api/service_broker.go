@@ -41,6 +41,31 @@ func serviceBrokerList(w http.ResponseWriter, r *http.Request, t auth.Token) err
 	})
 }
 
+// title: service broker info
+// path: /brokers/{broker}
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	401: Unauthorized
+//	404: Not Found
+func serviceBrokerInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	if !permission.Check(ctx, t, permission.PermServiceBrokerRead) {
+		return permission.ErrUnauthorized
+	}
+	broker, err := servicemanager.ServiceBroker.Find(ctx, r.URL.Query().Get(":broker"))
+	if err == service.ErrServiceBrokerNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(broker)
+}
+
 // title: Add service broker
 // path: /brokers
 // method: POST
@@ -54,10 +79,11 @@ func serviceBrokerAdd(w http.ResponseWriter, r *http.Request, t auth.Token) erro
 	if !permission.Check(ctx, t, permission.PermServiceBrokerCreate) {
 		return permission.ErrUnauthorized
 	}
-	broker, err := decodeServiceBroker(r)
+	input, err := decodeServiceBroker(r)
 	if err != nil {
 		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
 	}
+	broker := input.broker()
 	evt, err := event.New(ctx, &event.Opts{
 		Target:     eventTypes.Target{Type: eventTypes.TargetTypeServiceBroker, Value: broker.Name},
 		Kind:       permission.PermServiceBrokerCreate,
@@ -70,7 +96,7 @@ func serviceBrokerAdd(w http.ResponseWriter, r *http.Request, t auth.Token) erro
 		return err
 	}
 	defer func() { evt.Done(ctx, err) }()
-	if err = servicemanager.ServiceBroker.Create(ctx, *broker); err != nil {
+	if err = servicemanager.ServiceBroker.Create(ctx, broker); err != nil {
 		if err == service.ErrServiceBrokerAlreadyExists {
 			return &errors.HTTP{Code: http.StatusConflict, Message: "Broker already exists."}
 		}
@@ -97,10 +123,11 @@ func serviceBrokerUpdate(w http.ResponseWriter, r *http.Request, t auth.Token) e
 	if brokerName == "" {
 		return &errors.HTTP{Code: http.StatusBadRequest, Message: "Empty broker name."}
 	}
-	broker, err := decodeServiceBroker(r)
+	input, err := decodeServiceBroker(r)
 	if err != nil {
 		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
 	}
+	broker := input.broker()
 	evt, err := event.New(ctx, &event.Opts{
 		Target:     eventTypes.Target{Type: eventTypes.TargetTypeServiceBroker, Value: broker.Name},
 		Kind:       permission.PermServiceBrokerUpdate,
@@ -113,7 +140,19 @@ func serviceBrokerUpdate(w http.ResponseWriter, r *http.Request, t auth.Token) e
 		return err
 	}
 	defer func() { evt.Done(ctx, err) }()
-	if err = servicemanager.ServiceBroker.Update(ctx, brokerName, *broker); err == service.ErrServiceBrokerNotFound {
+	if input.PlanVisibility == nil {
+		var current service.Broker
+		current, err = servicemanager.ServiceBroker.Find(ctx, brokerName)
+		if err == service.ErrServiceBrokerNotFound {
+			w.WriteHeader(http.StatusNotFound)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		broker.PlanVisibility = current.PlanVisibility
+	}
+	if err = servicemanager.ServiceBroker.Update(ctx, brokerName, broker); err == service.ErrServiceBrokerNotFound {
 		w.WriteHeader(http.StatusNotFound)
 	}
 	return err
@@ -154,10 +193,28 @@ func serviceBrokerDelete(w http.ResponseWriter, r *http.Request, t auth.Token) e
 	return err
 }
 
-func decodeServiceBroker(request *http.Request) (*service.Broker, error) {
-	var broker service.Broker
-	if err := ParseInput(request, &broker); err != nil {
+// inputServiceBroker holds PlanVisibility as a pointer, so an omitted list,
+// which keeps the current rules on update, is told apart from an empty
+// one, which removes them.
+type inputServiceBroker struct {
+	Name           string
+	URL            string
+	Config         service.BrokerConfig
+	PlanVisibility *[]service.PlanVisibility
+}
+
+func (i *inputServiceBroker) broker() service.Broker {
+	broker := service.Broker{Name: i.Name, URL: i.URL, Config: i.Config}
+	if i.PlanVisibility != nil {
+		broker.PlanVisibility = *i.PlanVisibility
+	}
+	return broker
+}
+
+func decodeServiceBroker(request *http.Request) (*inputServiceBroker, error) {
+	var input inputServiceBroker
+	if err := ParseInput(request, &input); err != nil {
 		return nil, fmt.Errorf("unable to parse broker: %v", err)
 	}
-	return &broker, nil
+	return &input, nil
 }
@@ -33,6 +33,36 @@ func (s *S) TestServiceBrokerList(c *check.C) {
 	c.Assert(response["brokers"], check.DeepEquals, brokers)
 }
 
+func (s *S) TestServiceBrokerInfo(c *check.C) {
+	broker := service.Broker{
+		Name:           "broker-1",
+		URL:            "http://localhost:8080",
+		PlanVisibility: []service.PlanVisibility{{Service: "mysql", Plan: "large", Teams: []string{"team1"}}},
+	}
+	s.mockService.ServiceBroker.OnFind = func(name string) (service.Broker, error) {
+		if name != broker.Name {
+			return service.Broker{}, service.ErrServiceBrokerNotFound
+		}
+		return broker, nil
+	}
+	request, err := http.NewRequest("GET", "/1.7/brokers/broker-1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var response service.Broker
+	err = json.NewDecoder(recorder.Body).Decode(&response)
+	c.Assert(err, check.IsNil)
+	c.Assert(response, check.DeepEquals, broker)
+	request, err = http.NewRequest("GET", "/1.7/brokers/unknown", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
 func (s *S) TestServiceBrokerListEmpty(c *check.C) {
 	request, err := http.NewRequest("GET", "/1.7/brokers", nil)
 	c.Assert(err, check.IsNil)
@@ -204,6 +234,37 @@ func (s *S) TestServiceBrokerUpdateWithCache(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, http.StatusOK)
 }
 
+func (s *S) TestServiceBrokerUpdatePlanVisibility(c *check.C) {
+	visibility := []service.PlanVisibility{{Service: "mysql", Plan: "large", Teams: []string{"team1"}}}
+	s.mockService.ServiceBroker.OnFind = func(name string) (service.Broker, error) {
+		return service.Broker{Name: name, PlanVisibility: visibility}, nil
+	}
+	var updated service.Broker
+	s.mockService.ServiceBroker.OnUpdate = func(name string, b service.Broker) error {
+		updated = b
+		return nil
+	}
+	tests := []struct {
+		body     string
+		expected []service.PlanVisibility
+	}{
+		{body: `{"Name":"broker-name"}`, expected: visibility},
+		{body: `{"Name":"broker-name","PlanVisibility":null}`, expected: visibility},
+		{body: `{"Name":"broker-name","PlanVisibility":[]}`, expected: []service.PlanVisibility{}},
+		{body: `{"Name":"broker-name","PlanVisibility":[{"Plan":"small","Pools":["pool1"]}]}`, expected: []service.PlanVisibility{{Plan: "small", Pools: []string{"pool1"}}}},
+	}
+	for _, tt := range tests {
+		request, err := http.NewRequest("PUT", "/1.7/brokers/broker-name", strings.NewReader(tt.body))
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+		request.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, request)
+		c.Assert(recorder.Code, check.Equals, http.StatusOK)
+		c.Assert(updated.PlanVisibility, check.DeepEquals, tt.expected, check.Commentf("body: %s", tt.body))
+	}
+}
+
 func (s *S) TestServiceBrokerUpdateNotFound(c *check.C) {
 	broker := service.Broker{Name: "not-found"}
 	s.mockService.ServiceBroker.OnUpdate = func(name string, b service.Broker) error {
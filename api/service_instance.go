@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -68,6 +69,12 @@ func createServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 	}
 	tags, _ := InputValues(r, "tag")
 	instance.Tags = append(instance.Tags, tags...) // for compatibility
+	sharedPools, _ := InputValues(r, "shared-pool")
+	instance.SharedPools = append(instance.SharedPools, nonEmptyValues(sharedPools)...)
+	err = checkSharedPoolsAccess(ctx, t, nil, instance.SharedPools)
+	if err != nil {
+		return err
+	}
 	var teamOwner string
 	if instance.TeamOwner == "" {
 		teamOwner, err = permission.TeamForPermission(ctx, t, permission.PermServiceInstanceCreate)
@@ -123,6 +130,12 @@ func createServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 			Message: fmt.Sprintf("Service %q is not available in pool %q", srv.Name, instance.Pool),
 		}
 	}
+	if err == service.ErrRegularServiceInstanceCannotBeShared {
+		return &tsuruErrors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+	}
 	if err == service.ErrInstanceNameAlreadyExists {
 		return &tsuruErrors.HTTP{
 			Code:    http.StatusConflict,
@@ -197,6 +210,17 @@ func updateServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 		wantedPerms = append(wantedPerms, permission.PermServiceInstanceUpdateParameters)
 		si.Parameters = updateData.Parameters
 	}
+	if sharedPools, ok := InputValues(r, "shared-pool"); ok {
+		sharedPools = nonEmptyValues(sharedPools)
+		if len(sharedPools)+len(si.SharedPools) > 0 && !reflect.DeepEqual(si.SharedPools, sharedPools) {
+			err = checkSharedPoolsAccess(ctx, t, si.SharedPools, sharedPools)
+			if err != nil {
+				return err
+			}
+			wantedPerms = append(wantedPerms, permission.PermServiceInstanceUpdateSharedPools)
+			si.SharedPools = sharedPools
+		}
+	}
 	if len(wantedPerms) == 0 {
 		return &tsuruErrors.HTTP{
 			Code:    http.StatusBadRequest,
@@ -235,7 +259,14 @@ func updateServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 	}
 	defer func() { evt.Done(ctx, err) }()
 	requestID := requestIDHeader(r)
-	return si.Update(ctx, srv, *si, evt, requestID)
+	err = si.Update(ctx, srv, *si, evt, requestID)
+	if err == service.ErrRegularServiceInstanceCannotBeShared {
+		return &tsuruErrors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+	}
+	return err
 }
 
 // title: remove service instance
@@ -458,6 +489,76 @@ func serviceInstanceStatus(w http.ResponseWriter, r *http.Request, t auth.Token)
 	return err
 }
 
+// title: service instance status history
+// path: /services/{service}/instances/{instance}/status/history
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	204: No content
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Service instance not found
+func serviceInstanceStatusHistory(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	instanceName := r.URL.Query().Get(":instance")
+	serviceName := r.URL.Query().Get(":service")
+	serviceInstance, err := getServiceInstanceOrError(ctx, serviceName, instanceName)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(ctx, t, permission.PermServiceInstanceReadStatus,
+		contextsForServiceInstance(serviceInstance, serviceName)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	var filter service.StatusHistoryFilter
+	for param, dst := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		raw := r.URL.Query().Get(param)
+		if raw == "" {
+			continue
+		}
+		*dst, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return &tsuruErrors.HTTP{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("invalid %s value %q, must be in RFC3339 format", param, raw),
+			}
+		}
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil || filter.Limit > service.MaxStatusHistoryLimit {
+			return &tsuruErrors.HTTP{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("invalid limit value %q, must be a number up to %d", limit, service.MaxStatusHistoryLimit),
+			}
+		}
+	}
+	history, err := service.StatusHistory(ctx, serviceInstance, filter)
+	if err != nil {
+		return err
+	}
+	// info comes from the service API and may hold instance details only
+	// visible to who can read the instance
+	canRead := permission.Check(ctx, t, permission.PermServiceInstanceRead,
+		contextsForServiceInstance(serviceInstance, serviceName)...,
+	)
+	if !canRead {
+		for i := range history {
+			history[i].Info = nil
+		}
+	}
+	if len(history) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(history)
+}
+
 type serviceInstanceInfo struct {
 	Apps            []string
 	Jobs            []string
@@ -646,10 +747,52 @@ func servicePlans(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if err != nil {
 		return err
 	}
+	global, teams := teamsForToken(ctx, t)
+	plans, err = service.VisiblePlans(ctx, s, plans, service.PlanAudience{
+		Global: global,
+		Teams:  teams,
+		Pool:   r.URL.Query().Get("pool"),
+	})
+	if err != nil {
+		return err
+	}
+	if withSchemas, _ := strconv.ParseBool(r.URL.Query().Get("schemas")); withSchemas {
+		err = service.LoadPlanSchemas(ctx, s, pool, plans, requestID)
+		if err != nil {
+			return err
+		}
+	}
 	w.Header().Set("Content-Type", "application/json")
 	return json.NewEncoder(w).Encode(plans)
 }
 
+// checkSharedPoolsAccess ensures the token may use every pool being added to
+// the shared pools of a service instance.
+func checkSharedPoolsAccess(ctx stdContext.Context, t auth.Token, current, requested []string) error {
+	var added []string
+	for _, p := range requested {
+		if !slices.Contains(current, p) {
+			added = append(added, p)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	allowedPools, err := possiblePoolsForService(ctx, t)
+	if err != nil {
+		return err
+	}
+	for _, p := range added {
+		if !slices.Contains(allowedPools, p) {
+			return &tsuruErrors.HTTP{
+				Code:    http.StatusForbidden,
+				Message: fmt.Sprintf("You don't have access to pool %q", p),
+			}
+		}
+	}
+	return nil
+}
+
 func possiblePoolsForService(ctx stdContext.Context, t auth.Token) ([]string, error) {
 	global, teams := teamsForToken(ctx, t)
 	var pools []pool.Pool
@@ -860,6 +1003,197 @@ func serviceInstanceRevokeTeam(w http.ResponseWriter, r *http.Request, t auth.To
 	return serviceInstance.Revoke(ctx, teamName)
 }
 
+// title: rotate service instance credentials
+// path: /services/{service}/instances/{instance}/rotate
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/x-json-stream
+// responses:
+//
+//	200: Credentials rotated
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Service instance not found
+//	409: Service instance locked
+func serviceInstanceRotateCredentials(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	instanceName := r.URL.Query().Get(":instance")
+	serviceName := r.URL.Query().Get(":service")
+	serviceInstance, err := getServiceInstanceOrError(ctx, serviceName, instanceName)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(ctx, t, permission.PermServiceInstanceUpdateCredentials,
+		contextsForServiceInstance(serviceInstance, serviceName)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	gracePeriod := service.RotationGracePeriod()
+	if value := InputValue(r, "grace-period"); value != "" {
+		seconds, parseErr := strconv.Atoi(value)
+		if parseErr != nil || seconds < 0 {
+			return &tsuruErrors.HTTP{
+				Code:    http.StatusBadRequest,
+				Message: "grace-period must be a non-negative number of seconds",
+			}
+		}
+		gracePeriod = time.Duration(seconds) * time.Second
+	}
+	if gracePeriod > service.MaxRotationGracePeriod() {
+		return &tsuruErrors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("grace-period must not be longer than %d seconds", int(service.MaxRotationGracePeriod().Seconds())),
+		}
+	}
+	var extraTargets []eventTypes.ExtraTarget
+	for _, appName := range serviceInstance.Apps {
+		extraTargets = append(extraTargets, eventTypes.ExtraTarget{Target: appTarget(appName)})
+	}
+	for _, jobName := range serviceInstance.Jobs {
+		extraTargets = append(extraTargets, eventTypes.ExtraTarget{Target: jobTarget(jobName)})
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:       serviceInstanceTarget(serviceName, instanceName),
+		ExtraTargets: extraTargets,
+		Kind:         permission.PermServiceInstanceUpdateCredentials,
+		Owner:        t,
+		RemoteAddr:   r.RemoteAddr,
+		CustomData:   event.FormToCustomData(InputFields(r)),
+		Allowed: event.Allowed(permission.PermServiceInstanceReadEvents,
+			contextsForServiceInstance(serviceInstance, serviceName)...),
+	})
+	if _, isLocked := err.(event.ErrEventLocked); isLocked {
+		return &tsuruErrors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	evt.SetLogWriter(writer)
+	err = serviceInstance.RotateCredentials(ctx, service.RotateCredentialsArgs{
+		GracePeriod: gracePeriod,
+		Writer:      evt,
+		Event:       evt,
+		RequestID:   requestIDHeader(r),
+	})
+	if err == service.ErrRotationNotSupported || err == service.ErrInstanceProvisionInProgress || err == service.ErrInstanceNotReady {
+		return &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(evt, "credentials of service instance %q successfully rotated\n", instanceName)
+	return nil
+}
+
+// title: service instance actions
+// path: /services/{service}/instances/{instance}/actions
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	204: No content
+//	401: Unauthorized
+//	404: Service instance not found
+func serviceInstanceActions(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	instanceName := r.URL.Query().Get(":instance")
+	serviceName := r.URL.Query().Get(":service")
+	serviceInstance, err := getServiceInstanceOrError(ctx, serviceName, instanceName)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(ctx, t, permission.PermServiceInstanceReadActions,
+		contextsForServiceInstance(serviceInstance, serviceName)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	actions, err := serviceInstance.Actions(ctx, requestIDHeader(r))
+	if err != nil {
+		return err
+	}
+	if len(actions) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(actions)
+}
+
+// title: execute service instance action
+// path: /services/{service}/instances/{instance}/actions/{action}
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/x-json-stream
+// responses:
+//
+//	200: Action executed
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Service instance or action not found
+func serviceInstanceExecuteAction(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	instanceName := r.URL.Query().Get(":instance")
+	serviceName := r.URL.Query().Get(":service")
+	actionName := r.URL.Query().Get(":action")
+	var input struct {
+		Parameters map[string]interface{}
+	}
+	err = ParseInput(r, &input)
+	if err != nil {
+		return err
+	}
+	serviceInstance, err := getServiceInstanceOrError(ctx, serviceName, instanceName)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(ctx, t, permission.PermServiceInstanceUpdateActions,
+		contextsForServiceInstance(serviceInstance, serviceName)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     serviceInstanceTarget(serviceName, instanceName),
+		Kind:       permission.PermServiceInstanceUpdateActions,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed: event.Allowed(permission.PermServiceInstanceReadEvents,
+			contextsForServiceInstance(serviceInstance, serviceName)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	evt.SetLogWriter(writer)
+	err = serviceInstance.ExecuteAction(ctx, service.ExecuteActionArgs{
+		Action:     actionName,
+		Parameters: input.Parameters,
+		Writer:     evt,
+		Event:      evt,
+		RequestID:  requestIDHeader(r),
+	})
+	if err == service.ErrInstanceActionNotFound {
+		return &tsuruErrors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if err == service.ErrInstanceProvisionInProgress {
+		return &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	return err
+}
+
 func contextsForServiceInstance(si *service.ServiceInstance, serviceName string) []permTypes.PermissionContext {
 	permissionValue := serviceIntancePermName(serviceName, si.Name)
 	return append(permission.Contexts(permTypes.CtxTeam, si.Teams),
@@ -884,6 +1218,16 @@ func sortedServiceNames(services map[string]*service.ServiceModel) []string {
 	return serviceNames
 }
 
+func nonEmptyValues(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
 func requestIDHeader(r *http.Request) string {
 	requestIDHeader, _ := config.GetString("request-id-header")
 	return context.GetRequestID(r, requestIDHeader)
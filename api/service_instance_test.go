@@ -17,6 +17,7 @@ import (
 	"sort"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/api/context"
@@ -24,6 +25,7 @@ import (
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/db/storagev2"
 	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/event/eventtest"
 	"github.com/tsuru/tsuru/io"
 	"github.com/tsuru/tsuru/permission"
@@ -575,6 +577,34 @@ func (s *ServiceInstanceSuite) TestCreateServiceInstanceWithTags(c *check.C) {
 	})
 }
 
+func (s *ServiceInstanceSuite) TestCreateServiceInstanceWithInvalidParameters(c *check.C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/resources/plans/small/schemas" {
+			w.Write([]byte(`{"service_instance": {"create": {"parameters": {"type": "object", "properties": {"size": {"type": "integer"}}}}}}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+	srvc := service.Service{Name: "pgsql", Endpoint: map[string]string{"production": ts.URL}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := service.Create(stdContext.TODO(), srvc)
+	c.Assert(err, check.IsNil)
+	params := map[string]interface{}{
+		"name":            "brainsql",
+		"service_name":    "pgsql",
+		"plan":            "small",
+		"owner":           s.team.Name,
+		"parameters.size": "huge",
+	}
+	recorder, request := makeRequestToCreateServiceInstance(params, c)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, `invalid parameters for plan "small": size must be of type integer: "string"`+"\n")
+	_, err = service.GetServiceInstance(stdContext.TODO(), "pgsql", "brainsql")
+	c.Assert(err, check.Equals, service.ErrServiceInstanceNotFound)
+}
+
 func (s *ServiceInstanceSuite) TestCreateServiceInstanceWithTagsAndTagValidator(c *check.C) {
 	previousTagService := servicemanager.Tag
 	defer func() {
@@ -1787,6 +1817,94 @@ func (s *ServiceInstanceSuite) TestServiceInstanceStatusWithSameInstanceName(c *
 	c.Assert(recorder.Body.String(), check.Equals, "Service instance \"my_nosql\" is down")
 }
 
+func (s *ServiceInstanceSuite) TestServiceInstanceStatusHistory(c *check.C) {
+	si := service.ServiceInstance{Name: "my_nosql", ServiceName: "mongodb", Teams: []string{s.team.Name}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	statusCollection, err := storagev2.ServiceInstanceStatusCollection()
+	c.Assert(err, check.IsNil)
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i, status := range []string{"up", "down"} {
+		_, err = statusCollection.InsertOne(stdContext.TODO(), service.StatusSnapshot{
+			ServiceName:  "mongodb",
+			InstanceName: "my_nosql",
+			Status:       status,
+			Timestamp:    base.Add(time.Duration(i) * time.Minute),
+			ExpireAt:     time.Now().Add(time.Hour),
+		})
+		c.Assert(err, check.IsNil)
+	}
+	request, err := http.NewRequest("GET", "/services/mongodb/instances/my_nosql/status/history?since=2026-10-01T12:00:30Z", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var history []service.StatusSnapshot
+	err = json.Unmarshal(recorder.Body.Bytes(), &history)
+	c.Assert(err, check.IsNil)
+	c.Assert(history, check.HasLen, 1)
+	c.Assert(history[0].Status, check.Equals, "down")
+	request, err = http.NewRequest("GET", "/services/mongodb/instances/my_nosql/status/history?since=yesterday", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+}
+
+func (s *ServiceInstanceSuite) TestServiceInstanceStatusHistoryWithoutReadPermissionHidesInfo(c *check.C) {
+	si := service.ServiceInstance{Name: "my_nosql", ServiceName: "mongodb", Teams: []string{s.team.Name}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	statusCollection, err := storagev2.ServiceInstanceStatusCollection()
+	c.Assert(err, check.IsNil)
+	_, err = statusCollection.InsertOne(stdContext.TODO(), service.StatusSnapshot{
+		ServiceName:  "mongodb",
+		InstanceName: "my_nosql",
+		Status:       "up",
+		Info:         map[string]string{"address": "10.0.0.1:27017"},
+		Timestamp:    time.Now().UTC(),
+		ExpireAt:     time.Now().Add(time.Hour),
+	})
+	c.Assert(err, check.IsNil)
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "statusreader", permTypes.Permission{
+		Scheme:  permission.PermServiceInstanceReadStatus,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	request, err := http.NewRequest("GET", "/services/mongodb/instances/my_nosql/status/history", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var history []service.StatusSnapshot
+	err = json.Unmarshal(recorder.Body.Bytes(), &history)
+	c.Assert(err, check.IsNil)
+	c.Assert(history, check.HasLen, 1)
+	c.Assert(history[0].Status, check.Equals, "up")
+	c.Assert(history[0].Info, check.IsNil)
+}
+
+func (s *ServiceInstanceSuite) TestServiceInstanceStatusHistoryLimitTooHigh(c *check.C) {
+	si := service.ServiceInstance{Name: "my_nosql", ServiceName: "mongodb", Teams: []string{s.team.Name}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/services/mongodb/instances/my_nosql/status/history?limit=1001", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "invalid limit value \"1001\", must be a number up to 1000\n")
+}
+
 func (s *ServiceInstanceSuite) TestServiceInstanceStatusShouldReturnErrorWhenServiceInstanceNotExists(c *check.C) {
 	recorder, request := makeRequestToServiceInstanceStatus("service", "inexistent-instance", c)
 	err := serviceInstanceStatus(recorder, request, s.token)
@@ -2295,6 +2413,45 @@ func (s *ServiceInstanceSuite) TestServicePlans(c *check.C) {
 	c.Assert(plans, check.DeepEquals, expected)
 }
 
+func (s *ServiceInstanceSuite) TestServicePlansWithSchemas(c *check.C) {
+	var schemaRequests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/resources/plans":
+			w.Write([]byte(`[{"name": "small", "description": "small plan"}]`))
+		case "/resources/plans/small/schemas":
+			atomic.AddInt32(&schemaRequests, 1)
+			w.Write([]byte(`{"service_instance": {"create": {"parameters": {"type": "object"}}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	srvc := service.Service{Name: "mysqlplan", Endpoint: map[string]string{"production": ts.URL}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := service.Create(stdContext.TODO(), srvc)
+	c.Assert(err, check.IsNil)
+	for _, path := range []string{"/services/mysqlplan/plans", "/services/mysqlplan/plans?schemas=true"} {
+		request, err := http.NewRequest("GET", path, nil)
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Authorization", "b "+s.token.GetValue())
+		recorder := httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, request)
+		c.Assert(recorder.Code, check.Equals, http.StatusOK)
+		var plans []service.Plan
+		err = json.Unmarshal(recorder.Body.Bytes(), &plans)
+		c.Assert(err, check.IsNil)
+		c.Assert(plans, check.HasLen, 1)
+		if strings.HasSuffix(path, "schemas=true") {
+			c.Assert(plans[0].Schemas, check.NotNil)
+			c.Assert(plans[0].Schemas.ServiceInstance.Create.Parameters, check.DeepEquals, map[string]interface{}{"type": "object"})
+		} else {
+			c.Assert(plans[0].Schemas, check.IsNil)
+			c.Assert(atomic.LoadInt32(&schemaRequests), check.Equals, int32(0))
+		}
+	}
+	c.Assert(atomic.LoadInt32(&schemaRequests), check.Equals, int32(1))
+}
+
 func (s *ServiceInstanceSuite) TestServicePlansWithMissingPool(c *check.C) {
 	for _, poolName := range []string{"test1", "test2"} {
 		err := pool.SetPoolConstraint(stdContext.TODO(), &pool.PoolConstraint{PoolExpr: poolName, Field: pool.ConstraintTypeTeam, Values: []string{"tsuruteam"}, Blacklist: false})
@@ -2415,6 +2572,35 @@ func (s *ServiceInstanceSuite) TestBrokeredServicePlans(c *check.C) {
 	c.Assert(plans, check.DeepEquals, expected)
 }
 
+func (s *ServiceInstanceSuite) TestBrokeredServicePlansVisibility(c *check.C) {
+	s.mockService.ServiceBroker.OnFind = func(broker string) (serviceTypes.Broker, error) {
+		return serviceTypes.Broker{Name: broker, PlanVisibility: []serviceTypes.PlanVisibility{
+			{Service: "s3", Plan: "small", Teams: []string{"other-team"}},
+			{Service: "s3", Plan: "ignite", Teams: []string{s.team.Name}},
+		}}, nil
+	}
+	s.mockService.ServiceBrokerCatalogCache.OnLoad = func(broker string) (*serviceTypes.BrokerCatalog, error) {
+		return &serviceTypes.BrokerCatalog{
+			Services: []serviceTypes.BrokerService{
+				{Name: "s3", Plans: []serviceTypes.BrokerPlan{
+					{Name: "ignite", Description: "some value"},
+					{Name: "small", Description: "no space left for you"},
+				}},
+			},
+		}, nil
+	}
+	request, err := http.NewRequest("GET", "/services/aws::s3/plans", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var plans []service.Plan
+	err = json.Unmarshal(recorder.Body.Bytes(), &plans)
+	c.Assert(err, check.IsNil)
+	c.Assert(plans, check.DeepEquals, []service.Plan{{Name: "ignite", Description: "some value"}})
+}
+
 func (s *ServiceInstanceSuite) TestServiceInstanceProxy(c *check.C) {
 	var proxyedRequest *http.Request
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -2924,3 +3110,291 @@ func (s *ServiceInstanceSuite) TestGrantRevokeServiceToTeamWithManyInstanceName(
 	c.Assert(err, check.IsNil)
 	c.Assert(sinst.Teams, check.DeepEquals, []string{s.team.Name})
 }
+
+func (s *ServiceInstanceSuite) TestServiceInstanceRotateCredentials(c *check.C) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`{"DATABASE_PASSWORD":"new-secret"}`))
+	}))
+	defer ts.Close()
+	se := service.Service{Name: "go", Endpoint: map[string]string{"production": ts.URL}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := service.Create(stdContext.TODO(), se)
+	c.Assert(err, check.IsNil)
+	si := service.ServiceInstance{Name: "si-test", ServiceName: "go", Teams: []string{s.team.Name}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader("grace-period=0")
+	request, err := http.NewRequest("POST", "/services/go/instances/si-test/rotate?:service=go&:instance=si-test", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	err = serviceInstanceRotateCredentials(recorder, request, s.token)
+	c.Assert(err, check.IsNil)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/x-json-stream")
+	c.Assert(recorder.Body.String(), check.Matches, `(?s).*credentials of service instance \\"si-test\\" successfully rotated.*`)
+	c.Assert(paths, check.DeepEquals, []string{
+		"POST /resources/si-test/credentials",
+		"DELETE /resources/si-test/credentials/previous",
+	})
+	c.Assert(eventtest.EventDesc{
+		Target: serviceInstanceTarget("go", "si-test"),
+		Owner:  s.token.GetUserName(),
+		Kind:   "service-instance.update.credentials",
+		StartCustomData: []map[string]interface{}{
+			{"name": "grace-period", "value": "0"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *ServiceInstanceSuite) TestServiceInstanceRotateCredentialsInvalidGracePeriod(c *check.C) {
+	se := service.Service{Name: "go", Endpoint: map[string]string{"production": s.ts.URL}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := service.Create(stdContext.TODO(), se)
+	c.Assert(err, check.IsNil)
+	si := service.ServiceInstance{Name: "si-test", ServiceName: "go", Teams: []string{s.team.Name}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader("grace-period=soon")
+	request, err := http.NewRequest("POST", "/services/go/instances/si-test/rotate?:service=go&:instance=si-test", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	err = serviceInstanceRotateCredentials(recorder, request, s.token)
+	c.Assert(err, check.DeepEquals, &errors.HTTP{
+		Code:    http.StatusBadRequest,
+		Message: "grace-period must be a non-negative number of seconds",
+	})
+}
+
+func (s *ServiceInstanceSuite) TestServiceInstanceRotateCredentialsGracePeriodTooLong(c *check.C) {
+	config.Set("service:credentials-rotation-max-grace-period", 3600)
+	defer config.Unset("service:credentials-rotation-max-grace-period")
+	se := service.Service{Name: "go", Endpoint: map[string]string{"production": s.ts.URL}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := service.Create(stdContext.TODO(), se)
+	c.Assert(err, check.IsNil)
+	si := service.ServiceInstance{Name: "si-test", ServiceName: "go", Teams: []string{s.team.Name}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader("grace-period=3601")
+	request, err := http.NewRequest("POST", "/services/go/instances/si-test/rotate?:service=go&:instance=si-test", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	err = serviceInstanceRotateCredentials(recorder, request, s.token)
+	c.Assert(err, check.DeepEquals, &errors.HTTP{
+		Code:    http.StatusBadRequest,
+		Message: "grace-period must not be longer than 3600 seconds",
+	})
+}
+
+func (s *ServiceInstanceSuite) TestServiceInstanceRotateCredentialsInstanceNotReady(c *check.C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+	}))
+	defer ts.Close()
+	se := service.Service{Name: "go", Endpoint: map[string]string{"production": ts.URL}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := service.Create(stdContext.TODO(), se)
+	c.Assert(err, check.IsNil)
+	si := service.ServiceInstance{Name: "si-test", ServiceName: "go", Teams: []string{s.team.Name}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/services/go/instances/si-test/rotate?:service=go&:instance=si-test", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = serviceInstanceRotateCredentials(recorder, request, s.token)
+	c.Assert(err, check.DeepEquals, &errors.HTTP{
+		Code:    http.StatusBadRequest,
+		Message: service.ErrInstanceNotReady.Error(),
+	})
+}
+
+func (s *ServiceInstanceSuite) TestServiceInstanceRotateCredentialsNotSupported(c *check.C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+	se := service.Service{Name: "go", Endpoint: map[string]string{"production": ts.URL}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := service.Create(stdContext.TODO(), se)
+	c.Assert(err, check.IsNil)
+	si := service.ServiceInstance{Name: "si-test", ServiceName: "go", Teams: []string{s.team.Name}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/services/go/instances/si-test/rotate?:service=go&:instance=si-test", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = serviceInstanceRotateCredentials(recorder, request, s.token)
+	c.Assert(err, check.DeepEquals, &errors.HTTP{
+		Code:    http.StatusBadRequest,
+		Message: service.ErrRotationNotSupported.Error(),
+	})
+}
+
+func (s *ServiceInstanceSuite) TestServiceInstanceRotateCredentialsLocked(c *check.C) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+	}))
+	defer ts.Close()
+	se := service.Service{Name: "go", Endpoint: map[string]string{"production": ts.URL}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := service.Create(stdContext.TODO(), se)
+	c.Assert(err, check.IsNil)
+	si := service.ServiceInstance{Name: "si-test", ServiceName: "go", Teams: []string{s.team.Name}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	evt, err := event.New(stdContext.TODO(), &event.Opts{
+		Target:  serviceInstanceTarget("go", "si-test"),
+		Kind:    permission.PermServiceInstanceUpdateCredentials,
+		Owner:   s.token,
+		Allowed: event.Allowed(permission.PermServiceInstanceReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	defer evt.Done(stdContext.TODO(), nil)
+	request, err := http.NewRequest("POST", "/services/go/instances/si-test/rotate?:service=go&:instance=si-test", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = serviceInstanceRotateCredentials(recorder, request, s.token)
+	c.Assert(err, check.FitsTypeOf, &errors.HTTP{})
+	c.Assert(err.(*errors.HTTP).Code, check.Equals, http.StatusConflict)
+	c.Assert(paths, check.HasLen, 0)
+}
+
+func (s *ServiceInstanceSuite) TestServiceInstanceRotateCredentialsWithoutPermission(c *check.C) {
+	se := service.Service{Name: "go", Endpoint: map[string]string{"production": s.ts.URL}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := service.Create(stdContext.TODO(), se)
+	c.Assert(err, check.IsNil)
+	si := service.ServiceInstance{Name: "si-test", ServiceName: "go", Teams: []string{"other-team"}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/services/go/instances/si-test/rotate?:service=go&:instance=si-test", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = serviceInstanceRotateCredentials(recorder, request, s.token)
+	c.Assert(err, check.Equals, permission.ErrUnauthorized)
+}
+
+func (s *ServiceInstanceSuite) TestServiceInstanceActions(c *check.C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Assert(r.URL.Path, check.Equals, "/resources/si-test/actions")
+		w.Write([]byte(`[{"name": "snapshot"}, {"name": "backup", "description": "backup to a bucket"}]`))
+	}))
+	defer ts.Close()
+	se := service.Service{Name: "go", Endpoint: map[string]string{"production": ts.URL}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := service.Create(stdContext.TODO(), se)
+	c.Assert(err, check.IsNil)
+	si := service.ServiceInstance{Name: "si-test", ServiceName: "go", Teams: []string{s.team.Name}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/services/go/instances/si-test/actions?:service=go&:instance=si-test", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = serviceInstanceActions(recorder, request, s.token)
+	c.Assert(err, check.IsNil)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var actions []service.InstanceAction
+	err = json.Unmarshal(recorder.Body.Bytes(), &actions)
+	c.Assert(err, check.IsNil)
+	c.Assert(actions, check.DeepEquals, []service.InstanceAction{
+		{Name: "backup", Description: "backup to a bucket"},
+		{Name: "snapshot"},
+	})
+}
+
+func (s *ServiceInstanceSuite) TestServiceInstanceActionsWithoutPermission(c *check.C) {
+	se := service.Service{Name: "go", Endpoint: map[string]string{"production": s.ts.URL}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := service.Create(stdContext.TODO(), se)
+	c.Assert(err, check.IsNil)
+	si := service.ServiceInstance{Name: "si-test", ServiceName: "go", Teams: []string{"other-team"}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/services/go/instances/si-test/actions?:service=go&:instance=si-test", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = serviceInstanceActions(recorder, request, s.token)
+	c.Assert(err, check.Equals, permission.ErrUnauthorized)
+}
+
+func (s *ServiceInstanceSuite) TestServiceInstanceExecuteAction(c *check.C) {
+	var bucket string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /resources/si-test/actions":
+			w.Write([]byte(`[{"name": "backup"}]`))
+		case "POST /resources/si-test/actions/backup":
+			bucket = r.FormValue("parameters.bucket")
+			w.Write([]byte("backup finished\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	se := service.Service{Name: "go", Endpoint: map[string]string{"production": ts.URL}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := service.Create(stdContext.TODO(), se)
+	c.Assert(err, check.IsNil)
+	si := service.ServiceInstance{Name: "si-test", ServiceName: "go", Teams: []string{s.team.Name}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader("parameters.bucket=my-bucket")
+	request, err := http.NewRequest("POST", "/services/go/instances/si-test/actions/backup?:service=go&:instance=si-test&:action=backup", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	err = serviceInstanceExecuteAction(recorder, request, s.token)
+	c.Assert(err, check.IsNil)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/x-json-stream")
+	c.Assert(recorder.Body.String(), check.Matches, `(?s).*backup finished.*`)
+	c.Assert(bucket, check.Equals, "my-bucket")
+	c.Assert(eventtest.EventDesc{
+		Target: serviceInstanceTarget("go", "si-test"),
+		Owner:  s.token.GetUserName(),
+		Kind:   "service-instance.update.actions",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":action", "value": "backup"},
+			{"name": "parameters.bucket", "value": "my-bucket"},
+		},
+		LogMatches: []string{"backup finished"},
+	}, eventtest.HasEvent)
+}
+
+func (s *ServiceInstanceSuite) TestServiceInstanceExecuteActionNotFound(c *check.C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name": "backup"}]`))
+	}))
+	defer ts.Close()
+	se := service.Service{Name: "go", Endpoint: map[string]string{"production": ts.URL}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := service.Create(stdContext.TODO(), se)
+	c.Assert(err, check.IsNil)
+	si := service.ServiceInstance{Name: "si-test", ServiceName: "go", Teams: []string{s.team.Name}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/services/go/instances/si-test/actions/restore?:service=go&:instance=si-test&:action=restore", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = serviceInstanceExecuteAction(recorder, request, s.token)
+	c.Assert(err, check.DeepEquals, &errors.HTTP{
+		Code:    http.StatusNotFound,
+		Message: service.ErrInstanceActionNotFound.Error(),
+	})
+}
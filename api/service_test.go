@@ -421,6 +421,34 @@ func (s *ProvisionSuite) TestServiceUpdateWithoutTeamIgnoresOwnerTeams(c *check.
 	}, eventtest.HasEvent)
 }
 
+func (s *ProvisionSuite) TestServiceUpdateKeepsOmittedRequestOptions(c *check.C) {
+	srv := service.Service{
+		Name:           "mysqlapi",
+		Endpoint:       map[string]string{"production": "sqlapi.com"},
+		OwnerTeams:     []string{s.team.Name},
+		Password:       "oldold",
+		Timeout:        10,
+		CircuitBreaker: service.CircuitBreakerConfig{Threshold: 5, CooldownSeconds: 60},
+	}
+	err := service.Create(context.TODO(), srv)
+	c.Assert(err, check.IsNil)
+	v := url.Values{}
+	v.Set("username", "mysqltest")
+	v.Set("password", "yyyy")
+	v.Set("endpoint", "mysqlapi.com")
+	v.Set("circuit-breaker-threshold", "0")
+	recorder, request := s.makeRequest(http.MethodPut, "/services/mysqlapi", v.Encode(), c)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	err = servicesCollection.FindOne(context.TODO(), mongoBSON.M{"_id": srv.Name}).Decode(&srv)
+	c.Assert(err, check.IsNil)
+	c.Assert(srv.Timeout, check.Equals, 10)
+	c.Assert(srv.CircuitBreaker, check.DeepEquals, service.CircuitBreakerConfig{CooldownSeconds: 60})
+}
+
 func (s *ProvisionSuite) TestServiceUpdateReturnsBadRequestWithoutPassword(c *check.C) {
 	srv := service.Service{
 		Name:       "some-service",
@@ -139,6 +139,10 @@ func (s *S) SetUpTest(c *check.C) {
 	c.Assert(err, check.IsNil)
 	servicemanager.Job, err = job.JobService()
 	c.Assert(err, check.IsNil)
+	servicemanager.JobWorkflow, err = job.WorkflowService()
+	c.Assert(err, check.IsNil)
+	servicemanager.JobTemplate, err = job.TemplateService()
+	c.Assert(err, check.IsNil)
 	servicemanager.Tag, err = tag.TagService()
 	c.Assert(err, check.IsNil)
 }
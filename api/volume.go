@@ -281,6 +281,7 @@ func volumeDelete(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 // responses:
 //
 //	200: Volume binded
+//	400: Invalid data
 //	401: Unauthorized
 //	404: Volume not found
 //	409: Volume bind already exists
@@ -288,6 +289,7 @@ func volumeBind(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	ctx := r.Context()
 	var bindInfo struct {
 		App        string
+		Job        string
 		MountPoint string
 		ReadOnly   bool
 		NoRestart  bool
@@ -307,6 +309,17 @@ func volumeBind(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if !canBindVolume {
 		return permission.ErrUnauthorized
 	}
+	if bindInfo.Job != "" {
+		if bindInfo.App != "" {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: volumeBindTargetMsg}
+		}
+		return volumeBindJob(w, r, t, dbVolume, &volumeTypes.BindOpts{
+			Volume:     dbVolume,
+			JobName:    bindInfo.Job,
+			MountPoint: bindInfo.MountPoint,
+			ReadOnly:   bindInfo.ReadOnly,
+		})
+	}
 	a, err := getAppFromContext(bindInfo.App, r)
 	if err != nil {
 		return err
@@ -354,12 +367,14 @@ func volumeBind(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 // responses:
 //
 //	200: Volume unbinded
+//	400: Invalid data
 //	401: Unauthorized
 //	404: Volume not found
 func volumeUnbind(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	ctx := r.Context()
 	var bindInfo struct {
 		App        string
+		Job        string
 		MountPoint string
 		NoRestart  bool
 	}
@@ -378,6 +393,16 @@ func volumeUnbind(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if !canUnbind {
 		return permission.ErrUnauthorized
 	}
+	if bindInfo.Job != "" {
+		if bindInfo.App != "" {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: volumeBindTargetMsg}
+		}
+		return volumeUnbindJob(w, r, t, dbVolume, &volumeTypes.BindOpts{
+			Volume:     dbVolume,
+			JobName:    bindInfo.Job,
+			MountPoint: bindInfo.MountPoint,
+		})
+	}
 	a, err := getAppFromContext(bindInfo.App, r)
 	if err != nil {
 		return err
@@ -416,3 +441,76 @@ func volumeUnbind(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	evt.SetLogWriter(writer)
 	return app.Restart(ctx, a, "", "", evt)
 }
+
+const volumeBindTargetMsg = "volume must be bound to either an app or a job"
+
+func volumeBindJob(w http.ResponseWriter, r *http.Request, t auth.Token, dbVolume *volumeTypes.Volume, opts *volumeTypes.BindOpts) (err error) {
+	ctx := r.Context()
+	j, err := getJob(ctx, opts.JobName)
+	if err != nil {
+		return err
+	}
+	canBindJob := permission.Check(ctx, t, permission.PermJobUpdateBindVolume, contextsForJob(j)...)
+	if !canBindJob {
+		return permission.ErrUnauthorized
+	}
+	err = servicemanager.Volume.CheckPoolVolumeConstraints(ctx, volumeTypes.Volume{Pool: j.Pool, Plan: dbVolume.Plan})
+	if err == volumeTypes.ErrVolumePlanNotFound || err == pool.ErrPoolHasNoVolumePlan {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: dbVolume.Name},
+		Kind:       permission.PermVolumeUpdateBind,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermVolumeReadEvents, contextsForVolume(dbVolume)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	err = servicemanager.Volume.BindJob(ctx, opts)
+	if err == volumeTypes.ErrVolumeAlreadyBound {
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	return servicemanager.Job.UpdateJobProv(ctx, j)
+}
+
+func volumeUnbindJob(w http.ResponseWriter, r *http.Request, t auth.Token, dbVolume *volumeTypes.Volume, opts *volumeTypes.BindOpts) (err error) {
+	ctx := r.Context()
+	j, err := getJob(ctx, opts.JobName)
+	if err != nil {
+		return err
+	}
+	canUnbindJob := permission.Check(ctx, t, permission.PermJobUpdateUnbindVolume, contextsForJob(j)...)
+	if !canUnbindJob {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: dbVolume.Name},
+		Kind:       permission.PermVolumeUpdateUnbind,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermVolumeReadEvents, contextsForVolume(dbVolume)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	err = servicemanager.Volume.UnbindJob(ctx, opts)
+	if err == volumeTypes.ErrVolumeBindNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	return servicemanager.Job.UpdateJobProv(ctx, j)
+}
@@ -14,14 +14,19 @@ import (
 
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/permission/permissiontest"
+	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/provision/provisiontest"
 	"github.com/tsuru/tsuru/servicemanager"
 	appTypes "github.com/tsuru/tsuru/types/app"
 	authTypes "github.com/tsuru/tsuru/types/auth"
+	jobTypes "github.com/tsuru/tsuru/types/job"
 	permTypes "github.com/tsuru/tsuru/types/permission"
+	provTypes "github.com/tsuru/tsuru/types/provision"
 	volumeTypes "github.com/tsuru/tsuru/types/volume"
 	check "gopkg.in/check.v1"
 )
@@ -878,3 +883,138 @@ func (s *S) TestVolumeUnbindNoRestart(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, http.StatusOK)
 	c.Assert(recorder.Body.String(), check.Equals, "")
 }
+
+func (s *S) createVolumeJob(c *check.C) *jobTypes.Job {
+	j := &jobTypes.Job{
+		Name:      "myjob",
+		TeamOwner: s.team.Name,
+		Pool:      s.Pool,
+		Spec: jobTypes.JobSpec{
+			Schedule: "* * * * *",
+		},
+		DeployOptions: &jobTypes.DeployOptions{
+			Kind:  provTypes.DeployImage,
+			Image: "busybox:1.28",
+		},
+	}
+	user, _ := auth.ConvertOldUser(s.user, nil)
+	err := servicemanager.Job.CreateJob(context.TODO(), j, user)
+	c.Assert(err, check.IsNil)
+	return j
+}
+
+func (s *S) TestVolumeBindJob(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "jobProv"
+	provision.Register("jobProv", func() (provision.Provisioner, error) {
+		return &provisiontest.JobProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}, nil
+	})
+	defer provision.Unregister("jobProv")
+	v1 := volumeTypes.Volume{Name: "v1", Pool: s.Pool, TeamOwner: s.team.Name, Plan: volumeTypes.VolumePlan{Name: "nfs"}}
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &v1, nil
+	}
+	var bindOpts *volumeTypes.BindOpts
+	s.mockService.VolumeService.OnBindJob = func(ctx context.Context, opts *volumeTypes.BindOpts) error {
+		bindOpts = opts
+		return nil
+	}
+	s.createVolumeJob(c)
+	body := strings.NewReader(`job=myjob&mountpoint=/mnt1&readonly=true`)
+	request, err := http.NewRequest("POST", "/1.4/volumes/v1/bind", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(bindOpts, check.NotNil)
+	c.Assert(bindOpts.JobName, check.Equals, "myjob")
+	c.Assert(bindOpts.MountPoint, check.Equals, "/mnt1")
+	c.Assert(bindOpts.ReadOnly, check.Equals, true)
+	c.Assert(bindOpts.Volume.Name, check.Equals, "v1")
+}
+
+func (s *S) TestVolumeBindJobAndApp(c *check.C) {
+	v1 := volumeTypes.Volume{Name: "v1", Pool: s.Pool, TeamOwner: s.team.Name, Plan: volumeTypes.VolumePlan{Name: "nfs"}}
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &v1, nil
+	}
+	body := strings.NewReader(`job=myjob&app=myapp&mountpoint=/mnt1`)
+	request, err := http.NewRequest("POST", "/1.4/volumes/v1/bind", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, volumeBindTargetMsg+"\n")
+}
+
+func (s *S) TestVolumeBindJobPlanNotAllowed(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "jobProv"
+	provision.Register("jobProv", func() (provision.Provisioner, error) {
+		return &provisiontest.JobProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}, nil
+	})
+	defer provision.Unregister("jobProv")
+	v1 := volumeTypes.Volume{Name: "v1", Pool: s.Pool, TeamOwner: s.team.Name, Plan: volumeTypes.VolumePlan{Name: "nfs"}}
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &v1, nil
+	}
+	s.mockService.VolumeService.OnCheckPoolVolumeConstraints = func(ctx context.Context, v volumeTypes.Volume) error {
+		return pool.ErrPoolHasNoVolumePlan
+	}
+	s.mockService.VolumeService.OnBindJob = func(ctx context.Context, opts *volumeTypes.BindOpts) error {
+		c.Fatal("BindJob should not be called")
+		return nil
+	}
+	s.createVolumeJob(c)
+	body := strings.NewReader(`job=myjob&mountpoint=/mnt1`)
+	request, err := http.NewRequest("POST", "/1.4/volumes/v1/bind", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+}
+
+func (s *S) TestVolumeUnbindJob(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "jobProv"
+	provision.Register("jobProv", func() (provision.Provisioner, error) {
+		return &provisiontest.JobProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}, nil
+	})
+	defer provision.Unregister("jobProv")
+	v1 := volumeTypes.Volume{Name: "v1", Pool: s.Pool, TeamOwner: s.team.Name, Plan: volumeTypes.VolumePlan{Name: "nfs"}}
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &v1, nil
+	}
+	s.mockService.VolumeService.OnUnbindJob = func(ctx context.Context, opts *volumeTypes.BindOpts) error {
+		return volumeTypes.ErrVolumeBindNotFound
+	}
+	s.createVolumeJob(c)
+	request, err := http.NewRequest("DELETE", "/1.4/volumes/v1/bind?job=myjob&mountpoint=/mnt1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	var unbindOpts *volumeTypes.BindOpts
+	s.mockService.VolumeService.OnUnbindJob = func(ctx context.Context, opts *volumeTypes.BindOpts) error {
+		unbindOpts = opts
+		return nil
+	}
+	request, err = http.NewRequest("DELETE", "/1.4/volumes/v1/bind?job=myjob&mountpoint=/mnt1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(unbindOpts.JobName, check.Equals, "myjob")
+	c.Assert(unbindOpts.MountPoint, check.Equals, "/mnt1")
+}
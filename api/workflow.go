@@ -0,0 +1,469 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	stdContext "context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+type inputWorkflow struct {
+	Name        string                  `json:"name"`
+	TeamOwner   string                  `json:"teamOwner"`
+	Description string                  `json:"description"`
+	Nodes       []string                `json:"nodes"`
+	Edges       []jobTypes.WorkflowEdge `json:"edges"`
+}
+
+func workflowTarget(name string) eventTypes.Target {
+	return eventTypes.Target{Type: eventTypes.TargetTypeJobWorkflow, Value: name}
+}
+
+func contextsForWorkflow(wf *jobTypes.Workflow) []permTypes.PermissionContext {
+	return []permTypes.PermissionContext{permission.Context(permTypes.CtxTeam, wf.TeamOwner)}
+}
+
+func getWorkflow(ctx stdContext.Context, name string) (*jobTypes.Workflow, error) {
+	wf, err := servicemanager.JobWorkflow.GetWorkflow(ctx, name)
+	if err == jobTypes.ErrWorkflowNotFound {
+		return nil, &errors.HTTP{Code: http.StatusNotFound, Message: fmt.Sprintf("Workflow %s not found.", name)}
+	}
+	return wf, err
+}
+
+func getWorkflowRun(ctx stdContext.Context, wf *jobTypes.Workflow, id string) (*jobTypes.WorkflowRun, error) {
+	run, err := servicemanager.JobWorkflow.GetWorkflowRun(ctx, wf, id)
+	if err == jobTypes.ErrWorkflowRunNotFound {
+		return nil, &errors.HTTP{Code: http.StatusNotFound, Message: fmt.Sprintf("Run %s of workflow %s not found.", id, wf.Name)}
+	}
+	return run, err
+}
+
+// canTriggerWorkflowJobs ensures the user may trigger every job of the
+// workflow, since the workflow triggers them on their behalf. Unknown jobs
+// are reported by the workflow validation.
+func canTriggerWorkflowJobs(ctx stdContext.Context, t auth.Token, wf *jobTypes.Workflow) (bool, error) {
+	for _, node := range wf.Nodes {
+		j, err := servicemanager.Job.GetByName(ctx, node)
+		if err == jobTypes.ErrJobNotFound {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if !permission.Check(ctx, t, permission.PermJobRun, contextsForJob(j)...) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// title: workflow create
+// path: /workflows
+// method: POST
+// consume: application/x-www-form-urlencoded, application/json
+// produce: application/json
+// responses:
+//
+//	201: Workflow created
+//	400: Invalid data
+//	401: Unauthorized
+//	409: Workflow already exists
+func createWorkflow(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	var iw inputWorkflow
+	if err = ParseInput(r, &iw); err != nil {
+		return err
+	}
+	wf := &jobTypes.Workflow{
+		Name:        iw.Name,
+		TeamOwner:   iw.TeamOwner,
+		Owner:       t.GetUserName(),
+		Description: iw.Description,
+		Nodes:       iw.Nodes,
+		Edges:       iw.Edges,
+	}
+	if wf.TeamOwner == "" {
+		wf.TeamOwner, err = autoTeamOwner(ctx, t, permission.PermJobWorkflowCreate)
+		if err != nil {
+			return err
+		}
+	}
+	if !permission.Check(ctx, t, permission.PermJobWorkflowCreate, contextsForWorkflow(wf)...) {
+		return permission.ErrUnauthorized
+	}
+	canTrigger, err := canTriggerWorkflowJobs(ctx, t, wf)
+	if err != nil {
+		return err
+	}
+	if !canTrigger {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     workflowTarget(wf.Name),
+		Kind:       permission.PermJobWorkflowCreate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermJobWorkflowRead, contextsForWorkflow(wf)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	err = servicemanager.JobWorkflow.CreateWorkflow(ctx, wf)
+	if err == jobTypes.ErrWorkflowAlreadyExists {
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(wf)
+}
+
+// title: workflow update
+// path: /workflows/{name}
+// method: PUT
+// consume: application/x-www-form-urlencoded, application/json
+// responses:
+//
+//	200: Workflow updated
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Not found
+func updateWorkflow(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	wf, err := getWorkflow(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobWorkflowUpdate, contextsForWorkflow(wf)...) {
+		return permission.ErrUnauthorized
+	}
+	var iw inputWorkflow
+	if err = ParseInput(r, &iw); err != nil {
+		return err
+	}
+	if iw.Description != "" {
+		wf.Description = iw.Description
+	}
+	if iw.Nodes != nil {
+		wf.Nodes = iw.Nodes
+		wf.Edges = iw.Edges
+	}
+	canTrigger, err := canTriggerWorkflowJobs(ctx, t, wf)
+	if err != nil {
+		return err
+	}
+	if !canTrigger {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     workflowTarget(wf.Name),
+		Kind:       permission.PermJobWorkflowUpdate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermJobWorkflowRead, contextsForWorkflow(wf)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	return servicemanager.JobWorkflow.UpdateWorkflow(ctx, wf)
+}
+
+// title: workflow list
+// path: /workflows
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: List workflows
+//	204: No content
+//	401: Unauthorized
+func workflowList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	contexts := permission.ContextsForPermission(ctx, t, permission.PermJobWorkflowRead)
+	if len(contexts) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	filter := &jobTypes.WorkflowFilter{}
+	for _, c := range contexts {
+		if c.CtxType == permTypes.CtxGlobal {
+			filter.TeamOwners = nil
+			break
+		}
+		filter.TeamOwners = append(filter.TeamOwners, c.Value)
+	}
+	workflows, err := servicemanager.JobWorkflow.ListWorkflows(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if len(workflows) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(workflows)
+}
+
+// title: workflow info
+// path: /workflows/{name}
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	401: Unauthorized
+//	404: Not found
+func workflowInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	wf, err := getWorkflow(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobWorkflowRead, contextsForWorkflow(wf)...) {
+		return permission.ErrUnauthorized
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(wf)
+}
+
+// title: workflow delete
+// path: /workflows/{name}
+// method: DELETE
+// responses:
+//
+//	200: Workflow removed
+//	401: Unauthorized
+//	404: Not found
+func deleteWorkflow(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	wf, err := getWorkflow(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobWorkflowDelete, contextsForWorkflow(wf)...) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     workflowTarget(wf.Name),
+		Kind:       permission.PermJobWorkflowDelete,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermJobWorkflowRead, contextsForWorkflow(wf)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	return servicemanager.JobWorkflow.RemoveWorkflow(ctx, wf)
+}
+
+// title: workflow run
+// path: /workflows/{name}/run
+// method: POST
+// produce: application/json
+// responses:
+//
+//	201: Workflow run started
+//	401: Unauthorized
+//	404: Not found
+func runWorkflow(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	wf, err := getWorkflow(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobWorkflowRun, contextsForWorkflow(wf)...) {
+		return permission.ErrUnauthorized
+	}
+	canTrigger, err := canTriggerWorkflowJobs(ctx, t, wf)
+	if err != nil {
+		return err
+	}
+	if !canTrigger {
+		return permission.ErrUnauthorized
+	}
+	run, err := servicemanager.JobWorkflow.RunWorkflow(ctx, wf, t.GetUserName())
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(run)
+}
+
+// title: workflow runs
+// path: /workflows/{name}/runs
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	204: No content
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Not found
+func workflowRuns(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	wf, err := getWorkflow(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobWorkflowRead, contextsForWorkflow(wf)...) {
+		return permission.ErrUnauthorized
+	}
+	var limit int
+	if l := r.URL.Query().Get("limit"); l != "" {
+		limit, err = strconv.Atoi(l)
+		if err != nil {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: `Parameter "limit" must be an integer.`}
+		}
+	}
+	runs, err := servicemanager.JobWorkflow.ListWorkflowRuns(ctx, wf, limit)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(runs)
+}
+
+// title: workflow run info
+// path: /workflows/{name}/runs/{run}
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	401: Unauthorized
+//	404: Not found
+func workflowRunInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	wf, err := getWorkflow(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobWorkflowRead, contextsForWorkflow(wf)...) {
+		return permission.ErrUnauthorized
+	}
+	run, err := getWorkflowRun(ctx, wf, r.URL.Query().Get(":run"))
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(run)
+}
+
+// title: workflow run cancel
+// path: /workflows/{name}/runs/{run}/cancel
+// method: POST
+// responses:
+//
+//	200: Workflow run canceled
+//	401: Unauthorized
+//	404: Not found
+//	409: Workflow run is not running
+func cancelWorkflowRun(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	wf, err := getWorkflow(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobWorkflowCancel, contextsForWorkflow(wf)...) {
+		return permission.ErrUnauthorized
+	}
+	run, err := getWorkflowRun(ctx, wf, r.URL.Query().Get(":run"))
+	if err != nil {
+		return err
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:      workflowTarget(wf.Name),
+		Kind:        permission.PermJobWorkflowCancel,
+		Owner:       t,
+		RemoteAddr:  r.RemoteAddr,
+		CustomData:  map[string]string{"run": run.ID, "parent": run.EventID},
+		DisableLock: true,
+		Allowed:     event.Allowed(permission.PermJobWorkflowRead, contextsForWorkflow(wf)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	err = servicemanager.JobWorkflow.CancelWorkflowRun(ctx, run, t.GetUserName())
+	if err == jobTypes.ErrWorkflowRunNotRunning {
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	}
+	return err
+}
+
+// title: workflow rerun
+// path: /workflows/{name}/runs/{run}/rerun
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/json
+// responses:
+//
+//	201: Workflow run started
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Not found
+//	409: Workflow run is still running
+func rerunWorkflow(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	wf, err := getWorkflow(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return err
+	}
+	if !permission.Check(ctx, t, permission.PermJobWorkflowRun, contextsForWorkflow(wf)...) {
+		return permission.ErrUnauthorized
+	}
+	canTrigger, err := canTriggerWorkflowJobs(ctx, t, wf)
+	if err != nil {
+		return err
+	}
+	if !canTrigger {
+		return permission.ErrUnauthorized
+	}
+	run, err := getWorkflowRun(ctx, wf, r.URL.Query().Get(":run"))
+	if err != nil {
+		return err
+	}
+	node := InputValue(r, "node")
+	if node == "" {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: `Parameter "node" is required.`}
+	}
+	newRun, err := servicemanager.JobWorkflow.RerunWorkflow(ctx, wf, run, node, t.GetUserName())
+	if err == jobTypes.ErrWorkflowRunStillRunning {
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(newRun)
+}
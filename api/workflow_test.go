@@ -0,0 +1,208 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	"github.com/tsuru/tsuru/servicemanager"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) insertWorkflowJobs(c *check.C, names ...string) {
+	jobsCollection, err := storagev2.JobsCollection()
+	c.Assert(err, check.IsNil)
+	for _, name := range names {
+		_, err = jobsCollection.InsertOne(context.TODO(), jobTypes.Job{Name: name, Pool: "test1", TeamOwner: s.team.Name, Teams: []string{s.team.Name}})
+		c.Assert(err, check.IsNil)
+	}
+}
+
+func (s *S) TestCreateWorkflow(c *check.C) {
+	s.insertWorkflowJobs(c, "extract", "load")
+	body := `{"name":"etl","teamOwner":"` + s.team.Name + `","nodes":["extract","load"],"edges":[{"from":"extract","to":"load","on":"success"}]}`
+	request, err := http.NewRequest("POST", "/workflows", strings.NewReader(body))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	wf, err := servicemanager.JobWorkflow.GetWorkflow(context.TODO(), "etl")
+	c.Assert(err, check.IsNil)
+	c.Assert(wf.Owner, check.Equals, s.token.GetUserName())
+	c.Assert(wf.Edges, check.DeepEquals, []jobTypes.WorkflowEdge{{From: "extract", To: "load", On: jobTypes.WorkflowOnSuccess}})
+	c.Assert(eventtest.EventDesc{
+		Target: workflowTarget("etl"),
+		Owner:  s.token.GetUserName(),
+		Kind:   "job.workflow.create",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestCreateWorkflowCycle(c *check.C) {
+	s.insertWorkflowJobs(c, "extract", "load")
+	body := `{"name":"etl","teamOwner":"` + s.team.Name + `","nodes":["extract","load"],"edges":[{"from":"extract","to":"load","on":"success"},{"from":"load","to":"extract","on":"failure"}]}`
+	request, err := http.NewRequest("POST", "/workflows", strings.NewReader(body))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, jobTypes.ErrWorkflowCycle.Error()+"\n")
+}
+
+func (s *S) TestCreateWorkflowWithoutTriggerPermission(c *check.C) {
+	s.insertWorkflowJobs(c, "extract")
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermJobWorkflowCreate,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	body := `{"name":"etl","teamOwner":"` + s.team.Name + `","nodes":["extract"]}`
+	request, err := http.NewRequest("POST", "/workflows", strings.NewReader(body))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestRunWorkflow(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "jobProv"
+	jobProv := &provisiontest.JobProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}
+	provision.Register("jobProv", func() (provision.Provisioner, error) {
+		return jobProv, nil
+	})
+	defer provision.Unregister("jobProv")
+	s.insertWorkflowJobs(c, "extract", "load")
+	for _, name := range []string{"extract", "load"} {
+		err := jobProv.EnsureJob(context.TODO(), &jobTypes.Job{Name: name})
+		c.Assert(err, check.IsNil)
+	}
+	wf := &jobTypes.Workflow{
+		Name:      "etl",
+		TeamOwner: s.team.Name,
+		Nodes:     []string{"extract", "load"},
+		Edges:     []jobTypes.WorkflowEdge{{From: "extract", To: "load", On: jobTypes.WorkflowOnSuccess}},
+	}
+	err := servicemanager.JobWorkflow.CreateWorkflow(context.TODO(), wf)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/workflows/etl/run", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	var run jobTypes.WorkflowRun
+	err = json.Unmarshal(recorder.Body.Bytes(), &run)
+	c.Assert(err, check.IsNil)
+	c.Assert(run.Status, check.Equals, jobTypes.WorkflowRunRunning)
+	c.Assert(run.Owner, check.Equals, s.token.GetUserName())
+	c.Assert(jobProv.JobExecutions("extract"), check.Equals, 1)
+	c.Assert(jobProv.JobExecutions("load"), check.Equals, 0)
+	request, err = http.NewRequest("POST", "/workflows/etl/runs/"+run.ID+"/rerun", strings.NewReader("node=load"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+	request, err = http.NewRequest("POST", "/workflows/etl/runs/"+run.ID+"/cancel", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	request, err = http.NewRequest("GET", "/workflows/etl/runs/"+run.ID, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	err = json.Unmarshal(recorder.Body.Bytes(), &run)
+	c.Assert(err, check.IsNil)
+	c.Assert(run.Status, check.Equals, jobTypes.WorkflowRunCanceled)
+}
+
+func (s *S) TestRunWorkflowWithoutTriggerPermission(c *check.C) {
+	s.insertWorkflowJobs(c, "extract")
+	err := servicemanager.JobWorkflow.CreateWorkflow(context.TODO(), &jobTypes.Workflow{Name: "etl", TeamOwner: s.team.Name, Nodes: []string{"extract"}})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermJobWorkflowRun,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	request, err := http.NewRequest("POST", "/workflows/etl/run", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	request, err = http.NewRequest("POST", "/workflows/etl/runs/some-run/rerun", strings.NewReader("node=extract"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestRunWorkflowRequiresJobRunPermission(c *check.C) {
+	s.insertWorkflowJobs(c, "extract")
+	err := servicemanager.JobWorkflow.CreateWorkflow(context.TODO(), &jobTypes.Workflow{Name: "etl", TeamOwner: s.team.Name, Nodes: []string{"extract"}})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermJobWorkflowRun,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	}, permTypes.Permission{
+		Scheme:  permission.PermJobTrigger,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	request, err := http.NewRequest("POST", "/workflows/etl/run", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestWorkflowRunNotFound(c *check.C) {
+	s.insertWorkflowJobs(c, "extract")
+	err := servicemanager.JobWorkflow.CreateWorkflow(context.TODO(), &jobTypes.Workflow{Name: "etl", TeamOwner: s.team.Name, Nodes: []string{"extract"}})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/workflows/etl/runs/unknown", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestDeleteJobUsedByWorkflow(c *check.C) {
+	s.insertWorkflowJobs(c, "extract")
+	err := servicemanager.JobWorkflow.CreateWorkflow(context.TODO(), &jobTypes.Workflow{Name: "etl", TeamOwner: s.team.Name, Nodes: []string{"extract"}})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("DELETE", "/jobs/extract", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+	c.Assert(recorder.Body.String(), check.Equals, `job "extract" is used by the workflows: etl`+"\n")
+}
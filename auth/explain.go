@@ -0,0 +1,185 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tsuru/tsuru/permission"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+// PermissionExplanation describes how a permission check was evaluated
+// against every role instance available to a user.
+type PermissionExplanation struct {
+	Permission string           `json:"permission"`
+	Contexts   []string         `json:"contexts"`
+	Allowed    bool             `json:"allowed"`
+	GrantedBy  *RoleEvaluation  `json:"grantedBy,omitempty"`
+	Evaluated  []RoleEvaluation `json:"evaluated"`
+}
+
+// RoleEvaluation is the outcome of checking a single role instance.
+type RoleEvaluation struct {
+	Role         string `json:"role,omitempty"`
+	ContextType  string `json:"contextType,omitempty"`
+	ContextValue string `json:"contextValue,omitempty"`
+	Group        string `json:"group,omitempty"`
+	Implicit     bool   `json:"implicit,omitempty"`
+	Granted      bool   `json:"granted"`
+	Reason       string `json:"reason"`
+}
+
+// ExplainPermission evaluates the same chain used by permission.Check for
+// the user (own roles, group roles and the implicit user permission) and
+// reports which role instance granted the permission or why none did.
+func (u *User) ExplainPermission(ctx context.Context, scheme *permTypes.PermissionScheme, contexts ...permTypes.PermissionContext) (*PermissionExplanation, error) {
+	explanation := &PermissionExplanation{
+		Permission: scheme.FullName(),
+		Contexts:   make([]string, len(contexts)),
+		Evaluated:  []RoleEvaluation{},
+	}
+	for i, c := range contexts {
+		explanation.Contexts[i] = formatContext(c)
+	}
+	e := &explainer{
+		explanation: explanation,
+		scheme:      scheme,
+		contexts:    contexts,
+		roles:       map[string]*permission.Role{},
+		now:         time.Now(),
+	}
+	if !u.FromToken {
+		e.evaluateImplicit(u.Email)
+	}
+	for _, roleInstance := range u.Roles {
+		err := e.evaluate(ctx, roleInstance, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !u.FromToken {
+		groups, err := u.UserGroups()
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range groups {
+			for _, roleInstance := range group.Roles {
+				err = e.evaluate(ctx, roleInstance, group.Name)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for i := range explanation.Evaluated {
+		if explanation.Evaluated[i].Granted {
+			explanation.Allowed = true
+			explanation.GrantedBy = &explanation.Evaluated[i]
+			break
+		}
+	}
+	return explanation, nil
+}
+
+type explainer struct {
+	explanation *PermissionExplanation
+	scheme      *permTypes.PermissionScheme
+	contexts    []permTypes.PermissionContext
+	roles       map[string]*permission.Role
+	now         time.Time
+}
+
+func (e *explainer) evaluateImplicit(email string) {
+	userPerm := permTypes.Permission{
+		Scheme:  permission.PermUser,
+		Context: permission.Context(permTypes.CtxUser, email),
+	}
+	if !userPerm.Scheme.IsParent(e.scheme) {
+		return
+	}
+	evaluation := RoleEvaluation{
+		ContextType:  string(permTypes.CtxUser),
+		ContextValue: email,
+		Implicit:     true,
+	}
+	evaluation.Granted, evaluation.Reason = e.matchContext(userPerm)
+	e.explanation.Evaluated = append(e.explanation.Evaluated, evaluation)
+}
+
+func (e *explainer) evaluate(ctx context.Context, roleInstance authTypes.RoleInstance, group string) error {
+	evaluation := RoleEvaluation{
+		Role:         roleInstance.Name,
+		ContextValue: roleInstance.ContextValue,
+		Group:        group,
+	}
+	defer func() {
+		e.explanation.Evaluated = append(e.explanation.Evaluated, evaluation)
+	}()
+	if roleInstance.Expired(e.now) {
+		evaluation.Reason = fmt.Sprintf("role assignment expired at %s", roleInstance.ExpiresAt.Format(time.RFC3339))
+		return nil
+	}
+	role := e.roles[roleInstance.Name]
+	if role == nil {
+		foundRole, err := permission.FindRole(ctx, roleInstance.Name)
+		if err == permTypes.ErrRoleNotFound {
+			evaluation.Reason = "role not found"
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		role = &foundRole
+		e.roles[roleInstance.Name] = role
+	}
+	evaluation.ContextType = string(role.ContextType)
+	var candidates []permTypes.Permission
+	for _, perm := range role.PermissionsFor(roleInstance.ContextValue) {
+		if perm.Scheme.IsParent(e.scheme) {
+			candidates = append(candidates, perm)
+		}
+	}
+	if len(candidates) == 0 {
+		evaluation.Reason = fmt.Sprintf("role does not include permission %q", e.explanation.Permission)
+		return nil
+	}
+	for _, perm := range candidates {
+		evaluation.Granted, evaluation.Reason = e.matchContext(perm)
+		if evaluation.Granted {
+			break
+		}
+	}
+	return nil
+}
+
+func (e *explainer) matchContext(perm permTypes.Permission) (bool, string) {
+	if perm.Condition != nil && !perm.Condition.Matches(e.contexts) {
+		return false, fmt.Sprintf("permission %q condition not satisfied: %s", perm.Scheme.FullName(), perm.Condition)
+	}
+	if perm.Context.CtxType == permTypes.CtxGlobal {
+		return true, fmt.Sprintf("permission %q granted in global context", perm.Scheme.FullName())
+	}
+	for _, c := range e.contexts {
+		if c.CtxType == perm.Context.CtxType && c.Value == perm.Context.Value {
+			return true, fmt.Sprintf("permission %q granted in context %s", perm.Scheme.FullName(), formatContext(c))
+		}
+	}
+	if len(e.contexts) == 0 {
+		return false, fmt.Sprintf("role context %s is not global and no context was checked", formatContext(perm.Context))
+	}
+	return false, fmt.Sprintf("role context %s does not match any of: %s", formatContext(perm.Context), strings.Join(e.explanation.Contexts, ", "))
+}
+
+func formatContext(c permTypes.PermissionContext) string {
+	if c.Value == "" {
+		return string(c.CtxType)
+	}
+	return fmt.Sprintf("%s:%s", c.CtxType, c.Value)
+}
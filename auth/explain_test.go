@@ -0,0 +1,70 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestUserExplainPermissionGranted(c *check.C) {
+	ctx := context.TODO()
+	r1, err := permission.NewRole(ctx, "r1", "app", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions(ctx, "app.update.env")
+	c.Assert(err, check.IsNil)
+	r2, err := permission.NewRole(ctx, "r2", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r2.AddPermissions(ctx, "app.deploy")
+	c.Assert(err, check.IsNil)
+	u := User{Email: "me@tsuru.com", Password: "123", Groups: []string{"g1"}}
+	err = u.Create(ctx)
+	c.Assert(err, check.IsNil)
+	err = u.AddRole(ctx, "r1", "myapp")
+	c.Assert(err, check.IsNil)
+	err = servicemanager.AuthGroup.AddRole(ctx, "g1", "r2", "myteam")
+	c.Assert(err, check.IsNil)
+	explanation, err := u.ExplainPermission(ctx, permission.PermAppDeploy,
+		permission.Context(permTypes.CtxApp, "myapp"),
+		permission.Context(permTypes.CtxTeam, "myteam"),
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Allowed, check.Equals, true)
+	c.Assert(explanation.Contexts, check.DeepEquals, []string{"app:myapp", "team:myteam"})
+	c.Assert(explanation.Evaluated, check.DeepEquals, []RoleEvaluation{
+		{Role: "r1", ContextType: "app", ContextValue: "myapp", Reason: `role does not include permission "app.deploy"`},
+		{Role: "r2", ContextType: "team", ContextValue: "myteam", Group: "g1", Granted: true, Reason: `permission "app.deploy" granted in context team:myteam`},
+	})
+	c.Assert(explanation.GrantedBy, check.DeepEquals, &explanation.Evaluated[1])
+}
+
+func (s *S) TestUserExplainPermissionDenied(c *check.C) {
+	ctx := context.TODO()
+	r1, err := permission.NewRole(ctx, "r1", "app", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions(ctx, "app.deploy")
+	c.Assert(err, check.IsNil)
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err = u.Create(ctx)
+	c.Assert(err, check.IsNil)
+	err = u.AddRole(ctx, "r1", "otherapp")
+	c.Assert(err, check.IsNil)
+	expiresAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	err = u.AddRoleWithExpiration(ctx, "r1", "myapp", expiresAt, "old incident")
+	c.Assert(err, check.IsNil)
+	explanation, err := u.ExplainPermission(ctx, permission.PermAppDeploy, permission.Context(permTypes.CtxApp, "myapp"))
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Allowed, check.Equals, false)
+	c.Assert(explanation.GrantedBy, check.IsNil)
+	c.Assert(explanation.Evaluated, check.DeepEquals, []RoleEvaluation{
+		{Role: "r1", ContextType: "app", ContextValue: "otherapp", Reason: "role context app:otherapp does not match any of: app:myapp"},
+		{Role: "r1", ContextValue: "myapp", Reason: "role assignment expired at 2020-01-01T00:00:00Z"},
+	})
+}
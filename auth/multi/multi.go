@@ -16,8 +16,9 @@ import (
 )
 
 var (
-	_ auth.Scheme      = &multiScheme{}
-	_ auth.MultiScheme = &multiScheme{}
+	_ auth.Scheme        = &multiScheme{}
+	_ auth.MultiScheme   = &multiScheme{}
+	_ auth.SessionScheme = &multiScheme{}
 )
 
 func init() {
@@ -265,6 +266,70 @@ func (s *multiScheme) Remove(ctx context.Context, user *auth.User) error {
 	return newErrNotImplemented("remove")
 }
 
+func (s *multiScheme) ListSessions(ctx context.Context, email string) ([]authTypes.Session, error) {
+	schemes, err := s.schemes()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []authTypes.Session{}
+	for _, scheme := range schemes {
+		sessionScheme, ok := scheme.(auth.SessionScheme)
+		if !ok {
+			continue
+		}
+		schemeSessions, err := sessionScheme.ListSessions(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, schemeSessions...)
+	}
+
+	return sessions, nil
+}
+
+func (s *multiScheme) RevokeSession(ctx context.Context, email, id string) error {
+	schemes, err := s.schemes()
+	if err != nil {
+		return err
+	}
+
+	for _, scheme := range schemes {
+		sessionScheme, ok := scheme.(auth.SessionScheme)
+		if !ok {
+			continue
+		}
+		err := sessionScheme.RevokeSession(ctx, email, id)
+		if err == authTypes.ErrSessionNotFound {
+			continue
+		}
+		return err
+	}
+
+	return authTypes.ErrSessionNotFound
+}
+
+func (s *multiScheme) RevokeAllSessions(ctx context.Context, email string) error {
+	schemes, err := s.schemes()
+	if err != nil {
+		return err
+	}
+
+	errors := tsuruErrors.NewMultiError()
+	for _, scheme := range schemes {
+		sessionScheme, ok := scheme.(auth.SessionScheme)
+		if !ok {
+			continue
+		}
+		err := sessionScheme.RevokeAllSessions(ctx, email)
+		if err != nil {
+			errors.Add(err)
+		}
+	}
+
+	return errors.ToError()
+}
+
 func (s *multiScheme) schemes() ([]auth.Scheme, error) {
 	schemes := s.cachedSchemes.Load()
 	if schemes == nil {
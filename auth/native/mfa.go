@@ -0,0 +1,255 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package native
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/db/storagev2"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	mfaPeriod          = 30
+	mfaDigits          = 6
+	mfaSkew            = 1
+	mfaSecretSize      = 20
+	recoveryCodesCount = 10
+	recoveryCodeSize   = 10
+	recoveryCodeChars  = "abcdefghjkmnpqrstuvwxyz23456789"
+	defaultMFAIssuer   = "tsuru"
+	mfaMaxAttempts     = 5
+	mfaLockout         = 5 * time.Minute
+)
+
+var mfaEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var _ auth.MFAScheme = &NativeScheme{}
+
+// EnrollMFA generates a new TOTP secret for the user, requiring the current
+// password, so a leaked token is not enough to take over the second factor.
+// The secret is only enforced after it's confirmed with ConfirmMFA.
+func (s NativeScheme) EnrollMFA(ctx context.Context, u *auth.User, password string) (*authTypes.MFAEnrollment, error) {
+	if u.MFAEnabled() {
+		return nil, authTypes.ErrMFAAlreadyEnabled
+	}
+	if err := checkPassword(u.Password, password); err != nil {
+		return nil, err
+	}
+	rawSecret := make([]byte, mfaSecretSize)
+	if _, err := rand.Read(rawSecret); err != nil {
+		return nil, err
+	}
+	secret := mfaEncoding.EncodeToString(rawSecret)
+	u.MFA = &authTypes.MFA{Secret: secret}
+	if err := u.Update(ctx); err != nil {
+		return nil, err
+	}
+	return &authTypes.MFAEnrollment{
+		Secret: secret,
+		URL:    mfaURL(u.Email, secret),
+	}, nil
+}
+
+// ConfirmMFA enables the pending enrolment when code is valid for it,
+// returning the recovery codes, which are never displayed again.
+func (s NativeScheme) ConfirmMFA(ctx context.Context, u *auth.User, code string) ([]string, error) {
+	if u.MFA == nil || u.MFA.Secret == "" {
+		return nil, authTypes.ErrMFANotEnrolled
+	}
+	if u.MFA.Enabled {
+		return nil, authTypes.ErrMFAAlreadyEnabled
+	}
+	if time.Now().Before(u.MFA.LockedUntil) {
+		return nil, authTypes.ErrMFALocked
+	}
+	step, ok := validateTOTP(u.MFA.Secret, code, time.Now(), u.MFA.LastStep)
+	if !ok {
+		return nil, registerMFAFailure(ctx, u)
+	}
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	u.MFA.Enabled = true
+	u.MFA.LastStep = step
+	u.MFA.RecoveryCodes = hashes
+	u.MFA.FailedAttempts = 0
+	return codes, u.Update(ctx)
+}
+
+// DisableMFA removes the enrolment of the user, requiring a valid TOTP or
+// recovery code.
+func (s NativeScheme) DisableMFA(ctx context.Context, u *auth.User, code string) error {
+	if !u.MFAEnabled() {
+		return authTypes.ErrMFANotEnrolled
+	}
+	if err := s.VerifyMFA(ctx, u, code); err != nil {
+		return err
+	}
+	u.MFA = nil
+	return u.Update(ctx)
+}
+
+// VerifyMFA checks a TOTP or recovery code for users with MFA enabled.
+// Accepted codes can't be used again.
+func (s NativeScheme) VerifyMFA(ctx context.Context, u *auth.User, code string) error {
+	if !u.MFAEnabled() {
+		return nil
+	}
+	if code == "" {
+		return authTypes.ErrMFARequired
+	}
+	if time.Now().Before(u.MFA.LockedUntil) {
+		return authTypes.ErrMFALocked
+	}
+	if step, ok := validateTOTP(u.MFA.Secret, code, time.Now(), u.MFA.LastStep); ok {
+		u.MFA.LastStep = step
+		u.MFA.FailedAttempts = 0
+		return u.Update(ctx)
+	}
+	for i, hash := range u.MFA.RecoveryCodes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(normalizeRecoveryCode(code))) == nil {
+			u.MFA.RecoveryCodes = append(u.MFA.RecoveryCodes[:i], u.MFA.RecoveryCodes[i+1:]...)
+			u.MFA.FailedAttempts = 0
+			return u.Update(ctx)
+		}
+	}
+	return registerMFAFailure(ctx, u)
+}
+
+// registerMFAFailure counts an invalid code for the user, locking the
+// verification for mfaLockout after mfaMaxAttempts consecutive failures. The
+// counter is incremented atomically so concurrent guesses are also counted.
+func registerMFAFailure(ctx context.Context, u *auth.User) error {
+	collection, err := storagev2.UsersCollection()
+	if err != nil {
+		return err
+	}
+	var updated auth.User
+	err = collection.FindOneAndUpdate(ctx,
+		mongoBSON.M{"email": u.Email},
+		mongoBSON.M{"$inc": mongoBSON.M{"mfa.failedattempts": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		return err
+	}
+	if updated.MFA == nil {
+		return authTypes.ErrInvalidMFACode
+	}
+	u.MFA.FailedAttempts = updated.MFA.FailedAttempts
+	if updated.MFA.FailedAttempts < mfaMaxAttempts {
+		return authTypes.ErrInvalidMFACode
+	}
+	u.MFA.FailedAttempts = 0
+	u.MFA.LockedUntil = time.Now().Add(mfaLockout)
+	_, err = collection.UpdateOne(ctx, mongoBSON.M{"email": u.Email}, mongoBSON.M{"$set": mongoBSON.M{
+		"mfa.failedattempts": 0,
+		"mfa.lockeduntil":    u.MFA.LockedUntil,
+	}})
+	if err != nil {
+		return err
+	}
+	return authTypes.ErrMFALocked
+}
+
+// MFARequiredRoles returns the roles which are only effective for tokens
+// issued after a multi-factor authentication.
+func (s NativeScheme) MFARequiredRoles() []string {
+	return auth.MFARequiredRoles()
+}
+
+func mfaURL(email, secret string) string {
+	issuer, _ := config.GetString("auth:mfa:issuer")
+	if issuer == "" {
+		issuer = defaultMFAIssuer
+	}
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("digits", fmt.Sprint(mfaDigits))
+	params.Set("period", fmt.Sprint(mfaPeriod))
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + email,
+		RawQuery: params.Encode(),
+	}).String()
+}
+
+// validateTOTP checks code against the steps around now, as described in
+// RFC 6238, rejecting steps not after lastStep. It returns the matched step.
+func validateTOTP(secret, code string, now time.Time, lastStep int64) (int64, bool) {
+	key, err := mfaEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != mfaDigits {
+		return 0, false
+	}
+	current := now.Unix() / mfaPeriod
+	for step := current - mfaSkew; step <= current+mfaSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func totpCode(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", mfaDigits, value%1000000)
+}
+
+func generateRecoveryCodes() ([]string, []string, error) {
+	loadConfig()
+	codes := make([]string, recoveryCodesCount)
+	hashes := make([]string, recoveryCodesCount)
+	charsLen := big.NewInt(int64(len(recoveryCodeChars)))
+	for i := range codes {
+		raw := make([]byte, recoveryCodeSize)
+		for j := range raw {
+			n, err := rand.Int(rand.Reader, charsLen)
+			if err != nil {
+				return nil, nil, err
+			}
+			raw[j] = recoveryCodeChars[n.Int64()]
+		}
+		codes[i] = string(raw[:recoveryCodeSize/2]) + "-" + string(raw[recoveryCodeSize/2:])
+		hash, err := bcrypt.GenerateFromPassword([]byte(normalizeRecoveryCode(codes[i])), cost)
+		if err != nil {
+			return nil, nil, err
+		}
+		hashes[i] = string(hash)
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package native
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func currentTOTP(c *check.C, secret string) string {
+	key, err := mfaEncoding.DecodeString(secret)
+	c.Assert(err, check.IsNil)
+	return totpCode(key, time.Now().Unix()/mfaPeriod)
+}
+
+func (s *S) enrollMFA(c *check.C) (string, []string) {
+	enrollment, err := nativeScheme.EnrollMFA(context.TODO(), s.user, "123456")
+	c.Assert(err, check.IsNil)
+	codes, err := nativeScheme.ConfirmMFA(context.TODO(), s.user, currentTOTP(c, enrollment.Secret))
+	c.Assert(err, check.IsNil)
+	return enrollment.Secret, codes
+}
+
+func (s *S) TestTOTPCode(c *check.C) {
+	// Test vectors from RFC 6238, truncated to 6 digits.
+	key := []byte("12345678901234567890")
+	c.Assert(totpCode(key, 59/mfaPeriod), check.Equals, "287082")
+	c.Assert(totpCode(key, 1111111109/mfaPeriod), check.Equals, "081804")
+	c.Assert(totpCode(key, 2000000000/mfaPeriod), check.Equals, "279037")
+}
+
+func (s *S) TestValidateTOTP(c *check.C) {
+	secret := mfaEncoding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111109, 0)
+	step, ok := validateTOTP(secret, "081804", now, 0)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(step, check.Equals, int64(1111111109/mfaPeriod))
+	_, ok = validateTOTP(secret, "081804", now.Add(mfaPeriod*time.Second), 0)
+	c.Assert(ok, check.Equals, true)
+	_, ok = validateTOTP(secret, "081804", now.Add(5*mfaPeriod*time.Second), 0)
+	c.Assert(ok, check.Equals, false)
+	_, ok = validateTOTP(secret, "081804", now, step)
+	c.Assert(ok, check.Equals, false)
+	_, ok = validateTOTP(secret, "81804", now, 0)
+	c.Assert(ok, check.Equals, false)
+}
+
+func (s *S) TestEnrollMFA(c *check.C) {
+	enrollment, err := nativeScheme.EnrollMFA(context.TODO(), s.user, "123456")
+	c.Assert(err, check.IsNil)
+	c.Assert(enrollment.Secret, check.Not(check.Equals), "")
+	c.Assert(enrollment.URL, check.Matches, `otpauth://totp/tsuru:timeredbull@globo.com\?digits=6&issuer=tsuru&period=30&secret=`+enrollment.Secret)
+	u, err := auth.GetUserByEmail(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.MFA, check.DeepEquals, &authTypes.MFA{Secret: enrollment.Secret})
+	c.Assert(u.MFAEnabled(), check.Equals, false)
+	_, err = nativeScheme.ConfirmMFA(context.TODO(), u, "000000")
+	c.Assert(err, check.Equals, authTypes.ErrInvalidMFACode)
+	codes, err := nativeScheme.ConfirmMFA(context.TODO(), u, currentTOTP(c, enrollment.Secret))
+	c.Assert(err, check.IsNil)
+	c.Assert(codes, check.HasLen, recoveryCodesCount)
+	u, err = auth.GetUserByEmail(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.MFAEnabled(), check.Equals, true)
+	c.Assert(u.MFA.RecoveryCodes, check.HasLen, recoveryCodesCount)
+	_, err = nativeScheme.EnrollMFA(context.TODO(), u, "123456")
+	c.Assert(err, check.Equals, authTypes.ErrMFAAlreadyEnabled)
+}
+
+func (s *S) TestEnrollMFAWrongPassword(c *check.C) {
+	_, err := nativeScheme.EnrollMFA(context.TODO(), s.user, "wrong-password")
+	c.Assert(err, check.FitsTypeOf, auth.AuthenticationFailure{})
+	u, err := auth.GetUserByEmail(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.MFA, check.IsNil)
+}
+
+func (s *S) TestGenerateRecoveryCodes(c *check.C) {
+	codes, hashes, err := generateRecoveryCodes()
+	c.Assert(err, check.IsNil)
+	c.Assert(codes, check.HasLen, recoveryCodesCount)
+	c.Assert(hashes, check.HasLen, recoveryCodesCount)
+	for _, code := range codes {
+		c.Assert(code, check.Matches, `[`+recoveryCodeChars+`]{5}-[`+recoveryCodeChars+`]{5}`)
+	}
+}
+
+func (s *S) TestLoginWithMFA(c *check.C) {
+	secret, recoveryCodes := s.enrollMFA(c)
+	params := map[string]string{"email": s.user.Email, "password": "123456"}
+	_, err := nativeScheme.Login(context.TODO(), params)
+	c.Assert(err, check.Equals, authTypes.ErrMFARequired)
+	params["otp"] = "000000"
+	_, err = nativeScheme.Login(context.TODO(), params)
+	c.Assert(err, check.Equals, authTypes.ErrInvalidMFACode)
+	params["password"] = "wrong-password"
+	_, err = nativeScheme.Login(context.TODO(), params)
+	c.Assert(err, check.FitsTypeOf, auth.AuthenticationFailure{})
+	params["password"] = "123456"
+	params["otp"] = recoveryCodes[0]
+	token, err := nativeScheme.Login(context.TODO(), params)
+	c.Assert(err, check.IsNil)
+	c.Assert(token.GetUserName(), check.Equals, s.user.Email)
+	_, err = nativeScheme.Login(context.TODO(), params)
+	c.Assert(err, check.Equals, authTypes.ErrInvalidMFACode)
+	u, err := auth.GetUserByEmail(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.MFA.RecoveryCodes, check.HasLen, recoveryCodesCount-1)
+	c.Assert(u.MFA.Secret, check.Equals, secret)
+}
+
+func (s *S) TestDisableMFA(c *check.C) {
+	_, recoveryCodes := s.enrollMFA(c)
+	err := nativeScheme.DisableMFA(context.TODO(), s.user, "")
+	c.Assert(err, check.Equals, authTypes.ErrMFARequired)
+	err = nativeScheme.DisableMFA(context.TODO(), s.user, recoveryCodes[1])
+	c.Assert(err, check.IsNil)
+	u, err := auth.GetUserByEmail(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.MFA, check.IsNil)
+	err = nativeScheme.DisableMFA(context.TODO(), u, "")
+	c.Assert(err, check.Equals, authTypes.ErrMFANotEnrolled)
+}
+
+func (s *S) TestTokenPermissionsMFARequiredRoles(c *check.C) {
+	var err error
+	servicemanager.AuthGroup, err = auth.GroupService()
+	c.Assert(err, check.IsNil)
+	config.Set("auth:mfa:required-roles", []string{"admin"})
+	defer config.Unset("auth:mfa:required-roles")
+	role, err := permission.NewRole(context.TODO(), "admin", "global", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions(context.TODO(), "app.deploy")
+	c.Assert(err, check.IsNil)
+	err = s.user.AddRole(context.TODO(), "admin", "")
+	c.Assert(err, check.IsNil)
+	perms, err := s.token.Permissions(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(permission.CheckFromPermList(perms, permission.PermAppDeploy), check.Equals, false)
+	_, recoveryCodes := s.enrollMFA(c)
+	perms, err = s.token.Permissions(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(permission.CheckFromPermList(perms, permission.PermAppDeploy, permission.Context(permTypes.CtxApp, "myapp")), check.Equals, false)
+	apiKey, err := s.user.RegenerateAPIKey(context.TODO())
+	c.Assert(err, check.IsNil)
+	perms, err = (&auth.APIToken{Token: apiKey, UserEmail: s.user.Email}).Permissions(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(permission.CheckFromPermList(perms, permission.PermAppDeploy, permission.Context(permTypes.CtxApp, "myapp")), check.Equals, false)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": s.user.Email, "password": "123456", "otp": recoveryCodes[0]})
+	c.Assert(err, check.IsNil)
+	perms, err = token.Permissions(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(permission.CheckFromPermList(perms, permission.PermAppDeploy, permission.Context(permTypes.CtxApp, "myapp")), check.Equals, true)
+}
@@ -34,6 +34,7 @@ var (
 	_ auth.Scheme        = &NativeScheme{}
 	_ auth.UserScheme    = &NativeScheme{}
 	_ auth.ManagedScheme = &NativeScheme{}
+	_ auth.SessionScheme = &NativeScheme{}
 )
 
 func (s NativeScheme) Login(ctx context.Context, params map[string]string) (auth.Token, error) {
@@ -49,7 +50,13 @@ func (s NativeScheme) Login(ctx context.Context, params map[string]string) (auth
 	if err != nil {
 		return nil, err
 	}
-	token, err := createToken(ctx, user, password)
+	if err = checkPassword(user.Password, password); err != nil {
+		return nil, err
+	}
+	if err = s.VerifyMFA(ctx, user, params["otp"]); err != nil {
+		return nil, err
+	}
+	token, err := issueToken(ctx, user, params, user.MFAEnabled())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,105 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package native
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/log"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ListSessions returns the non-expired tokens of the user, newest first.
+func (s NativeScheme) ListSessions(ctx context.Context, email string) ([]authTypes.Session, error) {
+	tokens, err := userTokens(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	sessions := []authTypes.Session{}
+	for _, t := range tokens {
+		var expiresAt *time.Time
+		if t.Expires > 0 {
+			expiration := t.Creation.Add(t.Expires)
+			if time.Until(expiration) < 1 {
+				continue
+			}
+			expiresAt = &expiration
+		}
+		session := authTypes.Session{
+			ID:        auth.SessionID(t.Token),
+			Engine:    t.Engine(),
+			CreatedAt: t.Creation,
+			ExpiresAt: expiresAt,
+			ClientIP:  t.ClientIP,
+			UserAgent: t.UserAgent,
+		}
+		if !t.LastUsed.IsZero() {
+			lastUsed := t.LastUsed
+			session.LastUsed = &lastUsed
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// RevokeSession removes the token of the user identified by the session id.
+func (s NativeScheme) RevokeSession(ctx context.Context, email, id string) error {
+	tokens, err := userTokens(ctx, email)
+	if err != nil {
+		return err
+	}
+	for _, t := range tokens {
+		if auth.SessionID(t.Token) == id {
+			return deleteToken(ctx, t.Token)
+		}
+	}
+	return authTypes.ErrSessionNotFound
+}
+
+// RevokeAllSessions removes every token of the user.
+func (s NativeScheme) RevokeAllSessions(ctx context.Context, email string) error {
+	return deleteAllTokens(ctx, email)
+}
+
+func userTokens(ctx context.Context, email string) ([]Token, error) {
+	collection, err := storagev2.TokensCollection()
+	if err != nil {
+		return nil, err
+	}
+	opts := options.Find().SetSort(mongoBSON.M{"creation": -1})
+	cursor, err := collection.Find(ctx, mongoBSON.M{"useremail": email}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var tokens []Token
+	err = cursor.All(ctx, &tokens)
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func touchToken(ctx context.Context, t *Token) {
+	now := time.Now()
+	if now.Sub(t.LastUsed) < auth.SessionLastUsedInterval {
+		return
+	}
+	collection, err := storagev2.TokensCollection()
+	if err != nil {
+		log.Errorf("unable to update token last usage: %v", err)
+		return
+	}
+	_, err = collection.UpdateOne(ctx, mongoBSON.M{"token": t.Token}, mongoBSON.M{"$set": mongoBSON.M{"lastused": now}})
+	if err != nil {
+		log.Errorf("unable to update token last usage: %v", err)
+		return
+	}
+	t.LastUsed = now
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package native
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/db/storagev2"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestListSessions(c *check.C) {
+	ctx := context.TODO()
+	token, err := nativeScheme.Login(ctx, map[string]string{
+		"email":                    s.user.Email,
+		"password":                 "123456",
+		auth.SessionClientIPParam:  "10.0.0.1",
+		auth.SessionUserAgentParam: "tsuru-client/1.0",
+	})
+	c.Assert(err, check.IsNil)
+	sessions, err := nativeScheme.ListSessions(ctx, s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 2)
+	session := sessions[0]
+	c.Assert(session.ID, check.Equals, auth.SessionID(token.GetValue()))
+	c.Assert(session.Engine, check.Equals, "native")
+	c.Assert(session.ClientIP, check.Equals, "10.0.0.1")
+	c.Assert(session.UserAgent, check.Equals, "tsuru-client/1.0")
+	c.Assert(session.ExpiresAt, check.NotNil)
+	c.Assert(session.LastUsed, check.IsNil)
+	c.Assert(sessions[1].ID, check.Equals, auth.SessionID(s.token.GetValue()))
+}
+
+func (s *S) TestListSessionsIgnoresExpiredTokens(c *check.C) {
+	ctx := context.TODO()
+	tokensCollection, err := storagev2.TokensCollection()
+	c.Assert(err, check.IsNil)
+	_, err = tokensCollection.InsertOne(ctx, Token{
+		Token:     "expired-token",
+		Creation:  time.Now().Add(-2 * time.Hour),
+		Expires:   time.Hour,
+		UserEmail: s.user.Email,
+	})
+	c.Assert(err, check.IsNil)
+	sessions, err := nativeScheme.ListSessions(ctx, s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 1)
+	c.Assert(sessions[0].ID, check.Equals, auth.SessionID(s.token.GetValue()))
+}
+
+func (s *S) TestAuthUpdatesSessionLastUsed(c *check.C) {
+	ctx := context.TODO()
+	_, err := nativeScheme.Auth(ctx, "bearer "+s.token.GetValue())
+	c.Assert(err, check.IsNil)
+	tokensCollection, err := storagev2.TokensCollection()
+	c.Assert(err, check.IsNil)
+	var t Token
+	err = tokensCollection.FindOne(ctx, mongoBSON.M{"token": s.token.GetValue()}).Decode(&t)
+	c.Assert(err, check.IsNil)
+	c.Assert(time.Since(t.LastUsed) < time.Minute, check.Equals, true)
+}
+
+func (s *S) TestRevokeSession(c *check.C) {
+	ctx := context.TODO()
+	token, err := nativeScheme.Login(ctx, map[string]string{"email": s.user.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	err = nativeScheme.RevokeSession(ctx, s.user.Email, auth.SessionID(token.GetValue()))
+	c.Assert(err, check.IsNil)
+	_, err = nativeScheme.Auth(ctx, "bearer "+token.GetValue())
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+	_, err = nativeScheme.Auth(ctx, "bearer "+s.token.GetValue())
+	c.Assert(err, check.IsNil)
+	err = nativeScheme.RevokeSession(ctx, s.user.Email, auth.SessionID(token.GetValue()))
+	c.Assert(err, check.Equals, authTypes.ErrSessionNotFound)
+}
+
+func (s *S) TestRevokeSessionFromOtherUser(c *check.C) {
+	ctx := context.TODO()
+	err := nativeScheme.RevokeSession(ctx, "other@tsuru.io", auth.SessionID(s.token.GetValue()))
+	c.Assert(err, check.Equals, authTypes.ErrSessionNotFound)
+	_, err = nativeScheme.Auth(ctx, "bearer "+s.token.GetValue())
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestRevokeAllSessions(c *check.C) {
+	ctx := context.TODO()
+	_, err := nativeScheme.Login(ctx, map[string]string{"email": s.user.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	err = nativeScheme.RevokeAllSessions(ctx, s.user.Email)
+	c.Assert(err, check.IsNil)
+	sessions, err := nativeScheme.ListSessions(ctx, s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 0)
+}
@@ -43,6 +43,12 @@ type Token struct {
 	Creation  time.Time     `json:"creation"`
 	Expires   time.Duration `json:"expires"`
 	UserEmail string        `json:"email"`
+	LastUsed  time.Time     `json:"lastused"`
+	ClientIP  string        `json:"clientip"`
+	UserAgent string        `json:"useragent"`
+	// MFAVerified is set for tokens issued after a successful multi-factor
+	// authentication.
+	MFAVerified bool `json:"mfaverified"`
 }
 
 func (t *Token) GetValue() string {
@@ -60,6 +66,10 @@ func (t *Token) GetUserName() string {
 func (t *Token) Engine() string {
 	return "native"
 }
+func (t *Token) IsMFAVerified() bool {
+	return t.MFAVerified
+}
+
 func (t *Token) Permissions(ctx context.Context) ([]permTypes.Permission, error) {
 	return auth.BaseTokenPermission(ctx, t)
 }
@@ -177,6 +187,10 @@ func createToken(ctx context.Context, u *auth.User, password string) (*Token, er
 	if err := checkPassword(u.Password, password); err != nil {
 		return nil, err
 	}
+	return issueToken(ctx, u, nil, false)
+}
+
+func issueToken(ctx context.Context, u *auth.User, params map[string]string, mfaVerified bool) (*Token, error) {
 	collection, err := storagev2.TokensCollection()
 	if err != nil {
 		return nil, err
@@ -185,6 +199,9 @@ func createToken(ctx context.Context, u *auth.User, password string) (*Token, er
 	if err != nil {
 		return nil, err
 	}
+	token.ClientIP = params[auth.SessionClientIPParam]
+	token.UserAgent = params[auth.SessionUserAgentParam]
+	token.MFAVerified = mfaVerified
 	_, err = collection.InsertOne(ctx, token)
 	go removeOldTokens(context.WithoutCancel(ctx), u.Email)
 	return token, err
@@ -210,6 +227,7 @@ func getToken(ctx context.Context, header string) (*Token, error) {
 	if t.Expires > 0 && time.Until(t.Creation.Add(t.Expires)) < 1 {
 		return nil, auth.ErrInvalidToken
 	}
+	touchToken(ctx, &t)
 	return &t, nil
 }
 
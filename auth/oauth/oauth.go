@@ -39,8 +39,9 @@ var (
 		Help: "The total number of oauth request errors.",
 	})
 
-	_ auth.Scheme     = &oAuthScheme{}
-	_ auth.UserScheme = &oAuthScheme{}
+	_ auth.Scheme        = &oAuthScheme{}
+	_ auth.UserScheme    = &oAuthScheme{}
+	_ auth.SessionScheme = &oAuthScheme{}
 )
 
 type oAuthScheme struct {
@@ -118,10 +119,10 @@ func (s *oAuthScheme) Login(ctx context.Context, params map[string]string) (auth
 	if err != nil {
 		return nil, err
 	}
-	return s.handleToken(ctx, oauthToken)
+	return s.handleToken(ctx, oauthToken, params)
 }
 
-func (s *oAuthScheme) handleToken(ctx context.Context, t *oauth2.Token) (*tokenWrapper, error) {
+func (s *oAuthScheme) handleToken(ctx context.Context, t *oauth2.Token, params map[string]string) (*tokenWrapper, error) {
 	if t.AccessToken == "" {
 		return nil, ErrEmptyAccessToken
 	}
@@ -177,7 +178,12 @@ func (s *oAuthScheme) handleToken(ctx context.Context, t *oauth2.Token) (*tokenW
 	if err != nil {
 		return nil, err
 	}
-	token := tokenWrapper{Token: *t, UserEmail: user.Email}
+	token := tokenWrapper{
+		Token:     *t,
+		UserEmail: user.Email,
+		ClientIP:  params[auth.SessionClientIPParam],
+		UserAgent: params[auth.SessionUserAgentParam],
+	}
 	err = token.save(ctx)
 	if err != nil {
 		return nil, err
@@ -197,9 +203,57 @@ func (s *oAuthScheme) Auth(ctx context.Context, header string) (auth.Token, erro
 	if !token.Token.Valid() {
 		return token, auth.ErrInvalidToken
 	}
+	token.touch(ctx)
 	return token, nil
 }
 
+func (s *oAuthScheme) ListSessions(ctx context.Context, email string) ([]authTypes.Session, error) {
+	tokens, err := userTokens(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	sessions := []authTypes.Session{}
+	for _, t := range tokens {
+		if !t.Token.Valid() {
+			continue
+		}
+		session := authTypes.Session{
+			ID:        auth.SessionID(t.AccessToken),
+			Engine:    t.Engine(),
+			CreatedAt: t.Creation,
+			ClientIP:  t.ClientIP,
+			UserAgent: t.UserAgent,
+		}
+		if !t.Expiry.IsZero() {
+			expiry := t.Expiry
+			session.ExpiresAt = &expiry
+		}
+		if !t.LastUsed.IsZero() {
+			lastUsed := t.LastUsed
+			session.LastUsed = &lastUsed
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (s *oAuthScheme) RevokeSession(ctx context.Context, email, id string) error {
+	tokens, err := userTokens(ctx, email)
+	if err != nil {
+		return err
+	}
+	for _, t := range tokens {
+		if auth.SessionID(t.AccessToken) == id {
+			return deleteToken(ctx, t.AccessToken)
+		}
+	}
+	return authTypes.ErrSessionNotFound
+}
+
+func (s *oAuthScheme) RevokeAllSessions(ctx context.Context, email string) error {
+	return deleteAllTokens(ctx, email)
+}
+
 func (s *oAuthScheme) Info(ctx context.Context) (*authTypes.SchemeInfo, error) {
 	config, err := s.loadConfig()
 	if err != nil {
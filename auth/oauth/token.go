@@ -6,13 +6,16 @@ package oauth
 
 import (
 	"context"
+	"time"
 
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/log"
 	authTypes "github.com/tsuru/tsuru/types/auth"
 	permTypes "github.com/tsuru/tsuru/types/permission"
 	mongoBSON "go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/oauth2"
 )
 
@@ -20,7 +23,11 @@ var _ authTypes.Token = &tokenWrapper{}
 
 type tokenWrapper struct {
 	oauth2.Token
-	UserEmail string `json:"email"`
+	UserEmail string    `json:"email"`
+	Creation  time.Time `json:"creation"`
+	LastUsed  time.Time `json:"lastused"`
+	ClientIP  string    `json:"clientip"`
+	UserAgent string    `json:"useragent"`
 }
 
 func (t *tokenWrapper) GetValue() string {
@@ -92,6 +99,45 @@ func (t *tokenWrapper) save(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if t.Creation.IsZero() {
+		t.Creation = time.Now()
+	}
 	_, err = collection.InsertOne(ctx, t)
 	return err
 }
+
+func userTokens(ctx context.Context, email string) ([]tokenWrapper, error) {
+	collection, err := storagev2.OAuth2TokensCollection()
+	if err != nil {
+		return nil, err
+	}
+	opts := options.Find().SetSort(mongoBSON.M{"creation": -1})
+	cursor, err := collection.Find(ctx, mongoBSON.M{"useremail": email}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var tokens []tokenWrapper
+	err = cursor.All(ctx, &tokens)
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (t *tokenWrapper) touch(ctx context.Context) {
+	now := time.Now()
+	if now.Sub(t.LastUsed) < auth.SessionLastUsedInterval {
+		return
+	}
+	collection, err := storagev2.OAuth2TokensCollection()
+	if err != nil {
+		log.Errorf("unable to update token last usage: %v", err)
+		return
+	}
+	_, err = collection.UpdateOne(ctx, mongoBSON.M{"token.accesstoken": t.AccessToken}, mongoBSON.M{"$set": mongoBSON.M{"lastused": now}})
+	if err != nil {
+		log.Errorf("unable to update token last usage: %v", err)
+		return
+	}
+	t.LastUsed = now
+}
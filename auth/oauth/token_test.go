@@ -6,9 +6,11 @@ package oauth
 
 import (
 	"context"
+	"time"
 
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/db/storagev2"
+	authTypes "github.com/tsuru/tsuru/types/auth"
 	mongoBSON "go.mongodb.org/mongo-driver/bson"
 	"golang.org/x/oauth2"
 	check "gopkg.in/check.v1"
@@ -87,3 +89,42 @@ func (s *S) TestDelete(c *check.C) {
 
 	c.Assert(tokens, check.HasLen, 0)
 }
+
+func (s *S) TestListSessions(c *check.C) {
+	existing := tokenWrapper{
+		Token:     oauth2.Token{AccessToken: "myvalidtoken"},
+		UserEmail: "x@x.com",
+		ClientIP:  "10.0.0.1",
+		UserAgent: "tsuru-client/1.0",
+	}
+	err := existing.save(context.TODO())
+	c.Assert(err, check.IsNil)
+	expired := tokenWrapper{
+		Token:     oauth2.Token{AccessToken: "myexpiredtoken", Expiry: time.Now().Add(-time.Minute)},
+		UserEmail: "x@x.com",
+	}
+	err = expired.save(context.TODO())
+	c.Assert(err, check.IsNil)
+	scheme := oAuthScheme{}
+	sessions, err := scheme.ListSessions(context.TODO(), "x@x.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 1)
+	c.Assert(sessions[0].ID, check.Equals, auth.SessionID("myvalidtoken"))
+	c.Assert(sessions[0].Engine, check.Equals, "oauth")
+	c.Assert(sessions[0].ClientIP, check.Equals, "10.0.0.1")
+	c.Assert(sessions[0].UserAgent, check.Equals, "tsuru-client/1.0")
+	c.Assert(sessions[0].CreatedAt.IsZero(), check.Equals, false)
+}
+
+func (s *S) TestRevokeSession(c *check.C) {
+	existing := tokenWrapper{Token: oauth2.Token{AccessToken: "myvalidtoken"}, UserEmail: "x@x.com"}
+	err := existing.save(context.TODO())
+	c.Assert(err, check.IsNil)
+	scheme := oAuthScheme{}
+	err = scheme.RevokeSession(context.TODO(), "other@x.com", auth.SessionID("myvalidtoken"))
+	c.Assert(err, check.Equals, authTypes.ErrSessionNotFound)
+	err = scheme.RevokeSession(context.TODO(), "x@x.com", auth.SessionID("myvalidtoken"))
+	c.Assert(err, check.IsNil)
+	_, err = getToken(context.TODO(), "bearer myvalidtoken")
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+}
@@ -32,3 +32,25 @@ func TeamQuotaService() (quotaTypes.QuotaService[*authTypes.Team], error) {
 	}
 	return &quota.QuotaService[*authTypes.Team]{Storage: dbDriver.TeamQuotaStorage}, nil
 }
+
+func TeamJobQuotaService() (quotaTypes.QuotaService[*authTypes.Team], error) {
+	dbDriver, err := storage.GetCurrentDbDriver()
+	if err != nil {
+		dbDriver, err = storage.GetDefaultDbDriver()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &quota.QuotaService[*authTypes.Team]{Storage: dbDriver.TeamJobQuotaStorage}, nil
+}
+
+func TeamJobRunQuotaService() (quotaTypes.QuotaService[*authTypes.Team], error) {
+	dbDriver, err := storage.GetCurrentDbDriver()
+	if err != nil {
+		dbDriver, err = storage.GetDefaultDbDriver()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &quota.QuotaService[*authTypes.Team]{Storage: dbDriver.TeamJobRunQuotaStorage}, nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package rolesweeper periodically removes expired just-in-time role
+// assignments from users.
+package rolesweeper
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+)
+
+const (
+	defaultRunInterval = time.Minute
+	roleExpireKind     = "role-expire"
+)
+
+func Initialize() error {
+	interval := defaultRunInterval
+	if seconds, err := config.GetInt("auth:role-expiration:interval"); err == nil && seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	}
+	s := &sweeper{once: &sync.Once{}, interval: interval}
+	s.start()
+	shutdown.Register(s)
+	return nil
+}
+
+type sweeper struct {
+	once     *sync.Once
+	stopCh   chan struct{}
+	interval time.Duration
+}
+
+func (s *sweeper) start() {
+	s.once.Do(func() {
+		s.stopCh = make(chan struct{})
+		go s.spin()
+	})
+}
+
+func (s *sweeper) Shutdown(ctx context.Context) error {
+	if s.stopCh == nil {
+		return nil
+	}
+	s.stopCh <- struct{}{}
+	s.stopCh = nil
+	s.once = &sync.Once{}
+	return nil
+}
+
+func (s *sweeper) spin() {
+	for {
+		if err := sweep(context.Background(), time.Now()); err != nil {
+			log.Errorf("[role sweeper] %v", err)
+		}
+
+		select {
+		case <-s.stopCh:
+			return
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+func sweep(ctx context.Context, now time.Time) error {
+	removed, err := auth.RemoveExpiredRoles(ctx, now)
+	for _, expired := range removed {
+		if evtErr := recordExpiration(ctx, expired); evtErr != nil {
+			log.Errorf("[role sweeper] unable to record expiration of role %q for user %q: %v", expired.Role.Name, expired.Email, evtErr)
+		}
+	}
+	return errors.Wrap(err, "unable to remove expired roles")
+}
+
+func recordExpiration(ctx context.Context, expired auth.ExpiredRole) error {
+	evt, err := event.NewInternal(ctx, &event.Opts{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeRole, Value: expired.Role.Name},
+		ExtraTargets: []eventTypes.ExtraTarget{{Target: eventTypes.Target{Type: eventTypes.TargetTypeUser, Value: expired.Email}}},
+		InternalKind: roleExpireKind,
+		CustomData: map[string]interface{}{
+			"email":         expired.Email,
+			"context":       expired.Role.ContextValue,
+			"expiresAt":     expired.Role.ExpiresAt,
+			"justification": expired.Role.Justification,
+		},
+		Allowed:     event.Allowed(permission.PermRoleReadEvents),
+		DisableLock: true,
+	})
+	if err != nil {
+		return err
+	}
+	return evt.Done(ctx, nil)
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rolesweeper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	_ "github.com/tsuru/tsuru/storage/mongodb"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	"golang.org/x/crypto/bcrypt"
+	check "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type S struct{}
+
+var _ = check.Suite(&S{})
+
+func (s *S) SetUpSuite(c *check.C) {
+	config.Set("log:disable-syslog", true)
+	config.Set("auth:hash-cost", bcrypt.MinCost)
+	config.Set("database:url", "127.0.0.1:27017?maxPoolSize=100")
+	config.Set("database:name", "tsuru_auth_rolesweeper_test")
+	storagev2.Reset()
+	var err error
+	servicemanager.AuthGroup, err = auth.GroupService()
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) SetUpTest(c *check.C) {
+	err := storagev2.ClearAllCollections(nil)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TearDownSuite(c *check.C) {
+	storagev2.ClearAllCollections(nil)
+}
+
+func (s *S) TestSweep(c *check.C) {
+	ctx := context.TODO()
+	_, err := permission.NewRole(ctx, "oncall", "pool", "")
+	c.Assert(err, check.IsNil)
+	u := auth.User{Email: "me@tsuru.com", Password: "123456"}
+	err = u.Create(ctx)
+	c.Assert(err, check.IsNil)
+	now := time.Now()
+	err = u.AddRoleWithExpiration(ctx, "oncall", "prod", now.Add(-time.Minute), "incident")
+	c.Assert(err, check.IsNil)
+	err = u.AddRoleWithExpiration(ctx, "oncall", "staging", now.Add(time.Hour), "incident")
+	c.Assert(err, check.IsNil)
+	err = sweep(ctx, now)
+	c.Assert(err, check.IsNil)
+	dbUser, err := auth.GetUserByEmail(ctx, u.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.Roles, check.HasLen, 1)
+	c.Assert(dbUser.Roles[0].ContextValue, check.Equals, "staging")
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeRole, Value: "oncall"},
+		ExtraTargets: []eventTypes.ExtraTarget{
+			{Target: eventTypes.Target{Type: eventTypes.TargetTypeUser, Value: u.Email}},
+		},
+		Kind: roleExpireKind,
+	}, eventtest.HasEvent)
+}
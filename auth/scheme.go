@@ -36,6 +36,24 @@ type ManagedScheme interface {
 	ChangePassword(ctx context.Context, token Token, oldPassword string, newPassword string) error
 }
 
+// MFAScheme is implemented by schemes supporting multi-factor
+// authentication with time-based one-time passwords.
+type MFAScheme interface {
+	EnrollMFA(ctx context.Context, user *User, password string) (*authTypes.MFAEnrollment, error)
+	ConfirmMFA(ctx context.Context, user *User, code string) ([]string, error)
+	DisableMFA(ctx context.Context, user *User, code string) error
+	VerifyMFA(ctx context.Context, user *User, code string) error
+	MFARequiredRoles() []string
+}
+
+// SessionScheme is implemented by schemes storing login tokens, allowing
+// them to be listed and revoked individually.
+type SessionScheme interface {
+	ListSessions(ctx context.Context, email string) ([]authTypes.Session, error)
+	RevokeSession(ctx context.Context, email, id string) error
+	RevokeAllSessions(ctx context.Context, email string) error
+}
+
 type AuthenticationFailure struct {
 	Message string
 }
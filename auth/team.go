@@ -49,7 +49,15 @@ func (t *teamService) Create(ctx context.Context, name string, tags []string, us
 	if user == nil {
 		return errors.New("user cannot be null")
 	}
-	q, err := startingAppQuota()
+	q, err := startingTeamQuota("quota:apps-per-team")
+	if err != nil {
+		return err
+	}
+	jobQuota, err := startingTeamQuota("quota:jobs-per-team")
+	if err != nil {
+		return err
+	}
+	jobRunQuota, err := startingTeamQuota("quota:job-runs-per-team")
 	if err != nil {
 		return err
 	}
@@ -58,6 +66,8 @@ func (t *teamService) Create(ctx context.Context, name string, tags []string, us
 		CreatingUser: user.Email,
 		Tags:         processTags(tags),
 		Quota:        q,
+		JobQuota:     jobQuota,
+		JobRunQuota:  jobRunQuota,
 	}
 	if err = t.validate(team); err != nil {
 		return err
@@ -178,9 +188,9 @@ func processTags(tags []string) []string {
 	return processedTags
 }
 
-func startingAppQuota() (quota.Quota, error) {
-	limit, err := config.GetInt("quota:apps-per-team")
-	if errors.Is(err, config.ErrKeyNotFound{Key: "quota:apps-per-team"}) {
+func startingTeamQuota(key string) (quota.Quota, error) {
+	limit, err := config.GetInt(key)
+	if errors.Is(err, config.ErrKeyNotFound{Key: key}) {
 		return quota.UnlimitedQuota, nil // no quota defined in tsurud.yaml, returning unlimited quota
 	}
 	if err != nil {
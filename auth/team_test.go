@@ -7,8 +7,10 @@ package auth
 import (
 	"context"
 
+	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/db/storagev2"
 	authTypes "github.com/tsuru/tsuru/types/auth"
+	"github.com/tsuru/tsuru/types/quota"
 	mongoBSON "go.mongodb.org/mongo-driver/bson"
 	check "gopkg.in/check.v1"
 )
@@ -32,6 +34,24 @@ func (s *S) TestTeamServiceCreate(c *check.C) {
 	c.Assert(err, check.IsNil)
 }
 
+func (s *S) TestTeamServiceCreateWithJobQuotas(c *check.C) {
+	config.Set("quota:jobs-per-team", 10)
+	defer config.Unset("quota:jobs-per-team")
+	one := authTypes.User{Email: "king@pos.com"}
+	ts := &teamService{
+		storage: &authTypes.MockTeamStorage{
+			OnInsert: func(t authTypes.Team) error {
+				c.Assert(t.Quota, check.DeepEquals, quota.UnlimitedQuota)
+				c.Assert(t.JobQuota, check.DeepEquals, quota.Quota{Limit: 10})
+				c.Assert(t.JobRunQuota, check.DeepEquals, quota.UnlimitedQuota)
+				return nil
+			},
+		},
+	}
+	err := ts.Create(context.TODO(), "pos", nil, &one)
+	c.Assert(err, check.IsNil)
+}
+
 func (s *S) TestTeamServiceUpdate(c *check.C) {
 	teamName := "pos"
 	tags := []string{"tag1", "tag1 ", "tag2"}
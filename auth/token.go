@@ -6,9 +6,13 @@ package auth
 
 import (
 	"context"
+	"crypto/sha256"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/tsuru/config"
 	authTypes "github.com/tsuru/tsuru/types/auth"
 	permTypes "github.com/tsuru/tsuru/types/permission"
 )
@@ -39,6 +43,23 @@ var (
 	ErrUserDisabled = errors.New("Disabled user")
 )
 
+const (
+	// Login params filled by the API with information about the client
+	// creating the session, they're never taken from user input.
+	SessionClientIPParam  = "session-client-ip"
+	SessionUserAgentParam = "session-user-agent"
+
+	// SessionLastUsedInterval is the minimum interval between updates of
+	// the last time a session was used, avoiding a write on every request.
+	SessionLastUsedInterval = time.Minute
+)
+
+// SessionID returns the public identifier of the session represented by
+// the token value.
+func SessionID(token string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(token)))[:16]
+}
+
 // ParseToken extracts token from a header:
 // 'type token' or 'token'
 func ParseToken(header string) (string, error) {
@@ -53,10 +74,30 @@ func ParseToken(header string) (string, error) {
 	return value, ErrInvalidToken
 }
 
+// MFAToken is implemented by tokens which may be issued after a
+// multi-factor authentication.
+type MFAToken interface {
+	IsMFAVerified() bool
+}
+
+// MFARequiredRoles returns the roles which are only effective for tokens
+// issued after a multi-factor authentication.
+func MFARequiredRoles() []string {
+	roles, _ := config.GetList("auth:mfa:required-roles")
+	return roles
+}
+
 func BaseTokenPermission(ctx context.Context, t Token) ([]permTypes.Permission, error) {
 	u, err := ConvertNewUser(t.User(ctx))
 	if err != nil {
 		return nil, err
 	}
-	return u.Permissions(ctx)
+	requiredRoles := MFARequiredRoles()
+	if len(requiredRoles) == 0 {
+		return u.Permissions(ctx)
+	}
+	if mfaToken, ok := t.(MFAToken); ok && mfaToken.IsMFAVerified() && u.MFAEnabled() {
+		return u.Permissions(ctx)
+	}
+	return u.PermissionsWithoutRoles(ctx, requiredRoles...)
 }
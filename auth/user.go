@@ -19,6 +19,7 @@ import (
 	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/servicemanager"
+	"github.com/tsuru/tsuru/set"
 	authTypes "github.com/tsuru/tsuru/types/auth"
 	permTypes "github.com/tsuru/tsuru/types/permission"
 	"github.com/tsuru/tsuru/types/quota"
@@ -38,6 +39,12 @@ type User struct {
 
 	APIKeyLastAccess   time.Time `bson:"apikey_last_access"`
 	APIKeyUsageCounter int64     `bson:"apikey_usage_counter"`
+
+	MFA *authTypes.MFA `json:"-" bson:",omitempty"`
+}
+
+func (u *User) MFAEnabled() bool {
+	return u.MFA != nil && u.MFA.Enabled
 }
 
 func listUsers(ctx context.Context, filter mongoBSON.M) ([]User, error) {
@@ -172,7 +179,11 @@ func (u *User) reload(ctx context.Context) error {
 func expandRolePermissions(ctx context.Context, roleInstances []authTypes.RoleInstance) ([]permTypes.Permission, error) {
 	var permissions []permTypes.Permission
 	roles := make(map[string]*permission.Role)
+	now := time.Now()
 	for _, roleData := range roleInstances {
+		if roleData.Expired(now) {
+			continue
+		}
 		role := roles[roleData.Name]
 		if role == nil {
 			foundRole, err := permission.FindRole(ctx, roleData.Name)
@@ -200,6 +211,12 @@ func (u *User) UserGroups() ([]authTypes.Group, error) {
 }
 
 func (u *User) Permissions(ctx context.Context) ([]permTypes.Permission, error) {
+	return u.PermissionsWithoutRoles(ctx)
+}
+
+// PermissionsWithoutRoles works like Permissions, ignoring every instance
+// of the given roles, either assigned directly or through groups.
+func (u *User) PermissionsWithoutRoles(ctx context.Context, excludedRoles ...string) ([]permTypes.Permission, error) {
 	groups, err := u.UserGroups()
 	if err != nil {
 		return nil, err
@@ -208,6 +225,16 @@ func (u *User) Permissions(ctx context.Context) ([]permTypes.Permission, error)
 	for _, group := range groups {
 		allRoles = append(allRoles, group.Roles...)
 	}
+	if len(excludedRoles) > 0 {
+		excluded := set.FromSlice(excludedRoles)
+		filteredRoles := make([]authTypes.RoleInstance, 0, len(allRoles))
+		for _, roleInstance := range allRoles {
+			if !excluded.Includes(roleInstance.Name) {
+				filteredRoles = append(filteredRoles, roleInstance)
+			}
+		}
+		allRoles = filteredRoles
+	}
 	permissions, err := expandRolePermissions(ctx, allRoles)
 	if err != nil {
 		return nil, err
@@ -243,6 +270,89 @@ func (u *User) AddRole(ctx context.Context, roleName string, contextValue string
 	return u.reload(ctx)
 }
 
+// AddRoleWithExpiration adds a just-in-time role instance to the user, which
+// is valid only until expiresAt. A previous temporary instance of the same
+// role and context value is replaced.
+func (u *User) AddRoleWithExpiration(ctx context.Context, roleName, contextValue string, expiresAt time.Time, justification string) error {
+	_, err := permission.FindRole(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	usersCollection, err := storagev2.UsersCollection()
+	if err != nil {
+		return err
+	}
+	_, err = usersCollection.UpdateOne(ctx, mongoBSON.M{"email": u.Email}, mongoBSON.M{
+		"$pull": mongoBSON.M{
+			"roles": mongoBSON.M{
+				"name":         roleName,
+				"contextvalue": contextValue,
+				"expiresat":    mongoBSON.M{"$exists": true},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = usersCollection.UpdateOne(ctx, mongoBSON.M{"email": u.Email}, mongoBSON.M{
+		"$push": mongoBSON.M{
+			"roles": mongoBSON.D([]mongoBSON.E{
+				{Key: "name", Value: roleName},
+				{Key: "contextvalue", Value: contextValue},
+				{Key: "expiresat", Value: expiresAt},
+				{Key: "justification", Value: justification},
+			}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return u.reload(ctx)
+}
+
+// ExpiredRole is a role instance removed from a user due to its expiration.
+type ExpiredRole struct {
+	Email string
+	Role  authTypes.RoleInstance
+}
+
+// RemoveExpiredRoles removes from all users the role instances expired at
+// the given time, returning the ones effectively removed by this call.
+func RemoveExpiredRoles(ctx context.Context, now time.Time) ([]ExpiredRole, error) {
+	users, err := listUsers(ctx, mongoBSON.M{"roles.expiresat": mongoBSON.M{"$lte": now}})
+	if err != nil {
+		return nil, err
+	}
+	usersCollection, err := storagev2.UsersCollection()
+	if err != nil {
+		return nil, err
+	}
+	var removed []ExpiredRole
+	for _, u := range users {
+		for _, role := range u.Roles {
+			if !role.Expired(now) {
+				continue
+			}
+			result, err := usersCollection.UpdateOne(ctx, mongoBSON.M{"email": u.Email}, mongoBSON.M{
+				"$pull": mongoBSON.M{
+					"roles": mongoBSON.M{
+						"name":         role.Name,
+						"contextvalue": role.ContextValue,
+						"expiresat":    role.ExpiresAt,
+					},
+				},
+			})
+			if err != nil {
+				return removed, err
+			}
+			if result.ModifiedCount > 0 {
+				removed = append(removed, ExpiredRole{Email: u.Email, Role: role})
+			}
+		}
+	}
+	return removed, nil
+}
+
 func UpdateRoleFromAllUsers(ctx context.Context, roleName, newRoleName, permissionCtx, desc string) error {
 	role, err := permission.FindRole(ctx, roleName)
 	if err != nil {
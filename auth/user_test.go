@@ -7,6 +7,7 @@ package auth
 import (
 	"context"
 	"sort"
+	"time"
 
 	"github.com/tsuru/tsuru/db/storagev2"
 	"github.com/tsuru/tsuru/errors"
@@ -166,6 +167,67 @@ func (s *S) TestUserAddRole(c *check.C) {
 	c.Assert(uDB.Roles, check.DeepEquals, expected)
 }
 
+func (s *S) TestUserAddRoleWithExpiration(c *check.C) {
+	_, err := permission.NewRole(context.TODO(), "r1", "app", "")
+	c.Assert(err, check.IsNil)
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err = u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	err = u.AddRole(context.TODO(), "r1", "c1")
+	c.Assert(err, check.IsNil)
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Millisecond)
+	err = u.AddRoleWithExpiration(context.TODO(), "r1", "c1", expiresAt.Add(-time.Minute), "first")
+	c.Assert(err, check.IsNil)
+	err = u.AddRoleWithExpiration(context.TODO(), "r1", "c1", expiresAt, "on-call")
+	c.Assert(err, check.IsNil)
+	err = u.AddRoleWithExpiration(context.TODO(), "r2", "c1", expiresAt, "on-call")
+	c.Assert(err, check.Equals, permTypes.ErrRoleNotFound)
+	uDB, err := GetUserByEmail(context.TODO(), "me@tsuru.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(uDB.Roles, check.HasLen, 2)
+	c.Assert(uDB.Roles[0], check.DeepEquals, authTypes.RoleInstance{Name: "r1", ContextValue: "c1"})
+	c.Assert(uDB.Roles[1].ExpiresAt, check.NotNil)
+	c.Assert(uDB.Roles[1].ExpiresAt.Equal(expiresAt), check.Equals, true)
+	c.Assert(uDB.Roles[1].Justification, check.Equals, "on-call")
+}
+
+func (s *S) TestRemoveExpiredRoles(c *check.C) {
+	role, err := permission.NewRole(context.TODO(), "r1", "app", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions(context.TODO(), "app.deploy")
+	c.Assert(err, check.IsNil)
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err = u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	now := time.Now()
+	err = u.AddRole(context.TODO(), "r1", "permanent")
+	c.Assert(err, check.IsNil)
+	err = u.AddRoleWithExpiration(context.TODO(), "r1", "past", now.Add(-time.Minute), "old")
+	c.Assert(err, check.IsNil)
+	err = u.AddRoleWithExpiration(context.TODO(), "r1", "future", now.Add(time.Hour), "new")
+	c.Assert(err, check.IsNil)
+	perms, err := u.Permissions(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(perms, check.HasLen, 3)
+	for _, p := range perms {
+		c.Assert(p.Context.Value, check.Not(check.Equals), "past")
+	}
+	removed, err := RemoveExpiredRoles(context.TODO(), now)
+	c.Assert(err, check.IsNil)
+	c.Assert(removed, check.HasLen, 1)
+	c.Assert(removed[0].Email, check.Equals, "me@tsuru.com")
+	c.Assert(removed[0].Role.ContextValue, check.Equals, "past")
+	c.Assert(removed[0].Role.Justification, check.Equals, "old")
+	removed, err = RemoveExpiredRoles(context.TODO(), now)
+	c.Assert(err, check.IsNil)
+	c.Assert(removed, check.HasLen, 0)
+	uDB, err := GetUserByEmail(context.TODO(), "me@tsuru.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(uDB.Roles, check.HasLen, 2)
+	c.Assert(uDB.Roles[0].ContextValue, check.Equals, "permanent")
+	c.Assert(uDB.Roles[1].ContextValue, check.Equals, "future")
+}
+
 func (s *S) TestUserRemoveRole(c *check.C) {
 	u := User{
 		Email:    "me@tsuru.com",
@@ -14,6 +14,7 @@ import (
 	"github.com/tsuru/gnuflag"
 	"github.com/tsuru/tablecli"
 	"github.com/tsuru/tsuru/cmd"
+	"github.com/tsuru/tsuru/job"
 	"github.com/tsuru/tsuru/migration"
 	"github.com/tsuru/tsuru/provision"
 	kubeMigrate "github.com/tsuru/tsuru/provision/kubernetes/migrate"
@@ -33,6 +34,10 @@ func init() {
 	if err != nil {
 		log.Fatalf("unable to register migration: %s", err)
 	}
+	err = migration.Register("migrate-team-job-quota", job.MigrateTeamJobQuota)
+	if err != nil {
+		log.Fatalf("unable to register migration: %s", err)
+	}
 }
 
 func getProvisioner() (string, error) {
@@ -36,6 +36,10 @@ func ServiceInstancesCollection() (*mongo.Collection, error) {
 	return Collection("service_instances")
 }
 
+func ServiceInstanceStatusCollection() (*mongo.Collection, error) {
+	return Collection("service_instance_status")
+}
+
 func RolesCollection() (*mongo.Collection, error) {
 	return Collection("roles")
 }
@@ -48,6 +52,26 @@ func JobsCollection() (*mongo.Collection, error) {
 	return Collection("jobs")
 }
 
+func JobRunsCollection() (*mongo.Collection, error) {
+	return Collection("job_runs")
+}
+
+func JobWorkflowsCollection() (*mongo.Collection, error) {
+	return Collection("job_workflows")
+}
+
+func JobWorkflowRunsCollection() (*mongo.Collection, error) {
+	return Collection("job_workflow_runs")
+}
+
+func JobTemplatesCollection() (*mongo.Collection, error) {
+	return Collection("job_templates")
+}
+
+func ServiceCredentialsRevocationsCollection() (*mongo.Collection, error) {
+	return Collection("service_credentials_revocations")
+}
+
 func TokensCollection() (*mongo.Collection, error) {
 	return Collection("tokens")
 }
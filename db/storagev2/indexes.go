@@ -173,6 +173,81 @@ var EnsureIndexes = []EnsureIndex{
 		},
 	},
 
+	{
+		Collection: "service_schema_cache",
+		Indexes: []mongo.IndexModel{
+			{
+				Keys:    mongoBSON.D{{Key: "expireat", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(1),
+			},
+		},
+	},
+
+	{
+		Collection: "service_instance_status",
+		Indexes: []mongo.IndexModel{
+			{
+				Keys: mongoBSON.D{{Key: "service_name", Value: 1}, {Key: "instance_name", Value: 1}, {Key: "timestamp", Value: -1}},
+			},
+			{
+				Keys: mongoBSON.D{{Key: "timestamp", Value: -1}},
+			},
+			{
+				Keys:    mongoBSON.D{{Key: "expireat", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(1),
+			},
+		},
+	},
+
+	{
+		Collection: "job_runs",
+		Indexes: []mongo.IndexModel{
+			{
+				Keys:    mongoBSON.D{{Key: "job", Value: 1}, {Key: "name", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys: mongoBSON.D{{Key: "job", Value: 1}, {Key: "starttime", Value: -1}},
+			},
+			{
+				Keys:    mongoBSON.D{{Key: "expireat", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(1),
+			},
+		},
+	},
+
+	{
+		Collection: "job_workflows",
+		Indexes: []mongo.IndexModel{
+			{
+				Keys:    mongoBSON.D{{Key: "name", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys: mongoBSON.D{{Key: "nodes", Value: 1}},
+			},
+		},
+	},
+
+	{
+		Collection: "job_templates",
+		Indexes: []mongo.IndexModel{
+			{
+				Keys:    mongoBSON.D{{Key: "name", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+	},
+
+	{
+		Collection: "job_workflow_runs",
+		Indexes: []mongo.IndexModel{
+			{
+				Keys: mongoBSON.D{{Key: "workflow", Value: 1}, {Key: "starttime", Value: -1}},
+			},
+		},
+	},
+
 	{
 		Collection: "webhook",
 		Indexes: []mongo.IndexModel{
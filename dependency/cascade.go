@@ -0,0 +1,101 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dependency
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/service"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+)
+
+const (
+	ActionUnbind        = "unbind"
+	ActionRestart       = "restart"
+	ActionUnbindVolume  = "unbind-volume"
+	ActionRemoveBackend = "remove-backend"
+	ActionDestroy       = "destroy"
+	ActionRemove        = "remove"
+)
+
+// Step is a single change that would happen when removing a resource.
+type Step struct {
+	Action      string `json:"action"`
+	Target      Node   `json:"target"`
+	Description string `json:"description"`
+}
+
+// CascadePlan lists, in order, every change that removing Target would
+// trigger. When the removal would be refused, BlockedBy explains why.
+type CascadePlan struct {
+	Target    Node     `json:"target"`
+	Steps     []Step   `json:"steps"`
+	BlockedBy string   `json:"blockedBy,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+func (p *CascadePlan) add(action string, target Node, description string, args ...interface{}) {
+	p.Steps = append(p.Steps, Step{
+		Action:      action,
+		Target:      target,
+		Description: fmt.Sprintf(description, args...),
+	})
+}
+
+// AppDeletionPlan previews the removal of the app, following the same steps
+// of app.Delete.
+func AppDeletionPlan(ctx context.Context, a *appTypes.App) (*CascadePlan, error) {
+	target := appNode(a)
+	plan := &CascadePlan{Target: target, Steps: []Step{}}
+	instances, err := service.GetServiceInstancesBoundToApp(ctx, a.Name)
+	if err != nil {
+		return nil, err
+	}
+	for i := range instances {
+		si := &instances[i]
+		plan.add(ActionUnbind, instanceNode(si), "unbind app %q from service instance %q of service %q", a.Name, si.Name, si.ServiceName)
+	}
+	for _, r := range app.GetRouters(a) {
+		plan.add(ActionRemoveBackend, routerNode(r.Name), "remove app %q from router %q", a.Name, r.Name)
+	}
+	binds, err := appVolumeBinds(ctx, a.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range binds {
+		plan.add(ActionUnbindVolume, volumeNode(&b.volume), "unbind volume %q mounted at %q", b.volume.Name, b.bind.ID.MountPoint)
+	}
+	plan.add(ActionRemove, target, "remove app %q", a.Name)
+	return plan, nil
+}
+
+// ServiceInstanceDeletionPlan previews the removal of the service instance.
+// Apps bound to the instance block the removal, unless unbindAll is set, in
+// which case every app is unbound and restarted before the instance is
+// destroyed.
+func ServiceInstanceDeletionPlan(ctx context.Context, si *service.ServiceInstance, unbindAll bool) (*CascadePlan, error) {
+	target := instanceNode(si)
+	plan := &CascadePlan{Target: target, Steps: []Step{}}
+	if len(si.Apps) > 0 && !unbindAll {
+		plan.BlockedBy = service.ErrServiceInstanceBound.Error()
+	}
+	for _, appName := range si.Apps {
+		a, err := servicemanager.App.GetByName(ctx, appName)
+		if err != nil {
+			return nil, err
+		}
+		node := appNode(a)
+		plan.add(ActionUnbind, node, "unbind app %q from service instance %q", a.Name, si.Name)
+		plan.add(ActionRestart, node, "restart app %q to remove the service environment variables", a.Name)
+	}
+	for _, jobName := range si.Jobs {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("job %q is bound to the instance and will keep its service environment variables", jobName))
+	}
+	plan.add(ActionDestroy, target, "remove service instance %q from service %q", si.Name, si.ServiceName)
+	return plan, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dependency
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/service"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	check "gopkg.in/check.v1"
+)
+
+func planActions(plan *CascadePlan) []string {
+	var actions []string
+	for _, step := range plan.Steps {
+		actions = append(actions, step.Action+" "+step.Target.ID)
+	}
+	return actions
+}
+
+func (s *S) TestAppDeletionPlan(c *check.C) {
+	insertInstances(c,
+		service.ServiceInstance{Name: "db", ServiceName: "mysql", Apps: []string{"myapp"}},
+	)
+	s.mockVolumes("myapp")
+	a := &appTypes.App{Name: "myapp", Routers: []appTypes.AppRouter{{Name: "ingress"}}}
+	plan, err := AppDeletionPlan(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	c.Assert(plan.Target.ID, check.Equals, "app:myapp")
+	c.Assert(plan.BlockedBy, check.Equals, "")
+	c.Assert(planActions(plan), check.DeepEquals, []string{
+		"unbind service-instance:mysql/db",
+		"remove-backend router:ingress",
+		"unbind-volume volume:data",
+		"remove app:myapp",
+	})
+	c.Assert(plan.Steps[2].Description, check.Equals, `unbind volume "data" mounted at "/mnt/data"`)
+}
+
+func (s *S) TestServiceInstanceDeletionPlan(c *check.C) {
+	s.mockService.App.Apps = []*appTypes.App{{Name: "app1"}, {Name: "app2"}}
+	si := &service.ServiceInstance{Name: "db", ServiceName: "mysql", Apps: []string{"app1", "app2"}, Jobs: []string{"myjob"}}
+	plan, err := ServiceInstanceDeletionPlan(context.TODO(), si, false)
+	c.Assert(err, check.IsNil)
+	c.Assert(plan.BlockedBy, check.Equals, service.ErrServiceInstanceBound.Error())
+	c.Assert(planActions(plan), check.DeepEquals, []string{
+		"unbind app:app1",
+		"restart app:app1",
+		"unbind app:app2",
+		"restart app:app2",
+		"destroy service-instance:mysql/db",
+	})
+	c.Assert(plan.Warnings, check.DeepEquals, []string{`job "myjob" is bound to the instance and will keep its service environment variables`})
+	plan, err = ServiceInstanceDeletionPlan(context.TODO(), si, true)
+	c.Assert(err, check.IsNil)
+	c.Assert(plan.BlockedBy, check.Equals, "")
+	c.Assert(plan.Steps, check.HasLen, 5)
+}
+
+func (s *S) TestServiceInstanceDeletionPlanUnbound(c *check.C) {
+	si := &service.ServiceInstance{Name: "db", ServiceName: "mysql"}
+	plan, err := ServiceInstanceDeletionPlan(context.TODO(), si, false)
+	c.Assert(err, check.IsNil)
+	c.Assert(plan.BlockedBy, check.Equals, "")
+	c.Assert(planActions(plan), check.DeepEquals, []string{"destroy service-instance:mysql/db"})
+}
@@ -0,0 +1,316 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dependency builds the graph of resources that apps and jobs
+// depend on, like service instances, volumes and routers, and previews the
+// changes triggered by removing an app or a service instance.
+package dependency
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/service"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+)
+
+const (
+	KindApp             = "app"
+	KindJob             = "job"
+	KindServiceInstance = "service-instance"
+	KindVolume          = "volume"
+	KindRouter          = "router"
+)
+
+type Node struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Service   string `json:"service,omitempty"`
+	Pool      string `json:"pool,omitempty"`
+	TeamOwner string `json:"teamOwner,omitempty"`
+}
+
+// Edge means that the From node depends on the To node. Detail holds
+// information about the binding, like the mount point of a volume.
+type Edge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+
+	nodes  map[string]bool
+	edges  map[Edge]bool
+	filter *Filter
+}
+
+// Filter restricts the apps, jobs and service instances included in the
+// graph, usually to the ones the requesting user is allowed to read. A nil
+// function keeps every resource of its kind.
+type Filter struct {
+	App             func(*appTypes.App) bool
+	Job             func(*jobTypes.Job) bool
+	ServiceInstance func(*service.ServiceInstance) bool
+}
+
+func newGraph(f *Filter) *Graph {
+	if f == nil {
+		f = &Filter{}
+	}
+	return &Graph{
+		Nodes:  []Node{},
+		Edges:  []Edge{},
+		nodes:  map[string]bool{},
+		edges:  map[Edge]bool{},
+		filter: f,
+	}
+}
+
+func (g *Graph) allowApp(a *appTypes.App) bool {
+	return g.filter.App == nil || g.filter.App(a)
+}
+
+func (g *Graph) allowJob(j *jobTypes.Job) bool {
+	return g.filter.Job == nil || g.filter.Job(j)
+}
+
+func (g *Graph) allowInstance(si *service.ServiceInstance) bool {
+	return g.filter.ServiceInstance == nil || g.filter.ServiceInstance(si)
+}
+
+func nodeID(kind, name string) string {
+	return kind + ":" + name
+}
+
+func appNode(a *appTypes.App) Node {
+	return Node{ID: nodeID(KindApp, a.Name), Kind: KindApp, Name: a.Name, Pool: a.Pool, TeamOwner: a.TeamOwner}
+}
+
+func jobNode(j *jobTypes.Job) Node {
+	return Node{ID: nodeID(KindJob, j.Name), Kind: KindJob, Name: j.Name, Pool: j.Pool, TeamOwner: j.TeamOwner}
+}
+
+func instanceNode(si *service.ServiceInstance) Node {
+	return Node{
+		ID:        nodeID(KindServiceInstance, si.ServiceName+"/"+si.Name),
+		Kind:      KindServiceInstance,
+		Name:      si.Name,
+		Service:   si.ServiceName,
+		Pool:      si.Pool,
+		TeamOwner: si.TeamOwner,
+	}
+}
+
+func volumeNode(v *volumeTypes.Volume) Node {
+	return Node{ID: nodeID(KindVolume, v.Name), Kind: KindVolume, Name: v.Name, Pool: v.Pool, TeamOwner: v.TeamOwner}
+}
+
+func routerNode(name string) Node {
+	return Node{ID: nodeID(KindRouter, name), Kind: KindRouter, Name: name}
+}
+
+func (g *Graph) addNode(n Node) string {
+	if !g.nodes[n.ID] {
+		g.nodes[n.ID] = true
+		g.Nodes = append(g.Nodes, n)
+	}
+	return n.ID
+}
+
+func (g *Graph) addEdge(from, to, detail string) {
+	e := Edge{From: from, To: to, Detail: detail}
+	if !g.edges[e] {
+		g.edges[e] = true
+		g.Edges = append(g.Edges, e)
+	}
+}
+
+func (g *Graph) addApp(ctx context.Context, a *appTypes.App) error {
+	id := g.addNode(appNode(a))
+	instances, err := service.GetServiceInstancesBoundToApp(ctx, a.Name)
+	if err != nil {
+		return err
+	}
+	for i := range instances {
+		if g.allowInstance(&instances[i]) {
+			g.addEdge(id, g.addNode(instanceNode(&instances[i])), "")
+		}
+	}
+	binds, err := appVolumeBinds(ctx, a.Name)
+	if err != nil {
+		return err
+	}
+	for _, b := range binds {
+		g.addEdge(id, g.addNode(volumeNode(&b.volume)), volumeBindDetail(b.bind))
+	}
+	for _, r := range app.GetRouters(a) {
+		g.addEdge(id, g.addNode(routerNode(r.Name)), "")
+	}
+	return nil
+}
+
+func (g *Graph) addJob(ctx context.Context, j *jobTypes.Job) error {
+	id := g.addNode(jobNode(j))
+	instances, err := service.GetServiceInstancesBoundToJob(ctx, j.Name)
+	if err != nil {
+		return err
+	}
+	for i := range instances {
+		if g.allowInstance(&instances[i]) {
+			g.addEdge(id, g.addNode(instanceNode(&instances[i])), "")
+		}
+	}
+	return nil
+}
+
+func (g *Graph) sort() {
+	sort.SliceStable(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+	sort.SliceStable(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From == g.Edges[j].From {
+			return g.Edges[i].To < g.Edges[j].To
+		}
+		return g.Edges[i].From < g.Edges[j].From
+	})
+}
+
+type volumeBind struct {
+	volume volumeTypes.Volume
+	bind   volumeTypes.VolumeBind
+}
+
+func appVolumeBinds(ctx context.Context, appName string) ([]volumeBind, error) {
+	volumes, err := servicemanager.Volume.ListByApp(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+	var result []volumeBind
+	for _, v := range volumes {
+		binds, err := servicemanager.Volume.BindsForApp(ctx, &v, appName)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range binds {
+			result = append(result, volumeBind{volume: v, bind: b})
+		}
+	}
+	return result, nil
+}
+
+func volumeBindDetail(b volumeTypes.VolumeBind) string {
+	if b.ReadOnly {
+		return fmt.Sprintf("%s (read-only)", b.ID.MountPoint)
+	}
+	return b.ID.MountPoint
+}
+
+// ForApp returns the service instances, volumes and routers the app depends
+// on.
+func ForApp(ctx context.Context, a *appTypes.App) (*Graph, error) {
+	g := newGraph(nil)
+	if err := g.addApp(ctx, a); err != nil {
+		return nil, err
+	}
+	g.sort()
+	return g, nil
+}
+
+// ForJob returns the service instances the job depends on.
+func ForJob(ctx context.Context, j *jobTypes.Job) (*Graph, error) {
+	g := newGraph(nil)
+	if err := g.addJob(ctx, j); err != nil {
+		return nil, err
+	}
+	g.sort()
+	return g, nil
+}
+
+// ForTeam returns the dependencies of every app and job of the team, along
+// with the service instances and volumes the team has access to. Resources
+// rejected by f are left out of the graph.
+func ForTeam(ctx context.Context, team string, f *Filter) (*Graph, error) {
+	apps, err := servicemanager.App.List(ctx, &appTypes.Filter{
+		TeamOwner: team,
+		Extra:     map[string][]string{"teams": {team}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := servicemanager.Job.List(ctx, &jobTypes.Filter{TeamOwner: team})
+	if err != nil {
+		return nil, err
+	}
+	instances, err := service.GetServicesInstancesByTeamsAndNames(ctx, []string{team}, nil, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := servicemanager.Volume.ListByFilter(ctx, &volumeTypes.Filter{Teams: []string{team}})
+	if err != nil {
+		return nil, err
+	}
+	return build(ctx, f, apps, jobs, instances, volumes)
+}
+
+// ForPool returns the dependencies of every app and job running in the pool,
+// along with the service instances and volumes of the pool. Resources
+// rejected by f are left out of the graph.
+func ForPool(ctx context.Context, pool string, f *Filter) (*Graph, error) {
+	apps, err := servicemanager.App.List(ctx, &appTypes.Filter{Pool: pool})
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := servicemanager.Job.List(ctx, &jobTypes.Filter{Pool: pool})
+	if err != nil {
+		return nil, err
+	}
+	instances, err := service.GetServiceInstancesByPool(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := servicemanager.Volume.ListByFilter(ctx, &volumeTypes.Filter{Pools: []string{pool}})
+	if err != nil {
+		return nil, err
+	}
+	return build(ctx, f, apps, jobs, instances, volumes)
+}
+
+func build(ctx context.Context, f *Filter, apps []*appTypes.App, jobs []jobTypes.Job, instances []service.ServiceInstance, volumes []volumeTypes.Volume) (*Graph, error) {
+	g := newGraph(f)
+	for _, a := range apps {
+		if !g.allowApp(a) {
+			continue
+		}
+		if err := g.addApp(ctx, a); err != nil {
+			return nil, err
+		}
+	}
+	for i := range jobs {
+		if !g.allowJob(&jobs[i]) {
+			continue
+		}
+		if err := g.addJob(ctx, &jobs[i]); err != nil {
+			return nil, err
+		}
+	}
+	for i := range instances {
+		if g.allowInstance(&instances[i]) {
+			g.addNode(instanceNode(&instances[i]))
+		}
+	}
+	for i := range volumes {
+		g.addNode(volumeNode(&volumes[i]))
+	}
+	g.sort()
+	return g, nil
+}
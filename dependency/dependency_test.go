@@ -0,0 +1,143 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dependency
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/service"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) mockVolumes(appName string) {
+	v := volumeTypes.Volume{Name: "data", Pool: "pool1", TeamOwner: "team1"}
+	s.mockService.VolumeService.OnListByApp = func(ctx context.Context, name string) ([]volumeTypes.Volume, error) {
+		if name != appName {
+			return nil, nil
+		}
+		return []volumeTypes.Volume{v}, nil
+	}
+	s.mockService.VolumeService.OnBindsForApp = func(ctx context.Context, vol *volumeTypes.Volume, name string) ([]volumeTypes.VolumeBind, error) {
+		return []volumeTypes.VolumeBind{
+			{ID: volumeTypes.VolumeBindID{App: name, Volume: vol.Name, MountPoint: "/mnt/data"}, ReadOnly: true},
+		}, nil
+	}
+}
+
+func (s *S) TestForApp(c *check.C) {
+	insertInstances(c,
+		service.ServiceInstance{Name: "db", ServiceName: "mysql", TeamOwner: "team1", Apps: []string{"myapp"}},
+		service.ServiceInstance{Name: "cache", ServiceName: "redis", TeamOwner: "team1", Apps: []string{"otherapp"}},
+	)
+	s.mockVolumes("myapp")
+	a := &appTypes.App{Name: "myapp", Pool: "pool1", TeamOwner: "team1", Routers: []appTypes.AppRouter{{Name: "ingress"}}}
+	graph, err := ForApp(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	c.Assert(graph.Nodes, check.DeepEquals, []Node{
+		{ID: "app:myapp", Kind: KindApp, Name: "myapp", Pool: "pool1", TeamOwner: "team1"},
+		{ID: "router:ingress", Kind: KindRouter, Name: "ingress"},
+		{ID: "service-instance:mysql/db", Kind: KindServiceInstance, Name: "db", Service: "mysql", TeamOwner: "team1"},
+		{ID: "volume:data", Kind: KindVolume, Name: "data", Pool: "pool1", TeamOwner: "team1"},
+	})
+	c.Assert(graph.Edges, check.DeepEquals, []Edge{
+		{From: "app:myapp", To: "router:ingress"},
+		{From: "app:myapp", To: "service-instance:mysql/db"},
+		{From: "app:myapp", To: "volume:data", Detail: "/mnt/data (read-only)"},
+	})
+}
+
+func (s *S) TestForJob(c *check.C) {
+	insertInstances(c,
+		service.ServiceInstance{Name: "db", ServiceName: "mysql", Jobs: []string{"myjob"}},
+	)
+	graph, err := ForJob(context.TODO(), &jobTypes.Job{Name: "myjob", Pool: "pool1"})
+	c.Assert(err, check.IsNil)
+	c.Assert(graph.Nodes, check.HasLen, 2)
+	c.Assert(graph.Edges, check.DeepEquals, []Edge{
+		{From: "job:myjob", To: "service-instance:mysql/db"},
+	})
+}
+
+func (s *S) TestForTeam(c *check.C) {
+	insertInstances(c,
+		service.ServiceInstance{Name: "db", ServiceName: "mysql", Teams: []string{"team1"}, Apps: []string{"myapp"}, Jobs: []string{"myjob"}},
+		service.ServiceInstance{Name: "unused", ServiceName: "mysql", Teams: []string{"team1"}},
+		service.ServiceInstance{Name: "other", ServiceName: "mysql", Teams: []string{"team2"}},
+	)
+	s.mockService.App.OnList = func(f *appTypes.Filter) ([]*appTypes.App, error) {
+		c.Assert(f.TeamOwner, check.Equals, "team1")
+		return []*appTypes.App{{Name: "myapp", TeamOwner: "team1"}}, nil
+	}
+	s.mockService.JobService.OnList = func(f *jobTypes.Filter) ([]jobTypes.Job, error) {
+		c.Assert(f.TeamOwner, check.Equals, "team1")
+		return []jobTypes.Job{{Name: "myjob", TeamOwner: "team1"}}, nil
+	}
+	graph, err := ForTeam(context.TODO(), "team1", nil)
+	c.Assert(err, check.IsNil)
+	var ids []string
+	for _, n := range graph.Nodes {
+		ids = append(ids, n.ID)
+	}
+	c.Assert(ids, check.DeepEquals, []string{"app:myapp", "job:myjob", "service-instance:mysql/db", "service-instance:mysql/unused"})
+	c.Assert(graph.Edges, check.DeepEquals, []Edge{
+		{From: "app:myapp", To: "service-instance:mysql/db"},
+		{From: "job:myjob", To: "service-instance:mysql/db"},
+	})
+}
+
+func (s *S) TestForPool(c *check.C) {
+	insertInstances(c,
+		service.ServiceInstance{Name: "db", ServiceName: "mysql", Pool: "pool1"},
+		service.ServiceInstance{Name: "other", ServiceName: "mysql", Pool: "pool2"},
+	)
+	s.mockService.App.OnList = func(f *appTypes.Filter) ([]*appTypes.App, error) {
+		c.Assert(f.Pool, check.Equals, "pool1")
+		return []*appTypes.App{{Name: "myapp", Pool: "pool1"}}, nil
+	}
+	s.mockService.VolumeService.OnListByFilter = func(ctx context.Context, f *volumeTypes.Filter) ([]volumeTypes.Volume, error) {
+		c.Assert(f.Pools, check.DeepEquals, []string{"pool1"})
+		return []volumeTypes.Volume{{Name: "data", Pool: "pool1"}}, nil
+	}
+	graph, err := ForPool(context.TODO(), "pool1", nil)
+	c.Assert(err, check.IsNil)
+	var ids []string
+	for _, n := range graph.Nodes {
+		ids = append(ids, n.ID)
+	}
+	c.Assert(ids, check.DeepEquals, []string{"app:myapp", "service-instance:mysql/db", "volume:data"})
+	c.Assert(graph.Edges, check.HasLen, 0)
+}
+
+func (s *S) TestForTeamWithFilter(c *check.C) {
+	insertInstances(c,
+		service.ServiceInstance{Name: "db", ServiceName: "mysql", Teams: []string{"team1"}, Apps: []string{"myapp"}, Jobs: []string{"myjob"}},
+		service.ServiceInstance{Name: "secret", ServiceName: "mysql", Teams: []string{"team1"}, Apps: []string{"myapp"}},
+	)
+	s.mockService.App.OnList = func(f *appTypes.Filter) ([]*appTypes.App, error) {
+		return []*appTypes.App{{Name: "myapp", TeamOwner: "team1"}, {Name: "hidden", TeamOwner: "team1"}}, nil
+	}
+	s.mockService.JobService.OnList = func(f *jobTypes.Filter) ([]jobTypes.Job, error) {
+		return []jobTypes.Job{{Name: "myjob", TeamOwner: "team1"}}, nil
+	}
+	graph, err := ForTeam(context.TODO(), "team1", &Filter{
+		App: func(a *appTypes.App) bool { return a.Name != "hidden" },
+		Job: func(j *jobTypes.Job) bool { return false },
+		ServiceInstance: func(si *service.ServiceInstance) bool {
+			return si.Name != "secret"
+		},
+	})
+	c.Assert(err, check.IsNil)
+	var ids []string
+	for _, n := range graph.Nodes {
+		ids = append(ids, n.ID)
+	}
+	c.Assert(ids, check.DeepEquals, []string{"app:myapp", "service-instance:mysql/db"})
+	c.Assert(graph.Edges, check.DeepEquals, []Edge{
+		{From: "app:myapp", To: "service-instance:mysql/db"},
+	})
+}
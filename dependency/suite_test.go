@@ -0,0 +1,52 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dependency
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/service"
+	servicemock "github.com/tsuru/tsuru/servicemanager/mock"
+	_ "github.com/tsuru/tsuru/storage/mongodb"
+	check "gopkg.in/check.v1"
+)
+
+type S struct {
+	mockService servicemock.MockService
+}
+
+var _ = check.Suite(&S{})
+
+func Test(t *testing.T) {
+	check.TestingT(t)
+}
+
+func (s *S) SetUpSuite(c *check.C) {
+	config.Set("log:disable-syslog", true)
+	config.Set("database:url", "127.0.0.1:27017?maxPoolSize=100")
+	config.Set("database:name", "tsuru_dependency_test")
+	storagev2.Reset()
+}
+
+func (s *S) SetUpTest(c *check.C) {
+	storagev2.ClearAllCollections(nil)
+	servicemock.SetMockService(&s.mockService)
+}
+
+func (s *S) TearDownSuite(c *check.C) {
+	storagev2.ClearAllCollections(nil)
+}
+
+func insertInstances(c *check.C, instances ...service.ServiceInstance) {
+	collection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	for _, si := range instances {
+		_, err = collection.InsertOne(context.TODO(), si)
+		c.Assert(err, check.IsNil)
+	}
+}
@@ -224,9 +224,17 @@ type Opts struct {
 }
 
 func Allowed(scheme *permTypes.PermissionScheme, contexts ...permTypes.PermissionContext) eventTypes.AllowedPermission {
+	// tags are only used to evaluate role conditions, they are not stored
+	// since the events are never filtered by them
+	var allowedContexts []permTypes.PermissionContext
+	for _, ctx := range contexts {
+		if ctx.CtxType != permTypes.CtxTag {
+			allowedContexts = append(allowedContexts, ctx)
+		}
+	}
 	return eventTypes.AllowedPermission{
 		Scheme:   scheme.FullName(),
-		Contexts: contexts,
+		Contexts: allowedContexts,
 	}
 }
 
@@ -66,6 +66,21 @@ func (s *S) SetUpTest(c *check.C) {
 	servicemock.SetMockService(&servicemock.MockService{})
 }
 
+func (s *S) TestAllowedIgnoresTagContexts(c *check.C) {
+	allowed := Allowed(permission.PermAppReadEvents,
+		permission.Context(permTypes.CtxTeam, "team1"),
+		permission.Context(permTypes.CtxTag, "tier=dev"),
+		permission.Context(permTypes.CtxApp, "myapp"),
+	)
+	c.Assert(allowed, check.DeepEquals, eventTypes.AllowedPermission{
+		Scheme: permission.PermAppReadEvents.FullName(),
+		Contexts: []permTypes.PermissionContext{
+			permission.Context(permTypes.CtxTeam, "team1"),
+			permission.Context(permTypes.CtxApp, "myapp"),
+		},
+	})
+}
+
 func (s *S) TestNewDone(c *check.C) {
 	evt, err := New(context.TODO(), &Opts{
 		Target:  eventTypes.Target{Type: "app", Value: "myapp"},
@@ -61,11 +61,15 @@ var triggerCron = action.Action{
 		default:
 			return nil, errors.New("first parameter must be *Job")
 		}
+		var opts jobTypes.TriggerOptions
+		if len(ctx.Params) > 1 {
+			opts, _ = ctx.Params[1].(jobTypes.TriggerOptions)
+		}
 		prov, err := getProvisioner(ctx.Context, job)
 		if err != nil {
 			return nil, err
 		}
-		return nil, prov.TriggerCron(ctx.Context, job.Name, job.Pool)
+		return nil, prov.TriggerCron(ctx.Context, job.Name, job.Pool, opts)
 	},
 	MinParams: 1,
 }
@@ -177,7 +181,7 @@ var reserveTeamCronjob = action.Action{
 		default:
 			return nil, errors.New("first parameter must be *Job")
 		}
-		if err := servicemanager.TeamQuota.Inc(ctx.Context, &authTypes.Team{Name: job.TeamOwner}, 1); err != nil {
+		if err := servicemanager.TeamJobQuota.Inc(ctx.Context, &authTypes.Team{Name: job.TeamOwner}, 1); err != nil {
 			return nil, err
 		}
 		return map[string]string{"job": job.Name, "team": job.TeamOwner}, nil
@@ -185,7 +189,7 @@ var reserveTeamCronjob = action.Action{
 	Backward: func(ctx action.BWContext) {
 		m := ctx.FWResult.(map[string]string)
 		if teamStr, ok := m["team"]; ok {
-			servicemanager.TeamQuota.Inc(ctx.Context, &authTypes.Team{Name: teamStr}, -1)
+			servicemanager.TeamJobQuota.Inc(ctx.Context, &authTypes.Team{Name: teamStr}, -1)
 		}
 	},
 	MinParams: 2,
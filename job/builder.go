@@ -41,6 +41,21 @@ func buildPlan(ctx context.Context, job *jobTypes.Job) error {
 		}
 	}
 	job.Plan = *plan
+	for _, containers := range [][]jobTypes.Container{job.Spec.InitContainers, job.Spec.Sidecars} {
+		for i := range containers {
+			containerPlan, err := jobPool.GetDefaultPlan(ctx)
+			if err != nil {
+				return err
+			}
+			if containers[i].Plan.Name != "" {
+				containerPlan, err = servicemanager.Plan.FindByName(ctx, containers[i].Plan.Name)
+				if err != nil {
+					return err
+				}
+			}
+			containers[i].Plan = *containerPlan
+		}
+	}
 	return nil
 }
 
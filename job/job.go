@@ -8,7 +8,9 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"regexp"
 	"sort"
+	"time"
 
 	"github.com/imdario/mergo"
 	"github.com/pkg/errors"
@@ -29,6 +31,8 @@ import (
 	bindTypes "github.com/tsuru/tsuru/types/bind"
 	jobTypes "github.com/tsuru/tsuru/types/job"
 	provTypes "github.com/tsuru/tsuru/types/provision"
+	"github.com/tsuru/tsuru/types/quota"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
 	mongoBSON "go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -37,6 +41,8 @@ type jobService struct{}
 
 var _ jobTypes.JobService = &jobService{}
 
+var envVarNameRegexp = regexp.MustCompile("^[a-zA-Z][-_a-zA-Z0-9]*$")
+
 func getProvisioner(ctx context.Context, job *jobTypes.Job) (provision.JobProvisioner, error) {
 
 	prov, err := pool.GetProvisionerForPool(ctx, job.Pool)
@@ -59,6 +65,30 @@ func Units(ctx context.Context, job *jobTypes.Job) ([]provTypes.Unit, error) {
 	return prov.JobUnits(context.TODO(), job)
 }
 
+// RunningUnitsByPool returns the number of job units of the team running in
+// each pool the team has jobs.
+func RunningUnitsByPool(ctx context.Context, team string) (map[string]int, error) {
+	jobs, err := servicemanager.Job.List(ctx, &jobTypes.Filter{TeamOwner: team})
+	if err != nil {
+		return nil, err
+	}
+	running := map[string]int{}
+	for i := range jobs {
+		if _, ok := running[jobs[i].Pool]; ok {
+			continue
+		}
+		prov, err := getProvisioner(ctx, &jobs[i])
+		if err != nil {
+			return nil, err
+		}
+		running[jobs[i].Pool], err = prov.RunningJobUnits(ctx, team, jobs[i].Pool)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return running, nil
+}
+
 func JobService() (jobTypes.JobService, error) {
 	return &jobService{}, nil
 }
@@ -87,6 +117,9 @@ func (*jobService) GetByName(ctx context.Context, name string) (*jobTypes.Job, e
 }
 
 func (*jobService) RemoveJob(ctx context.Context, job *jobTypes.Job) error {
+	if err := unbindVolumes(ctx, job); err != nil {
+		return err
+	}
 	collection, err := storagev2.JobsCollection()
 	if err != nil {
 		return err
@@ -103,7 +136,11 @@ func (*jobService) RemoveJob(ctx context.Context, job *jobTypes.Job) error {
 		return jobTypes.ErrJobNotFound
 	}
 
-	servicemanager.TeamQuota.Inc(ctx, &authTypes.Team{Name: job.TeamOwner}, -1)
+	if runsCollection, err := storagev2.JobRunsCollection(); err == nil {
+		runsCollection.DeleteMany(ctx, mongoBSON.M{"job": job.Name})
+	}
+
+	servicemanager.TeamJobQuota.Inc(ctx, &authTypes.Team{Name: job.TeamOwner}, -1)
 	var user *auth.User
 	if user, err = auth.GetUserByEmail(ctx, job.Owner); err == nil {
 		servicemanager.UserQuota.Inc(ctx, user, -1)
@@ -111,6 +148,24 @@ func (*jobService) RemoveJob(ctx context.Context, job *jobTypes.Job) error {
 	return nil
 }
 
+func unbindVolumes(ctx context.Context, job *jobTypes.Job) error {
+	binds, err := servicemanager.Volume.BindsForJob(ctx, nil, job.Name)
+	if err != nil {
+		return errors.Wrap(err, "Unable to list volume binds for unbind")
+	}
+	for _, b := range binds {
+		err = servicemanager.Volume.UnbindJob(ctx, &volumeTypes.BindOpts{
+			Volume:     &volumeTypes.Volume{Name: b.ID.Volume},
+			JobName:    job.Name,
+			MountPoint: b.ID.MountPoint,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "Unable to unbind volume %q in %q", b.ID.Volume, b.ID.MountPoint)
+		}
+	}
+	return nil
+}
+
 func (*jobService) RemoveJobProv(ctx context.Context, job *jobTypes.Job) error {
 	prov, err := getProvisioner(ctx, job)
 	if err != nil {
@@ -307,11 +362,28 @@ func (*jobService) UpdateJob(ctx context.Context, newJob, oldJob *jobTypes.Job,
 	}
 	newJobActiveDeadlineSeconds := buildActiveDeadline(newJob.Spec.ActiveDeadlineSeconds)
 
+	// alert rules are replaced as a whole, otherwise a rule could never be
+	// disabled as mergo keeps the old value of the zeroed fields
+	var newAlerts *jobTypes.AlertRules
+	if newJob.Alerts != nil {
+		alerts := *newJob.Alerts
+		newAlerts = &alerts
+	}
+
 	deployOptionsHasChanged, updateErr := updateDeployOptions(oldJob, newJob)
 	if updateErr != nil {
 		return updateErr
 	}
 
+	// jobs rendered from a template have the fields it manages replaced as
+	// a whole, so a command, schedule or plan removed from the template is
+	// not kept by mergo
+	var rendered *jobTypes.Job
+	if newJob.Template != nil {
+		j := *newJob
+		rendered = &j
+	}
+
 	// NOTE: we're merging newJob as dst in mergo, newJob is not 100% populated, it just contains the changes the user wants to make
 	// in other words: we merge the non-empty values of oldJob and add to the empty values of newJob
 	// TODO: add an option to erase old values, it can be easily done with mergo.Merge(dst, src, mergo.WithOverwriteWithEmptyValue),
@@ -330,6 +402,15 @@ func (*jobService) UpdateJob(ctx context.Context, newJob, oldJob *jobTypes.Job,
 	if newJobActiveDeadlineSeconds != nil {
 		newJob.Spec.ActiveDeadlineSeconds = newJobActiveDeadlineSeconds
 	}
+	if newAlerts != nil {
+		newJob.Alerts = newAlerts
+	}
+	if rendered != nil {
+		newJob.Spec.Container.Command = rendered.Spec.Container.Command
+		newJob.Spec.Schedule = rendered.Spec.Schedule
+		newJob.Plan = rendered.Plan
+		manualJob = rendered.Spec.Manual
+	}
 	newJob.Spec.Manual = manualJob
 	if err := buildPlan(ctx, newJob); err != nil {
 		return err
@@ -408,9 +489,99 @@ func (*jobService) UpdateJobProv(ctx context.Context, job *jobTypes.Job) error {
 	return prov.EnsureJob(ctx, job)
 }
 
-// Trigger triggers an execution of either job or cronjob object
-func (*jobService) Trigger(ctx context.Context, job *jobTypes.Job) error {
-	return action.NewPipeline([]*action.Action{&triggerCron}...).Execute(ctx, job)
+// Trigger triggers an execution of either job or cronjob object, the
+// options override the envs, args and command of this execution only
+func (*jobService) Trigger(ctx context.Context, job *jobTypes.Job, opts jobTypes.TriggerOptions) error {
+	if err := validateTriggerOptions(job, opts); err != nil {
+		return err
+	}
+	if err := checkJobRunQuota(ctx, job); err != nil {
+		return err
+	}
+	return action.NewPipeline([]*action.Action{&triggerCron}...).Execute(ctx, job, opts)
+}
+
+// checkJobRunQuota ensures a new execution of the job fits in the number of
+// job units the team is allowed to run at the same time in the pool.
+func checkJobRunQuota(ctx context.Context, job *jobTypes.Job) error {
+	q, err := servicemanager.TeamJobRunQuota.Get(ctx, &authTypes.Team{Name: job.TeamOwner})
+	if err != nil {
+		return err
+	}
+	if q.IsUnlimited() {
+		return nil
+	}
+	prov, err := getProvisioner(ctx, job)
+	if err != nil {
+		return err
+	}
+	running, err := prov.RunningJobUnits(ctx, job.TeamOwner, job.Pool)
+	if err != nil {
+		return err
+	}
+	requested := 1
+	if job.Spec.Parallelism != nil && *job.Spec.Parallelism > 0 {
+		requested = int(*job.Spec.Parallelism)
+	}
+	if running+requested > q.Limit {
+		available := q.Limit - running
+		if available < 0 {
+			available = 0
+		}
+		return &quota.QuotaExceededError{
+			Available: uint(available),
+			Requested: uint(requested),
+		}
+	}
+	return nil
+}
+
+// Suspend pauses the scheduled executions of a cron job. The job can still be
+// triggered manually.
+func (*jobService) Suspend(ctx context.Context, job *jobTypes.Job) error {
+	return setSuspend(ctx, job, true)
+}
+
+// Resume restores the scheduled executions of a suspended cron job.
+func (*jobService) Resume(ctx context.Context, job *jobTypes.Job) error {
+	return setSuspend(ctx, job, false)
+}
+
+func setSuspend(ctx context.Context, job *jobTypes.Job, suspend bool) error {
+	if job.Spec.Manual {
+		return &tsuruErrors.ValidationError{Message: jobTypes.ErrSuspendManualJob.Error()}
+	}
+	job.Spec.Suspend = suspend
+	return action.NewPipeline(&jobUpdateDB, &updateJobProv).Execute(ctx, job)
+}
+
+// SetAutoScale makes the job executions follow its autoscale triggers.
+func (*jobService) SetAutoScale(ctx context.Context, job *jobTypes.Job, spec jobTypes.AutoScaleSpec) error {
+	job.Spec.AutoScale = &spec
+	if err := validateAutoScale(job); err != nil {
+		return err
+	}
+	return action.NewPipeline(&jobUpdateDB, &updateJobProv).Execute(ctx, job)
+}
+
+// RemoveAutoScale stops spawning executions of the job from its autoscale
+// triggers, it can still be triggered manually.
+func (*jobService) RemoveAutoScale(ctx context.Context, job *jobTypes.Job) error {
+	job.Spec.AutoScale = nil
+	return action.NewPipeline(&jobUpdateDB, &updateJobProv).Execute(ctx, job)
+}
+
+func validateAutoScale(j *jobTypes.Job) error {
+	if j.Spec.AutoScale == nil {
+		return nil
+	}
+	if !j.Spec.Manual {
+		return &tsuruErrors.ValidationError{Message: jobTypes.ErrAutoScaleScheduledJob.Error()}
+	}
+	if err := j.Spec.AutoScale.Validate(); err != nil {
+		return &tsuruErrors.ValidationError{Message: err.Error()}
+	}
+	return nil
 }
 
 func filterQuery(f *jobTypes.Filter) mongoBSON.M {
@@ -442,6 +613,9 @@ func filterQuery(f *jobTypes.Filter) mongoBSON.M {
 	if len(f.Pools) > 0 {
 		query["pool"] = mongoBSON.M{"$in": f.Pools}
 	}
+	if f.Template != "" {
+		query["template.name"] = f.Template
+	}
 	return query
 }
 
@@ -601,6 +775,28 @@ func indexEnvInSet(envName string, envs []bindTypes.EnvVar) int {
 	return -1
 }
 
+func validateTriggerOptions(job *jobTypes.Job, opts jobTypes.TriggerOptions) error {
+	serviceEnvs := map[string]bindTypes.ServiceEnvVar{}
+	for _, env := range job.Spec.ServiceEnvs {
+		serviceEnvs[env.Name] = env
+	}
+	for _, env := range opts.Envs {
+		if !envVarNameRegexp.MatchString(env.Name) {
+			return &tsuruErrors.ValidationError{Message: fmt.Sprintf("Invalid environment variable name: '%s'", env.Name)}
+		}
+		if serviceEnv, ok := serviceEnvs[env.Name]; ok {
+			msg := fmt.Sprintf("Environment variable %q is already in use by service bind \"%s/%s\"", env.Name, serviceEnv.ServiceName, serviceEnv.InstanceName)
+			return &tsuruErrors.ValidationError{Message: msg}
+		}
+	}
+	for _, cmd := range opts.Command {
+		if cmd == "" {
+			return &tsuruErrors.ValidationError{Message: "command override must not have empty arguments"}
+		}
+	}
+	return nil
+}
+
 func validatePool(ctx context.Context, job *jobTypes.Job) error {
 	p, err := pool.GetPoolByName(ctx, job.Pool)
 	if err != nil {
@@ -626,6 +822,20 @@ func validatePlan(ctx context.Context, poolName, planName string) error {
 	return nil
 }
 
+func validateContainers(ctx context.Context, j *jobTypes.Job) error {
+	if err := j.Spec.ValidateContainers(); err != nil {
+		return &tsuruErrors.ValidationError{Message: err.Error()}
+	}
+	for _, containers := range [][]jobTypes.Container{j.Spec.InitContainers, j.Spec.Sidecars} {
+		for _, c := range containers {
+			if err := validatePlan(ctx, j.Pool, c.Plan.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return pool.ValidateJobImages(ctx, j.Pool, j.Spec.ContainerImages())
+}
+
 func validateTeamOwner(ctx context.Context, job *jobTypes.Job, p *pool.Pool) error {
 	_, err := servicemanager.Team.FindByName(ctx, job.TeamOwner)
 	if err != nil {
@@ -658,6 +868,35 @@ func validateJob(ctx context.Context, j *jobTypes.Job) error {
 			return &tsuruErrors.ValidationError{Message: jobTypes.ErrInvalidSchedule.Error()}
 		}
 	}
+	if j.Spec.TTLSecondsAfterFinished != nil && *j.Spec.TTLSecondsAfterFinished < 0 {
+		return &tsuruErrors.ValidationError{Message: jobTypes.ErrInvalidTTLSecondsAfterFinished.Error()}
+	}
+	if j.Spec.StartingDeadlineSeconds != nil && *j.Spec.StartingDeadlineSeconds < 0 {
+		return &tsuruErrors.ValidationError{Message: jobTypes.ErrInvalidStartingDeadline.Error()}
+	}
+	for _, limit := range []*int32{j.Spec.SuccessfulJobsHistoryLimit, j.Spec.FailedJobsHistoryLimit} {
+		if limit != nil && *limit < 0 {
+			return &tsuruErrors.ValidationError{Message: jobTypes.ErrInvalidHistoryLimit.Error()}
+		}
+	}
+	if j.Spec.TimeZone != "" {
+		// Local depends on where the cluster runs, so it's refused as in
+		// the CronJob validation
+		if _, err := time.LoadLocation(j.Spec.TimeZone); err != nil || j.Spec.TimeZone == "Local" {
+			return &tsuruErrors.ValidationError{Message: jobTypes.ErrInvalidTimeZone.Error()}
+		}
+	}
+	if err := validateAutoScale(j); err != nil {
+		return err
+	}
+	if err := validateContainers(ctx, j); err != nil {
+		return err
+	}
+	if j.Alerts != nil {
+		if err := j.Alerts.Validate(); err != nil {
+			return &tsuruErrors.ValidationError{Message: err.Error()}
+		}
+	}
 	if j.Spec.ConcurrencyPolicy != nil {
 		allowedValues := []string{"Allow", "Forbid", "Replace"}
 		if !set.FromSlice(allowedValues).Includes(*j.Spec.ConcurrencyPolicy) {
@@ -189,7 +189,7 @@ func (s *S) TestIncreaseDecreaseQuotaForJob(c *check.C) {
 		c.Assert(item.GetName(), check.Equals, s.user.Email)
 		return nil
 	}
-	s.mockService.TeamQuota.OnInc = func(item *auth.Team, quantity int) error {
+	s.mockService.TeamJobQuota.OnInc = func(item *auth.Team, quantity int) error {
 		*teaminUseNow += quantity
 		c.Assert(item.Name, check.Equals, s.team.Name)
 		return nil
@@ -945,11 +945,348 @@ func (s *S) TestTriggerCronShouldExecuteJob(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(s.provisioner.ProvisionedJob(j1.Name), check.Equals, true)
 	c.Assert(s.provisioner.JobExecutions(j1.Name), check.Equals, 0)
-	err = servicemanager.Job.Trigger(context.TODO(), &j1)
+	err = servicemanager.Job.Trigger(context.TODO(), &j1, jobTypes.TriggerOptions{})
 	c.Assert(err, check.IsNil)
 	c.Assert(s.provisioner.JobExecutions(j1.Name), check.Equals, 1)
 }
 
+func (s *S) TestTriggerWithOptions(c *check.C) {
+	j1 := jobTypes.Job{
+		Name:      "some-job",
+		TeamOwner: s.team.Name,
+		Pool:      s.Pool,
+		Teams:     []string{s.team.Name},
+		Spec: jobTypes.JobSpec{
+			Manual: true,
+			Container: jobTypes.ContainerInfo{
+				Command: []string{"./reprocess"},
+			},
+		},
+		DeployOptions: &jobTypes.DeployOptions{
+			Kind:  provisionTypes.DeployImage,
+			Image: "alpine:latest",
+		},
+	}
+	err := servicemanager.Job.CreateJob(context.TODO(), &j1, s.user)
+	c.Assert(err, check.IsNil)
+	opts := jobTypes.TriggerOptions{
+		Envs: []bindTypes.EnvVar{{Name: "DATE", Value: "2026-01-01"}},
+		Args: []string{"--dry-run"},
+	}
+	err = servicemanager.Job.Trigger(context.TODO(), &j1, opts)
+	c.Assert(err, check.IsNil)
+	c.Assert(s.provisioner.JobExecutions(j1.Name), check.Equals, 1)
+	c.Assert(s.provisioner.LastJobTrigger(j1.Name), check.DeepEquals, opts)
+	dbJob, err := servicemanager.Job.GetByName(context.TODO(), j1.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbJob.Spec.Envs, check.HasLen, 0)
+	c.Assert(dbJob.Spec.Container.Command, check.DeepEquals, []string{"./reprocess"})
+}
+
+func (s *S) TestUpdateJobReplacesAlerts(c *check.C) {
+	j1 := jobTypes.Job{
+		Name:      "some-job",
+		TeamOwner: s.team.Name,
+		Pool:      s.Pool,
+		Teams:     []string{s.team.Name},
+		Alerts:    &jobTypes.AlertRules{ConsecutiveFailures: 3, MissedSchedule: true},
+		Spec: jobTypes.JobSpec{
+			Schedule: "0 3 * * *",
+			Container: jobTypes.ContainerInfo{
+				Command: []string{"./reprocess"},
+			},
+		},
+		DeployOptions: &jobTypes.DeployOptions{
+			Kind:  provisionTypes.DeployImage,
+			Image: "alpine:latest",
+		},
+	}
+	err := servicemanager.Job.CreateJob(context.TODO(), &j1, s.user)
+	c.Assert(err, check.IsNil)
+	oldJob, err := servicemanager.Job.GetByName(context.TODO(), j1.Name)
+	c.Assert(err, check.IsNil)
+	newJob := jobTypes.Job{
+		Name:   j1.Name,
+		Alerts: &jobTypes.AlertRules{MaxDurationSeconds: 600},
+	}
+	err = servicemanager.Job.UpdateJob(context.TODO(), &newJob, oldJob, s.user)
+	c.Assert(err, check.IsNil)
+	dbJob, err := servicemanager.Job.GetByName(context.TODO(), j1.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbJob.Alerts, check.DeepEquals, &jobTypes.AlertRules{MaxDurationSeconds: 600})
+	newJob = jobTypes.Job{
+		Name:   j1.Name,
+		Alerts: &jobTypes.AlertRules{ConsecutiveFailures: -1},
+	}
+	err = servicemanager.Job.UpdateJob(context.TODO(), &newJob, dbJob, s.user)
+	c.Assert(err, check.ErrorMatches, jobTypes.ErrInvalidAlertRules.Error())
+}
+
+func (s *S) TestSuspendResumeJob(c *check.C) {
+	j1 := jobTypes.Job{
+		Name:      "some-job",
+		TeamOwner: s.team.Name,
+		Pool:      s.Pool,
+		Teams:     []string{s.team.Name},
+		Spec: jobTypes.JobSpec{
+			Schedule: "0 3 * * *",
+			TimeZone: "America/Sao_Paulo",
+			Container: jobTypes.ContainerInfo{
+				Command: []string{"./reprocess"},
+			},
+		},
+		DeployOptions: &jobTypes.DeployOptions{
+			Kind:  provisionTypes.DeployImage,
+			Image: "alpine:latest",
+		},
+	}
+	err := servicemanager.Job.CreateJob(context.TODO(), &j1, s.user)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Job.Suspend(context.TODO(), &j1)
+	c.Assert(err, check.IsNil)
+	dbJob, err := servicemanager.Job.GetByName(context.TODO(), j1.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbJob.Spec.Suspend, check.Equals, true)
+	c.Assert(dbJob.Spec.TimeZone, check.Equals, "America/Sao_Paulo")
+	err = servicemanager.Job.Resume(context.TODO(), dbJob)
+	c.Assert(err, check.IsNil)
+	dbJob, err = servicemanager.Job.GetByName(context.TODO(), j1.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbJob.Spec.Suspend, check.Equals, false)
+}
+
+func (s *S) TestSuspendManualJob(c *check.C) {
+	j1 := jobTypes.Job{
+		Name: "some-job",
+		Spec: jobTypes.JobSpec{Manual: true},
+	}
+	err := servicemanager.Job.Suspend(context.TODO(), &j1)
+	c.Assert(err, check.ErrorMatches, jobTypes.ErrSuspendManualJob.Error())
+}
+
+func (s *S) TestCreateJobInvalidSchedulePolicies(c *check.C) {
+	tests := []struct {
+		spec jobTypes.JobSpec
+		err  error
+	}{
+		{spec: jobTypes.JobSpec{Schedule: "* * * * *", TimeZone: "Mars/Olympus"}, err: jobTypes.ErrInvalidTimeZone},
+		{spec: jobTypes.JobSpec{Schedule: "* * * * *", StartingDeadlineSeconds: func() *int64 { r := int64(-1); return &r }()}, err: jobTypes.ErrInvalidStartingDeadline},
+		{spec: jobTypes.JobSpec{Schedule: "* * * * *", FailedJobsHistoryLimit: func() *int32 { r := int32(-1); return &r }()}, err: jobTypes.ErrInvalidHistoryLimit},
+	}
+	for _, tt := range tests {
+		j1 := jobTypes.Job{
+			Name:      "some-job",
+			TeamOwner: s.team.Name,
+			Pool:      s.Pool,
+			Spec:      tt.spec,
+		}
+		err := servicemanager.Job.CreateJob(context.TODO(), &j1, s.user)
+		c.Assert(err, check.NotNil)
+		c.Assert(err.Error(), check.Matches, ".*"+tt.err.Error())
+		c.Assert(s.provisioner.ProvisionedJob(j1.Name), check.Equals, false)
+	}
+}
+
+func (s *S) TestCreateJobWithSidecarsAndInitContainers(c *check.C) {
+	j1 := jobTypes.Job{
+		Name:      "some-job",
+		TeamOwner: s.team.Name,
+		Pool:      s.Pool,
+		Teams:     []string{s.team.Name},
+		Spec: jobTypes.JobSpec{
+			Schedule: "* * * * *",
+			Container: jobTypes.ContainerInfo{
+				OriginalImageSrc: "alpine:latest",
+				Command:          []string{"./backup"},
+			},
+			InitContainers: []jobTypes.Container{
+				{Name: "setup", Image: "busybox:1.36", Command: []string{"./setup"}},
+			},
+			Sidecars: []jobTypes.Container{
+				{Name: "sql-proxy", Image: "cloud-sql-proxy:2", Command: []string{"/cloud-sql-proxy", "db"}, Plan: app.Plan{Name: "c4m2"}},
+			},
+		},
+	}
+	err := servicemanager.Job.CreateJob(context.TODO(), &j1, s.user)
+	c.Assert(err, check.IsNil)
+	dbJob, err := servicemanager.Job.GetByName(context.TODO(), j1.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbJob.Spec.InitContainers, check.HasLen, 1)
+	c.Assert(dbJob.Spec.InitContainers[0].Plan, check.DeepEquals, *s.defaultPlan)
+	c.Assert(dbJob.Spec.Sidecars, check.HasLen, 1)
+	c.Assert(dbJob.Spec.Sidecars[0].Plan, check.DeepEquals, *s.plan)
+	c.Assert(dbJob.Spec.Sidecars[0].Command, check.DeepEquals, []string{"/cloud-sql-proxy", "db"})
+}
+
+func (s *S) TestCreateJobInvalidContainers(c *check.C) {
+	command := []string{"./run"}
+	tests := []struct {
+		spec jobTypes.JobSpec
+		err  error
+	}{
+		{spec: jobTypes.JobSpec{InitContainers: []jobTypes.Container{{Name: "Setup", Image: "busybox"}}}, err: jobTypes.ErrInvalidContainerName},
+		{spec: jobTypes.JobSpec{InitContainers: []jobTypes.Container{{Name: "job", Image: "busybox"}}}, err: jobTypes.ErrDuplicatedContainerName},
+		{spec: jobTypes.JobSpec{InitContainers: []jobTypes.Container{{Name: "setup", Image: "busybox"}}, Sidecars: []jobTypes.Container{{Name: "setup", Image: "busybox", Command: command}}}, err: jobTypes.ErrDuplicatedContainerName},
+		{spec: jobTypes.JobSpec{InitContainers: []jobTypes.Container{{Name: "setup"}}}, err: jobTypes.ErrContainerImageRequired},
+		{spec: jobTypes.JobSpec{Sidecars: []jobTypes.Container{{Name: "proxy", Command: command}}}, err: jobTypes.ErrContainerImageRequired},
+	}
+	for _, tt := range tests {
+		tt.spec.Schedule = "* * * * *"
+		j1 := jobTypes.Job{
+			Name:      "some-job",
+			TeamOwner: s.team.Name,
+			Pool:      s.Pool,
+			Spec:      tt.spec,
+		}
+		err := servicemanager.Job.CreateJob(context.TODO(), &j1, s.user)
+		c.Assert(err, check.NotNil)
+		c.Assert(err.Error(), check.Matches, ".*"+tt.err.Error())
+		c.Assert(s.provisioner.ProvisionedJob(j1.Name), check.Equals, false)
+	}
+}
+
+func (s *S) TestCreateJobContainerImageNotAllowed(c *check.C) {
+	err := pool.SetPoolConstraint(context.TODO(), &pool.PoolConstraint{PoolExpr: s.Pool, Field: pool.ConstraintTypeJobImage, Values: []string{"busybox*"}})
+	c.Assert(err, check.IsNil)
+	j1 := jobTypes.Job{
+		Name:      "some-job",
+		TeamOwner: s.team.Name,
+		Pool:      s.Pool,
+		Spec: jobTypes.JobSpec{
+			Schedule:       "* * * * *",
+			InitContainers: []jobTypes.Container{{Name: "setup", Image: "alpine:latest"}},
+		},
+	}
+	err = servicemanager.Job.CreateJob(context.TODO(), &j1, s.user)
+	c.Assert(err, check.ErrorMatches, `image "alpine:latest" is not allowed for jobs in pool "`+s.Pool+`".`)
+	j1.Spec.InitContainers[0].Image = "busybox:1.36"
+	err = servicemanager.Job.CreateJob(context.TODO(), &j1, s.user)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestSetAndRemoveAutoScale(c *check.C) {
+	j1 := jobTypes.Job{
+		Name:      "some-job",
+		TeamOwner: s.team.Name,
+		Pool:      s.Pool,
+		Teams:     []string{s.team.Name},
+		Spec: jobTypes.JobSpec{
+			Manual: true,
+			Container: jobTypes.ContainerInfo{
+				Command: []string{"./consume"},
+			},
+		},
+		DeployOptions: &jobTypes.DeployOptions{
+			Kind:  provisionTypes.DeployImage,
+			Image: "alpine:latest",
+		},
+	}
+	err := servicemanager.Job.CreateJob(context.TODO(), &j1, s.user)
+	c.Assert(err, check.IsNil)
+	spec := jobTypes.AutoScaleSpec{
+		MinParallelism: 1,
+		MaxParallelism: 10,
+		Prometheus: []provisionTypes.AutoScalePrometheus{
+			{Name: "queue", Query: "sum(queue_depth)", Threshold: 5},
+		},
+	}
+	err = servicemanager.Job.SetAutoScale(context.TODO(), &j1, spec)
+	c.Assert(err, check.IsNil)
+	dbJob, err := servicemanager.Job.GetByName(context.TODO(), j1.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbJob.Spec.AutoScale, check.DeepEquals, &spec)
+	err = servicemanager.Job.RemoveAutoScale(context.TODO(), dbJob)
+	c.Assert(err, check.IsNil)
+	dbJob, err = servicemanager.Job.GetByName(context.TODO(), j1.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbJob.Spec.AutoScale, check.IsNil)
+}
+
+func (s *S) TestSetInvalidAutoScale(c *check.C) {
+	trigger := []provisionTypes.AutoScalePrometheus{{Name: "queue", Query: "sum(queue_depth)"}}
+	tests := []struct {
+		manual bool
+		spec   jobTypes.AutoScaleSpec
+		err    error
+	}{
+		{manual: false, spec: jobTypes.AutoScaleSpec{MaxParallelism: 1, Prometheus: trigger}, err: jobTypes.ErrAutoScaleScheduledJob},
+		{manual: true, spec: jobTypes.AutoScaleSpec{MinParallelism: 2, MaxParallelism: 1, Prometheus: trigger}, err: jobTypes.ErrInvalidAutoScaleParallelism},
+		{manual: true, spec: jobTypes.AutoScaleSpec{MaxParallelism: 1, PollingInterval: -1, Prometheus: trigger}, err: jobTypes.ErrInvalidAutoScalePolling},
+		{manual: true, spec: jobTypes.AutoScaleSpec{MaxParallelism: 1}, err: jobTypes.ErrAutoScaleNoTriggers},
+		{manual: true, spec: jobTypes.AutoScaleSpec{MaxParallelism: 1, Prometheus: []provisionTypes.AutoScalePrometheus{{Name: "queue"}}}, err: jobTypes.ErrInvalidAutoScaleTrigger},
+	}
+	for _, tt := range tests {
+		j1 := jobTypes.Job{
+			Name: "some-job",
+			Spec: jobTypes.JobSpec{Manual: tt.manual, Schedule: "* * * * *"},
+		}
+		if tt.manual {
+			j1.Spec.Schedule = ""
+		}
+		err := servicemanager.Job.SetAutoScale(context.TODO(), &j1, tt.spec)
+		c.Assert(err, check.ErrorMatches, tt.err.Error())
+	}
+}
+
+func (s *S) TestTriggerJobRunQuotaExceeded(c *check.C) {
+	s.mockService.TeamJobRunQuota.OnGet = func(item *auth.Team) (*quota.Quota, error) {
+		c.Assert(item.Name, check.Equals, s.team.Name)
+		return &quota.Quota{Limit: 3}, nil
+	}
+	defer func() {
+		s.mockService.TeamJobRunQuota.OnGet = func(_ *auth.Team) (*quota.Quota, error) {
+			return &quota.UnlimitedQuota, nil
+		}
+	}()
+	running := jobTypes.Job{
+		Name:      "running-job",
+		TeamOwner: s.team.Name,
+		Pool:      s.Pool,
+	}
+	_, err := s.provisioner.NewJobWithUnits(context.TODO(), &running)
+	c.Assert(err, check.IsNil)
+	j1 := jobTypes.Job{
+		Name:      "some-job",
+		TeamOwner: s.team.Name,
+		Pool:      s.Pool,
+		Teams:     []string{s.team.Name},
+		Spec: jobTypes.JobSpec{
+			Manual: true,
+		},
+		DeployOptions: &jobTypes.DeployOptions{
+			Kind:  provisionTypes.DeployImage,
+			Image: "alpine:latest",
+		},
+	}
+	err = servicemanager.Job.CreateJob(context.TODO(), &j1, s.user)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Job.Trigger(context.TODO(), &j1, jobTypes.TriggerOptions{})
+	c.Assert(err, check.IsNil)
+	j1.Spec.Parallelism = func() *int32 { r := int32(2); return &r }()
+	err = servicemanager.Job.Trigger(context.TODO(), &j1, jobTypes.TriggerOptions{})
+	c.Assert(err, check.DeepEquals, &quota.QuotaExceededError{Available: 1, Requested: 2})
+}
+
+func (s *S) TestTriggerWithInvalidOptions(c *check.C) {
+	j1 := jobTypes.Job{
+		Name: "some-job",
+		Spec: jobTypes.JobSpec{
+			ServiceEnvs: []bindTypes.ServiceEnvVar{
+				{ServiceName: "mysql", InstanceName: "db", EnvVar: bindTypes.EnvVar{Name: "DATABASE_HOST", Value: "localhost"}},
+			},
+		},
+	}
+	err := servicemanager.Job.Trigger(context.TODO(), &j1, jobTypes.TriggerOptions{
+		Envs: []bindTypes.EnvVar{{Name: "INVALID ENV"}},
+	})
+	c.Assert(err, check.ErrorMatches, `Invalid environment variable name: 'INVALID ENV'`)
+	err = servicemanager.Job.Trigger(context.TODO(), &j1, jobTypes.TriggerOptions{
+		Envs: []bindTypes.EnvVar{{Name: "DATABASE_HOST", Value: "other"}},
+	})
+	c.Assert(err, check.ErrorMatches, `Environment variable "DATABASE_HOST" is already in use by service bind "mysql/db"`)
+	c.Assert(s.provisioner.JobExecutions(j1.Name), check.Equals, 0)
+}
+
 func (s *S) TestList(c *check.C) {
 	j1 := jobTypes.Job{
 		Name:      "j1",
@@ -0,0 +1,78 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/db/storagev2"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/servicemanager"
+	"github.com/tsuru/tsuru/types/quota"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+)
+
+// MigrateTeamJobQuota moves the jobs counted in the team quota, shared with
+// apps, to the team job quota. Teams created before the job quotas get them
+// unlimited.
+func MigrateTeamJobQuota() error {
+	ctx := context.TODO()
+	teamsCollection, err := storagev2.TeamsCollection()
+	if err != nil {
+		return err
+	}
+	for _, field := range []string{"jobquota", "jobrunquota"} {
+		_, err = teamsCollection.UpdateMany(ctx,
+			mongoBSON.M{field: mongoBSON.M{"$exists": false}},
+			mongoBSON.M{"$set": mongoBSON.M{field: quota.UnlimitedQuota}},
+		)
+		if err != nil {
+			return errors.Wrapf(err, "failed to set %s of teams", field)
+		}
+	}
+	collection, err := storagev2.JobsCollection()
+	if err != nil {
+		return err
+	}
+	cursor, err := collection.Aggregate(ctx, []mongoBSON.M{
+		{"$group": mongoBSON.M{"_id": "$teamowner", "count": mongoBSON.M{"$sum": 1}}},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to count jobs by team")
+	}
+	var counts []struct {
+		Team  string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err = cursor.All(ctx, &counts); err != nil {
+		return errors.Wrap(err, "failed to count jobs by team")
+	}
+	multiErr := tsuruErrors.NewMultiError()
+	for _, c := range counts {
+		team, err := servicemanager.Team.FindByName(ctx, c.Team)
+		if err != nil {
+			multiErr.Add(errors.Wrapf(err, "failed to find team %q", c.Team))
+			continue
+		}
+		q, err := servicemanager.TeamQuota.Get(ctx, team)
+		if err != nil {
+			multiErr.Add(errors.Wrapf(err, "failed to get quota of team %q", c.Team))
+			continue
+		}
+		inUse := q.InUse - c.Count
+		if inUse < 0 {
+			inUse = 0
+		}
+		if err = servicemanager.TeamQuota.Set(ctx, team, inUse); err != nil {
+			multiErr.Add(errors.Wrapf(err, "failed to set quota of team %q", c.Team))
+			continue
+		}
+		if err = servicemanager.TeamJobQuota.Set(ctx, team, c.Count); err != nil {
+			multiErr.Add(errors.Wrapf(err, "failed to set job quota of team %q", c.Team))
+		}
+	}
+	return multiErr.ToError()
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/db/storagev2"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	"github.com/tsuru/tsuru/types/quota"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestMigrateTeamJobQuota(c *check.C) {
+	collection, err := storagev2.JobsCollection()
+	c.Assert(err, check.IsNil)
+	for _, name := range []string{"job1", "job2"} {
+		_, err = collection.InsertOne(context.TODO(), jobTypes.Job{Name: name, TeamOwner: s.team.Name})
+		c.Assert(err, check.IsNil)
+	}
+	s.mockService.TeamQuota.OnGet = func(_ *authTypes.Team) (*quota.Quota, error) {
+		return &quota.Quota{Limit: 10, InUse: 5}, nil
+	}
+	var teamInUse, jobInUse int
+	s.mockService.TeamQuota.OnSet = func(t *authTypes.Team, inUse int) error {
+		c.Assert(t.Name, check.Equals, s.team.Name)
+		teamInUse = inUse
+		return nil
+	}
+	s.mockService.TeamJobQuota.OnSet = func(t *authTypes.Team, inUse int) error {
+		c.Assert(t.Name, check.Equals, s.team.Name)
+		jobInUse = inUse
+		return nil
+	}
+	err = MigrateTeamJobQuota()
+	c.Assert(err, check.IsNil)
+	c.Assert(teamInUse, check.Equals, 3)
+	c.Assert(jobInUse, check.Equals, 2)
+}
+
+func (s *S) TestMigrateTeamJobQuotaSetsUnlimitedQuotas(c *check.C) {
+	collection, err := storagev2.TeamsCollection()
+	c.Assert(err, check.IsNil)
+	_, err = collection.InsertOne(context.TODO(), mongoBSON.M{"_id": "oldteam", "tags": []string{}})
+	c.Assert(err, check.IsNil)
+	_, err = collection.InsertOne(context.TODO(), mongoBSON.M{"_id": "newteam", "jobquota": quota.Quota{Limit: 5}, "jobrunquota": quota.Quota{Limit: 2}})
+	c.Assert(err, check.IsNil)
+	err = MigrateTeamJobQuota()
+	c.Assert(err, check.IsNil)
+	var teams []struct {
+		Name        string `bson:"_id"`
+		JobQuota    quota.Quota
+		JobRunQuota quota.Quota
+	}
+	cursor, err := collection.Find(context.TODO(), mongoBSON.M{"_id": mongoBSON.M{"$in": []string{"oldteam", "newteam"}}})
+	c.Assert(err, check.IsNil)
+	err = cursor.All(context.TODO(), &teams)
+	c.Assert(err, check.IsNil)
+	c.Assert(teams, check.HasLen, 2)
+	quotas := map[string][]quota.Quota{}
+	for _, t := range teams {
+		quotas[t.Name] = []quota.Quota{t.JobQuota, t.JobRunQuota}
+	}
+	c.Assert(quotas, check.DeepEquals, map[string][]quota.Quota{
+		"oldteam": {quota.UnlimitedQuota, quota.UnlimitedQuota},
+		"newteam": {{Limit: 5}, {Limit: 2}},
+	})
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db/storagev2"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultRunRetention = 30 * 24 * time.Hour
+	defaultRunsLimit    = 20
+)
+
+type runDocument struct {
+	jobTypes.Run `bson:",inline"`
+	ExpireAt     time.Time
+}
+
+func runRetention() time.Duration {
+	if hours, err := config.GetInt("jobs:run-history:retention-hours"); err == nil && hours > 0 {
+		return time.Duration(hours) * time.Hour
+	}
+	return defaultRunRetention
+}
+
+// RecordRun creates or updates the history entry of a job execution and
+// moves forward the workflow run that spawned it, if any.
+func (*jobService) RecordRun(ctx context.Context, run jobTypes.Run) error {
+	collection, err := storagev2.JobRunsCollection()
+	if err != nil {
+		return err
+	}
+	set := mongoBSON.M{
+		"pool":        run.Pool,
+		"manual":      run.Manual,
+		"triggeredby": run.TriggeredBy,
+		"workflowrun": run.WorkflowRun,
+		"status":      run.Status,
+		"starttime":   run.StartTime,
+		"attempts":    run.Attempts,
+		"expireat":    run.StartTime.Add(runRetention()),
+	}
+	// units may be gone by the time the run finishes, so the values seen on
+	// previous updates are kept
+	if run.EndTime != nil {
+		set["endtime"] = run.EndTime
+	}
+	if run.ExitCode != nil {
+		set["exitcode"] = run.ExitCode
+	}
+	if run.Reason != "" {
+		set["reason"] = run.Reason
+	}
+	if run.Message != "" {
+		set["message"] = run.Message
+	}
+	update := mongoBSON.M{"$set": set}
+	if len(run.Units) > 0 {
+		update["$addToSet"] = mongoBSON.M{"units": mongoBSON.M{"$each": run.Units}}
+	}
+	_, err = collection.UpdateOne(ctx, mongoBSON.M{"job": run.Job, "name": run.Name}, update, options.Update().SetUpsert(true))
+	if err != nil || run.WorkflowRun == "" {
+		return err
+	}
+	return onWorkflowJobRun(ctx, run)
+}
+
+// ListRuns returns the recorded executions of a job, most recent first.
+func (*jobService) ListRuns(ctx context.Context, job *jobTypes.Job, filter jobTypes.RunFilter) ([]jobTypes.Run, error) {
+	collection, err := storagev2.JobRunsCollection()
+	if err != nil {
+		return nil, err
+	}
+	query := mongoBSON.M{"job": job.Name}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultRunsLimit
+	}
+	opts := options.Find().SetSort(mongoBSON.M{"starttime": -1}).SetLimit(int64(limit))
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	var docs []runDocument
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	runs := make([]jobTypes.Run, len(docs))
+	for i := range docs {
+		runs[i] = docs[i].Run
+	}
+	return runs, nil
+}
+
+// GetRun returns a single recorded execution of a job.
+func (*jobService) GetRun(ctx context.Context, job *jobTypes.Job, runName string) (*jobTypes.Run, error) {
+	collection, err := storagev2.JobRunsCollection()
+	if err != nil {
+		return nil, err
+	}
+	var doc runDocument
+	err = collection.FindOne(ctx, mongoBSON.M{"job": job.Name, "name": runName}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, jobTypes.ErrJobRunNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &doc.Run, nil
+}
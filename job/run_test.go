@@ -0,0 +1,83 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/tsuru/servicemanager"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestRecordRunUpdatesExistingRun(c *check.C) {
+	job := &jobTypes.Job{Name: "myjob"}
+	start := time.Now().UTC().Truncate(time.Millisecond)
+	run := jobTypes.Run{Name: "myjob-1", Job: job.Name, Status: jobTypes.RunStatusRunning, StartTime: start, Attempts: 1}
+	err := servicemanager.Job.RecordRun(context.TODO(), run)
+	c.Assert(err, check.IsNil)
+	end := start.Add(time.Minute)
+	run.Status = jobTypes.RunStatusSucceeded
+	run.EndTime = &end
+	err = servicemanager.Job.RecordRun(context.TODO(), run)
+	c.Assert(err, check.IsNil)
+	runs, err := servicemanager.Job.ListRuns(context.TODO(), job, jobTypes.RunFilter{})
+	c.Assert(err, check.IsNil)
+	c.Assert(runs, check.DeepEquals, []jobTypes.Run{run})
+}
+
+func (s *S) TestRecordRunKeepsUnitsAndExitCode(c *check.C) {
+	job := &jobTypes.Job{Name: "myjob"}
+	start := time.Now().UTC().Truncate(time.Millisecond)
+	exitCode := int32(1)
+	run := jobTypes.Run{Name: "myjob-1", Job: job.Name, Status: jobTypes.RunStatusRunning, StartTime: start, Attempts: 1, Units: []string{"myjob-1-aaaaa"}, ExitCode: &exitCode}
+	err := servicemanager.Job.RecordRun(context.TODO(), run)
+	c.Assert(err, check.IsNil)
+	end := start.Add(time.Minute)
+	err = servicemanager.Job.RecordRun(context.TODO(), jobTypes.Run{Name: "myjob-1", Job: job.Name, Status: jobTypes.RunStatusFailed, StartTime: start, EndTime: &end, Attempts: 1})
+	c.Assert(err, check.IsNil)
+	runs, err := servicemanager.Job.ListRuns(context.TODO(), job, jobTypes.RunFilter{})
+	c.Assert(err, check.IsNil)
+	run.Status = jobTypes.RunStatusFailed
+	run.EndTime = &end
+	c.Assert(runs, check.DeepEquals, []jobTypes.Run{run})
+}
+
+func (s *S) TestListRuns(c *check.C) {
+	job := &jobTypes.Job{Name: "myjob"}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	for i, status := range []jobTypes.RunStatus{jobTypes.RunStatusSucceeded, jobTypes.RunStatusFailed, jobTypes.RunStatusSucceeded} {
+		err := servicemanager.Job.RecordRun(context.TODO(), jobTypes.Run{
+			Name:      "myjob-" + string(rune('a'+i)),
+			Job:       job.Name,
+			Status:    status,
+			StartTime: now.Add(time.Duration(i) * time.Minute),
+		})
+		c.Assert(err, check.IsNil)
+	}
+	err := servicemanager.Job.RecordRun(context.TODO(), jobTypes.Run{Name: "other-a", Job: "other", StartTime: now})
+	c.Assert(err, check.IsNil)
+	runs, err := servicemanager.Job.ListRuns(context.TODO(), job, jobTypes.RunFilter{})
+	c.Assert(err, check.IsNil)
+	c.Assert(runs, check.HasLen, 3)
+	c.Assert(runs[0].Name, check.Equals, "myjob-c")
+	c.Assert(runs[2].Name, check.Equals, "myjob-a")
+	runs, err = servicemanager.Job.ListRuns(context.TODO(), job, jobTypes.RunFilter{Status: jobTypes.RunStatusSucceeded, Limit: 1})
+	c.Assert(err, check.IsNil)
+	c.Assert(runs, check.HasLen, 1)
+	c.Assert(runs[0].Name, check.Equals, "myjob-c")
+}
+
+func (s *S) TestGetRun(c *check.C) {
+	job := &jobTypes.Job{Name: "myjob"}
+	err := servicemanager.Job.RecordRun(context.TODO(), jobTypes.Run{Name: "myjob-a", Job: job.Name, StartTime: time.Now()})
+	c.Assert(err, check.IsNil)
+	run, err := servicemanager.Job.GetRun(context.TODO(), job, "myjob-a")
+	c.Assert(err, check.IsNil)
+	c.Assert(run.Name, check.Equals, "myjob-a")
+	_, err = servicemanager.Job.GetRun(context.TODO(), job, "myjob-b")
+	c.Assert(err, check.Equals, jobTypes.ErrJobRunNotFound)
+}
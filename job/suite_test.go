@@ -108,6 +108,12 @@ func setupMocks(s *S) {
 	s.mockService.TeamQuota.OnGet = func(_ *authTypes.Team) (*quota.Quota, error) {
 		return &quota.UnlimitedQuota, nil
 	}
+	s.mockService.TeamJobQuota.OnGet = func(_ *authTypes.Team) (*quota.Quota, error) {
+		return &quota.UnlimitedQuota, nil
+	}
+	s.mockService.TeamJobRunQuota.OnGet = func(_ *authTypes.Team) (*quota.Quota, error) {
+		return &quota.UnlimitedQuota, nil
+	}
 	s.mockService.Pool.OnServices = func(pool string) ([]string, error) {
 		return []string{
 			"my",
@@ -0,0 +1,329 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/tsuru/tsuru/db/storagev2"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	bindTypes "github.com/tsuru/tsuru/types/bind"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	"github.com/tsuru/tsuru/validation"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// templateEnvsManager marks the envs set by a template, they are replaced
+// when the template is applied again while the envs set by the team are
+// kept.
+const templateEnvsManager = "job-template"
+
+var templateParamRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+type templateService struct{}
+
+var _ jobTypes.TemplateService = &templateService{}
+
+func TemplateService() (jobTypes.TemplateService, error) {
+	return &templateService{}, nil
+}
+
+func (*templateService) CreateTemplate(ctx context.Context, tmpl *jobTypes.Template) error {
+	if err := validateTemplate(ctx, tmpl); err != nil {
+		return err
+	}
+	collection, err := storagev2.JobTemplatesCollection()
+	if err != nil {
+		return err
+	}
+	_, err = collection.InsertOne(ctx, tmpl)
+	if mongo.IsDuplicateKeyError(err) {
+		return jobTypes.ErrTemplateAlreadyExists
+	}
+	return err
+}
+
+// UpdateTemplate replaces the template definition, jobs created from it
+// are only changed by ApplyTemplate.
+func (*templateService) UpdateTemplate(ctx context.Context, tmpl *jobTypes.Template) error {
+	if err := validateTemplate(ctx, tmpl); err != nil {
+		return err
+	}
+	collection, err := storagev2.JobTemplatesCollection()
+	if err != nil {
+		return err
+	}
+	result, err := collection.ReplaceOne(ctx, mongoBSON.M{"name": tmpl.Name}, tmpl)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return jobTypes.ErrTemplateNotFound
+	}
+	return nil
+}
+
+func (*templateService) GetTemplate(ctx context.Context, name string) (*jobTypes.Template, error) {
+	collection, err := storagev2.JobTemplatesCollection()
+	if err != nil {
+		return nil, err
+	}
+	var tmpl jobTypes.Template
+	err = collection.FindOne(ctx, mongoBSON.M{"name": name}).Decode(&tmpl)
+	if err == mongo.ErrNoDocuments {
+		return nil, jobTypes.ErrTemplateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+func (*templateService) ListTemplates(ctx context.Context, filter *jobTypes.TemplateFilter) ([]jobTypes.Template, error) {
+	collection, err := storagev2.JobTemplatesCollection()
+	if err != nil {
+		return nil, err
+	}
+	query := mongoBSON.M{}
+	if filter != nil && filter.TeamOwners != nil {
+		query["teamowner"] = mongoBSON.M{"$in": append([]string{""}, filter.TeamOwners...)}
+	}
+	cursor, err := collection.Find(ctx, query, options.Find().SetSort(mongoBSON.M{"name": 1}))
+	if err != nil {
+		return nil, err
+	}
+	templates := []jobTypes.Template{}
+	if err = cursor.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// RemoveTemplate removes the template, jobs created from it are kept as
+// regular jobs.
+func (*templateService) RemoveTemplate(ctx context.Context, tmpl *jobTypes.Template) error {
+	collection, err := storagev2.JobTemplatesCollection()
+	if err != nil {
+		return err
+	}
+	result, err := collection.DeleteOne(ctx, mongoBSON.M{"name": tmpl.Name})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return jobTypes.ErrTemplateNotFound
+	}
+	jobsCollection, err := storagev2.JobsCollection()
+	if err != nil {
+		return err
+	}
+	_, err = jobsCollection.UpdateMany(ctx, mongoBSON.M{"template.name": tmpl.Name}, mongoBSON.M{"$unset": mongoBSON.M{"template": ""}})
+	return err
+}
+
+func (*templateService) RenderJob(ctx context.Context, tmpl *jobTypes.Template, params map[string]string, job *jobTypes.Job) error {
+	resolved, err := resolveTemplateParams(tmpl, params)
+	if err != nil {
+		return err
+	}
+	image, err := renderTemplateValue(tmpl.Spec.Image, resolved)
+	if err != nil {
+		return err
+	}
+	schedule, err := renderTemplateValue(tmpl.Spec.Schedule, resolved)
+	if err != nil {
+		return err
+	}
+	var command []string
+	for _, arg := range tmpl.Spec.Command {
+		rendered, err := renderTemplateValue(arg, resolved)
+		if err != nil {
+			return err
+		}
+		command = append(command, rendered)
+	}
+	envs := []bindTypes.EnvVar{}
+	for _, env := range job.Spec.Envs {
+		if env.ManagedBy != templateEnvsManager {
+			envs = append(envs, env)
+		}
+	}
+	for _, env := range tmpl.Spec.Envs {
+		value, err := renderTemplateValue(env.Value, resolved)
+		if err != nil {
+			return err
+		}
+		envs = append(envs, bindTypes.EnvVar{Name: env.Name, Value: value, Public: env.Public, ManagedBy: templateEnvsManager})
+	}
+	job.DeployOptions = &jobTypes.DeployOptions{Kind: provTypes.DeployImage, Image: image}
+	job.Spec.Container.OriginalImageSrc = image
+	job.Spec.Container.Command = command
+	job.Spec.Manual = tmpl.Spec.Manual
+	job.Spec.Schedule = schedule
+	job.Spec.Envs = envs
+	if tmpl.Spec.Plan != "" {
+		job.Plan.Name = tmpl.Spec.Plan
+	}
+	if job.Description == "" {
+		job.Description = tmpl.Description
+	}
+	job.Template = &jobTypes.TemplateRef{Name: tmpl.Name, Params: params}
+	return nil
+}
+
+// ApplyTemplate updates every job created from the template, a failure in
+// one of them doesn't stop the remaining ones and is reported in its
+// result. Each job is changed under its own update event.
+func (s *templateService) ApplyTemplate(ctx context.Context, tmpl *jobTypes.Template, t authTypes.Token) ([]jobTypes.TemplateApplyResult, error) {
+	user, err := t.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	svc := &jobService{}
+	jobs, err := svc.List(ctx, &jobTypes.Filter{Template: tmpl.Name})
+	if err != nil {
+		return nil, err
+	}
+	results := []jobTypes.TemplateApplyResult{}
+	for i := range jobs {
+		oldJob := &jobs[i]
+		result := jobTypes.TemplateApplyResult{Job: oldJob.Name}
+		if err = s.applyTemplateToJob(ctx, svc, tmpl, oldJob, t, user); err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *templateService) applyTemplateToJob(ctx context.Context, svc *jobService, tmpl *jobTypes.Template, oldJob *jobTypes.Job, t authTypes.Token, user *authTypes.User) (err error) {
+	if !permission.Check(ctx, t, permission.PermJobUpdate, permission.Context(permTypes.CtxTeam, oldJob.TeamOwner)) {
+		return permission.ErrUnauthorized
+	}
+	allowed := append(permission.Contexts(permTypes.CtxTeam, oldJob.Teams),
+		permission.Context(permTypes.CtxJob, oldJob.Name),
+		permission.Context(permTypes.CtxPool, oldJob.Pool),
+	)
+	evt, err := event.New(ctx, &event.Opts{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeJob, Value: oldJob.Name},
+		ExtraTargets: []eventTypes.ExtraTarget{
+			{Target: eventTypes.Target{Type: eventTypes.TargetTypeJobTemplate, Value: tmpl.Name}},
+		},
+		Kind:       permission.PermJobUpdate,
+		Owner:      t,
+		CustomData: map[string]string{"template": tmpl.Name},
+		Allowed:    event.Allowed(permission.PermJobReadEvents, allowed...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	newJob := &jobTypes.Job{
+		Name: oldJob.Name,
+		Spec: jobTypes.JobSpec{Envs: oldJob.Spec.Envs},
+	}
+	if err = s.RenderJob(ctx, tmpl, oldJob.Template.Params, newJob); err != nil {
+		return err
+	}
+	return svc.UpdateJob(ctx, newJob, oldJob, user)
+}
+
+func resolveTemplateParams(tmpl *jobTypes.Template, params map[string]string) (map[string]string, error) {
+	declared := make(map[string]jobTypes.TemplateParam, len(tmpl.Params))
+	for _, p := range tmpl.Params {
+		declared[p.Name] = p
+	}
+	for name := range params {
+		if _, ok := declared[name]; !ok {
+			return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("unknown param %q for job template %q", name, tmpl.Name)}
+		}
+	}
+	resolved := make(map[string]string, len(tmpl.Params))
+	var missing []string
+	for _, p := range tmpl.Params {
+		value, ok := params[p.Name]
+		if !ok {
+			if p.Required {
+				missing = append(missing, p.Name)
+				continue
+			}
+			value = p.Default
+		}
+		resolved[p.Name] = value
+	}
+	if len(missing) > 0 {
+		return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("missing required params for job template %q: %s", tmpl.Name, strings.Join(missing, ", "))}
+	}
+	return resolved, nil
+}
+
+func renderTemplateValue(value string, params map[string]string) (string, error) {
+	t, err := template.New("").Option("missingkey=error").Parse(value)
+	if err != nil {
+		return "", &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid placeholder in %q: %v", value, err)}
+	}
+	var buf strings.Builder
+	if err = t.Execute(&buf, params); err != nil {
+		return "", &tsuruErrors.ValidationError{Message: fmt.Sprintf("unable to render %q: %v", value, err)}
+	}
+	return buf.String(), nil
+}
+
+func validateTemplate(ctx context.Context, tmpl *jobTypes.Template) error {
+	if !validation.ValidateName(tmpl.Name) {
+		return &tsuruErrors.ValidationError{Message: "Invalid job template name, job template name should have at most 40 " +
+			"characters, containing only lower case letters, numbers or dashes, starting with a letter."}
+	}
+	if tmpl.TeamOwner != "" {
+		if _, err := servicemanager.Team.FindByName(ctx, tmpl.TeamOwner); err != nil {
+			return &tsuruErrors.ValidationError{Message: err.Error()}
+		}
+	}
+	if tmpl.Spec.Image == "" {
+		return &tsuruErrors.ValidationError{Message: jobTypes.ErrTemplateNoImage.Error()}
+	}
+	if tmpl.Spec.Manual && tmpl.Spec.Schedule != "" {
+		return &tsuruErrors.ValidationError{Message: "job template can't set schedule and manual at the same time"}
+	}
+	params := map[string]string{}
+	for _, p := range tmpl.Params {
+		if !templateParamRegexp.MatchString(p.Name) {
+			return &tsuruErrors.ValidationError{Message: fmt.Sprintf("%s: %q", jobTypes.ErrTemplateInvalidParam, p.Name)}
+		}
+		if _, ok := params[p.Name]; ok {
+			return &tsuruErrors.ValidationError{Message: fmt.Sprintf("param %q is declared more than once", p.Name)}
+		}
+		params[p.Name] = p.Default
+	}
+	// every placeholder must refer to a declared param
+	values := append([]string{tmpl.Spec.Image, tmpl.Spec.Schedule}, tmpl.Spec.Command...)
+	for _, env := range tmpl.Spec.Envs {
+		values = append(values, env.Value)
+	}
+	for _, value := range values {
+		if _, err := renderTemplateValue(value, params); err != nil {
+			return err
+		}
+	}
+	if tmpl.Spec.Plan != "" {
+		if _, err := servicemanager.Plan.FindByName(ctx, tmpl.Spec.Plan); err != nil {
+			return &tsuruErrors.ValidationError{Message: err.Error()}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	bindTypes "github.com/tsuru/tsuru/types/bind"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+type templateToken struct {
+	user        *authTypes.User
+	permissions []permTypes.Permission
+}
+
+func (t *templateToken) GetValue() string    { return "" }
+func (t *templateToken) GetUserName() string { return t.user.Email }
+func (t *templateToken) Engine() string      { return "test" }
+
+func (t *templateToken) User(ctx context.Context) (*authTypes.User, error) {
+	return t.user, nil
+}
+
+func (t *templateToken) Permissions(ctx context.Context) ([]permTypes.Permission, error) {
+	return t.permissions, nil
+}
+
+func backupTemplate(team string) *jobTypes.Template {
+	return &jobTypes.Template{
+		Name:      "db-backup",
+		TeamOwner: team,
+		Params: []jobTypes.TemplateParam{
+			{Name: "database", Required: true},
+			{Name: "version", Default: "15"},
+		},
+		Spec: jobTypes.TemplateSpec{
+			Image:    "postgres:{{.version}}",
+			Command:  []string{"pg_dump", "{{.database}}"},
+			Schedule: "0 3 * * *",
+			Envs:     []bindTypes.EnvVar{{Name: "PGDATABASE", Value: "{{.database}}"}},
+		},
+	}
+}
+
+func (s *S) TestRenderJobTemplate(c *check.C) {
+	svc := &templateService{}
+	j := jobTypes.Job{Name: "orders-backup", TeamOwner: s.team.Name, Pool: s.Pool}
+	err := svc.RenderJob(context.TODO(), backupTemplate(s.team.Name), map[string]string{"database": "orders"}, &j)
+	c.Assert(err, check.IsNil)
+	c.Assert(j.DeployOptions.Image, check.Equals, "postgres:15")
+	c.Assert(j.Spec.Container.Command, check.DeepEquals, []string{"pg_dump", "orders"})
+	c.Assert(j.Spec.Schedule, check.Equals, "0 3 * * *")
+	c.Assert(j.Spec.Envs, check.DeepEquals, []bindTypes.EnvVar{{Name: "PGDATABASE", Value: "orders", ManagedBy: templateEnvsManager}})
+	c.Assert(j.Template, check.DeepEquals, &jobTypes.TemplateRef{Name: "db-backup", Params: map[string]string{"database": "orders"}})
+
+	err = svc.RenderJob(context.TODO(), backupTemplate(s.team.Name), map[string]string{}, &j)
+	c.Assert(err, check.ErrorMatches, `missing required params for job template "db-backup": database`)
+	err = svc.RenderJob(context.TODO(), backupTemplate(s.team.Name), map[string]string{"database": "orders", "host": "db"}, &j)
+	c.Assert(err, check.ErrorMatches, `unknown param "host" for job template "db-backup"`)
+}
+
+func (s *S) TestCreateTemplateInvalid(c *check.C) {
+	svc := &templateService{}
+	tmpl := backupTemplate(s.team.Name)
+	tmpl.Spec.Image = ""
+	err := svc.CreateTemplate(context.TODO(), tmpl)
+	c.Assert(err, check.ErrorMatches, jobTypes.ErrTemplateNoImage.Error())
+	tmpl = backupTemplate(s.team.Name)
+	tmpl.Spec.Command = []string{"pg_dump", "{{.host}}"}
+	err = svc.CreateTemplate(context.TODO(), tmpl)
+	c.Assert(err, check.ErrorMatches, `unable to render "{{.host}}": .*`)
+	tmpl = backupTemplate(s.team.Name)
+	tmpl.Params = append(tmpl.Params, jobTypes.TemplateParam{Name: "db-host"})
+	err = svc.CreateTemplate(context.TODO(), tmpl)
+	c.Assert(err, check.ErrorMatches, jobTypes.ErrTemplateInvalidParam.Error()+`: "db-host"`)
+	_, err = svc.GetTemplate(context.TODO(), "db-backup")
+	c.Assert(err, check.Equals, jobTypes.ErrTemplateNotFound)
+}
+
+func (s *S) TestListTemplates(c *check.C) {
+	svc := &templateService{}
+	err := svc.CreateTemplate(context.TODO(), backupTemplate(s.team.Name))
+	c.Assert(err, check.IsNil)
+	shared := backupTemplate("")
+	shared.Name = "report"
+	err = svc.CreateTemplate(context.TODO(), shared)
+	c.Assert(err, check.IsNil)
+	templates, err := svc.ListTemplates(context.TODO(), &jobTypes.TemplateFilter{TeamOwners: []string{"other-team"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(templates, check.HasLen, 1)
+	c.Assert(templates[0].Name, check.Equals, "report")
+	templates, err = svc.ListTemplates(context.TODO(), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(templates, check.HasLen, 2)
+}
+
+func (s *S) TestApplyTemplate(c *check.C) {
+	svc := &templateService{}
+	tmpl := backupTemplate(s.team.Name)
+	err := svc.CreateTemplate(context.TODO(), tmpl)
+	c.Assert(err, check.IsNil)
+	j := jobTypes.Job{Name: "orders-backup", TeamOwner: s.team.Name, Pool: s.Pool}
+	err = svc.RenderJob(context.TODO(), tmpl, map[string]string{"database": "orders"}, &j)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Job.CreateJob(context.TODO(), &j, s.user)
+	c.Assert(err, check.IsNil)
+	dbJob, err := servicemanager.Job.GetByName(context.TODO(), "orders-backup")
+	c.Assert(err, check.IsNil)
+	err = SetEnvs(context.TODO(), dbJob, bindTypes.SetEnvArgs{Envs: []bindTypes.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}})
+	c.Assert(err, check.IsNil)
+	jobs, err := servicemanager.Job.List(context.TODO(), &jobTypes.Filter{Template: "db-backup"})
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.HasLen, 1)
+
+	tmpl.Params[1].Default = "16"
+	tmpl.Spec.Command = nil
+	tmpl.Spec.Envs = append(tmpl.Spec.Envs, bindTypes.EnvVar{Name: "PGHOST", Value: "db.internal"})
+	err = svc.UpdateTemplate(context.TODO(), tmpl)
+	c.Assert(err, check.IsNil)
+	token := &templateToken{user: s.user, permissions: []permTypes.Permission{
+		{Scheme: permission.PermJobUpdate, Context: permission.Context(permTypes.CtxTeam, s.team.Name)},
+	}}
+	results, err := svc.ApplyTemplate(context.TODO(), tmpl, token)
+	c.Assert(err, check.IsNil)
+	c.Assert(results, check.DeepEquals, []jobTypes.TemplateApplyResult{{Job: "orders-backup"}})
+	dbJob, err = servicemanager.Job.GetByName(context.TODO(), "orders-backup")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbJob.Spec.Container.OriginalImageSrc, check.Equals, "postgres:16")
+	c.Assert(dbJob.Spec.Container.Command, check.HasLen, 0)
+	evts, err := event.List(context.TODO(), &event.Filter{Target: eventTypes.Target{Type: eventTypes.TargetTypeJob, Value: "orders-backup"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].Kind.Name, check.Equals, permission.PermJobUpdate.FullName())
+	c.Assert(dbJob.Spec.Envs, check.DeepEquals, []bindTypes.EnvVar{
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "PGDATABASE", Value: "orders", ManagedBy: templateEnvsManager},
+		{Name: "PGHOST", Value: "db.internal", ManagedBy: templateEnvsManager},
+	})
+}
+
+func (s *S) TestApplyTemplateWithoutJobPermission(c *check.C) {
+	svc := &templateService{}
+	tmpl := backupTemplate(s.team.Name)
+	err := svc.CreateTemplate(context.TODO(), tmpl)
+	c.Assert(err, check.IsNil)
+	j := jobTypes.Job{Name: "orders-backup", TeamOwner: s.team.Name, Pool: s.Pool}
+	err = svc.RenderJob(context.TODO(), tmpl, map[string]string{"database": "orders"}, &j)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Job.CreateJob(context.TODO(), &j, s.user)
+	c.Assert(err, check.IsNil)
+	tmpl.Params[1].Default = "16"
+	err = svc.UpdateTemplate(context.TODO(), tmpl)
+	c.Assert(err, check.IsNil)
+	token := &templateToken{user: s.user, permissions: []permTypes.Permission{
+		{Scheme: permission.PermJobUpdate, Context: permission.Context(permTypes.CtxTeam, "other-team")},
+	}}
+	results, err := svc.ApplyTemplate(context.TODO(), tmpl, token)
+	c.Assert(err, check.IsNil)
+	c.Assert(results, check.DeepEquals, []jobTypes.TemplateApplyResult{{Job: "orders-backup", Error: permission.ErrUnauthorized.Error()}})
+	dbJob, err := servicemanager.Job.GetByName(context.TODO(), "orders-backup")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbJob.Spec.Container.OriginalImageSrc, check.Equals, "postgres:15")
+}
+
+func (s *S) TestRemoveTemplateDetachesJobs(c *check.C) {
+	svc := &templateService{}
+	tmpl := backupTemplate(s.team.Name)
+	err := svc.CreateTemplate(context.TODO(), tmpl)
+	c.Assert(err, check.IsNil)
+	j := jobTypes.Job{Name: "orders-backup", TeamOwner: s.team.Name, Pool: s.Pool}
+	err = svc.RenderJob(context.TODO(), tmpl, map[string]string{"database": "orders"}, &j)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Job.CreateJob(context.TODO(), &j, s.user)
+	c.Assert(err, check.IsNil)
+	err = svc.RemoveTemplate(context.TODO(), tmpl)
+	c.Assert(err, check.IsNil)
+	dbJob, err := servicemanager.Job.GetByName(context.TODO(), "orders-backup")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbJob.Template, check.IsNil)
+	c.Assert(dbJob.Spec.Container.Command, check.DeepEquals, []string{"pg_dump", "orders"})
+}
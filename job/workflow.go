@@ -0,0 +1,573 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tsuru/tsuru/db/storagev2"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	"github.com/tsuru/tsuru/validation"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const defaultWorkflowRunsLimit = 20
+
+type workflowService struct{}
+
+var _ jobTypes.WorkflowService = &workflowService{}
+
+func WorkflowService() (jobTypes.WorkflowService, error) {
+	return &workflowService{}, nil
+}
+
+func (*workflowService) CreateWorkflow(ctx context.Context, wf *jobTypes.Workflow) error {
+	if err := validateWorkflow(ctx, wf); err != nil {
+		return err
+	}
+	collection, err := storagev2.JobWorkflowsCollection()
+	if err != nil {
+		return err
+	}
+	_, err = collection.InsertOne(ctx, wf)
+	if mongo.IsDuplicateKeyError(err) {
+		return jobTypes.ErrWorkflowAlreadyExists
+	}
+	return err
+}
+
+func (*workflowService) UpdateWorkflow(ctx context.Context, wf *jobTypes.Workflow) error {
+	if err := validateWorkflow(ctx, wf); err != nil {
+		return err
+	}
+	collection, err := storagev2.JobWorkflowsCollection()
+	if err != nil {
+		return err
+	}
+	result, err := collection.ReplaceOne(ctx, mongoBSON.M{"name": wf.Name}, wf)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return jobTypes.ErrWorkflowNotFound
+	}
+	return nil
+}
+
+func (*workflowService) GetWorkflow(ctx context.Context, name string) (*jobTypes.Workflow, error) {
+	collection, err := storagev2.JobWorkflowsCollection()
+	if err != nil {
+		return nil, err
+	}
+	var wf jobTypes.Workflow
+	err = collection.FindOne(ctx, mongoBSON.M{"name": name}).Decode(&wf)
+	if err == mongo.ErrNoDocuments {
+		return nil, jobTypes.ErrWorkflowNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &wf, nil
+}
+
+func (*workflowService) ListWorkflows(ctx context.Context, filter *jobTypes.WorkflowFilter) ([]jobTypes.Workflow, error) {
+	collection, err := storagev2.JobWorkflowsCollection()
+	if err != nil {
+		return nil, err
+	}
+	query := mongoBSON.M{}
+	if filter != nil {
+		if filter.TeamOwners != nil {
+			query["teamowner"] = mongoBSON.M{"$in": filter.TeamOwners}
+		}
+		if filter.Job != "" {
+			query["nodes"] = filter.Job
+		}
+	}
+	cursor, err := collection.Find(ctx, query, options.Find().SetSort(mongoBSON.M{"name": 1}))
+	if err != nil {
+		return nil, err
+	}
+	workflows := []jobTypes.Workflow{}
+	if err = cursor.All(ctx, &workflows); err != nil {
+		return nil, err
+	}
+	return workflows, nil
+}
+
+// RemoveWorkflow removes the workflow and its run history. Jobs still
+// running finish on their own, without triggering downstream jobs.
+func (*workflowService) RemoveWorkflow(ctx context.Context, wf *jobTypes.Workflow) error {
+	collection, err := storagev2.JobWorkflowsCollection()
+	if err != nil {
+		return err
+	}
+	result, err := collection.DeleteOne(ctx, mongoBSON.M{"name": wf.Name})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return jobTypes.ErrWorkflowNotFound
+	}
+	runsCollection, err := storagev2.JobWorkflowRunsCollection()
+	if err != nil {
+		return err
+	}
+	_, err = runsCollection.DeleteMany(ctx, mongoBSON.M{"workflow": wf.Name})
+	return err
+}
+
+// RunWorkflow starts a new run of the workflow, triggering every job without
+// upstream jobs.
+func (*workflowService) RunWorkflow(ctx context.Context, wf *jobTypes.Workflow, owner string) (*jobTypes.WorkflowRun, error) {
+	run := &jobTypes.WorkflowRun{
+		Workflow: wf.Name,
+		Owner:    owner,
+		Edges:    wf.Edges,
+	}
+	for _, node := range wf.Nodes {
+		run.Nodes = append(run.Nodes, jobTypes.WorkflowRunNode{Job: node, Status: jobTypes.WorkflowNodePending})
+	}
+	return startWorkflowRun(ctx, wf, run)
+}
+
+func (*workflowService) ListWorkflowRuns(ctx context.Context, wf *jobTypes.Workflow, limit int) ([]jobTypes.WorkflowRun, error) {
+	collection, err := storagev2.JobWorkflowRunsCollection()
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultWorkflowRunsLimit
+	}
+	opts := options.Find().SetSort(mongoBSON.M{"starttime": -1}).SetLimit(int64(limit))
+	cursor, err := collection.Find(ctx, mongoBSON.M{"workflow": wf.Name}, opts)
+	if err != nil {
+		return nil, err
+	}
+	runs := []jobTypes.WorkflowRun{}
+	if err = cursor.All(ctx, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (*workflowService) GetWorkflowRun(ctx context.Context, wf *jobTypes.Workflow, id string) (*jobTypes.WorkflowRun, error) {
+	run, err := getWorkflowRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if run.Workflow != wf.Name {
+		return nil, jobTypes.ErrWorkflowRunNotFound
+	}
+	return run, nil
+}
+
+// CancelWorkflowRun stops a running workflow, pending jobs are never
+// triggered and running jobs are killed.
+func (*workflowService) CancelWorkflowRun(ctx context.Context, run *jobTypes.WorkflowRun, owner string) error {
+	collection, err := storagev2.JobWorkflowRunsCollection()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+		mongoBSON.M{"n.status": mongoBSON.M{"$in": []jobTypes.WorkflowNodeStatus{jobTypes.WorkflowNodePending, jobTypes.WorkflowNodeRunning}}},
+	}})
+	result, err := collection.UpdateOne(ctx, mongoBSON.M{"_id": run.ID, "status": jobTypes.WorkflowRunRunning}, mongoBSON.M{"$set": mongoBSON.M{
+		"status":             jobTypes.WorkflowRunCanceled,
+		"endtime":            now,
+		"nodes.$[n].status":  jobTypes.WorkflowNodeCanceled,
+		"nodes.$[n].endtime": now,
+		"nodes.$[n].message": fmt.Sprintf("canceled by %s", owner),
+	}}, opts)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return jobTypes.ErrWorkflowRunNotRunning
+	}
+	for _, node := range run.Nodes {
+		if node.Status == jobTypes.WorkflowNodeRunning && node.Run != "" {
+			killWorkflowNode(ctx, node.Job, node.Run)
+		}
+	}
+	return nil
+}
+
+// RerunWorkflow starts a new run from a finished one. The given node and
+// every node downstream of it run again, the others keep the outcome they had
+// in the original run.
+func (*workflowService) RerunWorkflow(ctx context.Context, wf *jobTypes.Workflow, run *jobTypes.WorkflowRun, fromNode, owner string) (*jobTypes.WorkflowRun, error) {
+	if run.Status == jobTypes.WorkflowRunRunning {
+		return nil, jobTypes.ErrWorkflowRunStillRunning
+	}
+	if nodeIndex(run.Nodes, fromNode) < 0 {
+		return nil, &tsuruErrors.ValidationError{Message: jobTypes.ErrWorkflowNodeNotFound.Error()}
+	}
+	rerun := downstreamNodes(run.Edges, fromNode)
+	newRun := &jobTypes.WorkflowRun{
+		Workflow: wf.Name,
+		Owner:    owner,
+		RerunOf:  run.ID,
+		Edges:    run.Edges,
+	}
+	for _, node := range run.Nodes {
+		if rerun[node.Job] {
+			node = jobTypes.WorkflowRunNode{Job: node.Job, Status: jobTypes.WorkflowNodePending}
+		}
+		newRun.Nodes = append(newRun.Nodes, node)
+	}
+	return startWorkflowRun(ctx, wf, newRun)
+}
+
+func startWorkflowRun(ctx context.Context, wf *jobTypes.Workflow, run *jobTypes.WorkflowRun) (*jobTypes.WorkflowRun, error) {
+	run.ID = primitive.NewObjectID().Hex()
+	run.Status = jobTypes.WorkflowRunRunning
+	run.StartTime = time.Now().UTC()
+	evt, err := event.New(ctx, &event.Opts{
+		Target:      eventTypes.Target{Type: eventTypes.TargetTypeJobWorkflow, Value: wf.Name},
+		Kind:        permission.PermJobWorkflowRun,
+		RawOwner:    eventTypes.Owner{Type: eventTypes.OwnerTypeUser, Name: run.Owner},
+		CustomData:  map[string]string{"run": run.ID, "rerunOf": run.RerunOf},
+		DisableLock: true,
+		Allowed:     event.Allowed(permission.PermJobWorkflowRead, permission.Context(permTypes.CtxTeam, wf.TeamOwner)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	run.EventID = evt.UniqueID.Hex()
+	collection, err := storagev2.JobWorkflowRunsCollection()
+	if err != nil {
+		evt.Done(ctx, err)
+		return nil, err
+	}
+	if _, err = collection.InsertOne(ctx, run); err != nil {
+		evt.Done(ctx, err)
+		return nil, err
+	}
+	err = advanceWorkflowRun(ctx, run.ID)
+	evt.Done(ctx, err)
+	if err != nil {
+		return nil, err
+	}
+	return getWorkflowRun(ctx, run.ID)
+}
+
+func getWorkflowRun(ctx context.Context, id string) (*jobTypes.WorkflowRun, error) {
+	collection, err := storagev2.JobWorkflowRunsCollection()
+	if err != nil {
+		return nil, err
+	}
+	var run jobTypes.WorkflowRun
+	err = collection.FindOne(ctx, mongoBSON.M{"_id": id}).Decode(&run)
+	if err == mongo.ErrNoDocuments {
+		return nil, jobTypes.ErrWorkflowRunNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// advanceWorkflowRun triggers the pending nodes whose upstream jobs are done,
+// skips the ones that can no longer run and finishes the run once every node
+// is done. Nodes are claimed with conditional updates so concurrent calls
+// never trigger the same node twice.
+func advanceWorkflowRun(ctx context.Context, id string) error {
+	collection, err := storagev2.JobWorkflowRunsCollection()
+	if err != nil {
+		return err
+	}
+	for {
+		run, err := getWorkflowRun(ctx, id)
+		if err != nil {
+			return err
+		}
+		if run.Status != jobTypes.WorkflowRunRunning {
+			return nil
+		}
+		changed := false
+		for _, node := range run.Nodes {
+			if node.Status != jobTypes.WorkflowNodePending {
+				continue
+			}
+			ready, skip := nodeReadiness(run, node.Job)
+			if !ready && !skip {
+				continue
+			}
+			now := time.Now().UTC()
+			update := mongoBSON.M{"nodes.$.status": jobTypes.WorkflowNodeRunning, "nodes.$.starttime": now}
+			if skip {
+				update = mongoBSON.M{"nodes.$.status": jobTypes.WorkflowNodeSkipped, "nodes.$.endtime": now}
+			}
+			claimed, err := updateWorkflowNode(ctx, collection, run.ID, node.Job, jobTypes.WorkflowNodePending, update)
+			if err != nil {
+				return err
+			}
+			if !claimed {
+				continue
+			}
+			changed = true
+			if ready {
+				triggerWorkflowNode(ctx, collection, run, node.Job)
+			}
+		}
+		if !changed {
+			return finishWorkflowRun(ctx, collection, run)
+		}
+	}
+}
+
+// nodeReadiness checks the incoming edges of a node. A node is ready once
+// every upstream job finished with the outcome required by its edge and must
+// be skipped when any of them finished otherwise.
+func nodeReadiness(run *jobTypes.WorkflowRun, job string) (ready, skip bool) {
+	ready = true
+	for _, edge := range run.Edges {
+		if edge.To != job {
+			continue
+		}
+		idx := nodeIndex(run.Nodes, edge.From)
+		if idx < 0 {
+			continue
+		}
+		upstream := run.Nodes[idx].Status
+		if !upstream.IsFinished() {
+			ready = false
+			continue
+		}
+		satisfied := (edge.On == jobTypes.WorkflowOnSuccess && upstream == jobTypes.WorkflowNodeSucceeded) ||
+			(edge.On == jobTypes.WorkflowOnFailure && upstream == jobTypes.WorkflowNodeFailed)
+		if !satisfied {
+			return false, true
+		}
+	}
+	return ready, false
+}
+
+func triggerWorkflowNode(ctx context.Context, collection *mongo.Collection, run *jobTypes.WorkflowRun, jobName string) {
+	svc := &jobService{}
+	evt, err := event.New(ctx, &event.Opts{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeJob, Value: jobName},
+		ExtraTargets: []eventTypes.ExtraTarget{
+			{Target: eventTypes.Target{Type: eventTypes.TargetTypeJobWorkflow, Value: run.Workflow}},
+		},
+		Kind:        permission.PermJobTrigger,
+		RawOwner:    eventTypes.Owner{Type: eventTypes.OwnerTypeUser, Name: run.Owner},
+		CustomData:  map[string]string{"workflow": run.Workflow, "run": run.ID, "parent": run.EventID},
+		DisableLock: true,
+		Allowed:     event.Allowed(permission.PermJobReadEvents, permission.Context(permTypes.CtxJob, jobName)),
+	})
+	if err != nil {
+		log.Errorf("[workflow] unable to create event for node %s of run %s: %v", jobName, run.ID, err)
+	} else {
+		updateWorkflowNode(ctx, collection, run.ID, jobName, jobTypes.WorkflowNodeRunning, mongoBSON.M{"nodes.$.eventid": evt.UniqueID.Hex()})
+	}
+	job, err := svc.GetByName(ctx, jobName)
+	if err == nil {
+		err = svc.Trigger(ctx, job, jobTypes.TriggerOptions{TriggeredBy: run.Owner, WorkflowRun: run.ID})
+	}
+	if evt != nil {
+		evt.Done(ctx, err)
+	}
+	if err != nil {
+		updateWorkflowNode(ctx, collection, run.ID, jobName, jobTypes.WorkflowNodeRunning, mongoBSON.M{
+			"nodes.$.status":  jobTypes.WorkflowNodeFailed,
+			"nodes.$.endtime": time.Now().UTC(),
+			"nodes.$.message": err.Error(),
+		})
+	}
+}
+
+func finishWorkflowRun(ctx context.Context, collection *mongo.Collection, run *jobTypes.WorkflowRun) error {
+	status := jobTypes.WorkflowRunSucceeded
+	for _, node := range run.Nodes {
+		if !node.Status.IsFinished() {
+			return nil
+		}
+		if node.Status == jobTypes.WorkflowNodeFailed {
+			status = jobTypes.WorkflowRunFailed
+		}
+	}
+	_, err := collection.UpdateOne(ctx, mongoBSON.M{"_id": run.ID, "status": jobTypes.WorkflowRunRunning}, mongoBSON.M{
+		"$set": mongoBSON.M{"status": status, "endtime": time.Now().UTC()},
+	})
+	return err
+}
+
+func updateWorkflowNode(ctx context.Context, collection *mongo.Collection, runID, job string, status jobTypes.WorkflowNodeStatus, update mongoBSON.M) (bool, error) {
+	result, err := collection.UpdateOne(ctx, mongoBSON.M{
+		"_id":    runID,
+		"status": jobTypes.WorkflowRunRunning,
+		"nodes":  mongoBSON.M{"$elemMatch": mongoBSON.M{"job": job, "status": status}},
+	}, mongoBSON.M{"$set": update})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// onWorkflowJobRun updates the workflow run that spawned a job execution,
+// triggering the downstream jobs once the execution finishes.
+func onWorkflowJobRun(ctx context.Context, jobRun jobTypes.Run) error {
+	run, err := getWorkflowRun(ctx, jobRun.WorkflowRun)
+	if err == jobTypes.ErrWorkflowRunNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	idx := nodeIndex(run.Nodes, jobRun.Job)
+	if idx < 0 {
+		return nil
+	}
+	node := run.Nodes[idx]
+	if node.Status == jobTypes.WorkflowNodeCanceled && !jobRun.IsFinished() {
+		// the run was canceled before the execution was known
+		killWorkflowNode(ctx, jobRun.Job, jobRun.Name)
+		return nil
+	}
+	if node.Status != jobTypes.WorkflowNodeRunning {
+		return nil
+	}
+	collection, err := storagev2.JobWorkflowRunsCollection()
+	if err != nil {
+		return err
+	}
+	update := mongoBSON.M{"nodes.$.run": jobRun.Name}
+	if jobRun.IsFinished() {
+		update["nodes.$.status"] = jobTypes.WorkflowNodeSucceeded
+		if jobRun.Status == jobTypes.RunStatusFailed {
+			update["nodes.$.status"] = jobTypes.WorkflowNodeFailed
+		}
+		update["nodes.$.endtime"] = jobRun.EndTime
+		update["nodes.$.message"] = jobRun.Message
+	}
+	updated, err := updateWorkflowNode(ctx, collection, run.ID, jobRun.Job, jobTypes.WorkflowNodeRunning, update)
+	if err != nil || !updated || !jobRun.IsFinished() {
+		return err
+	}
+	return advanceWorkflowRun(ctx, run.ID)
+}
+
+func killWorkflowNode(ctx context.Context, jobName, runName string) {
+	svc := &jobService{}
+	job, err := svc.GetByName(ctx, jobName)
+	if err == nil {
+		err = svc.KillUnit(ctx, job, runName, false)
+	}
+	if err != nil {
+		log.Errorf("[workflow] unable to kill run %s of job %s: %v", runName, jobName, err)
+	}
+}
+
+func nodeIndex(nodes []jobTypes.WorkflowRunNode, job string) int {
+	for i := range nodes {
+		if nodes[i].Job == job {
+			return i
+		}
+	}
+	return -1
+}
+
+// downstreamNodes returns the given node and every node reachable from it.
+func downstreamNodes(edges []jobTypes.WorkflowEdge, from string) map[string]bool {
+	result := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range edges {
+			if edge.From == current && !result[edge.To] {
+				result[edge.To] = true
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+	return result
+}
+
+func validateWorkflow(ctx context.Context, wf *jobTypes.Workflow) error {
+	if !validation.ValidateName(wf.Name) {
+		return &tsuruErrors.ValidationError{Message: "Invalid workflow name, workflow name should have at most 40 " +
+			"characters, containing only lower case letters, numbers or dashes, starting with a letter."}
+	}
+	if _, err := servicemanager.Team.FindByName(ctx, wf.TeamOwner); err != nil {
+		return &tsuruErrors.ValidationError{Message: err.Error()}
+	}
+	if err := validateWorkflowGraph(wf); err != nil {
+		return err
+	}
+	svc := &jobService{}
+	for _, node := range wf.Nodes {
+		if _, err := svc.GetByName(ctx, node); err != nil {
+			if err == jobTypes.ErrJobNotFound {
+				return &tsuruErrors.ValidationError{Message: fmt.Sprintf("job %q not found", node)}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func validateWorkflowGraph(wf *jobTypes.Workflow) error {
+	if len(wf.Nodes) == 0 {
+		return &tsuruErrors.ValidationError{Message: jobTypes.ErrWorkflowNoNodes.Error()}
+	}
+	nodes := map[string]bool{}
+	for _, node := range wf.Nodes {
+		if nodes[node] {
+			return &tsuruErrors.ValidationError{Message: fmt.Sprintf("job %q is listed more than once", node)}
+		}
+		nodes[node] = true
+	}
+	inDegree := map[string]int{}
+	for _, edge := range wf.Edges {
+		if !nodes[edge.From] || !nodes[edge.To] {
+			return &tsuruErrors.ValidationError{Message: fmt.Sprintf("edge %s -> %s: %s", edge.From, edge.To, jobTypes.ErrWorkflowNodeNotFound)}
+		}
+		if edge.On != jobTypes.WorkflowOnSuccess && edge.On != jobTypes.WorkflowOnFailure {
+			return &tsuruErrors.ValidationError{Message: jobTypes.ErrInvalidWorkflowCondition.Error()}
+		}
+		inDegree[edge.To]++
+	}
+	var queue []string
+	for _, node := range wf.Nodes {
+		if inDegree[node] == 0 {
+			queue = append(queue, node)
+		}
+	}
+	visited := 0
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, edge := range wf.Edges {
+			if edge.From != current {
+				continue
+			}
+			inDegree[edge.To]--
+			if inDegree[edge.To] == 0 {
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+	if visited != len(wf.Nodes) {
+		return &tsuruErrors.ValidationError{Message: jobTypes.ErrWorkflowCycle.Error()}
+	}
+	return nil
+}
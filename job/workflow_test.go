@@ -0,0 +1,263 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/tsuru/servicemanager"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	provisionTypes "github.com/tsuru/tsuru/types/provision"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) createWorkflowJobs(c *check.C, names ...string) {
+	for _, name := range names {
+		j := jobTypes.Job{
+			Name:      name,
+			TeamOwner: s.team.Name,
+			Pool:      s.Pool,
+			Teams:     []string{s.team.Name},
+			Spec:      jobTypes.JobSpec{Manual: true},
+			DeployOptions: &jobTypes.DeployOptions{
+				Kind:  provisionTypes.DeployImage,
+				Image: "alpine:latest",
+			},
+		}
+		err := servicemanager.Job.CreateJob(context.TODO(), &j, s.user)
+		c.Assert(err, check.IsNil)
+	}
+}
+
+func (s *S) finishWorkflowJob(c *check.C, run *jobTypes.WorkflowRun, job string, status jobTypes.RunStatus) {
+	end := time.Now().UTC()
+	err := servicemanager.Job.RecordRun(context.TODO(), jobTypes.Run{
+		Name:        job + "-manual",
+		Job:         job,
+		Status:      status,
+		StartTime:   end.Add(-time.Minute),
+		EndTime:     &end,
+		WorkflowRun: run.ID,
+	})
+	c.Assert(err, check.IsNil)
+}
+
+func nodeStatuses(run *jobTypes.WorkflowRun) map[string]jobTypes.WorkflowNodeStatus {
+	statuses := map[string]jobTypes.WorkflowNodeStatus{}
+	for _, node := range run.Nodes {
+		statuses[node.Job] = node.Status
+	}
+	return statuses
+}
+
+func (s *S) TestValidateWorkflowGraph(c *check.C) {
+	tests := []struct {
+		wf       jobTypes.Workflow
+		expected string
+	}{
+		{
+			wf:       jobTypes.Workflow{},
+			expected: jobTypes.ErrWorkflowNoNodes.Error(),
+		},
+		{
+			wf:       jobTypes.Workflow{Nodes: []string{"a", "a"}},
+			expected: `job "a" is listed more than once`,
+		},
+		{
+			wf:       jobTypes.Workflow{Nodes: []string{"a"}, Edges: []jobTypes.WorkflowEdge{{From: "a", To: "b", On: jobTypes.WorkflowOnSuccess}}},
+			expected: "edge a -> b: " + jobTypes.ErrWorkflowNodeNotFound.Error(),
+		},
+		{
+			wf:       jobTypes.Workflow{Nodes: []string{"a", "b"}, Edges: []jobTypes.WorkflowEdge{{From: "a", To: "b", On: "always"}}},
+			expected: jobTypes.ErrInvalidWorkflowCondition.Error(),
+		},
+		{
+			wf: jobTypes.Workflow{Nodes: []string{"a", "b", "c"}, Edges: []jobTypes.WorkflowEdge{
+				{From: "a", To: "b", On: jobTypes.WorkflowOnSuccess},
+				{From: "b", To: "c", On: jobTypes.WorkflowOnSuccess},
+				{From: "c", To: "b", On: jobTypes.WorkflowOnFailure},
+			}},
+			expected: jobTypes.ErrWorkflowCycle.Error(),
+		},
+	}
+	for i, tt := range tests {
+		err := validateWorkflowGraph(&tt.wf)
+		c.Check(err, check.ErrorMatches, tt.expected, check.Commentf("test %d", i))
+	}
+	err := validateWorkflowGraph(&jobTypes.Workflow{Nodes: []string{"a", "b", "c"}, Edges: []jobTypes.WorkflowEdge{
+		{From: "a", To: "b", On: jobTypes.WorkflowOnSuccess},
+		{From: "a", To: "c", On: jobTypes.WorkflowOnFailure},
+	}})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestNodeReadiness(c *check.C) {
+	run := &jobTypes.WorkflowRun{
+		Edges: []jobTypes.WorkflowEdge{
+			{From: "a", To: "c", On: jobTypes.WorkflowOnSuccess},
+			{From: "b", To: "c", On: jobTypes.WorkflowOnSuccess},
+			{From: "a", To: "d", On: jobTypes.WorkflowOnFailure},
+		},
+		Nodes: []jobTypes.WorkflowRunNode{
+			{Job: "a", Status: jobTypes.WorkflowNodeSucceeded},
+			{Job: "b", Status: jobTypes.WorkflowNodeRunning},
+			{Job: "c", Status: jobTypes.WorkflowNodePending},
+			{Job: "d", Status: jobTypes.WorkflowNodePending},
+		},
+	}
+	ready, skip := nodeReadiness(run, "a")
+	c.Assert(ready, check.Equals, true)
+	c.Assert(skip, check.Equals, false)
+	ready, skip = nodeReadiness(run, "c")
+	c.Assert(ready, check.Equals, false)
+	c.Assert(skip, check.Equals, false)
+	ready, skip = nodeReadiness(run, "d")
+	c.Assert(ready, check.Equals, false)
+	c.Assert(skip, check.Equals, true)
+	run.Nodes[1].Status = jobTypes.WorkflowNodeSucceeded
+	ready, skip = nodeReadiness(run, "c")
+	c.Assert(ready, check.Equals, true)
+	c.Assert(skip, check.Equals, false)
+}
+
+func (s *S) TestDownstreamNodes(c *check.C) {
+	edges := []jobTypes.WorkflowEdge{
+		{From: "a", To: "b", On: jobTypes.WorkflowOnSuccess},
+		{From: "b", To: "c", On: jobTypes.WorkflowOnSuccess},
+		{From: "b", To: "d", On: jobTypes.WorkflowOnFailure},
+		{From: "x", To: "c", On: jobTypes.WorkflowOnSuccess},
+	}
+	c.Assert(downstreamNodes(edges, "b"), check.DeepEquals, map[string]bool{"b": true, "c": true, "d": true})
+	c.Assert(downstreamNodes(edges, "c"), check.DeepEquals, map[string]bool{"c": true})
+}
+
+func (s *S) TestWorkflowRun(c *check.C) {
+	s.createWorkflowJobs(c, "extract", "transform", "load", "notify")
+	svc, err := WorkflowService()
+	c.Assert(err, check.IsNil)
+	wf := &jobTypes.Workflow{
+		Name:      "etl",
+		TeamOwner: s.team.Name,
+		Nodes:     []string{"extract", "transform", "load", "notify"},
+		Edges: []jobTypes.WorkflowEdge{
+			{From: "extract", To: "transform", On: jobTypes.WorkflowOnSuccess},
+			{From: "transform", To: "load", On: jobTypes.WorkflowOnSuccess},
+			{From: "transform", To: "notify", On: jobTypes.WorkflowOnFailure},
+		},
+	}
+	err = svc.CreateWorkflow(context.TODO(), wf)
+	c.Assert(err, check.IsNil)
+	run, err := svc.RunWorkflow(context.TODO(), wf, s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(run.Status, check.Equals, jobTypes.WorkflowRunRunning)
+	c.Assert(run.EventID, check.Not(check.Equals), "")
+	c.Assert(nodeStatuses(run), check.DeepEquals, map[string]jobTypes.WorkflowNodeStatus{
+		"extract":   jobTypes.WorkflowNodeRunning,
+		"transform": jobTypes.WorkflowNodePending,
+		"load":      jobTypes.WorkflowNodePending,
+		"notify":    jobTypes.WorkflowNodePending,
+	})
+	c.Assert(s.provisioner.JobExecutions("extract"), check.Equals, 1)
+	c.Assert(s.provisioner.LastJobTrigger("extract").WorkflowRun, check.Equals, run.ID)
+	s.finishWorkflowJob(c, run, "extract", jobTypes.RunStatusSucceeded)
+	c.Assert(s.provisioner.JobExecutions("transform"), check.Equals, 1)
+	s.finishWorkflowJob(c, run, "transform", jobTypes.RunStatusSucceeded)
+	c.Assert(s.provisioner.JobExecutions("load"), check.Equals, 1)
+	s.finishWorkflowJob(c, run, "load", jobTypes.RunStatusSucceeded)
+	c.Assert(s.provisioner.JobExecutions("notify"), check.Equals, 0)
+	run, err = svc.GetWorkflowRun(context.TODO(), wf, run.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(run.Status, check.Equals, jobTypes.WorkflowRunSucceeded)
+	c.Assert(run.EndTime, check.NotNil)
+	c.Assert(nodeStatuses(run), check.DeepEquals, map[string]jobTypes.WorkflowNodeStatus{
+		"extract":   jobTypes.WorkflowNodeSucceeded,
+		"transform": jobTypes.WorkflowNodeSucceeded,
+		"load":      jobTypes.WorkflowNodeSucceeded,
+		"notify":    jobTypes.WorkflowNodeSkipped,
+	})
+	c.Assert(run.Nodes[0].Run, check.Equals, "extract-manual")
+}
+
+func (s *S) TestWorkflowRunFailureAndRerun(c *check.C) {
+	s.createWorkflowJobs(c, "extract", "transform", "load", "notify")
+	svc, err := WorkflowService()
+	c.Assert(err, check.IsNil)
+	wf := &jobTypes.Workflow{
+		Name:      "etl",
+		TeamOwner: s.team.Name,
+		Nodes:     []string{"extract", "transform", "load", "notify"},
+		Edges: []jobTypes.WorkflowEdge{
+			{From: "extract", To: "transform", On: jobTypes.WorkflowOnSuccess},
+			{From: "transform", To: "load", On: jobTypes.WorkflowOnSuccess},
+			{From: "transform", To: "notify", On: jobTypes.WorkflowOnFailure},
+		},
+	}
+	err = svc.CreateWorkflow(context.TODO(), wf)
+	c.Assert(err, check.IsNil)
+	run, err := svc.RunWorkflow(context.TODO(), wf, s.user.Email)
+	c.Assert(err, check.IsNil)
+	_, err = svc.RerunWorkflow(context.TODO(), wf, run, "transform", s.user.Email)
+	c.Assert(err, check.Equals, jobTypes.ErrWorkflowRunStillRunning)
+	s.finishWorkflowJob(c, run, "extract", jobTypes.RunStatusSucceeded)
+	s.finishWorkflowJob(c, run, "transform", jobTypes.RunStatusFailed)
+	c.Assert(s.provisioner.JobExecutions("notify"), check.Equals, 1)
+	s.finishWorkflowJob(c, run, "notify", jobTypes.RunStatusSucceeded)
+	run, err = svc.GetWorkflowRun(context.TODO(), wf, run.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(run.Status, check.Equals, jobTypes.WorkflowRunFailed)
+	c.Assert(nodeStatuses(run)["load"], check.Equals, jobTypes.WorkflowNodeSkipped)
+	rerun, err := svc.RerunWorkflow(context.TODO(), wf, run, "transform", s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(rerun.RerunOf, check.Equals, run.ID)
+	c.Assert(nodeStatuses(rerun), check.DeepEquals, map[string]jobTypes.WorkflowNodeStatus{
+		"extract":   jobTypes.WorkflowNodeSucceeded,
+		"transform": jobTypes.WorkflowNodeRunning,
+		"load":      jobTypes.WorkflowNodePending,
+		"notify":    jobTypes.WorkflowNodePending,
+	})
+	c.Assert(s.provisioner.JobExecutions("extract"), check.Equals, 1)
+	c.Assert(s.provisioner.JobExecutions("transform"), check.Equals, 2)
+	runs, err := svc.ListWorkflowRuns(context.TODO(), wf, 0)
+	c.Assert(err, check.IsNil)
+	c.Assert(runs, check.HasLen, 2)
+	c.Assert(runs[0].ID, check.Equals, rerun.ID)
+}
+
+func (s *S) TestCancelWorkflowRun(c *check.C) {
+	s.createWorkflowJobs(c, "extract", "load")
+	svc, err := WorkflowService()
+	c.Assert(err, check.IsNil)
+	wf := &jobTypes.Workflow{
+		Name:      "etl",
+		TeamOwner: s.team.Name,
+		Nodes:     []string{"extract", "load"},
+		Edges:     []jobTypes.WorkflowEdge{{From: "extract", To: "load", On: jobTypes.WorkflowOnSuccess}},
+	}
+	err = svc.CreateWorkflow(context.TODO(), wf)
+	c.Assert(err, check.IsNil)
+	run, err := svc.RunWorkflow(context.TODO(), wf, s.user.Email)
+	c.Assert(err, check.IsNil)
+	err = svc.CancelWorkflowRun(context.TODO(), run, s.user.Email)
+	c.Assert(err, check.IsNil)
+	err = svc.CancelWorkflowRun(context.TODO(), run, s.user.Email)
+	c.Assert(err, check.Equals, jobTypes.ErrWorkflowRunNotRunning)
+	s.finishWorkflowJob(c, run, "extract", jobTypes.RunStatusSucceeded)
+	c.Assert(s.provisioner.JobExecutions("load"), check.Equals, 0)
+	run, err = svc.GetWorkflowRun(context.TODO(), wf, run.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(run.Status, check.Equals, jobTypes.WorkflowRunCanceled)
+	c.Assert(nodeStatuses(run), check.DeepEquals, map[string]jobTypes.WorkflowNodeStatus{
+		"extract": jobTypes.WorkflowNodeCanceled,
+		"load":    jobTypes.WorkflowNodeCanceled,
+	})
+}
+
+func (s *S) TestCreateWorkflowUnknownJob(c *check.C) {
+	svc, err := WorkflowService()
+	c.Assert(err, check.IsNil)
+	err = svc.CreateWorkflow(context.TODO(), &jobTypes.Workflow{Name: "etl", TeamOwner: s.team.Name, Nodes: []string{"missing"}})
+	c.Assert(err, check.ErrorMatches, `job "missing" not found`)
+}
@@ -61,9 +61,27 @@ func ListContextValues(ctx context.Context, t Token, scheme *permTypes.Permissio
 	return values, nil
 }
 
+// ContextsFromListForPermission returns the contexts where the permission is
+// granted. Permissions restricted by a role condition are left out, as they
+// only apply to the objects matching the condition, see
+// ConditionalContextsFromListForPermission.
 func ContextsFromListForPermission(perms []permTypes.Permission, scheme *permTypes.PermissionScheme, ctxTypes ...permTypes.ContextType) []permTypes.PermissionContext {
+	return contextsFromList(perms, scheme, false, ctxTypes...)
+}
+
+// ConditionalContextsFromListForPermission returns the contexts of the
+// permissions restricted by a role condition. Objects found in these contexts
+// must still be checked with CheckFromPermList.
+func ConditionalContextsFromListForPermission(perms []permTypes.Permission, scheme *permTypes.PermissionScheme, ctxTypes ...permTypes.ContextType) []permTypes.PermissionContext {
+	return contextsFromList(perms, scheme, true, ctxTypes...)
+}
+
+func contextsFromList(perms []permTypes.Permission, scheme *permTypes.PermissionScheme, conditional bool, ctxTypes ...permTypes.ContextType) []permTypes.PermissionContext {
 	var contexts []permTypes.PermissionContext
 	for _, perm := range perms {
+		if (perm.Condition != nil) != conditional {
+			continue
+		}
 		if perm.Scheme.IsParent(scheme) {
 			if len(ctxTypes) > 0 {
 				for _, t := range ctxTypes {
@@ -100,6 +118,9 @@ func Check(ctx context.Context, token Token, scheme *permTypes.PermissionScheme,
 func CheckFromPermList(perms []permTypes.Permission, scheme *permTypes.PermissionScheme, contexts ...permTypes.PermissionContext) bool {
 	for _, perm := range perms {
 		if perm.Scheme.IsParent(scheme) {
+			if perm.Condition != nil && !perm.Condition.Matches(contexts) {
+				continue
+			}
 			if perm.Context.CtxType == permTypes.CtxGlobal {
 				return true
 			}
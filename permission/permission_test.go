@@ -81,6 +81,60 @@ func (s *S) TestCheckSuperToken(c *check.C) {
 	c.Assert(Check(ctx, t, PermAppUpdateEnvUnset), check.Equals, true)
 }
 
+func (s *S) TestCheckWithCondition(c *check.C) {
+	ctx := context.TODO()
+	t := &userToken{
+		permissions: []permTypes.Permission{
+			{
+				Scheme:    PermAppUpdateEnv,
+				Context:   permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"},
+				Condition: &permTypes.Condition{Permission: "app.update.env", Tags: []string{"tier=dev"}},
+			},
+			{
+				Scheme:    PermAppDeploy,
+				Context:   permTypes.PermissionContext{CtxType: permTypes.CtxGlobal},
+				Condition: &permTypes.Condition{Permission: "app.deploy", Pools: []string{"staging-*"}},
+			},
+		},
+	}
+	team1 := permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}
+	devTag := permTypes.PermissionContext{CtxType: permTypes.CtxTag, Value: "tier=dev"}
+	prodTag := permTypes.PermissionContext{CtxType: permTypes.CtxTag, Value: "tier=prod"}
+	stagingPool := permTypes.PermissionContext{CtxType: permTypes.CtxPool, Value: "staging-east"}
+	prodPool := permTypes.PermissionContext{CtxType: permTypes.CtxPool, Value: "prod-east"}
+	c.Assert(Check(ctx, t, PermAppUpdateEnvSet, team1, devTag), check.Equals, true)
+	c.Assert(Check(ctx, t, PermAppUpdateEnvSet, team1, prodTag), check.Equals, false)
+	c.Assert(Check(ctx, t, PermAppUpdateEnvSet, team1), check.Equals, false)
+	c.Assert(Check(ctx, t, PermAppDeploy, team1, stagingPool), check.Equals, true)
+	c.Assert(Check(ctx, t, PermAppDeploy, team1, prodPool), check.Equals, false)
+	c.Assert(Check(ctx, t, PermAppDeploy), check.Equals, false)
+}
+
+func (s *S) TestContextsForPermissionIgnoresConditionalPermissions(c *check.C) {
+	t := &userToken{
+		permissions: []permTypes.Permission{
+			{Scheme: PermAppRead, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}},
+			{
+				Scheme:    PermAppRead,
+				Context:   permTypes.PermissionContext{CtxType: permTypes.CtxGlobal},
+				Condition: &permTypes.Condition{Permission: "app.read", Tags: []string{"tier=dev"}},
+			},
+		},
+	}
+	c.Assert(ContextsForPermission(context.TODO(), t, PermAppRead), check.DeepEquals, []permTypes.PermissionContext{
+		{CtxType: permTypes.CtxTeam, Value: "team1"},
+	})
+	values, err := ListContextValues(context.TODO(), t, PermAppRead, true)
+	c.Assert(err, check.IsNil)
+	c.Assert(values, check.DeepEquals, []string{"team1"})
+	team, err := TeamForPermission(context.TODO(), t, PermAppRead)
+	c.Assert(err, check.IsNil)
+	c.Assert(team, check.Equals, "team1")
+	c.Assert(ConditionalContextsFromListForPermission(t.permissions, PermAppRead), check.DeepEquals, []permTypes.PermissionContext{
+		{CtxType: permTypes.CtxGlobal},
+	})
+}
+
 func (s *S) TestGetTeamForPermission(c *check.C) {
 	t := &userToken{
 		permissions: []permTypes.Permission{
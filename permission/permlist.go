@@ -104,6 +104,9 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"user.update.quota",
 	"user.update.password",
 	"user.update.reset",
+	"user.update.mfa",
+	"user.read.sessions",
+	"user.update.sessions",
 ).addWithCtx(
 	"apikey", []permTypes.ContextType{permTypes.CtxUser},
 ).add(
@@ -134,6 +137,7 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 ).add(
 	"service-instance.read.events",
 	"service-instance.read.status",
+	"service-instance.read.actions",
 	"service-instance.delete",
 	"service-instance.update.proxy",
 	"service-instance.update.bind",
@@ -145,6 +149,9 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"service-instance.update.teamowner",
 	"service-instance.update.plan",
 	"service-instance.update.parameters",
+	"service-instance.update.shared-pools",
+	"service-instance.update.credentials",
+	"service-instance.update.actions",
 ).add(
 	"role.create",
 	"role.delete",
@@ -222,6 +229,12 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"job.create", []permTypes.ContextType{permTypes.CtxTeam},
 ).add(
 	"job.update",
+	"job.update.bind-volume",
+	"job.update.unbind-volume",
+	"job.update.suspend",
+	"job.update.resume",
+	"job.update.autoscale.add",
+	"job.update.autoscale.remove",
 ).add(
 	"job.run",
 ).add(
@@ -235,8 +248,25 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"job.read.logs",
 ).add(
 	"job.trigger",
+	"job.trigger.parameters",
 ).add(
 	"job.unit.kill",
 ).add(
 	"job.deploy",
+).addWithCtx(
+	"job.workflow", []permTypes.ContextType{permTypes.CtxTeam},
+).add(
+	"job.workflow.create",
+	"job.workflow.read",
+	"job.workflow.update",
+	"job.workflow.delete",
+	"job.workflow.run",
+	"job.workflow.cancel",
+).addWithCtx(
+	"job.template", []permTypes.ContextType{permTypes.CtxTeam},
+).add(
+	"job.template.create",
+	"job.template.read",
+	"job.template.update",
+	"job.template.delete",
 )
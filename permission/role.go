@@ -20,8 +20,9 @@ type Role struct {
 	Name        string                `bson:"_id" json:"name"`
 	ContextType permTypes.ContextType `json:"context"`
 	Description string
-	SchemeNames []string `json:"scheme_names,omitempty"`
-	Events      []string `json:"events,omitempty"`
+	SchemeNames []string              `json:"scheme_names,omitempty"`
+	Events      []string              `json:"events,omitempty"`
+	Conditions  []permTypes.Condition `json:"conditions,omitempty"`
 }
 
 func NewRole(ctx context.Context, name string, permissionCtx string, description string) (Role, error) {
@@ -230,7 +231,10 @@ func (r *Role) RemovePermissions(ctx context.Context, permNames ...string) error
 	if err != nil {
 		return err
 	}
-	_, err = collection.UpdateOne(ctx, mongoBSON.M{"_id": r.Name}, mongoBSON.M{"$pullAll": mongoBSON.M{"schemenames": permNames}})
+	_, err = collection.UpdateOne(ctx, mongoBSON.M{"_id": r.Name}, mongoBSON.M{
+		"$pullAll": mongoBSON.M{"schemenames": permNames},
+		"$pull":    mongoBSON.M{"conditions": mongoBSON.M{"permission": mongoBSON.M{"$in": permNames}}},
+	})
 	if err != nil {
 		return err
 	}
@@ -239,6 +243,92 @@ func (r *Role) RemovePermissions(ctx context.Context, permNames ...string) error
 		return err
 	}
 	r.SchemeNames = dbRole.SchemeNames
+	r.Conditions = dbRole.Conditions
+	return nil
+}
+
+// SetCondition restricts a permission already present in the role to
+// objects matching the condition, replacing any previous condition set for
+// the same permission. Conditions match the current tags, labels and pool
+// of the object, see permTypes.Condition for the implications of matching
+// user editable attributes.
+func (r *Role) SetCondition(ctx context.Context, cond permTypes.Condition) error {
+	err := cond.Validate()
+	if err != nil {
+		return err
+	}
+	var inRole bool
+	for _, name := range r.SchemeNames {
+		if name == cond.Permission {
+			inRole = true
+			break
+		}
+	}
+	if !inRole {
+		return &permTypes.ErrInvalidCondition{Permission: cond.Permission, Reason: "permission is not part of the role"}
+	}
+	schemeName := cond.Permission
+	if schemeName == "*" {
+		schemeName = ""
+	}
+	scheme := PermissionRegistry.getSubRegistry(schemeName)
+	if scheme == nil {
+		return &permTypes.ErrPermissionNotFound{Permission: cond.Permission}
+	}
+	var hasObjectContext bool
+	for _, ctxType := range scheme.AllowedContexts() {
+		if ctxType == permTypes.CtxApp || ctxType == permTypes.CtxJob {
+			hasObjectContext = true
+			break
+		}
+	}
+	if !hasObjectContext {
+		return &permTypes.ErrInvalidCondition{Permission: cond.Permission, Reason: "conditions are only supported on app and job permissions"}
+	}
+	collection, err := storagev2.RolesCollection()
+	if err != nil {
+		return err
+	}
+	_, err = collection.UpdateOne(ctx, mongoBSON.M{"_id": r.Name}, mongoBSON.M{"$pull": mongoBSON.M{"conditions": mongoBSON.M{"permission": cond.Permission}}})
+	if err != nil {
+		return err
+	}
+	_, err = collection.UpdateOne(ctx, mongoBSON.M{"_id": r.Name}, mongoBSON.M{"$push": mongoBSON.M{"conditions": cond}})
+	if err != nil {
+		return err
+	}
+	dbRole, err := FindRole(ctx, r.Name)
+	if err != nil {
+		return err
+	}
+	r.Conditions = dbRole.Conditions
+	return nil
+}
+
+func (r *Role) RemoveCondition(ctx context.Context, permName string) error {
+	collection, err := storagev2.RolesCollection()
+	if err != nil {
+		return err
+	}
+	_, err = collection.UpdateOne(ctx, mongoBSON.M{"_id": r.Name}, mongoBSON.M{"$pull": mongoBSON.M{"conditions": mongoBSON.M{"permission": permName}}})
+	if err != nil {
+		return err
+	}
+	dbRole, err := FindRole(ctx, r.Name)
+	if err != nil {
+		return err
+	}
+	r.Conditions = dbRole.Conditions
+	return nil
+}
+
+func (r *Role) conditionFor(scheme *permTypes.PermissionScheme) *permTypes.Condition {
+	name := scheme.FullName()
+	for i, cond := range r.Conditions {
+		if cond.Permission == name || (cond.Permission == "*" && name == "") {
+			return &r.Conditions[i]
+		}
+	}
 	return nil
 }
 
@@ -273,6 +363,7 @@ func (r *Role) PermissionsFor(contextValue string) []permTypes.Permission {
 				CtxType: r.ContextType,
 				Value:   contextValue,
 			},
+			Condition: r.conditionFor(scheme),
 		}
 	}
 	return permissions
@@ -342,7 +433,7 @@ func (r *Role) Add(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	insertRole := Role{Name: name, ContextType: r.ContextType, Description: r.Description, SchemeNames: r.SchemeNames, Events: r.Events}
+	insertRole := Role{Name: name, ContextType: r.ContextType, Description: r.Description, SchemeNames: r.SchemeNames, Events: r.Events, Conditions: r.Conditions}
 	_, err = collection.InsertOne(ctx, insertRole)
 	if mongo.IsDuplicateKeyError(err) {
 		return permTypes.ErrRoleAlreadyExists
@@ -122,6 +122,52 @@ func (s *S) TestRemovePermissions(c *check.C) {
 	c.Assert(dbR.SchemeNames, check.DeepEquals, expected)
 }
 
+func (s *S) TestRoleSetCondition(c *check.C) {
+	r, err := NewRole(context.TODO(), "myrole", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r.AddPermissions(context.TODO(), "app.update.env", "app.deploy")
+	c.Assert(err, check.IsNil)
+	err = r.SetCondition(context.TODO(), permTypes.Condition{Permission: "app.deploy", Pools: []string{"dev-*"}})
+	c.Assert(err, check.IsNil)
+	err = r.SetCondition(context.TODO(), permTypes.Condition{Permission: "app.deploy", Pools: []string{"staging-*"}})
+	c.Assert(err, check.IsNil)
+	err = r.SetCondition(context.TODO(), permTypes.Condition{Permission: "app.update.env", Tags: []string{"tier=dev"}})
+	c.Assert(err, check.IsNil)
+	expected := []permTypes.Condition{
+		{Permission: "app.deploy", Pools: []string{"staging-*"}},
+		{Permission: "app.update.env", Tags: []string{"tier=dev"}},
+	}
+	c.Assert(r.Conditions, check.DeepEquals, expected)
+	dbR, err := FindRole(context.TODO(), "myrole")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbR.Conditions, check.DeepEquals, expected)
+	perms := dbR.PermissionsFor("team1")
+	c.Assert(perms, check.HasLen, 2)
+	c.Assert(perms[0].Scheme, check.Equals, PermAppDeploy)
+	c.Assert(perms[0].Condition, check.DeepEquals, &expected[0])
+	err = r.RemovePermissions(context.TODO(), "app.deploy")
+	c.Assert(err, check.IsNil)
+	c.Assert(r.Conditions, check.DeepEquals, expected[1:])
+	err = r.RemoveCondition(context.TODO(), "app.update.env")
+	c.Assert(err, check.IsNil)
+	c.Assert(r.Conditions, check.HasLen, 0)
+}
+
+func (s *S) TestRoleSetConditionInvalid(c *check.C) {
+	r, err := NewRole(context.TODO(), "myrole", "global", "")
+	c.Assert(err, check.IsNil)
+	err = r.AddPermissions(context.TODO(), "app.deploy", "pool.create")
+	c.Assert(err, check.IsNil)
+	err = r.SetCondition(context.TODO(), permTypes.Condition{Permission: "app.deploy"})
+	c.Assert(err, check.Equals, permTypes.ErrEmptyCondition)
+	err = r.SetCondition(context.TODO(), permTypes.Condition{Permission: "app.deploy", Pools: []string{"[a-"}})
+	c.Assert(err, check.ErrorMatches, `invalid condition for permission "app.deploy": invalid pool pattern "\[a-"`)
+	err = r.SetCondition(context.TODO(), permTypes.Condition{Permission: "app.update", Tags: []string{"a"}})
+	c.Assert(err, check.ErrorMatches, `invalid condition for permission "app.update": permission is not part of the role`)
+	err = r.SetCondition(context.TODO(), permTypes.Condition{Permission: "pool.create", Tags: []string{"a"}})
+	c.Assert(err, check.ErrorMatches, `invalid condition for permission "pool.create": conditions are only supported on app and job permissions`)
+}
+
 func (s *S) TestDestroyRole(c *check.C) {
 	_, err := NewRole(context.TODO(), "myrole", "team", "")
 	c.Assert(err, check.IsNil)
@@ -35,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/version"
 	vpaclientset "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -82,6 +83,7 @@ const (
 	buildServiceTLSKey            = "build-service-tls"
 	buildServiceTLSSkipVerify     = "build-service-tls-skip-verify"
 	jobEventCreationKey           = "job-event-creation"
+	jobNativeSidecarsKey          = "job-native-sidecars"
 	topologySpreadConstraintsKey  = "topology-spread-constraints"
 
 	dialTimeout  = 30 * time.Second
@@ -117,10 +119,15 @@ var (
 		buildServiceTLSKey:            "Whether should access Build service through TLS",
 		buildServiceTLSSkipVerify:     "Whether should skip certificate chain validation",
 		jobEventCreationKey:           "Enable k8s event data tracking cross-referencing with Jobs and send them to tsuru database",
+		jobNativeSidecarsKey:          "Whether the cluster supports native sidecar containers on jobs, detected from the server version (1.29+) when not set",
 		topologySpreadConstraintsKey:  "Enable topology spread constraints for apps",
 	}
 )
 
+// nativeSidecarsMinVersion is the first version with the SidecarContainers
+// feature gate enabled by default.
+var nativeSidecarsMinVersion = version.MustParseGeneric("1.29.0")
+
 var ClientForConfig = func(conf *rest.Config) (kubernetes.Interface, error) {
 	return kubernetes.NewForConfig(conf)
 }
@@ -542,6 +549,25 @@ func (c *ClusterClient) EnableJobEventCreation() (bool, error) {
 	return strconv.ParseBool(jobEventCreation)
 }
 
+// NativeSidecars reports whether the cluster runs init containers restarted
+// always as sidecars. The SidecarContainers feature gate is enabled by
+// default from Kubernetes 1.29, older clusters enabling it must set it in
+// the cluster config.
+func (c *ClusterClient) NativeSidecars() (bool, error) {
+	if nativeSidecars := c.configForContext("", jobNativeSidecarsKey); nativeSidecars != "" {
+		return strconv.ParseBool(nativeSidecars)
+	}
+	info, err := c.Discovery().ServerVersion()
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	serverVersion, err := version.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return serverVersion.AtLeast(nativeSidecarsMinVersion), nil
+}
+
 func (c *ClusterClient) Registry() imgTypes.ImageRegistry {
 	registry := c.configForContext("", registryKey)
 	return imgTypes.ImageRegistry(registry)
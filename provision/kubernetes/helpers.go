@@ -44,6 +44,12 @@ const (
 	tsuruExtraAnnotationsMeta = tsuruLabelPrefix + "extra-annotations"
 	tsuruLabelAppName         = tsuruLabelPrefix + provision.LabelAppName
 	tsuruLabelJobName         = tsuruLabelPrefix + provision.LabelJobName
+	tsuruLabelJobPool         = tsuruLabelPrefix + provision.LabelJobPool
+	tsuruLabelJobTeamOwner    = tsuruLabelPrefix + provision.LabelJobTeamOwner
+	tsuruJobQuotaSuspended    = tsuruLabelPrefix + "job-quota-suspended"
+	tsuruJobDurationAlerted   = tsuruLabelPrefix + "job-duration-alerted"
+	tsuruJobTriggeredBy       = tsuruLabelPrefix + "triggered-by"
+	tsuruJobWorkflowRun       = tsuruLabelPrefix + "workflow-run"
 	tsuruLabelAppVersion      = tsuruLabelPrefix + provision.LabelAppVersion
 	tsuruLabelIsBuild         = tsuruLabelPrefix + provision.LabelIsBuild
 	tsuruLabelAppProcess      = tsuruLabelPrefix + provision.LabelAppProcess
@@ -25,6 +25,8 @@ import (
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/utils/ptr"
@@ -60,9 +62,16 @@ var (
 		Name:      "started_total",
 		Help:      "The total number of started jobs",
 	}, []string{"job_name"})
+
+	jobLastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: promNamespace,
+		Subsystem: promSubsystem,
+		Name:      "last_success_timestamp_seconds",
+		Help:      "The unix timestamp of the last successful execution of the job",
+	}, []string{"job_name"})
 )
 
-func buildJobSpec(job *jobTypes.Job, client *ClusterClient, labels, annotations map[string]string) (batchv1.JobSpec, error) {
+func buildJobSpec(ctx context.Context, job *jobTypes.Job, client *ClusterClient, labels, annotations map[string]string) (batchv1.JobSpec, error) {
 	jSpec := job.Spec
 
 	requirements, err := resourceRequirements(&job.Plan, job.Pool, client, requirementsFactors{})
@@ -70,6 +79,11 @@ func buildJobSpec(job *jobTypes.Job, client *ClusterClient, labels, annotations
 		return batchv1.JobSpec{}, err
 	}
 
+	volumes, mounts, err := createVolumesForJob(ctx, client, job)
+	if err != nil {
+		return batchv1.JobSpec{}, err
+	}
+
 	envs := []apiv1.EnvVar{}
 
 	for _, env := range jSpec.Envs {
@@ -91,37 +105,44 @@ func buildJobSpec(job *jobTypes.Job, client *ClusterClient, labels, annotations
 		imageURL = jSpec.Container.OriginalImageSrc
 	}
 
+	podSpec := apiv1.PodSpec{
+		RestartPolicy: "OnFailure",
+		Containers: []apiv1.Container{
+			{
+				Name:         jobTypes.MainContainerName,
+				Image:        imageURL,
+				Command:      jSpec.Container.Command,
+				Resources:    requirements,
+				Env:          envs,
+				VolumeMounts: mounts,
+			},
+		},
+		Volumes:            volumes,
+		ServiceAccountName: serviceAccountNameForJob(*job),
+	}
+	if err = addJobContainers(&podSpec, job, client, mounts); err != nil {
+		return batchv1.JobSpec{}, err
+	}
+
 	return batchv1.JobSpec{
 		Parallelism:             jSpec.Parallelism,
 		BackoffLimit:            jSpec.BackoffLimit,
 		Completions:             jSpec.Completions,
 		ActiveDeadlineSeconds:   buildActiveDeadline(jSpec.ActiveDeadlineSeconds),
-		TTLSecondsAfterFinished: func() *int32 { ttlSecondsAfterFinished := int32(86400); return &ttlSecondsAfterFinished }(), // hardcoded to a day, since we keep logs stored elsewhere on the cloud
+		TTLSecondsAfterFinished: buildTTLSecondsAfterFinished(jSpec.TTLSecondsAfterFinished),
 		Template: apiv1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
 				Labels:      labels,
 				Annotations: annotations,
 			},
-			Spec: apiv1.PodSpec{
-				RestartPolicy: "OnFailure",
-				Containers: []apiv1.Container{
-					{
-						Name:      "job",
-						Image:     imageURL,
-						Command:   jSpec.Container.Command,
-						Resources: requirements,
-						Env:       envs,
-					},
-				},
-				ServiceAccountName: serviceAccountNameForJob(*job),
-			},
+			Spec: podSpec,
 		},
 	}, nil
 }
 
 func ensureCronjob(ctx context.Context, client *ClusterClient, job *jobTypes.Job) error {
 	labels, annotations := buildMetadata(ctx, job)
-	jobSpec, err := buildJobSpec(job, client, labels, annotations)
+	jobSpec, err := buildJobSpec(ctx, job, client, labels, annotations)
 	if err != nil {
 		return err
 	}
@@ -136,8 +157,9 @@ func ensureCronjob(ctx context.Context, client *ClusterClient, job *jobTypes.Job
 	}
 
 	// when the schedule suffer changes some cronjobs may suffer a unexpected execution
-	// for these reason we decided to recreate the entire cronjob to avoid this
-	if existingCronjob != nil && existingCronjob.Spec.Schedule != job.Spec.Schedule {
+	// for these reason we decided to recreate the entire cronjob to avoid this,
+	// the same applies to the time zone the schedule is evaluated in
+	if existingCronjob != nil && (existingCronjob.Spec.Schedule != job.Spec.Schedule || ptr.Deref(existingCronjob.Spec.TimeZone, "") != job.Spec.TimeZone) {
 
 		wait, waitErr := waitToJobDeletion(ctx, client, existingCronjob)
 		if waitErr != nil {
@@ -175,13 +197,19 @@ func ensureCronjob(ctx context.Context, client *ClusterClient, job *jobTypes.Job
 		},
 		Spec: batchv1.CronJobSpec{
 			Schedule: job.Spec.Schedule,
-			Suspend:  &job.Spec.Manual,
+			Suspend:  ptr.To(job.Spec.Manual || job.Spec.Suspend),
 			JobTemplate: batchv1.JobTemplateSpec{
 				Spec: jobSpec,
 			},
-			ConcurrencyPolicy: batchv1.ConcurrencyPolicy(concurrencyPolicy),
+			ConcurrencyPolicy:          batchv1.ConcurrencyPolicy(concurrencyPolicy),
+			StartingDeadlineSeconds:    job.Spec.StartingDeadlineSeconds,
+			SuccessfulJobsHistoryLimit: job.Spec.SuccessfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     job.Spec.FailedJobsHistoryLimit,
 		},
 	}
+	if job.Spec.TimeZone != "" {
+		cronjob.Spec.TimeZone = ptr.To(job.Spec.TimeZone)
+	}
 
 	if existingCronjob == nil {
 		_, err = client.BatchV1().CronJobs(namespace).Create(ctx, cronjob, metav1.CreateOptions{})
@@ -266,10 +294,14 @@ func (p *kubernetesProvisioner) EnsureJob(ctx context.Context, job *jobTypes.Job
 		return err
 	}
 
-	return ensureCronjob(ctx, client, job)
+	if err = ensureCronjob(ctx, client, job); err != nil {
+		return err
+	}
+
+	return ensureJobAutoScale(ctx, client, job)
 }
 
-func (p *kubernetesProvisioner) TriggerCron(ctx context.Context, name, pool string) error {
+func (p *kubernetesProvisioner) TriggerCron(ctx context.Context, name, pool string, opts jobTypes.TriggerOptions) error {
 	client, err := clusterForPool(ctx, pool)
 	if err != nil {
 		return err
@@ -301,13 +333,60 @@ func (p *kubernetesProvisioner) TriggerCron(ctx context.Context, name, pool stri
 	} else {
 		cronChild.Annotations["cronjob.kubernetes.io/instantiate"] = "manual"
 	}
+	if opts.TriggeredBy != "" {
+		cronChild.Annotations[tsuruJobTriggeredBy] = opts.TriggeredBy
+	}
+	if opts.WorkflowRun != "" {
+		cronChild.Annotations[tsuruJobWorkflowRun] = opts.WorkflowRun
+	}
+	applyTriggerOptions(&cronChild.Spec.Template.Spec, opts)
 	_, err = client.BatchV1().Jobs(cron.Namespace).Create(ctx, &cronChild, metav1.CreateOptions{})
 	return err
 }
 
+// applyTriggerOptions overrides the job container of a spawned job with the
+// parameters of a single execution, leaving the cronjob template untouched.
+func applyTriggerOptions(podSpec *apiv1.PodSpec, opts jobTypes.TriggerOptions) {
+	if opts.IsEmpty() {
+		return
+	}
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if container.Name != jobTypes.MainContainerName {
+			continue
+		}
+		if len(opts.Command) > 0 {
+			container.Command = append([]string{}, opts.Command...)
+		}
+		if len(opts.Args) > 0 {
+			container.Args = append(append([]string{}, container.Args...), opts.Args...)
+		}
+		envs := append([]apiv1.EnvVar{}, container.Env...)
+	envsLoop:
+		for _, env := range opts.Envs {
+			value := strings.ReplaceAll(env.Value, "$", "$$")
+			for j := range envs {
+				if envs[j].Name == env.Name {
+					envs[j].Value = value
+					continue envsLoop
+				}
+			}
+			envs = append(envs, apiv1.EnvVar{Name: env.Name, Value: value})
+		}
+		container.Env = envs
+	}
+}
+
+// getManualJobName returns the name of a job spawned from the cronjob, the
+// random suffix avoids conflicts between triggers in the same minute.
 func getManualJobName(job string) string {
 	scheduledTime := time.Now()
-	return fmt.Sprintf("%s-manual-job-%d", job, scheduledTime.Unix()/60)
+	name := fmt.Sprintf("%s-manual-job-%d", job, scheduledTime.Unix()/60)
+	suffix := "-" + utilrand.String(5)
+	if maxLen := validation.DNS1123LabelMaxLength - len(suffix); len(name) > maxLen {
+		name = name[:maxLen]
+	}
+	return name + suffix
 }
 
 // JobUnits returns information about units related to a specific Job or CronJob
@@ -343,7 +422,7 @@ func (p *kubernetesProvisioner) DestroyJob(ctx context.Context, job *jobTypes.Jo
 		return err
 	}
 
-	return nil
+	return removeKEDAScaledJob(ctx, client, namespace, job.Name)
 }
 
 func (p *kubernetesProvisioner) KillJobUnit(ctx context.Context, job *jobTypes.Job, unit string, force bool) error {
@@ -422,6 +501,7 @@ func incrementJobMetrics(job *batchv1.Job, evt *apiv1.Event, wg *sync.WaitGroup)
 	switch evt.Reason {
 	case "Completed":
 		jobCompleted.WithLabelValues(jobName).Inc()
+		jobLastSuccess.WithLabelValues(jobName).Set(float64(eventTime(evt).Unix()))
 	case "BackoffLimitExceeded":
 		jobFailed.WithLabelValues(jobName, evt.Message).Inc()
 	case "SuccessfulCreate":
@@ -494,6 +574,7 @@ func createJobEvent(clusterClient *ClusterClient, job *batchv1.Job, evt *apiv1.E
 		"cluster-start-time": evt.CreationTimestamp.String(),
 	}
 	e.DoneCustomData(ctx, evtErr, customData)
+	checkJobAlerts(ctx, realJobOwner, job, evt)
 }
 
 func ensureServiceAccountForJob(ctx context.Context, client *ClusterClient, job jobTypes.Job) error {
@@ -505,6 +586,15 @@ func ensureServiceAccountForJob(ctx context.Context, client *ClusterClient, job
 	return ensureServiceAccount(ctx, client, serviceAccountNameForJob(job), labels, ns, &job.Metadata)
 }
 
+// buildTTLSecondsAfterFinished defaults to a day, since we keep logs stored
+// elsewhere on the cloud and runs are recorded in the job history
+func buildTTLSecondsAfterFinished(ttl *int32) *int32 {
+	if ttl == nil {
+		return ptr.To[int32](86400)
+	}
+	return ttl
+}
+
 func buildActiveDeadline(activeDeadlineSeconds *int64) *int64 {
 	defaultActiveDeadline := int64(60 * 60)
 	if activeDeadlineSeconds == nil || *activeDeadlineSeconds == int64(0) {
@@ -0,0 +1,191 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+)
+
+// reasons used by the cronjob controller when a scheduled execution is not
+// started in time
+var cronJobMissedReasons = []string{"MissSchedule", "TooManyMissedTimes"}
+
+type jobAlert struct {
+	Type    jobTypes.AlertType
+	Message string
+}
+
+// checkJobAlerts evaluates the alert rules of the job owning the k8s job
+// from a finished execution event.
+func checkJobAlerts(ctx context.Context, jobName string, job *batchv1.Job, evt *apiv1.Event) {
+	tsuruJob, err := servicemanager.Job.GetByName(ctx, jobName)
+	if err != nil || tsuruJob == nil || tsuruJob.Alerts == nil {
+		return
+	}
+	rules := tsuruJob.Alerts
+	var alerts []jobAlert
+	// runs already reported while active by checkActiveJobDuration
+	if rules.MaxDurationSeconds > 0 && job.Status.StartTime != nil && job.Annotations[tsuruJobDurationAlerted] != "true" {
+		end := eventTime(evt)
+		if job.Status.CompletionTime != nil {
+			end = job.Status.CompletionTime.Time
+		}
+		maxDuration := time.Duration(rules.MaxDurationSeconds) * time.Second
+		if duration := end.Sub(job.Status.StartTime.Time); duration > maxDuration {
+			alerts = append(alerts, jobAlert{
+				Type:    jobTypes.AlertMaxDuration,
+				Message: fmt.Sprintf("run %q took %s, exceeding the maximum duration of %s", job.Name, duration.Round(time.Second), maxDuration),
+			})
+		}
+	}
+	if rules.ConsecutiveFailures > 0 && evt.Reason == "BackoffLimitExceeded" {
+		runs, err := servicemanager.Job.ListRuns(ctx, tsuruJob, jobTypes.RunFilter{Limit: rules.ConsecutiveFailures})
+		if err != nil {
+			log.Errorf("[job alerts] unable to list runs of job %q: %v", jobName, err)
+			return
+		}
+		if consecutiveFailures(job.Name, runs) == rules.ConsecutiveFailures {
+			alerts = append(alerts, jobAlert{
+				Type:    jobTypes.AlertConsecutiveFailures,
+				Message: fmt.Sprintf("job failed %d consecutive times, last run %q", rules.ConsecutiveFailures, job.Name),
+			})
+		}
+	}
+	for _, alert := range alerts {
+		emitJobAlert(ctx, jobName, alert, evt)
+	}
+}
+
+// checkActiveJobDuration alerts once when an active run exceeds the maximum
+// duration of its job. It's called on every resync of the job informer, so
+// long runs are reported while they are still running.
+func checkActiveJobDuration(client *ClusterClient, job *batchv1.Job) {
+	if job.Status.StartTime == nil || job.Annotations[tsuruJobDurationAlerted] == "true" || jobRunFromK8sJob(job, nil).IsFinished() {
+		return
+	}
+	jobName := job.Labels[tsuruLabelJobName]
+	if jobName == "" {
+		return
+	}
+	ctx := context.Background()
+	tsuruJob, err := servicemanager.Job.GetByName(ctx, jobName)
+	if err != nil || tsuruJob == nil || tsuruJob.Alerts == nil || tsuruJob.Alerts.MaxDurationSeconds <= 0 {
+		return
+	}
+	maxDuration := time.Duration(tsuruJob.Alerts.MaxDurationSeconds) * time.Second
+	duration := time.Since(job.Status.StartTime.Time)
+	if duration <= maxDuration {
+		return
+	}
+	// the run is marked before alerting, a failure here is retried on the
+	// next resync instead of alerting again every minute
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{tsuruJobDurationAlerted: "true"},
+		},
+	})
+	if err != nil {
+		return
+	}
+	_, err = client.BatchV1().Jobs(job.Namespace).Patch(ctx, job.Name, k8sTypes.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		log.Errorf("[job alerts] unable to mark run %s as alerted: %v", job.Name, err)
+		return
+	}
+	emitJobAlert(ctx, jobName, jobAlert{
+		Type:    jobTypes.AlertMaxDuration,
+		Message: fmt.Sprintf("run %q is running for %s, exceeding the maximum duration of %s", job.Name, duration.Round(time.Second), maxDuration),
+	}, nil)
+}
+
+// consecutiveFailures counts the failed runs in a row ending with the
+// current one. The current run may not be recorded as failed yet, since the
+// history is updated by another informer, so it's always taken as failed.
+func consecutiveFailures(current string, runs []jobTypes.Run) int {
+	count := 1
+	for _, run := range runs {
+		if run.Name == current || run.Status == jobTypes.RunStatusRunning {
+			continue
+		}
+		if run.Status != jobTypes.RunStatusFailed {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+func createCronJobAlertEvent(evt *apiv1.Event, wg *sync.WaitGroup) {
+	defer wg.Done()
+	if !isCronJobMissedEvent(evt) {
+		return
+	}
+	ctx := context.Background()
+	tsuruJob, err := servicemanager.Job.GetByName(ctx, evt.InvolvedObject.Name)
+	if err != nil || tsuruJob == nil || tsuruJob.Alerts == nil || !tsuruJob.Alerts.MissedSchedule {
+		return
+	}
+	emitJobAlert(ctx, tsuruJob.Name, jobAlert{
+		Type:    jobTypes.AlertMissedSchedule,
+		Message: evt.Message,
+	}, evt)
+}
+
+func isCronJobMissedEvent(evt *apiv1.Event) bool {
+	for _, reason := range cronJobMissedReasons {
+		if evt.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// emitJobAlert creates the alert event, evt is the cluster event that
+// triggered it, if any.
+func emitJobAlert(ctx context.Context, jobName string, alert jobAlert, evt *apiv1.Event) {
+	customData := map[string]string{"alert": string(alert.Type)}
+	if evt != nil {
+		customData["event-reason"] = evt.Reason
+		customData["cluster-start-time"] = eventTime(evt).String()
+	}
+	e, err := event.NewInternal(ctx, &event.Opts{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeJob, Value: jobName},
+		InternalKind: jobTypes.AlertEventKind,
+		Allowed:      event.Allowed(permission.PermJobReadEvents, permission.Context(permTypes.CtxJob, jobName)),
+		DisableLock:  true,
+		CustomData:   customData,
+	})
+	if err != nil {
+		log.Errorf("[job alerts] unable to create %s alert event for job %q: %v", alert.Type, jobName, err)
+		return
+	}
+	// alerts are finished with an error so webhooks filtering on failures
+	// are notified as well
+	e.Done(ctx, errors.New(alert.Message))
+}
+
+func eventTime(evt *apiv1.Event) time.Time {
+	if !evt.LastTimestamp.IsZero() {
+		return evt.LastTimestamp.Time
+	}
+	return evt.CreationTimestamp.Time
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tsuru/tsuru/event"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	check "gopkg.in/check.v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (s *S) TestConsecutiveFailures(c *check.C) {
+	runs := []jobTypes.Run{
+		{Name: "myjob-4", Status: jobTypes.RunStatusRunning},
+		{Name: "myjob-3", Status: jobTypes.RunStatusFailed},
+		{Name: "myjob-2", Status: jobTypes.RunStatusFailed},
+		{Name: "myjob-1", Status: jobTypes.RunStatusSucceeded},
+		{Name: "myjob-0", Status: jobTypes.RunStatusFailed},
+	}
+	c.Assert(consecutiveFailures("myjob-3", runs), check.Equals, 2)
+	c.Assert(consecutiveFailures("myjob-5", runs), check.Equals, 3)
+	c.Assert(consecutiveFailures("myjob-5", nil), check.Equals, 1)
+}
+
+func (s *S) TestCreateJobEventWithAlerts(c *check.C) {
+	s.mockService.JobService.OnGetByName = func(name string) (*jobTypes.Job, error) {
+		c.Assert(name, check.Equals, "myjob")
+		return &jobTypes.Job{
+			Name:   name,
+			Alerts: &jobTypes.AlertRules{ConsecutiveFailures: 2, MaxDurationSeconds: 60},
+		}, nil
+	}
+	s.mockService.JobService.OnListRuns = func(_ *jobTypes.Job, filter jobTypes.RunFilter) ([]jobTypes.Run, error) {
+		c.Assert(filter.Limit, check.Equals, 2)
+		return []jobTypes.Run{{Name: "myjob-1", Status: jobTypes.RunStatusFailed}}, nil
+	}
+	defer func() {
+		s.mockService.JobService.OnGetByName = nil
+		s.mockService.JobService.OnListRuns = nil
+	}()
+	start := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+	j := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myjob-2",
+			Namespace: "default",
+			Labels:    map[string]string{"tsuru.io/is-tsuru": "true", "tsuru.io/job-name": "myjob"},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "batch/v1", Kind: "CronJob", Name: "myjob"},
+			},
+		},
+		Status: batchv1.JobStatus{StartTime: &start},
+	}
+	evt := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "myjob-2-somehash", Namespace: j.Namespace},
+		InvolvedObject: corev1.ObjectReference{Kind: "Job", Name: j.Name, Namespace: j.Namespace},
+		Message:        "Job has reached the specified backoff limit",
+		Reason:         "BackoffLimitExceeded",
+		Type:           "Warning",
+	}
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	createJobEvent(s.clusterClient, j, evt, wg)
+	evts, err := event.List(context.TODO(), &event.Filter{KindNames: []string{jobTypes.AlertEventKind}, Sort: "_id"})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 2)
+	alerts := map[string]string{}
+	for _, e := range evts {
+		c.Assert(e.Target, check.DeepEquals, eventTypes.Target{Type: eventTypes.TargetTypeJob, Value: "myjob"})
+		c.Assert(e.Kind.Type, check.Equals, eventTypes.KindTypeInternal)
+		var data map[string]string
+		err = e.StartCustomData.Unmarshal(&data)
+		c.Assert(err, check.IsNil)
+		alerts[data["alert"]] = e.Error
+	}
+	c.Assert(alerts[string(jobTypes.AlertConsecutiveFailures)], check.Equals, `job failed 2 consecutive times, last run "myjob-2"`)
+	c.Assert(alerts[string(jobTypes.AlertMaxDuration)], check.Matches, `run "myjob-2" took .*, exceeding the maximum duration of 1m0s`)
+}
+
+func (s *S) TestCheckActiveJobDuration(c *check.C) {
+	s.mockService.JobService.OnGetByName = func(name string) (*jobTypes.Job, error) {
+		c.Assert(name, check.Equals, "myjob")
+		return &jobTypes.Job{Name: name, Alerts: &jobTypes.AlertRules{MaxDurationSeconds: 60}}, nil
+	}
+	defer func() { s.mockService.JobService.OnGetByName = nil }()
+	start := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+	j := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myjob-2",
+			Namespace: "default",
+			Labels:    map[string]string{"tsuru.io/is-tsuru": "true", tsuruLabelJobName: "myjob"},
+		},
+		Status: batchv1.JobStatus{StartTime: &start, Active: 1},
+	}
+	j, err := s.client.BatchV1().Jobs(j.Namespace).Create(context.TODO(), j, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	checkActiveJobDuration(s.clusterClient, j)
+	j, err = s.client.BatchV1().Jobs(j.Namespace).Get(context.TODO(), j.Name, metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(j.Annotations[tsuruJobDurationAlerted], check.Equals, "true")
+	checkActiveJobDuration(s.clusterClient, j)
+	evts, err := event.List(context.TODO(), &event.Filter{KindNames: []string{jobTypes.AlertEventKind}})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].Target, check.DeepEquals, eventTypes.Target{Type: eventTypes.TargetTypeJob, Value: "myjob"})
+	c.Assert(evts[0].Error, check.Matches, `run "myjob-2" is running for .*, exceeding the maximum duration of 1m0s`)
+}
+
+func (s *S) TestCreateCronJobAlertEventMissedSchedule(c *check.C) {
+	s.mockService.JobService.OnGetByName = func(name string) (*jobTypes.Job, error) {
+		return &jobTypes.Job{Name: name, Alerts: &jobTypes.AlertRules{MissedSchedule: true}}, nil
+	}
+	defer func() { s.mockService.JobService.OnGetByName = nil }()
+	evt := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "myjob-somehash", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "CronJob", Name: "myjob", Namespace: "default"},
+		Message:        "Missed scheduled time to start a job",
+		Reason:         "MissSchedule",
+		Type:           "Warning",
+	}
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	createCronJobAlertEvent(evt, wg)
+	evts, err := event.List(context.TODO(), &event.Filter{KindNames: []string{jobTypes.AlertEventKind}})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].Target, check.DeepEquals, eventTypes.Target{Type: eventTypes.TargetTypeJob, Value: "myjob"})
+	c.Assert(evts[0].Error, check.Equals, "Missed scheduled time to start a job")
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/pkg/errors"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	batchv1 "k8s.io/api/batch/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ensureJobAutoScale keeps the KEDA ScaledJob spawning executions of the job
+// in sync with its autoscale spec, removing it when there is none.
+func ensureJobAutoScale(ctx context.Context, client *ClusterClient, job *jobTypes.Job) error {
+	ns := client.PoolNamespace(job.Pool)
+	if job.Spec.AutoScale == nil {
+		return removeKEDAScaledJob(ctx, client, ns, job.Name)
+	}
+
+	labels, annotations := buildMetadata(ctx, job)
+	jobSpec, err := buildJobSpec(ctx, job, client, labels, annotations)
+	if err != nil {
+		return err
+	}
+
+	expectedScaledJob, err := newKEDAScaledJob(job, &jobSpec, ns, labels, annotations)
+	if err != nil {
+		return err
+	}
+
+	kedaClient, err := KEDAClientForConfig(client.restConfig)
+	if err != nil {
+		return err
+	}
+
+	observedScaledJob, err := kedaClient.KedaV1alpha1().ScaledJobs(ns).Get(ctx, job.Name, metav1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		_, err = kedaClient.KedaV1alpha1().ScaledJobs(ns).Create(ctx, expectedScaledJob, metav1.CreateOptions{})
+		return errors.WithStack(err)
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	expectedScaledJob.ResourceVersion = observedScaledJob.ResourceVersion
+	_, err = kedaClient.KedaV1alpha1().ScaledJobs(ns).Update(ctx, expectedScaledJob, metav1.UpdateOptions{})
+	return errors.WithStack(err)
+}
+
+func newKEDAScaledJob(job *jobTypes.Job, jobSpec *batchv1.JobSpec, ns string, labels, annotations map[string]string) (*kedav1alpha1.ScaledJob, error) {
+	spec := job.Spec.AutoScale
+	kedaTriggers := []kedav1alpha1.ScaleTriggers{}
+	for _, prometheus := range spec.Prometheus {
+		prometheusTrigger, err := buildPrometheusTrigger(ns, prometheus)
+		if err != nil {
+			return nil, err
+		}
+		kedaTriggers = append(kedaTriggers, *prometheusTrigger)
+	}
+
+	// executions spawned by KEDA are not bound to the static parallelism of
+	// the job, each one of them runs a single pod
+	jobSpec.Parallelism = nil
+	jobSpec.Completions = nil
+
+	var pollingInterval *int32
+	if spec.PollingInterval > 0 {
+		pollingInterval = &spec.PollingInterval
+	}
+
+	return &kedav1alpha1.ScaledJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        job.Name,
+			Namespace:   ns,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: kedav1alpha1.ScaledJobSpec{
+			JobTargetRef:               jobSpec,
+			PollingInterval:            pollingInterval,
+			MinReplicaCount:            &spec.MinParallelism,
+			MaxReplicaCount:            &spec.MaxParallelism,
+			SuccessfulJobsHistoryLimit: job.Spec.SuccessfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     job.Spec.FailedJobsHistoryLimit,
+			Triggers:                   kedaTriggers,
+		},
+	}, nil
+}
+
+func removeKEDAScaledJob(ctx context.Context, client *ClusterClient, ns string, scaledJobName string) error {
+	kedaClient, err := KEDAClientForConfig(client.restConfig)
+	if err != nil {
+		return err
+	}
+
+	err = kedaClient.KedaV1alpha1().ScaledJobs(ns).Delete(ctx, scaledJobName, metav1.DeleteOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
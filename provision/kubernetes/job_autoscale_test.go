@@ -0,0 +1,85 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/tsuru/config"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	check "gopkg.in/check.v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (s *S) TestProvisionerEnsureJobAutoScale(c *check.C) {
+	waitCron := s.mock.CronJobReactions(c)
+	defer waitCron()
+
+	config.Set("kubernetes:keda:prometheus-address-template", "http://prometheus-address-test.{{.namespace}}")
+	defer config.Unset("kubernetes:keda:prometheus-address-template")
+
+	j := jobTypes.Job{
+		Name:      "myconsumer",
+		TeamOwner: s.team.Name,
+		Pool:      "test-default",
+		Spec: jobTypes.JobSpec{
+			Manual: true,
+			Container: jobTypes.ContainerInfo{
+				OriginalImageSrc: "ubuntu:latest",
+				Command:          []string{"consume"},
+			},
+			AutoScale: &jobTypes.AutoScaleSpec{
+				MinParallelism:  0,
+				MaxParallelism:  10,
+				PollingInterval: 15,
+				Prometheus: []provTypes.AutoScalePrometheus{
+					{
+						Name:      "queue_depth",
+						Query:     "sum(queue_messages{queue=\"tasks\"})",
+						Threshold: 5,
+					},
+				},
+			},
+		},
+	}
+	err := s.p.EnsureJob(context.TODO(), &j)
+	waitCron()
+	c.Assert(err, check.IsNil)
+
+	scaledJob, err := s.client.KEDAClientForConfig.KedaV1alpha1().ScaledJobs("default").Get(context.TODO(), "myconsumer", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(*scaledJob.Spec.MinReplicaCount, check.Equals, int32(0))
+	c.Assert(*scaledJob.Spec.MaxReplicaCount, check.Equals, int32(10))
+	c.Assert(*scaledJob.Spec.PollingInterval, check.Equals, int32(15))
+	c.Assert(scaledJob.Spec.JobTargetRef.Parallelism, check.IsNil)
+	c.Assert(scaledJob.Spec.JobTargetRef.Template.Spec.Containers[0].Command, check.DeepEquals, []string{"consume"})
+	c.Assert(scaledJob.Labels["tsuru.io/job-name"], check.Equals, "myconsumer")
+	c.Assert(scaledJob.Spec.Triggers, check.HasLen, 1)
+	c.Assert(scaledJob.Spec.Triggers[0].Type, check.Equals, "prometheus")
+	c.Assert(scaledJob.Spec.Triggers[0].Metadata, check.DeepEquals, map[string]string{
+		"serverAddress":        "http://prometheus-address-test.default",
+		"query":                "sum(queue_messages{queue=\"tasks\"})",
+		"threshold":            "5",
+		"activationThreshold":  "0",
+		"prometheusMetricName": "queue_depth",
+	})
+
+	j.Spec.AutoScale.MaxParallelism = 20
+	err = s.p.EnsureJob(context.TODO(), &j)
+	waitCron()
+	c.Assert(err, check.IsNil)
+	scaledJob, err = s.client.KEDAClientForConfig.KedaV1alpha1().ScaledJobs("default").Get(context.TODO(), "myconsumer", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(*scaledJob.Spec.MaxReplicaCount, check.Equals, int32(20))
+
+	j.Spec.AutoScale = nil
+	err = s.p.EnsureJob(context.TODO(), &j)
+	waitCron()
+	c.Assert(err, check.IsNil)
+	_, err = s.client.KEDAClientForConfig.KedaV1alpha1().ScaledJobs("default").Get(context.TODO(), "myconsumer", metav1.GetOptions{})
+	c.Assert(k8sErrors.IsNotFound(err), check.Equals, true)
+}
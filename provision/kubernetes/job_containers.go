@@ -0,0 +1,68 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"strings"
+
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+func buildJobContainer(c *jobTypes.Container, pool string, client *ClusterClient, mounts []apiv1.VolumeMount) (apiv1.Container, error) {
+	requirements, err := resourceRequirements(&c.Plan, pool, client, requirementsFactors{})
+	if err != nil {
+		return apiv1.Container{}, err
+	}
+	envs := []apiv1.EnvVar{}
+	for _, env := range c.Envs {
+		envs = append(envs, apiv1.EnvVar{
+			Name:  env.Name,
+			Value: strings.ReplaceAll(env.Value, "$", "$$"),
+		})
+	}
+	return apiv1.Container{
+		Name:         c.Name,
+		Image:        c.Image,
+		Command:      c.Command,
+		Resources:    requirements,
+		Env:          envs,
+		VolumeMounts: mounts,
+	}, nil
+}
+
+// addJobContainers adds the init containers and sidecars of the job to the
+// pod spec. Sidecars are native sidecars, init containers restarted always,
+// so they start before the job container and are stopped by the kubelet
+// once it finishes. Clusters without native sidecars would run them as
+// plain init containers that never exit, so sidecars are rejected there.
+func addJobContainers(podSpec *apiv1.PodSpec, job *jobTypes.Job, client *ClusterClient, mounts []apiv1.VolumeMount) error {
+	if len(job.Spec.Sidecars) > 0 {
+		supported, err := client.NativeSidecars()
+		if err != nil {
+			return err
+		}
+		if !supported {
+			return jobTypes.ErrSidecarsNotSupported
+		}
+	}
+	for i := range job.Spec.InitContainers {
+		container, err := buildJobContainer(&job.Spec.InitContainers[i], job.Pool, client, mounts)
+		if err != nil {
+			return err
+		}
+		podSpec.InitContainers = append(podSpec.InitContainers, container)
+	}
+	for i := range job.Spec.Sidecars {
+		container, err := buildJobContainer(&job.Spec.Sidecars[i], job.Pool, client, mounts)
+		if err != nil {
+			return err
+		}
+		container.RestartPolicy = ptr.To(apiv1.ContainerRestartPolicyAlways)
+		podSpec.InitContainers = append(podSpec.InitContainers, container)
+	}
+	return nil
+}
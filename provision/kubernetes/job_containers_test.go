@@ -0,0 +1,156 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+
+	appTypes "github.com/tsuru/tsuru/types/app"
+	bindTypes "github.com/tsuru/tsuru/types/bind"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	check "gopkg.in/check.v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/utils/ptr"
+)
+
+func (s *S) TestProvisionerEnsureJobWithSidecarsAndInitContainers(c *check.C) {
+	s.client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.29.0"}
+	waitCron := s.mock.CronJobReactions(c)
+	defer waitCron()
+	j := jobTypes.Job{
+		Name:      "mybackup",
+		TeamOwner: s.team.Name,
+		Pool:      "test-default",
+		Spec: jobTypes.JobSpec{
+			Schedule: "* * * * *",
+			Container: jobTypes.ContainerInfo{
+				OriginalImageSrc: "ubuntu:latest",
+				Command:          []string{"./backup", "--all"},
+			},
+			InitContainers: []jobTypes.Container{
+				{
+					Name:    "setup",
+					Image:   "busybox:1.36",
+					Command: []string{"./setup"},
+					Envs:    []bindTypes.EnvVar{{Name: "TARGET", Value: "$HOME"}},
+				},
+			},
+			Sidecars: []jobTypes.Container{
+				{
+					Name:    "sql-proxy",
+					Image:   "cloud-sql-proxy:2",
+					Command: []string{"/cloud-sql-proxy", "db"},
+					Plan:    appTypes.Plan{Name: "small", Memory: 128 * 1024 * 1024},
+				},
+			},
+		},
+	}
+	err := s.p.EnsureJob(context.TODO(), &j)
+	waitCron()
+	c.Assert(err, check.IsNil)
+	cron, err := s.client.BatchV1().CronJobs("default").Get(context.TODO(), "mybackup", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	podSpec := cron.Spec.JobTemplate.Spec.Template.Spec
+
+	c.Assert(podSpec.InitContainers, check.HasLen, 2)
+	c.Assert(podSpec.InitContainers[0].Name, check.Equals, "setup")
+	c.Assert(podSpec.InitContainers[0].Image, check.Equals, "busybox:1.36")
+	c.Assert(podSpec.InitContainers[0].Command, check.DeepEquals, []string{"./setup"})
+	c.Assert(podSpec.InitContainers[0].Env, check.DeepEquals, []corev1.EnvVar{{Name: "TARGET", Value: "$$HOME"}})
+	c.Assert(podSpec.InitContainers[0].VolumeMounts, check.HasLen, 0)
+	c.Assert(podSpec.InitContainers[0].RestartPolicy, check.IsNil)
+	c.Assert(podSpec.InitContainers[1].Name, check.Equals, "sql-proxy")
+	c.Assert(podSpec.InitContainers[1].Image, check.Equals, "cloud-sql-proxy:2")
+	c.Assert(podSpec.InitContainers[1].Command, check.DeepEquals, []string{"/cloud-sql-proxy", "db"})
+	c.Assert(podSpec.InitContainers[1].RestartPolicy, check.DeepEquals, ptr.To(corev1.ContainerRestartPolicyAlways))
+	memory := podSpec.InitContainers[1].Resources.Limits[corev1.ResourceMemory]
+	c.Assert(memory.Cmp(resource.MustParse("128Mi")), check.Equals, 0)
+
+	c.Assert(podSpec.Containers, check.HasLen, 1)
+	c.Assert(podSpec.Containers[0].Name, check.Equals, "job")
+	c.Assert(podSpec.Containers[0].Command, check.DeepEquals, []string{"./backup", "--all"})
+	c.Assert(podSpec.Containers[0].Args, check.IsNil)
+	c.Assert(podSpec.Volumes, check.HasLen, 0)
+}
+
+func (s *S) TestProvisionerEnsureJobWithInitContainersOnly(c *check.C) {
+	waitCron := s.mock.CronJobReactions(c)
+	defer waitCron()
+	j := jobTypes.Job{
+		Name:      "mybackup",
+		TeamOwner: s.team.Name,
+		Pool:      "test-default",
+		Spec: jobTypes.JobSpec{
+			Schedule: "* * * * *",
+			Container: jobTypes.ContainerInfo{
+				OriginalImageSrc: "ubuntu:latest",
+				Command:          []string{"./backup"},
+			},
+			InitContainers: []jobTypes.Container{
+				{Name: "setup", Image: "busybox:1.36", Command: []string{"./setup"}},
+			},
+		},
+	}
+	err := s.p.EnsureJob(context.TODO(), &j)
+	waitCron()
+	c.Assert(err, check.IsNil)
+	cron, err := s.client.BatchV1().CronJobs("default").Get(context.TODO(), "mybackup", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	podSpec := cron.Spec.JobTemplate.Spec.Template.Spec
+	c.Assert(podSpec.InitContainers, check.HasLen, 1)
+	c.Assert(podSpec.Containers, check.HasLen, 1)
+	c.Assert(podSpec.Containers[0].Command, check.DeepEquals, []string{"./backup"})
+	c.Assert(podSpec.Containers[0].Args, check.IsNil)
+	c.Assert(podSpec.Volumes, check.HasLen, 0)
+}
+
+func (s *S) TestProvisionerEnsureJobWithSidecarsNotSupported(c *check.C) {
+	s.client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.28.3"}
+	j := jobTypes.Job{
+		Name:      "mybackup",
+		TeamOwner: s.team.Name,
+		Pool:      "test-default",
+		Spec: jobTypes.JobSpec{
+			Schedule: "* * * * *",
+			Container: jobTypes.ContainerInfo{
+				OriginalImageSrc: "ubuntu:latest",
+				Command:          []string{"./backup"},
+			},
+			Sidecars: []jobTypes.Container{
+				{Name: "sql-proxy", Image: "cloud-sql-proxy:2"},
+			},
+		},
+	}
+	err := s.p.EnsureJob(context.TODO(), &j)
+	c.Assert(err, check.Equals, jobTypes.ErrSidecarsNotSupported)
+	_, err = s.client.BatchV1().CronJobs("default").Get(context.TODO(), "mybackup", metav1.GetOptions{})
+	c.Assert(err, check.NotNil)
+}
+
+func (s *S) TestClusterClientNativeSidecars(c *check.C) {
+	fakeDiscovery := s.client.Discovery().(*fakediscovery.FakeDiscovery)
+	tests := []struct {
+		serverVersion string
+		config        string
+		expected      bool
+	}{
+		{serverVersion: "v1.28.3", expected: false},
+		{serverVersion: "v1.29.0", expected: true},
+		{serverVersion: "v1.30.1-gke.100", expected: true},
+		{serverVersion: "v1.28.3", config: "true", expected: true},
+		{serverVersion: "v1.29.0", config: "false", expected: false},
+	}
+	for _, tt := range tests {
+		fakeDiscovery.FakedServerVersion = &version.Info{GitVersion: tt.serverVersion}
+		s.clusterClient.CustomData[jobNativeSidecarsKey] = tt.config
+		supported, err := s.clusterClient.NativeSidecars()
+		c.Assert(err, check.IsNil)
+		c.Assert(supported, check.Equals, tt.expected, check.Commentf("version %q config %q", tt.serverVersion, tt.config))
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+	v1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+// executions created before this window, e.g. the ones listed when the
+// informer starts, are never suspended by the run quota.
+const jobAdmissionWindow = time.Minute
+
+// RunningJobUnits returns the number of units of the jobs owned by the team
+// that are running, or about to run, in the pool.
+func (p *kubernetesProvisioner) RunningJobUnits(ctx context.Context, team, pool string) (int, error) {
+	client, err := clusterForPool(ctx, pool)
+	if err != nil {
+		return 0, err
+	}
+	controller, err := getClusterController(p, client)
+	if err != nil {
+		return 0, err
+	}
+	podInformer, err := controller.getPodInformer()
+	if err != nil {
+		return 0, err
+	}
+	jobInformer, err := controller.getJobInformer()
+	if err != nil {
+		return 0, err
+	}
+	return countRunningJobUnits(podInformer.Lister(), jobInformer.Lister(), client.PoolNamespace(pool), team, pool, nil)
+}
+
+// countRunningJobUnits counts the units of the team in the pool. Executions
+// admitted but without units yet, e.g. in a burst of executions, count as
+// many units as they'll run. When admitting is set, it's left out of the
+// count along with the executions created after it and not started yet, as
+// those are only admitted after it.
+func countRunningJobUnits(podLister v1listers.PodLister, jobLister batchv1listers.JobLister, namespace, team, pool string, admitting *batchv1.Job) (int, error) {
+	selector := labels.SelectorFromSet(labels.Set{
+		tsuruLabelJobTeamOwner: team,
+		tsuruLabelJobPool:      pool,
+	})
+	pods, err := podLister.Pods(namespace).List(selector)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	jobs, err := jobLister.Jobs(namespace).List(selector)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	units := map[string]int{}
+	for _, pod := range pods {
+		if pod.Status.Phase == apiv1.PodSucceeded || pod.Status.Phase == apiv1.PodFailed || pod.DeletionTimestamp != nil {
+			continue
+		}
+		units[pod.Labels["job-name"]]++
+	}
+	for _, job := range jobs {
+		if ptr.Deref(job.Spec.Suspend, false) || jobRunFromK8sJob(job, nil).IsFinished() {
+			continue
+		}
+		if admitting != nil && units[job.Name] == 0 && job.Status.StartTime == nil && admittedAfter(job, admitting) {
+			continue
+		}
+		if jobUnits := jobRunUnits(job); units[job.Name] < jobUnits {
+			units[job.Name] = jobUnits
+		}
+	}
+	if admitting != nil {
+		delete(units, admitting.Name)
+	}
+	var count int
+	for _, jobUnits := range units {
+		count += jobUnits
+	}
+	return count, nil
+}
+
+// admittedAfter reports whether job is admitted after other, executions are
+// admitted in creation order.
+func admittedAfter(job, other *batchv1.Job) bool {
+	if job.CreationTimestamp.Equal(&other.CreationTimestamp) {
+		return job.Name > other.Name
+	}
+	return other.CreationTimestamp.Before(&job.CreationTimestamp)
+}
+
+func jobRunUnits(job *batchv1.Job) int {
+	if parallelism := ptr.Deref(job.Spec.Parallelism, 0); parallelism > 0 {
+		return int(parallelism)
+	}
+	return 1
+}
+
+func jobRunQuotaLimit(ctx context.Context, team string) (int, bool) {
+	q, err := servicemanager.TeamJobRunQuota.Get(ctx, &authTypes.Team{Name: team})
+	if err != nil {
+		log.Errorf("[job quota] unable to get job run quota of team %q: %v", team, err)
+		return 0, false
+	}
+	if q.IsUnlimited() {
+		return 0, false
+	}
+	return q.Limit, true
+}
+
+// admitJobRun suspends a new execution when the team is already running as
+// many job units in the pool as its quota allows. Suspended executions are
+// resumed by releaseJobRuns once other executions finish.
+func admitJobRun(client *ClusterClient, podLister v1listers.PodLister, jobLister batchv1listers.JobLister, job *batchv1.Job) {
+	team := job.Labels[tsuruLabelJobTeamOwner]
+	if team == "" || ptr.Deref(job.Spec.Suspend, false) || job.Status.Succeeded+job.Status.Failed > 0 {
+		return
+	}
+	if time.Since(job.CreationTimestamp.Time) > jobAdmissionWindow {
+		return
+	}
+	ctx := context.Background()
+	limit, limited := jobRunQuotaLimit(ctx, team)
+	if !limited {
+		return
+	}
+	running, err := countRunningJobUnits(podLister, jobLister, job.Namespace, team, job.Labels[tsuruLabelJobPool], job)
+	if err != nil {
+		log.Errorf("[job quota] unable to count running units of team %q: %v", team, err)
+		return
+	}
+	if running+jobRunUnits(job) <= limit {
+		return
+	}
+	log.Debugf("[job quota] suspending %s, team %q is running %d of %d units", job.Name, team, running, limit)
+	if err = setJobRunSuspended(ctx, client, job, true); err != nil {
+		log.Errorf("[job quota] unable to suspend %s: %v", job.Name, err)
+	}
+}
+
+// releaseJobRuns resumes the executions suspended by admitJobRun in the
+// team and pool of job, oldest first, while they fit in the run quota of the
+// team. It's called when an execution finishes and on every resync of the
+// suspended executions, so they're resumed when the quota is raised or units
+// are removed.
+func releaseJobRuns(client *ClusterClient, podLister v1listers.PodLister, jobLister batchv1listers.JobLister, job *batchv1.Job) {
+	team := job.Labels[tsuruLabelJobTeamOwner]
+	pool := job.Labels[tsuruLabelJobPool]
+	if team == "" {
+		return
+	}
+	ctx := context.Background()
+	jobs, err := jobLister.Jobs(job.Namespace).List(labels.SelectorFromSet(labels.Set{
+		tsuruLabelJobTeamOwner: team,
+		tsuruLabelJobPool:      pool,
+	}))
+	if err != nil {
+		log.Errorf("[job quota] unable to list jobs of team %q: %v", team, err)
+		return
+	}
+	var suspended []*batchv1.Job
+	for _, j := range jobs {
+		if isQuotaSuspended(j) {
+			suspended = append(suspended, j)
+		}
+	}
+	if len(suspended) == 0 {
+		return
+	}
+	sort.Slice(suspended, func(i, j int) bool {
+		return suspended[i].CreationTimestamp.Before(&suspended[j].CreationTimestamp)
+	})
+	limit, limited := jobRunQuotaLimit(ctx, team)
+	running, err := countRunningJobUnits(podLister, jobLister, job.Namespace, team, pool, nil)
+	if err != nil {
+		log.Errorf("[job quota] unable to count running units of team %q: %v", team, err)
+		return
+	}
+	for _, j := range suspended {
+		units := jobRunUnits(j)
+		if limited && running+units > limit {
+			return
+		}
+		if err = setJobRunSuspended(ctx, client, j, false); err != nil {
+			log.Errorf("[job quota] unable to resume %s: %v", j.Name, err)
+			return
+		}
+		running += units
+	}
+}
+
+func isQuotaSuspended(job *batchv1.Job) bool {
+	return job.Annotations[tsuruJobQuotaSuspended] == "true" && ptr.Deref(job.Spec.Suspend, false)
+}
+
+func setJobRunSuspended(ctx context.Context, client *ClusterClient, job *batchv1.Job, suspend bool) error {
+	var annotation interface{}
+	if suspend {
+		annotation = "true"
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{tsuruJobQuotaSuspended: annotation},
+		},
+		"spec": map[string]interface{}{"suspend": suspend},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = client.BatchV1().Jobs(job.Namespace).Patch(ctx, job.Name, k8sTypes.MergePatchType, patch, metav1.PatchOptions{})
+	return errors.WithStack(err)
+}
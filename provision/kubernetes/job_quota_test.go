@@ -0,0 +1,154 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"sync/atomic"
+
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	"github.com/tsuru/tsuru/types/quota"
+	check "gopkg.in/check.v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func (s *S) TestAdmitAndReleaseJobRuns(c *check.C) {
+	s.mockService.TeamJobRunQuota.OnGet = func(team *authTypes.Team) (*quota.Quota, error) {
+		c.Assert(team.Name, check.Equals, "team1")
+		return &quota.Quota{Limit: 2}, nil
+	}
+	ns := s.clusterClient.PoolNamespace("test-default")
+	teamLabels := map[string]string{
+		tsuruLabelJobTeamOwner: "team1",
+		tsuruLabelJobPool:      "test-default",
+	}
+	for _, name := range []string{"running-1", "running-2"} {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+				Labels:    map[string]string{"tsuru.io/is-tsuru": "true", tsuruLabelJobTeamOwner: "team1", tsuruLabelJobPool: "test-default", "job-name": "running"},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		s.waitPodUpdate(c, func() {
+			_, err := s.client.CoreV1().Pods(ns).Create(context.TODO(), pod, metav1.CreateOptions{})
+			c.Assert(err, check.IsNil)
+		})
+	}
+	podLister := s.podLister(c)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "myjob-28000000",
+			Namespace:         ns,
+			Labels:            teamLabels,
+			CreationTimestamp: metav1.Now(),
+		},
+	}
+	job, err := s.client.BatchV1().Jobs(ns).Create(context.TODO(), job, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	s.waitJobListed(c, ns, job.Name, func(*batchv1.Job) bool { return true })
+	jobLister := s.jobLister(c)
+	count, err := s.p.RunningJobUnits(context.TODO(), "team1", "test-default")
+	c.Assert(err, check.IsNil)
+	c.Assert(count, check.Equals, 3)
+
+	admitJobRun(s.clusterClient, podLister, jobLister, job)
+	job, err = s.client.BatchV1().Jobs(ns).Get(context.TODO(), "myjob-28000000", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(ptr.Deref(job.Spec.Suspend, false), check.Equals, true)
+	c.Assert(job.Annotations[tsuruJobQuotaSuspended], check.Equals, "true")
+	s.waitJobListed(c, ns, job.Name, isQuotaSuspended)
+
+	s.waitPodUpdate(c, func() {
+		err = s.client.CoreV1().Pods(ns).Delete(context.TODO(), "running-1", metav1.DeleteOptions{})
+		c.Assert(err, check.IsNil)
+	})
+	releaseJobRuns(s.clusterClient, podLister, jobLister, job)
+	job, err = s.client.BatchV1().Jobs(ns).Get(context.TODO(), "myjob-28000000", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(ptr.Deref(job.Spec.Suspend, false), check.Equals, false)
+	_, suspended := job.Annotations[tsuruJobQuotaSuspended]
+	c.Assert(suspended, check.Equals, false)
+}
+
+func (s *S) TestAdmitJobRunUnlimited(c *check.C) {
+	ns := s.clusterClient.PoolNamespace("test-default")
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "myjob-28000000",
+			Namespace:         ns,
+			Labels:            map[string]string{tsuruLabelJobTeamOwner: "team1", tsuruLabelJobPool: "test-default"},
+			CreationTimestamp: metav1.Now(),
+		},
+	}
+	job, err := s.client.BatchV1().Jobs(ns).Create(context.TODO(), job, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	admitJobRun(s.clusterClient, s.podLister(c), s.jobLister(c), job)
+	job, err = s.client.BatchV1().Jobs(ns).Get(context.TODO(), "myjob-28000000", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(job.Spec.Suspend, check.IsNil)
+}
+
+func (s *S) TestAdmitJobRunCountsPendingJobs(c *check.C) {
+	s.mockService.TeamJobRunQuota.OnGet = func(team *authTypes.Team) (*quota.Quota, error) {
+		return &quota.Quota{Limit: 1}, nil
+	}
+	ns := s.clusterClient.PoolNamespace("test-default")
+	now := metav1.Now()
+	var jobs []*batchv1.Job
+	for _, name := range []string{"myjob-1", "myjob-2"} {
+		job, err := s.client.BatchV1().Jobs(ns).Create(context.TODO(), &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         ns,
+				Labels:            map[string]string{tsuruLabelJobTeamOwner: "team1", tsuruLabelJobPool: "test-default"},
+				CreationTimestamp: now,
+			},
+		}, metav1.CreateOptions{})
+		c.Assert(err, check.IsNil)
+		s.waitJobListed(c, ns, name, func(*batchv1.Job) bool { return true })
+		jobs = append(jobs, job)
+	}
+	podLister, jobLister := s.podLister(c), s.jobLister(c)
+	// the units of myjob-1 aren't created yet, but it's admitted first
+	admitJobRun(s.clusterClient, podLister, jobLister, jobs[1])
+	admitJobRun(s.clusterClient, podLister, jobLister, jobs[0])
+	job, err := s.client.BatchV1().Jobs(ns).Get(context.TODO(), "myjob-1", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(job.Spec.Suspend, check.IsNil)
+	job, err = s.client.BatchV1().Jobs(ns).Get(context.TODO(), "myjob-2", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(ptr.Deref(job.Spec.Suspend, false), check.Equals, true)
+}
+
+func (s *S) TestJobResyncReleasesSuspendedRuns(c *check.C) {
+	ns := s.clusterClient.PoolNamespace("test-default")
+	job, err := s.client.BatchV1().Jobs(ns).Create(context.TODO(), &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "myjob-1",
+			Namespace:         ns,
+			Labels:            map[string]string{tsuruLabelJobTeamOwner: "team1", tsuruLabelJobPool: "test-default"},
+			Annotations:       map[string]string{tsuruJobQuotaSuspended: "true"},
+			CreationTimestamp: metav1.Now(),
+		},
+		Spec: batchv1.JobSpec{Suspend: ptr.To(true)},
+	}, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	s.waitJobListed(c, ns, job.Name, isQuotaSuspended)
+	controller, err := getClusterController(s.p, s.clusterClient)
+	c.Assert(err, check.IsNil)
+	atomic.StoreInt32(&controller.leader, 1)
+	// the quota of the team is unlimited now
+	controller.onJobResync(job)
+	job, err = s.client.BatchV1().Jobs(ns).Get(context.TODO(), "myjob-1", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(ptr.Deref(job.Spec.Suspend, false), check.Equals, false)
+	_, suspended := job.Annotations[tsuruJobQuotaSuspended]
+	c.Assert(suspended, check.Equals, false)
+}
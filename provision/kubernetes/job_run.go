@@ -0,0 +1,92 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"sort"
+
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/servicemanager"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	v1listers "k8s.io/client-go/listers/core/v1"
+)
+
+// recordJobRun stores the current state of a job execution in the job run
+// history, so it can be inspected after the cluster removes the job.
+func recordJobRun(podLister v1listers.PodLister, job *batchv1.Job) {
+	if job.Labels[tsuruLabelJobName] == "" {
+		return
+	}
+	pods, err := podLister.Pods(job.Namespace).List(labels.SelectorFromSet(labels.Set{"job-name": job.Name}))
+	if err != nil {
+		log.Errorf("[job run] unable to list units of %s: %v", job.Name, err)
+		return
+	}
+	run := jobRunFromK8sJob(job, pods)
+	if err = servicemanager.Job.RecordRun(context.Background(), run); err != nil {
+		log.Errorf("[job run] unable to record run %s of job %s: %v", run.Name, run.Job, err)
+	}
+}
+
+func jobRunFromK8sJob(job *batchv1.Job, pods []*apiv1.Pod) jobTypes.Run {
+	run := jobTypes.Run{
+		Name:        job.Name,
+		Job:         job.Labels[tsuruLabelJobName],
+		Pool:        job.Labels[tsuruLabelJobPool],
+		Manual:      job.Annotations["cronjob.kubernetes.io/instantiate"] == "manual",
+		TriggeredBy: job.Annotations[tsuruJobTriggeredBy],
+		WorkflowRun: job.Annotations[tsuruJobWorkflowRun],
+		Status:      jobTypes.RunStatusRunning,
+		StartTime:   job.CreationTimestamp.Time.UTC(),
+		Attempts:    job.Status.Active + job.Status.Succeeded + job.Status.Failed,
+	}
+	if job.Status.StartTime != nil {
+		run.StartTime = job.Status.StartTime.Time.UTC()
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != apiv1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			run.Status = jobTypes.RunStatusSucceeded
+		case batchv1.JobFailed:
+			run.Status = jobTypes.RunStatusFailed
+			run.Reason = cond.Reason
+			run.Message = cond.Message
+		default:
+			continue
+		}
+		endTime := cond.LastTransitionTime.Time.UTC()
+		if job.Status.CompletionTime != nil {
+			endTime = job.Status.CompletionTime.Time.UTC()
+		}
+		run.EndTime = &endTime
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].CreationTimestamp.Before(&pods[j].CreationTimestamp)
+	})
+	for _, pod := range pods {
+		run.Units = append(run.Units, pod.Name)
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != jobTypes.MainContainerName {
+				continue
+			}
+			terminated := status.State.Terminated
+			if terminated == nil {
+				terminated = status.LastTerminationState.Terminated
+			}
+			if terminated != nil {
+				exitCode := terminated.ExitCode
+				run.ExitCode = &exitCode
+			}
+		}
+	}
+	return run
+}
@@ -7,6 +7,7 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -264,6 +265,47 @@ func (s *S) TestProvisionerCreateCronJob(c *check.C) {
 				c.Assert(err, check.IsNil)
 			},
 		},
+		{
+			name:      "create suspended cronjob with schedule policies",
+			jobName:   "myjob",
+			namespace: "default",
+			scenario: func() {
+				cj := jobTypes.Job{
+					Name:      "myjob",
+					TeamOwner: s.team.Name,
+					Pool:      "test-default",
+					Spec: jobTypes.JobSpec{
+						Schedule:                   "0 3 * * *",
+						Suspend:                    true,
+						StartingDeadlineSeconds:    func() *int64 { r := int64(300); return &r }(),
+						TimeZone:                   "America/Sao_Paulo",
+						SuccessfulJobsHistoryLimit: func() *int32 { r := int32(1); return &r }(),
+						FailedJobsHistoryLimit:     func() *int32 { r := int32(5); return &r }(),
+						Container: jobTypes.ContainerInfo{
+							OriginalImageSrc: "ubuntu:latest",
+							Command:          []string{"echo", "hello world"},
+						},
+					},
+				}
+				err := s.p.EnsureJob(context.TODO(), &cj)
+				waitCron()
+				c.Assert(err, check.IsNil)
+			},
+			assertion: func(c *check.C, gotCron *batchv1.CronJob) {
+				c.Assert(*gotCron.Spec.Suspend, check.Equals, true)
+				c.Assert(*gotCron.Spec.StartingDeadlineSeconds, check.Equals, int64(300))
+				c.Assert(*gotCron.Spec.TimeZone, check.Equals, "America/Sao_Paulo")
+				c.Assert(*gotCron.Spec.SuccessfulJobsHistoryLimit, check.Equals, int32(1))
+				c.Assert(*gotCron.Spec.FailedJobsHistoryLimit, check.Equals, int32(5))
+			},
+			teardown: func() {
+				err := s.p.DestroyJob(context.TODO(), &jobTypes.Job{
+					Name: "myjob",
+					Pool: "test-default",
+				})
+				c.Assert(err, check.IsNil)
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt.scenario()
@@ -647,7 +689,7 @@ func (s *S) TestProvisionerTriggerCron(c *check.C) {
 			},
 			scenario: func(t *time.Time) {
 				*t = time.Now()
-				err := s.p.TriggerCron(context.TODO(), "myjob", "test-default")
+				err := s.p.TriggerCron(context.TODO(), "myjob", "test-default", jobTypes.TriggerOptions{})
 				c.Assert(err, check.IsNil)
 				waitCron()
 			},
@@ -738,8 +780,10 @@ func (s *S) TestProvisionerTriggerCron(c *check.C) {
 				listJobs, err := s.client.BatchV1().Jobs(expected.Namespace).List(context.TODO(), metav1.ListOptions{})
 				c.Assert(err, check.IsNil)
 				c.Assert(len(listJobs.Items), check.Equals, 1)
-				gotJob, err := s.client.BatchV1().Jobs(expected.Namespace).Get(context.TODO(), expected.Name, metav1.GetOptions{})
-				c.Assert(err, check.IsNil)
+				gotJob := &listJobs.Items[0]
+				c.Assert(strings.HasPrefix(gotJob.Name, expected.Name+"-"), check.Equals, true)
+				c.Assert(gotJob.Name, check.HasLen, len(expected.Name)+6)
+				expected.Name = gotJob.Name
 				c.Assert(gotJob, check.DeepEquals, expected)
 				// cleanup
 				err = s.client.BatchV1().CronJobs(expected.Namespace).Delete(context.TODO(), "myjob", metav1.DeleteOptions{})
@@ -755,6 +799,42 @@ func (s *S) TestProvisionerTriggerCron(c *check.C) {
 	}
 }
 
+func (s *S) TestGetManualJobName(c *check.C) {
+	name := getManualJobName("myjob")
+	c.Assert(name, check.Matches, `myjob-manual-job-\d+-[a-z0-9]{5}`)
+	c.Assert(getManualJobName("myjob"), check.Not(check.Equals), name)
+	longName := getManualJobName(strings.Repeat("a", 40))
+	c.Assert(len(longName) <= 63, check.Equals, true)
+}
+
+func (s *S) TestApplyTriggerOptions(c *check.C) {
+	podSpec := corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name:    "job",
+				Command: []string{"./reprocess"},
+				Env:     []corev1.EnvVar{{Name: "DATE", Value: "today"}, {Name: "MODE", Value: "full"}},
+			},
+		},
+	}
+	applyTriggerOptions(&podSpec, jobTypes.TriggerOptions{
+		Envs: []bindTypes.EnvVar{{Name: "DATE", Value: "$yesterday"}, {Name: "VERBOSE", Value: "1"}},
+		Args: []string{"--dry-run"},
+	})
+	c.Assert(podSpec.Containers[0], check.DeepEquals, corev1.Container{
+		Name:    "job",
+		Command: []string{"./reprocess"},
+		Args:    []string{"--dry-run"},
+		Env: []corev1.EnvVar{
+			{Name: "DATE", Value: "$$yesterday"},
+			{Name: "MODE", Value: "full"},
+			{Name: "VERBOSE", Value: "1"},
+		},
+	})
+	applyTriggerOptions(&podSpec, jobTypes.TriggerOptions{Command: []string{"sh", "-c", "exit 0"}})
+	c.Assert(podSpec.Containers[0].Command, check.DeepEquals, []string{"sh", "-c", "exit 0"})
+}
+
 func (s *S) TestCreateJobEvent(c *check.C) {
 	boolTrue := true
 	cleanup := func() {
@@ -1347,3 +1427,67 @@ func (s *S) TestKillJobUnitUnknow(c *check.C) {
 	_, err = s.client.BatchV1().Jobs("default").Get(context.TODO(), "myjob-unit1", metav1.GetOptions{})
 	c.Assert(err, check.IsNil)
 }
+
+func (s *S) TestJobRunFromK8sJob(c *check.C) {
+	created := metav1.NewTime(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC))
+	started := metav1.NewTime(created.Add(time.Second))
+	failedAt := metav1.NewTime(created.Add(time.Minute))
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "myjob-manual-job-123",
+			CreationTimestamp: created,
+			Labels: map[string]string{
+				"tsuru.io/job-name": "myjob",
+				"tsuru.io/job-pool": "pool1",
+			},
+			Annotations: map[string]string{
+				"cronjob.kubernetes.io/instantiate": "manual",
+				"tsuru.io/triggered-by":             "admin@example.com",
+				"tsuru.io/workflow-run":             "6627a3f0c1d2e3f4a5b6c7d8",
+			},
+		},
+		Status: batchv1.JobStatus{
+			StartTime: &started,
+			Failed:    2,
+			Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit", LastTransitionTime: failedAt},
+			},
+		},
+	}
+	pods := []*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "myjob-manual-job-123-bbbbb", CreationTimestamp: failedAt},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "job", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 2}}},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "myjob-manual-job-123-aaaaa", CreationTimestamp: started},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "job", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}},
+			}},
+		},
+	}
+	endTime := failedAt.Time.UTC()
+	c.Assert(jobRunFromK8sJob(job, pods), check.DeepEquals, jobTypes.Run{
+		Name:        "myjob-manual-job-123",
+		Job:         "myjob",
+		Pool:        "pool1",
+		Manual:      true,
+		TriggeredBy: "admin@example.com",
+		WorkflowRun: "6627a3f0c1d2e3f4a5b6c7d8",
+		Status:      jobTypes.RunStatusFailed,
+		StartTime:   started.Time.UTC(),
+		EndTime:     &endTime,
+		ExitCode:    ptr.To[int32](2),
+		Attempts:    2,
+		Reason:      "BackoffLimitExceeded",
+		Message:     "Job has reached the specified backoff limit",
+		Units:       []string{"myjob-manual-job-123-aaaaa", "myjob-manual-job-123-bbbbb"},
+	})
+}
+
+func (s *S) TestBuildTTLSecondsAfterFinished(c *check.C) {
+	c.Assert(*buildTTLSecondsAfterFinished(nil), check.Equals, int32(86400))
+	c.Assert(*buildTTLSecondsAfterFinished(ptr.To[int32](0)), check.Equals, int32(0))
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/tsuru/tsuru/set"
 	appTypes "github.com/tsuru/tsuru/types/app"
+	jobTypes "github.com/tsuru/tsuru/types/job"
 	logTypes "github.com/tsuru/tsuru/types/log"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -137,6 +138,7 @@ func listLogsFromPods(ctx context.Context, clusterClient *ClusterClient, ns stri
 			defer wg.Done()
 
 			request := clusterClient.CoreV1().Pods(ns).GetLogs(pod.ObjectMeta.Name, &apiv1.PodLogOptions{
+				Container:  logContainer(pod.ObjectMeta.Labels),
 				TailLines:  tailLimit,
 				Timestamps: true,
 			})
@@ -282,6 +284,15 @@ func logMetadata(labels map[string]string) (string, logTypes.LogType) {
 	return "", logTypes.LogTypeApp
 }
 
+// logContainer returns the container whose logs are streamed, pods of jobs
+// may also run sidecars so the job container must be picked explicitly.
+func logContainer(labels map[string]string) string {
+	if _, logType := logMetadata(labels); logType == logTypes.LogTypeJob {
+		return jobTypes.MainContainerName
+	}
+	return ""
+}
+
 func (k *k8sLogsWatcher) watchPod(pod *apiv1.Pod, addedLater bool) {
 	defer k.wg.Done()
 	name, logType := logMetadata(pod.ObjectMeta.Labels)
@@ -294,6 +305,7 @@ func (k *k8sLogsWatcher) watchPod(pod *apiv1.Pod, addedLater bool) {
 	}
 
 	request := k.clusterClient.CoreV1().Pods(k.ns).GetLogs(pod.ObjectMeta.Name, &apiv1.PodLogOptions{
+		Container:  logContainer(pod.ObjectMeta.Labels),
 		Follow:     true,
 		TailLines:  &tailLines,
 		Timestamps: true,
@@ -14,7 +14,9 @@ import (
 	"github.com/pkg/errors"
 	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/provision"
+	batchv1 "k8s.io/api/batch/v1"
 	apiv1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	vpaInformers "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/informers/externalversions"
@@ -26,6 +28,8 @@ import (
 	v1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/informers/internalinterfaces"
 	"k8s.io/client-go/kubernetes/scheme"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+	v1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/leaderelection"
@@ -136,9 +140,28 @@ func (c *clusterController) isLeader() bool {
 	return atomic.LoadInt32(&c.leader) == 1
 }
 
+// startJobInformer registers the handlers tracking job runs, quotas, alerts
+// and workflows. Kubernetes events of jobs are only recorded on clusters with
+// job event creation enabled.
 func (c *clusterController) startJobInformer() error {
+	jobInformer, err := c.getJobInformerWait(false)
+	if err != nil {
+		return err
+	}
+	jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.onJobAdd(obj)
+			c.onJobChange(nil, obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.onJobFinish(oldObj, newObj)
+			c.onJobChange(oldObj, newObj)
+			c.onJobResync(newObj)
+		},
+	})
+
 	if enable, _ := c.cluster.EnableJobEventCreation(); !enable {
-		return errors.New("job event creation is not enabled")
+		return nil
 	}
 	eventsInformer, err := c.getEventInformerWait(false)
 	if err != nil {
@@ -154,6 +177,13 @@ func (c *clusterController) startJobInformer() error {
 			if !ok {
 				return
 			}
+			if evt.InvolvedObject.Kind == "CronJob" {
+				wg := &sync.WaitGroup{}
+				wg.Add(1)
+				go createCronJobAlertEvent(evt, wg)
+				wg.Wait()
+				return
+			}
 			if evt.InvolvedObject.Kind != "Job" {
 				return
 			}
@@ -179,6 +209,100 @@ func (c *clusterController) startJobInformer() error {
 	return nil
 }
 
+func (c *clusterController) onJobAdd(obj interface{}) {
+	if !c.isLeader() {
+		return
+	}
+	job, ok := obj.(*batchv1.Job)
+	if !ok {
+		return
+	}
+	podLister, jobLister, err := c.jobQuotaListers()
+	if err != nil {
+		log.Errorf("[job quota] %v", err)
+		return
+	}
+	admitJobRun(c.cluster, podLister, jobLister, job)
+}
+
+func (c *clusterController) onJobFinish(oldObj, newObj interface{}) {
+	if !c.isLeader() {
+		return
+	}
+	oldJob, ok := oldObj.(*batchv1.Job)
+	if !ok {
+		return
+	}
+	newJob, ok := newObj.(*batchv1.Job)
+	if !ok {
+		return
+	}
+	if jobRunFromK8sJob(oldJob, nil).IsFinished() || !jobRunFromK8sJob(newJob, nil).IsFinished() {
+		return
+	}
+	podLister, jobLister, err := c.jobQuotaListers()
+	if err != nil {
+		log.Errorf("[job quota] %v", err)
+		return
+	}
+	releaseJobRuns(c.cluster, podLister, jobLister, newJob)
+}
+
+func (c *clusterController) jobQuotaListers() (v1listers.PodLister, batchv1listers.JobLister, error) {
+	podInformer, err := c.getPodInformer()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unable to get pod informer")
+	}
+	jobInformer, err := c.getJobInformer()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unable to get job informer")
+	}
+	return podInformer.Lister(), jobInformer.Lister(), nil
+}
+
+func (c *clusterController) onJobChange(oldObj, newObj interface{}) {
+	if !c.isLeader() {
+		return
+	}
+	job, ok := newObj.(*batchv1.Job)
+	if !ok {
+		return
+	}
+	// resyncs and metadata changes don't change the recorded run
+	if oldJob, ok := oldObj.(*batchv1.Job); ok && apiequality.Semantic.DeepEqual(oldJob.Status, job.Status) {
+		return
+	}
+	podInformer, err := c.getPodInformer()
+	if err != nil {
+		log.Errorf("[job run] unable to get pod informer: %v", err)
+		return
+	}
+	recordJobRun(podInformer.Lister(), job)
+}
+
+// onJobResync is called on every update of the job, including the periodic
+// resyncs of the informer, to check the duration of active runs and retry
+// resuming the runs suspended by the run quota.
+func (c *clusterController) onJobResync(obj interface{}) {
+	if !c.isLeader() {
+		return
+	}
+	job, ok := obj.(*batchv1.Job)
+	if !ok {
+		return
+	}
+	checkActiveJobDuration(c.cluster, job)
+	if !isQuotaSuspended(job) {
+		return
+	}
+	podLister, jobLister, err := c.jobQuotaListers()
+	if err != nil {
+		log.Errorf("[job quota] %v", err)
+		return
+	}
+	releaseJobRuns(c.cluster, podLister, jobLister, job)
+}
+
 func (c *clusterController) start() (v1informers.PodInformer, error) {
 	informer, err := c.getPodInformerWait(false)
 	if err != nil {
@@ -285,6 +409,10 @@ func (c *clusterController) getVPAInformer() (vpaV1Informers.VerticalPodAutoscal
 }
 
 func (c *clusterController) getJobInformer() (jobsInformer.JobInformer, error) {
+	return c.getJobInformerWait(true)
+}
+
+func (c *clusterController) getJobInformerWait(wait bool) (jobsInformer.JobInformer, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.jobsInformer == nil {
@@ -296,7 +424,10 @@ func (c *clusterController) getJobInformer() (jobsInformer.JobInformer, error) {
 			return nil, err
 		}
 	}
-	err := c.waitForSync(c.jobsInformer.Informer())
+	var err error
+	if wait {
+		err = c.waitForSync(c.jobsInformer.Informer())
+	}
 	return c.jobsInformer, err
 }
 
@@ -443,7 +574,7 @@ func (c *clusterController) createElector(hostID string) (*leaderelection.Leader
 		Component: leaderElectionName,
 	})
 	lock, err := resourcelock.New(
-		resourcelock.EndpointsLeasesResourceLock,
+		resourcelock.LeasesResourceLock,
 		c.cluster.Namespace(),
 		leaderElectionName,
 		c.cluster.CoreV1(),
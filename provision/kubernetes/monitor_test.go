@@ -5,8 +5,15 @@
 package kubernetes
 
 import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	jobTypes "github.com/tsuru/tsuru/types/job"
 	check "gopkg.in/check.v1"
+	batchv1 "k8s.io/api/batch/v1"
 	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func (s *S) TestNewRouterControllerSameInstance(c *check.C) {
@@ -41,3 +48,42 @@ func (s *S) TestPodListeners(c *check.C) {
 	clusterController.removePodListener("listerner2")
 	c.Assert(clusterController.podListeners, check.HasLen, 0)
 }
+
+func (s *S) TestJobInformerWithoutJobEventCreation(c *check.C) {
+	enabled, err := s.clusterClient.EnableJobEventCreation()
+	c.Assert(err, check.IsNil)
+	c.Assert(enabled, check.Equals, false)
+	recorded := make(chan jobTypes.Run, 1)
+	s.mockService.JobService.OnRecordRun = func(run jobTypes.Run) error {
+		select {
+		case recorded <- run:
+		default:
+		}
+		return nil
+	}
+	defer func() {
+		s.mockService.JobService.OnRecordRun = nil
+	}()
+	controller, err := getClusterController(s.p, s.clusterClient)
+	c.Assert(err, check.IsNil)
+	atomic.StoreInt32(&controller.leader, 1)
+	_, err = s.client.BatchV1().Jobs("default").Create(context.TODO(), &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myjob-1",
+			Namespace: "default",
+			Labels: map[string]string{
+				"tsuru.io/is-tsuru": "true",
+				"tsuru.io/is-job":   "true",
+				tsuruLabelJobName:   "myjob",
+			},
+		},
+	}, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	select {
+	case run := <-recorded:
+		c.Assert(run.Name, check.Equals, "myjob-1")
+		c.Assert(run.Job, check.Equals, "myjob")
+	case <-time.After(5 * time.Second):
+		c.Fatal("timeout waiting for the job run to be recorded")
+	}
+}
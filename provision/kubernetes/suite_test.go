@@ -36,6 +36,7 @@ import (
 	_ "go.uber.org/automaxprocs"
 	"golang.org/x/crypto/bcrypt"
 	check "gopkg.in/check.v1"
+	batchv1 "k8s.io/api/batch/v1"
 	apiv1 "k8s.io/api/core/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	fakeapiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
@@ -47,6 +48,8 @@ import (
 	"k8s.io/client-go/informers/internalinterfaces"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+	v1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
 	backendConfigClientSet "k8s.io/ingress-gce/pkg/backendconfig/client/clientset/versioned"
 	fakeBackendConfig "k8s.io/ingress-gce/pkg/backendconfig/client/clientset/versioned/fake"
@@ -196,6 +199,9 @@ func (s *S) SetUpTest(c *check.C) {
 	s.mockService.Team.OnFindByName = func(_ string) (*authTypes.Team, error) {
 		return s.team, nil
 	}
+	s.mockService.TeamJobRunQuota.OnGet = func(_ *authTypes.Team) (*quota.Quota, error) {
+		return &quota.UnlimitedQuota, nil
+	}
 	s.mockService.Team.OnFindByNames = func(_ []string) ([]authTypes.Team, error) {
 		return []authTypes.Team{{Name: s.team.Name}}, nil
 	}
@@ -276,6 +282,39 @@ func sortPods(pods []*apiv1.Pod) {
 	})
 }
 
+func (s *S) podLister(c *check.C) v1listers.PodLister {
+	controller, err := getClusterController(s.p, s.clusterClient)
+	c.Assert(err, check.IsNil)
+	podInformer, err := controller.getPodInformer()
+	c.Assert(err, check.IsNil)
+	return podInformer.Lister()
+}
+
+func (s *S) jobLister(c *check.C) batchv1listers.JobLister {
+	controller, err := getClusterController(s.p, s.clusterClient)
+	c.Assert(err, check.IsNil)
+	jobInformer, err := controller.getJobInformer()
+	c.Assert(err, check.IsNil)
+	return jobInformer.Lister()
+}
+
+// waitJobListed waits until the job informer lists the job matching cond.
+func (s *S) waitJobListed(c *check.C, namespace, name string, cond func(*batchv1.Job) bool) {
+	jobLister := s.jobLister(c)
+	timeout := time.After(5 * time.Second)
+	for {
+		job, err := jobLister.Jobs(namespace).Get(name)
+		if err == nil && cond(job) {
+			return
+		}
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-timeout:
+			c.Fatalf("timeout waiting for job %s", name)
+		}
+	}
+}
+
 func (s *S) waitPodUpdate(c *check.C, fn func()) {
 	controller, err := getClusterController(s.p, s.clusterClient)
 	c.Assert(err, check.IsNil)
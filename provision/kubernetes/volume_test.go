@@ -11,6 +11,7 @@ import (
 	tsuruv1 "github.com/tsuru/tsuru/provision/kubernetes/pkg/apis/tsuru/v1"
 	"github.com/tsuru/tsuru/provision/provisiontest"
 	"github.com/tsuru/tsuru/servicemanager"
+	jobTypes "github.com/tsuru/tsuru/types/job"
 	volumeTypes "github.com/tsuru/tsuru/types/volume"
 	check "gopkg.in/check.v1"
 	apiv1 "k8s.io/api/core/v1"
@@ -499,6 +500,70 @@ func (s *S) TestCreateVolumeMultipleNamespacesFail(c *check.C) {
 	c.Assert(err, check.ErrorMatches, `multiple namespaces for volume not allowed: "tsuru-otherpool" and "tsuru-test-default"`)
 }
 
+func (s *S) TestCreateVolumeJobNamespace(c *check.C) {
+	config.Set("kubernetes:use-pool-namespaces", true)
+	defer config.Unset("kubernetes:use-pool-namespaces")
+	config.Set("volume-plans:p1:kubernetes:plugin", "nfs")
+	defer config.Unset("volume-plans")
+	waitCron := s.mock.CronJobReactions(c)
+	defer waitCron()
+	j := jobTypes.Job{
+		Name:      "myjob",
+		TeamOwner: s.team.Name,
+		Pool:      "test-default",
+		Spec: jobTypes.JobSpec{
+			Schedule: "* * * * *",
+			Container: jobTypes.ContainerInfo{
+				OriginalImageSrc: "ubuntu:latest",
+				Command:          []string{"echo", "hello world"},
+			},
+		},
+	}
+	s.mockService.JobService.OnGetByName = func(name string) (*jobTypes.Job, error) {
+		c.Assert(name, check.Equals, j.Name)
+		return &j, nil
+	}
+	defer func() { s.mockService.JobService.OnGetByName = nil }()
+	v := volumeTypes.Volume{
+		Name: "v1",
+		Opts: map[string]string{
+			"path":         "/exports",
+			"server":       "192.168.1.1",
+			"capacity":     "20Gi",
+			"access-modes": string(apiv1.ReadWriteMany),
+		},
+		Plan:      volumeTypes.VolumePlan{Name: "p1"},
+		Pool:      "test-default",
+		TeamOwner: "admin",
+	}
+	err := servicemanager.Volume.Create(context.TODO(), &v)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Volume.BindJob(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &v,
+		JobName:    j.Name,
+		MountPoint: "/mnt",
+		ReadOnly:   false,
+	})
+	c.Assert(err, check.IsNil)
+	err = s.p.EnsureJob(context.TODO(), &j)
+	waitCron()
+	c.Assert(err, check.IsNil)
+	ns := s.clusterClient.PoolNamespace(j.Pool)
+	pvc, err := s.client.CoreV1().PersistentVolumeClaims(ns).Get(context.TODO(), volumeClaimName(v.Name), metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(pvc.Namespace, check.Equals, "tsuru-test-default")
+	cron, err := s.client.BatchV1().CronJobs(ns).Get(context.TODO(), j.Name, metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(cron.Spec.JobTemplate.Spec.Template.Spec.Volumes, check.DeepEquals, []apiv1.Volume{{
+		Name: volumeName(v.Name),
+		VolumeSource: apiv1.VolumeSource{
+			PersistentVolumeClaim: &apiv1.PersistentVolumeClaimVolumeSource{
+				ClaimName: volumeClaimName(v.Name),
+			},
+		},
+	}})
+}
+
 func (s *S) TestDeleteVolume(c *check.C) {
 	config.Set("volume-plans:p1:kubernetes:plugin", "nfs")
 	defer config.Unset("volume-plans")
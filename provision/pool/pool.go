@@ -627,6 +627,30 @@ func ValidatePoolService(ctx context.Context, pool string, services []string) er
 	return nil
 }
 
+// ValidateJobImages checks the images of the init containers and sidecars of
+// a job against the job-image constraint of the pool. Every image is allowed
+// when the pool has no such constraint.
+func ValidateJobImages(ctx context.Context, pool string, images []string) error {
+	if len(images) == 0 {
+		return nil
+	}
+	constraints, err := getConstraintsForPool(ctx, pool, ConstraintTypeJobImage)
+	if err != nil {
+		return err
+	}
+	constraint := constraints[ConstraintTypeJobImage]
+	if constraint == nil {
+		return nil
+	}
+	for _, image := range images {
+		if !constraint.check(image) {
+			msg := fmt.Sprintf("image %q is not allowed for jobs in pool %q.", image, pool)
+			return &tsuruErrors.ValidationError{Message: msg}
+		}
+	}
+	return nil
+}
+
 func contains(arr []string, c string) bool {
 	for _, item := range arr {
 		if item == c {
@@ -926,3 +926,16 @@ func (s *S) TestGetAffinity(c *check.C) {
 		t.assertion(t.testName, c, affinity, err)
 	}
 }
+
+func (s *S) TestValidateJobImages(c *check.C) {
+	err := ValidateJobImages(context.TODO(), "pool1", []string{"any/image:latest"})
+	c.Assert(err, check.IsNil)
+	err = SetPoolConstraint(context.TODO(), &PoolConstraint{PoolExpr: "pool*", Field: ConstraintTypeJobImage, Values: []string{"gcr.io/cloudsql-docker/*", "busybox*"}})
+	c.Assert(err, check.IsNil)
+	err = ValidateJobImages(context.TODO(), "pool1", []string{"gcr.io/cloudsql-docker/gce-proxy:1.33", "busybox:1.36"})
+	c.Assert(err, check.IsNil)
+	err = ValidateJobImages(context.TODO(), "pool1", []string{"busybox:1.36", "any/image:latest"})
+	c.Assert(err, check.DeepEquals, &tsuruErrors.ValidationError{Message: `image "any/image:latest" is not allowed for jobs in pool "pool1".`})
+	err = ValidateJobImages(context.TODO(), "other", []string{"any/image:latest"})
+	c.Assert(err, check.IsNil)
+}
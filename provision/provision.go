@@ -154,8 +154,15 @@ type JobProvisioner interface {
 	EnsureJob(context.Context, *jobTypes.Job) error
 
 	DestroyJob(context.Context, *jobTypes.Job) error
-	TriggerCron(ctx context.Context, name, pool string) error
+
+	// TriggerCron spawns a new execution of the cronjob, applying the given
+	// per-run parameters only to the spawned job.
+	TriggerCron(ctx context.Context, name, pool string, opts jobTypes.TriggerOptions) error
 	KillJobUnit(ctx context.Context, job *jobTypes.Job, unitName string, force bool) error
+
+	// RunningJobUnits returns the number of units of the jobs owned by the
+	// team that are running in the pool
+	RunningJobUnits(ctx context.Context, team, pool string) (int, error)
 }
 
 type ExecOptions struct {
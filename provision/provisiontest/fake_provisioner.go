@@ -222,6 +222,16 @@ func (p *FakeProvisioner) JobExecutions(jobName string) int {
 	return 0
 }
 
+// LastJobTrigger returns the parameters of the last execution of a job
+func (p *FakeProvisioner) LastJobTrigger(jobName string) jobTypes.TriggerOptions {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+	if j, ok := p.jobs[jobName]; ok {
+		return j.lastTrigger
+	}
+	return jobTypes.TriggerOptions{}
+}
+
 func (p *FakeProvisioner) GetUnits(app *appTypes.App) []provTypes.Unit {
 	p.mut.RLock()
 	pApp := p.apps[app.Name]
@@ -793,9 +803,10 @@ type provisionedApp struct {
 }
 
 type provisionedJob struct {
-	units      []provTypes.Unit
-	job        *jobTypes.Job
-	executions int
+	units       []provTypes.Unit
+	job         *jobTypes.Job
+	executions  int
+	lastTrigger jobTypes.TriggerOptions
 }
 
 type AutoScaleProvisioner struct {
@@ -879,7 +890,7 @@ func (p *JobProvisioner) DestroyJob(ctx context.Context, job *jobTypes.Job) erro
 	return nil
 }
 
-func (p *JobProvisioner) TriggerCron(ctx context.Context, name, pool string) error {
+func (p *JobProvisioner) TriggerCron(ctx context.Context, name, pool string, opts jobTypes.TriggerOptions) error {
 	p.mut.Lock()
 	defer p.mut.Unlock()
 	j, ok := p.jobs[name]
@@ -887,9 +898,23 @@ func (p *JobProvisioner) TriggerCron(ctx context.Context, name, pool string) err
 		return errNotProvisioned
 	}
 	j.executions++
+	j.lastTrigger = opts
 	return nil
 }
 
+func (p *JobProvisioner) RunningJobUnits(ctx context.Context, team, pool string) (int, error) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	var count int
+	for _, j := range p.jobs {
+		if j.job.TeamOwner != team || j.job.Pool != pool {
+			continue
+		}
+		count += len(j.units)
+	}
+	return count, nil
+}
+
 func (p *JobProvisioner) NewJobWithUnits(ctx context.Context, job *jobTypes.Job) (string, error) {
 	p.mut.Lock()
 	defer p.mut.Unlock()
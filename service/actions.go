@@ -146,11 +146,12 @@ var updateServiceInstance = action.Action{
 			mongoBSON.M{"name": instance.Name, "service_name": instance.ServiceName},
 			mongoBSON.M{
 				"$set": mongoBSON.M{
-					"description": updateData.Description,
-					"tags":        updateData.Tags,
-					"teamowner":   updateData.TeamOwner,
-					"plan_name":   updateData.PlanName,
-					"parameters":  updateData.Parameters,
+					"description":  updateData.Description,
+					"tags":         updateData.Tags,
+					"teamowner":    updateData.TeamOwner,
+					"plan_name":    updateData.PlanName,
+					"parameters":   updateData.Parameters,
+					"shared_pools": updateData.SharedPools,
 				},
 				"$addToSet": mongoBSON.M{
 					"teams": updateData.TeamOwner,
@@ -176,11 +177,12 @@ var updateServiceInstance = action.Action{
 			mongoBSON.M{"name": instance.Name, "service_name": instance.ServiceName},
 			mongoBSON.M{
 				"$set": mongoBSON.M{
-					"description": instance.Description,
-					"tags":        instance.Tags,
-					"teamowner":   instance.TeamOwner,
-					"teams":       instance.Teams,
-					"plan_name":   instance.PlanName,
+					"description":  instance.Description,
+					"tags":         instance.Tags,
+					"teamowner":    instance.TeamOwner,
+					"teams":        instance.Teams,
+					"plan_name":    instance.PlanName,
+					"shared_pools": instance.SharedPools,
 				},
 			},
 		)
@@ -405,22 +407,7 @@ var setBoundEnvsAction = &action.Action{
 		if args == nil {
 			return nil, errors.New("invalid arguments for pipeline, expected *bindAppPipelineArgs.")
 		}
-		envMap := ctx.Previous.(map[string]string)
-		envs := make([]bindTypes.ServiceEnvVar, 0, len(envMap))
-		for k, v := range envMap {
-			envs = append(envs, bindTypes.ServiceEnvVar{
-				ServiceName:  args.serviceInstance.ServiceName,
-				InstanceName: args.serviceInstance.Name,
-				EnvVar: bindTypes.EnvVar{
-					Public: false,
-					Name:   k,
-					Value:  v,
-				},
-			})
-		}
-		sort.Slice(envs, func(i, j int) bool {
-			return envs[i].Name < envs[j].Name
-		})
+		envs := serviceEnvVars(args.serviceInstance, ctx.Previous.(map[string]string))
 		addArgs := bindTypes.AddInstanceArgs{
 			Envs:          envs,
 			ShouldRestart: args.shouldRestart,
@@ -449,23 +436,7 @@ var setJobBoundEnvsAction = &action.Action{
 		if args == nil {
 			return nil, errors.New("invalid arguments for pipeline, expected *bindJobPipelineArgs.")
 		}
-		envMap := ctx.Previous.(map[string]string)
-		envs := make([]bindTypes.ServiceEnvVar, 0, len(envMap))
-		for k, v := range envMap {
-			envs = append(envs, bindTypes.ServiceEnvVar{
-				ServiceName:  args.serviceInstance.ServiceName,
-				InstanceName: args.serviceInstance.Name,
-				EnvVar: bindTypes.EnvVar{
-					Public: false,
-					Name:   k,
-					Value:  v,
-				},
-			})
-		}
-		sort.Slice(envs, func(i, j int) bool {
-			return envs[i].Name < envs[j].Name
-		})
-
+		envs := serviceEnvVars(args.serviceInstance, ctx.Previous.(map[string]string))
 		addArgs := jobTypes.AddInstanceArgs{
 			Envs:   envs,
 			Writer: args.writer,
@@ -676,3 +647,22 @@ var removeJobBoundEnvs = action.Action{
 	},
 	MinParams: 1,
 }
+
+func serviceEnvVars(si *ServiceInstance, envMap map[string]string) []bindTypes.ServiceEnvVar {
+	envs := make([]bindTypes.ServiceEnvVar, 0, len(envMap))
+	for k, v := range envMap {
+		envs = append(envs, bindTypes.ServiceEnvVar{
+			ServiceName:  si.ServiceName,
+			InstanceName: si.Name,
+			EnvVar: bindTypes.EnvVar{
+				Public: false,
+				Name:   k,
+				Value:  v,
+			},
+		})
+	}
+	sort.Slice(envs, func(i, j int) bool {
+		return envs[i].Name < envs[j].Name
+	})
+	return envs
+}
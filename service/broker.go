@@ -5,10 +5,14 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"strconv"
+	"strings"
 
 	uuid "github.com/nu7hatch/gouuid"
 	"github.com/pkg/errors"
@@ -16,6 +20,7 @@ import (
 	"github.com/tsuru/tsuru/db/storagev2"
 	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/log"
+	tsuruNet "github.com/tsuru/tsuru/net"
 	"github.com/tsuru/tsuru/servicemanager"
 	appTypes "github.com/tsuru/tsuru/types/app"
 	jobTypes "github.com/tsuru/tsuru/types/job"
@@ -25,7 +30,12 @@ import (
 
 var ErrInvalidBrokerData = errors.New("Invalid broker data")
 
-const serviceNameBrokerSep = "::"
+const (
+	serviceNameBrokerSep = "::"
+
+	brokerOperationProvision = "provision"
+	brokerOperationUpdate    = "update"
+)
 
 // ClientFactory provides a way to customize the Open Service
 // Broker API client. Should be used in tests to create a fake client.
@@ -171,6 +181,7 @@ func (b *brokerClient) Create(ctx context.Context, instance *ServiceInstance, ev
 	if resp != nil && resp.OperationKey != nil {
 		instance.BrokerData.LastOperationKey = string(*resp.OperationKey)
 	}
+	instance.BrokerData.setOperation(brokerOperationProvision, resp != nil && resp.Async)
 	return nil
 }
 
@@ -220,6 +231,7 @@ func (b *brokerClient) Update(ctx context.Context, instance *ServiceInstance, ev
 	if resp != nil && resp.OperationKey != nil {
 		instance.BrokerData.LastOperationKey = string(*resp.OperationKey)
 	}
+	instance.BrokerData.setOperation(brokerOperationUpdate, resp != nil && resp.Async)
 	return updateBrokerData(ctx, instance)
 }
 
@@ -301,15 +313,11 @@ func (b *brokerClient) BindApp(ctx context.Context, instance *ServiceInstance, a
 		bind.OperationKey = string(*resp.OperationKey)
 		instance.BrokerData.LastOperationKey = string(*resp.OperationKey)
 	}
-	envs := make(map[string]string)
-	for k, v := range resp.Credentials {
-		switch s := v.(type) {
-		case string:
-			envs[k] = s
-		case int:
-			envs[k] = strconv.Itoa(s)
-		}
+	if resp.Async {
+		bind.State = string(osb.StateInProgress)
+		instance.BrokerData.Pending = true
 	}
+	envs := credentialsToEnvs(resp.Credentials)
 	if instance.BrokerData.Binds == nil {
 		instance.BrokerData.Binds = make(map[string]BrokerInstanceBind)
 	}
@@ -349,27 +357,258 @@ func (b *brokerClient) UnbindApp(ctx context.Context, instance *ServiceInstance,
 	return err
 }
 
-func (b *brokerClient) Status(ctx context.Context, instance *ServiceInstance, requestID string) (string, error) {
+// RotateCredentials creates a new binding for every app and job bound to
+// the instance. The previous bindings are kept until RevokeCredentials.
+func (b *brokerClient) RotateCredentials(ctx context.Context, instance *ServiceInstance, evt *event.Event, requestID string) (*CredentialRotation, error) {
 	if instance.BrokerData == nil {
-		return "", ErrInvalidBrokerData
+		return nil, ErrInvalidBrokerData
 	}
-	origID, err := json.Marshal(map[string]interface{}{
-		"team": instance.TeamOwner,
-	})
+	rotation := &CredentialRotation{
+		AppEnvs: map[string]map[string]string{},
+		JobEnvs: map[string]map[string]string{},
+	}
+	appBinds := map[string]BrokerInstanceBind{}
+	jobBinds := map[string]BrokerInstanceBind{}
+	var err error
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, binds := range []map[string]BrokerInstanceBind{appBinds, jobBinds} {
+			for _, bind := range binds {
+				if unbindErr := b.unbind(instance, bind, evt); unbindErr != nil {
+					log.Errorf("[broker rotate credentials] unable to remove binding %s: %v", bind.UUID, unbindErr)
+				}
+			}
+		}
+	}()
+	for appName, previous := range instance.BrokerData.Binds {
+		var a *appTypes.App
+		a, err = servicemanager.App.GetByName(ctx, appName)
+		if err != nil {
+			return nil, err
+		}
+		var appGUID string
+		appGUID, err = servicemanager.App.EnsureUUID(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		var bind BrokerInstanceBind
+		bind, rotation.AppEnvs[appName], err = b.rebind(instance, previous, &appGUID, map[string]interface{}{}, evt, requestID)
+		if err != nil {
+			return nil, err
+		}
+		appBinds[appName] = bind
+	}
+	for jobName, previous := range instance.BrokerData.JobBinds {
+		var bind BrokerInstanceBind
+		bind, rotation.JobEnvs[jobName], err = b.rebind(instance, previous, nil, map[string]interface{}{"job_name": jobName}, evt, requestID)
+		if err != nil {
+			return nil, err
+		}
+		jobBinds[jobName] = bind
+	}
+	for appName, bind := range appBinds {
+		rotation.previousBinds = append(rotation.previousBinds, instance.BrokerData.Binds[appName])
+		instance.BrokerData.Binds[appName] = bind
+	}
+	for jobName, bind := range jobBinds {
+		rotation.previousBinds = append(rotation.previousBinds, instance.BrokerData.JobBinds[jobName])
+		instance.BrokerData.JobBinds[jobName] = bind
+	}
+	err = updateBrokerData(ctx, instance)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	opKey := osb.OperationKey(instance.BrokerData.LastOperationKey)
-	op, err := b.client.PollLastOperation(&osb.LastOperationRequest{
-		ServiceID:  &instance.BrokerData.ServiceID,
-		PlanID:     &instance.BrokerData.PlanID,
-		InstanceID: instance.BrokerData.UUID,
-		OriginatingIdentity: &osb.OriginatingIdentity{
-			Platform: "tsuru",
-			Value:    string(origID),
+	return rotation, nil
+}
+
+// RevokeCredentials removes the bindings replaced by RotateCredentials.
+func (b *brokerClient) RevokeCredentials(ctx context.Context, instance *ServiceInstance, rotation *CredentialRotation, evt *event.Event, requestID string) error {
+	if instance.BrokerData == nil {
+		return ErrInvalidBrokerData
+	}
+	var errs []string
+	for _, bind := range rotation.previousBinds {
+		if err := b.unbind(instance, bind, evt); err != nil {
+			errs = append(errs, fmt.Sprintf("binding %s: %v", bind.UUID, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Errorf("unable to remove previous bindings: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// rebind creates a binding replacing previous, with the same parameters.
+// Bindings are created synchronously, as the credentials are needed right
+// away.
+func (b *brokerClient) rebind(instance *ServiceInstance, previous BrokerInstanceBind, appGUID *string, reqContext map[string]interface{}, evt *event.Event, requestID string) (BrokerInstanceBind, map[string]string, error) {
+	id, err := idForEvent(evt)
+	if err != nil {
+		return BrokerInstanceBind{}, nil, err
+	}
+	bindID, err := uuid.NewV4()
+	if err != nil {
+		return BrokerInstanceBind{}, nil, err
+	}
+	bind := BrokerInstanceBind{
+		UUID:       bindID.String(),
+		Parameters: previous.Parameters,
+	}
+	req := osb.BindRequest{
+		ServiceID:           instance.BrokerData.ServiceID,
+		InstanceID:          instance.BrokerData.UUID,
+		PlanID:              instance.BrokerData.PlanID,
+		BindingID:           bind.UUID,
+		AppGUID:             appGUID,
+		Parameters:          previous.Parameters,
+		OriginatingIdentity: id,
+		Context: map[string]interface{}{
+			"request_id": requestID,
+			"event_id":   evt.UniqueID.Hex(),
 		},
-		OperationKey: &opKey,
+	}
+	if appGUID != nil {
+		req.BindResource = &osb.BindResource{AppGUID: appGUID}
+	}
+	for k, v := range reqContext {
+		req.Context[k] = v
+	}
+	for k, v := range b.broker.Config.Context {
+		req.Context[k] = v
+	}
+	resp, err := b.client.Bind(&req)
+	if err != nil {
+		return BrokerInstanceBind{}, nil, err
+	}
+	return bind, credentialsToEnvs(resp.Credentials), nil
+}
+
+func (b *brokerClient) unbind(instance *ServiceInstance, bind BrokerInstanceBind, evt *event.Event) error {
+	id, err := idForEvent(evt)
+	if err != nil {
+		return err
+	}
+	_, err = b.client.Unbind(&osb.UnbindRequest{
+		InstanceID:          instance.BrokerData.UUID,
+		BindingID:           bind.UUID,
+		ServiceID:           instance.BrokerData.ServiceID,
+		PlanID:              instance.BrokerData.PlanID,
+		OriginatingIdentity: id,
 	})
+	if err != nil && !osb.IsGoneError(err) {
+		return err
+	}
+	return nil
+}
+
+// Actions returns the actions available for the instance through the
+// tsuru extension of the Open Service Broker API:
+// GET /v2/service_instances/<instance_id>/actions
+func (b *brokerClient) Actions(ctx context.Context, instance *ServiceInstance, requestID string) ([]InstanceAction, error) {
+	if instance.BrokerData == nil {
+		return nil, ErrInvalidBrokerData
+	}
+	resp, err := b.extensionRequest(ctx, http.MethodGet, "/v2/service_instances/"+instance.BrokerData.UUID+"/actions", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, extensionError(resp)
+	}
+	var result struct {
+		Actions []InstanceAction `json:"actions"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Actions, nil
+}
+
+// ExecuteAction runs an action through the tsuru extension of the Open
+// Service Broker API, copying the response body to w:
+// POST /v2/service_instances/<instance_id>/actions/<action>
+func (b *brokerClient) ExecuteAction(ctx context.Context, instance *ServiceInstance, action string, params map[string]interface{}, w io.Writer, evt *event.Event, requestID string) error {
+	if instance.BrokerData == nil {
+		return ErrInvalidBrokerData
+	}
+	reqContext := map[string]interface{}{
+		"request_id": requestID,
+		"event_id":   evt.UniqueID.Hex(),
+	}
+	for k, v := range b.broker.Config.Context {
+		reqContext[k] = v
+	}
+	body := map[string]interface{}{
+		"service_id": instance.BrokerData.ServiceID,
+		"plan_id":    instance.BrokerData.PlanID,
+		"parameters": params,
+		"context":    reqContext,
+	}
+	resp, err := b.extensionRequest(ctx, http.MethodPost, "/v2/service_instances/"+instance.BrokerData.UUID+"/actions/"+action, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrInstanceActionNotFound
+	}
+	if resp.StatusCode > 299 {
+		return extensionError(resp)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+func (b *brokerClient) extensionRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(b.broker.URL, "/")+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Broker-API-Version", osb.LatestAPIVersion().HeaderValue())
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if auth := b.broker.Config.AuthConfig; auth != nil {
+		if auth.BasicAuthConfig != nil {
+			req.SetBasicAuth(auth.BasicAuthConfig.Username, auth.BasicAuthConfig.Password)
+		}
+		if auth.BearerConfig != nil {
+			req.Header.Set("Authorization", "Bearer "+auth.BearerConfig.Token)
+		}
+	}
+	client := tsuruNet.Dial15Full300Client
+	if b.broker.Config.Insecure {
+		client = tsuruNet.Dial15Full60ClientNoKeepAliveInsecure
+	}
+	return client.Do(req)
+}
+
+func extensionError(resp *http.Response) error {
+	data, _ := io.ReadAll(resp.Body)
+	return errors.Errorf("invalid response from broker: %s (code: %d)", strings.TrimSpace(string(data)), resp.StatusCode)
+}
+
+func (b *brokerClient) Status(ctx context.Context, instance *ServiceInstance, requestID string) (string, error) {
+	if instance.BrokerData == nil {
+		return "", ErrInvalidBrokerData
+	}
+	op, err := b.lastOperation(instance)
 	if err != nil {
 		return "", err
 	}
@@ -380,6 +619,51 @@ func (b *brokerClient) Status(ctx context.Context, instance *ServiceInstance, re
 	return output, nil
 }
 
+func (b *brokerClient) lastOperation(instance *ServiceInstance) (*osb.LastOperationResponse, error) {
+	id, err := idForTeam(instance.TeamOwner)
+	if err != nil {
+		return nil, err
+	}
+	opKey := osb.OperationKey(instance.BrokerData.LastOperationKey)
+	return b.client.PollLastOperation(&osb.LastOperationRequest{
+		ServiceID:           &instance.BrokerData.ServiceID,
+		PlanID:              &instance.BrokerData.PlanID,
+		InstanceID:          instance.BrokerData.UUID,
+		OriginatingIdentity: id,
+		OperationKey:        &opKey,
+	})
+}
+
+func (b *brokerClient) bindingLastOperation(instance *ServiceInstance, bind BrokerInstanceBind) (*osb.LastOperationResponse, error) {
+	id, err := idForTeam(instance.TeamOwner)
+	if err != nil {
+		return nil, err
+	}
+	req := &osb.BindingLastOperationRequest{
+		ServiceID:           &instance.BrokerData.ServiceID,
+		PlanID:              &instance.BrokerData.PlanID,
+		InstanceID:          instance.BrokerData.UUID,
+		BindingID:           bind.UUID,
+		OriginatingIdentity: id,
+	}
+	if bind.OperationKey != "" {
+		opKey := osb.OperationKey(bind.OperationKey)
+		req.OperationKey = &opKey
+	}
+	return b.client.PollBindingLastOperation(req)
+}
+
+func (b *brokerClient) bindingCredentials(instance *ServiceInstance, bind BrokerInstanceBind) (map[string]string, error) {
+	resp, err := b.client.GetBinding(&osb.GetBindingRequest{
+		InstanceID: instance.BrokerData.UUID,
+		BindingID:  bind.UUID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return credentialsToEnvs(resp.Credentials), nil
+}
+
 func (b *brokerClient) Info(ctx context.Context, instance *ServiceInstance, requestID string) ([]map[string]string, error) {
 	var params []map[string]string
 	for k, v := range instance.Parameters {
@@ -407,19 +691,114 @@ func (b *brokerClient) Plans(ctx context.Context, _, _ string) ([]Plan, error) {
 	return plans, nil
 }
 
+// PlanSchemas returns the schemas of the plan from the broker catalog,
+// which is already cached.
+func (b *brokerClient) PlanSchemas(ctx context.Context, _, plan, _ string) (*osb.Schemas, error) {
+	_, s, err := b.getService(ctx, b.service, b.broker.Name)
+	if err != nil {
+		return nil, err
+	}
+	p, err := getPlan(s, plan)
+	if err != nil {
+		return nil, err
+	}
+	return p.Schemas, nil
+}
+
 // Proxy is not implemented for OSB API implementations
 func (b *brokerClient) Proxy(ctx context.Context, opts *ProxyOpts) error {
 	return fmt.Errorf("service proxy is not available for broker services")
 }
 
-// UnbindJob is a no-op for OSB API implementations
-func (b *brokerClient) UnbindJob(ctx context.Context, instance *ServiceInstance, job *jobTypes.Job, evt *event.Event, requestID string) error {
-	return nil
+func (b *brokerClient) BindJob(ctx context.Context, instance *ServiceInstance, job *jobTypes.Job, evt *event.Event, requestID string) (map[string]string, error) {
+	if instance.BrokerData == nil {
+		return nil, ErrInvalidBrokerData
+	}
+	id, err := idForEvent(evt)
+	if err != nil {
+		return nil, err
+	}
+	bindID, err := jobBindingID(instance, job)
+	if err != nil {
+		return nil, err
+	}
+	bind := BrokerInstanceBind{
+		UUID: bindID,
+	}
+	req := osb.BindRequest{
+		ServiceID:           instance.BrokerData.ServiceID,
+		InstanceID:          instance.BrokerData.UUID,
+		PlanID:              instance.BrokerData.PlanID,
+		BindingID:           bind.UUID,
+		OriginatingIdentity: id,
+		Context: map[string]interface{}{
+			"request_id": requestID,
+			"event_id":   evt.UniqueID.Hex(),
+			"job_name":   job.Name,
+		},
+		AcceptsIncomplete: true,
+	}
+	for k, v := range b.broker.Config.Context {
+		req.Context[k] = v
+	}
+	resp, err := b.client.Bind(&req)
+	if osb.IsAsyncBindingOperationsNotAllowedError(err) {
+		req.AcceptsIncomplete = false
+		resp, err = b.client.Bind(&req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.OperationKey != nil {
+		bind.OperationKey = string(*resp.OperationKey)
+		instance.BrokerData.LastOperationKey = string(*resp.OperationKey)
+	}
+	if resp.Async {
+		bind.State = string(osb.StateInProgress)
+		instance.BrokerData.Pending = true
+	}
+	if instance.BrokerData.JobBinds == nil {
+		instance.BrokerData.JobBinds = make(map[string]BrokerInstanceBind)
+	}
+	instance.BrokerData.JobBinds[job.Name] = bind
+	return credentialsToEnvs(resp.Credentials), updateBrokerData(ctx, instance)
 }
 
-// BindJob is a no-op for OSB API implementations
-func (b *brokerClient) BindJob(ctx context.Context, instance *ServiceInstance, job *jobTypes.Job, evt *event.Event, requestID string) (map[string]string, error) {
-	return nil, nil
+// UnbindJob removes the binding created by BindJob. Jobs bound before
+// bindings were created for them have nothing to be removed in the broker.
+func (b *brokerClient) UnbindJob(ctx context.Context, instance *ServiceInstance, job *jobTypes.Job, evt *event.Event, requestID string) error {
+	if instance.BrokerData == nil {
+		return ErrInvalidBrokerData
+	}
+	bind, ok := instance.BrokerData.JobBinds[job.Name]
+	if !ok {
+		return nil
+	}
+	id, err := idForEvent(evt)
+	if err != nil {
+		return err
+	}
+	req := osb.UnbindRequest{
+		InstanceID:          instance.BrokerData.UUID,
+		BindingID:           bind.UUID,
+		ServiceID:           instance.BrokerData.ServiceID,
+		PlanID:              instance.BrokerData.PlanID,
+		OriginatingIdentity: id,
+		AcceptsIncomplete:   true,
+	}
+	resp, err := b.client.Unbind(&req)
+	if osb.IsAsyncBindingOperationsNotAllowedError(err) {
+		req.AcceptsIncomplete = false
+		resp, err = b.client.Unbind(&req)
+	}
+	if err != nil && !osb.IsGoneError(err) {
+		return err
+	}
+	delete(instance.BrokerData.JobBinds, job.Name)
+	if resp != nil && resp.OperationKey != nil {
+		instance.BrokerData.LastOperationKey = string(*resp.OperationKey)
+	}
+	return updateBrokerData(ctx, instance)
 }
 
 func (b *brokerClient) getCatalog(ctx context.Context, name string) (*osb.CatalogResponse, error) {
@@ -470,6 +849,43 @@ func newService(broker serviceTypes.Broker, osbservice osb.Service) Service {
 	}
 }
 
+// jobBindingID derives the binding ID from the instance and job names, so
+// binding the same job again always reaches the same binding in the broker.
+func jobBindingID(instance *ServiceInstance, job *jobTypes.Job) (string, error) {
+	name := fmt.Sprintf("tsuru:job:%s:%s", instance.BrokerData.UUID, job.Name)
+	bindID, err := uuid.NewV5(uuid.NamespaceURL, []byte(name))
+	if err != nil {
+		return "", err
+	}
+	return bindID.String(), nil
+}
+
+func credentialsToEnvs(credentials map[string]interface{}) map[string]string {
+	envs := make(map[string]string)
+	for k, v := range credentials {
+		switch s := v.(type) {
+		case string:
+			envs[k] = s
+		case int:
+			envs[k] = strconv.Itoa(s)
+		}
+	}
+	return envs
+}
+
+func idForTeam(team string) (*osb.OriginatingIdentity, error) {
+	identity, err := json.Marshal(map[string]interface{}{
+		"team": team,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &osb.OriginatingIdentity{
+		Platform: "tsuru",
+		Value:    string(identity),
+	}, nil
+}
+
 func idForEvent(evt *event.Event) (*osb.OriginatingIdentity, error) {
 	identity, err := json.Marshal(map[string]interface{}{
 		"user": evt.Owner.Name,
@@ -483,6 +899,33 @@ func idForEvent(evt *event.Event) (*osb.OriginatingIdentity, error) {
 	}, nil
 }
 
+// setOperation records the operation just requested to the broker, which
+// is still in progress when the broker answered asynchronously.
+func (d *BrokerInstanceData) setOperation(opType string, async bool) {
+	d.LastOperationType = opType
+	d.LastOperationDescription = ""
+	if async {
+		d.LastOperationState = string(osb.StateInProgress)
+	} else {
+		d.LastOperationState = string(osb.StateSucceeded)
+	}
+	d.Pending = d.hasOperationInProgress()
+}
+
+func (d *BrokerInstanceData) hasOperationInProgress() bool {
+	if d.LastOperationState == string(osb.StateInProgress) {
+		return true
+	}
+	for _, binds := range []map[string]BrokerInstanceBind{d.Binds, d.JobBinds} {
+		for _, bind := range binds {
+			if bind.State == string(osb.StateInProgress) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func updateBrokerData(ctx context.Context, instance *ServiceInstance) error {
 	collection, err := storagev2.ServiceInstancesCollection()
 	if err != nil {
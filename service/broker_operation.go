@@ -0,0 +1,277 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	osb "github.com/pmorie/go-open-service-broker-client/v2"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	bindTypes "github.com/tsuru/tsuru/types/bind"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	defaultBrokerPollInterval = 30 * time.Second
+	brokerOperationKind       = "broker operation"
+	brokerOperationBind       = "bind"
+)
+
+// InitializeBrokerOperationPoller starts the worker driving asynchronous
+// Open Service Broker operations to a final state.
+func InitializeBrokerOperationPoller() error {
+	interval := defaultBrokerPollInterval
+	if seconds, err := config.GetInt("service:broker-poll-interval"); err == nil && seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	}
+	p := &brokerOperationPoller{once: &sync.Once{}, interval: interval}
+	p.start()
+	shutdown.Register(p)
+	return nil
+}
+
+type brokerOperationPoller struct {
+	once     *sync.Once
+	stopCh   chan struct{}
+	interval time.Duration
+}
+
+func (p *brokerOperationPoller) start() {
+	p.once.Do(func() {
+		p.stopCh = make(chan struct{})
+		go p.spin()
+	})
+}
+
+func (p *brokerOperationPoller) Shutdown(ctx context.Context) error {
+	if p.stopCh == nil {
+		return nil
+	}
+	p.stopCh <- struct{}{}
+	p.stopCh = nil
+	p.once = &sync.Once{}
+	return nil
+}
+
+func (p *brokerOperationPoller) spin() {
+	for {
+		if err := pollBrokerOperations(context.Background()); err != nil {
+			log.Errorf("[broker operation poller] %v", err)
+		}
+
+		select {
+		case <-p.stopCh:
+			return
+		case <-time.After(p.interval):
+		}
+	}
+}
+
+func pollBrokerOperations(ctx context.Context) error {
+	collection, err := storagev2.ServiceInstancesCollection()
+	if err != nil {
+		return err
+	}
+	cursor, err := collection.Find(ctx, mongoBSON.M{"broker_data.pending": true})
+	if err != nil {
+		return errors.Wrap(err, "unable to find instances with pending operations")
+	}
+	var instances []ServiceInstance
+	err = cursor.All(ctx, &instances)
+	if err != nil {
+		return errors.Wrap(err, "unable to find instances with pending operations")
+	}
+	for i := range instances {
+		si := &instances[i]
+		if err = pollInstanceOperations(ctx, si); err != nil {
+			log.Errorf("[broker operation poller] unable to poll operations of instance %s/%s: %v", si.ServiceName, si.Name, err)
+		}
+	}
+	return nil
+}
+
+func pollInstanceOperations(ctx context.Context, si *ServiceInstance) error {
+	s, err := Get(ctx, si.ServiceName)
+	if err != nil {
+		return err
+	}
+	endpoint, err := s.getClientForPool(ctx, si.Pool)
+	if err != nil {
+		return err
+	}
+	client, ok := endpoint.(*brokerClient)
+	if !ok || si.BrokerData == nil {
+		return nil
+	}
+	if si.BrokerData.LastOperationState == string(osb.StateInProgress) {
+		if err = pollInstanceOperation(ctx, client, si); err != nil {
+			return err
+		}
+	}
+	for appName, bind := range si.BrokerData.Binds {
+		if bind.State != string(osb.StateInProgress) {
+			continue
+		}
+		err = pollBindingOperation(ctx, client, si, bind, eventTypes.Target{Type: eventTypes.TargetTypeApp, Value: appName})
+		if err != nil {
+			return err
+		}
+	}
+	for jobName, bind := range si.BrokerData.JobBinds {
+		if bind.State != string(osb.StateInProgress) {
+			continue
+		}
+		err = pollBindingOperation(ctx, client, si, bind, eventTypes.Target{Type: eventTypes.TargetTypeJob, Value: jobName})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func pollInstanceOperation(ctx context.Context, client *brokerClient, si *ServiceInstance) error {
+	resp, err := client.lastOperation(si)
+	if err != nil {
+		return err
+	}
+	if resp.State == osb.StateInProgress {
+		return nil
+	}
+	filter := mongoBSON.M{
+		"broker_data.lastoperationkey":   si.BrokerData.LastOperationKey,
+		"broker_data.lastoperationstate": si.BrokerData.LastOperationState,
+	}
+	si.BrokerData.LastOperationState = string(resp.State)
+	si.BrokerData.LastOperationDescription = ""
+	if resp.Description != nil {
+		si.BrokerData.LastOperationDescription = *resp.Description
+	}
+	updated, err := si.updateBrokerOperation(ctx, filter)
+	if err != nil || !updated {
+		return err
+	}
+	return recordBrokerOperation(ctx, si, nil, si.BrokerData.LastOperationType, si.BrokerData.LastOperationState, si.BrokerData.LastOperationDescription, nil)
+}
+
+func pollBindingOperation(ctx context.Context, client *brokerClient, si *ServiceInstance, bind BrokerInstanceBind, target eventTypes.Target) error {
+	resp, err := client.bindingLastOperation(si, bind)
+	if err != nil {
+		return err
+	}
+	if resp.State == osb.StateInProgress {
+		return nil
+	}
+	field := "binds"
+	binds := si.BrokerData.Binds
+	if target.Type == eventTypes.TargetTypeJob {
+		field = "jobbinds"
+		binds = si.BrokerData.JobBinds
+	}
+	filter := mongoBSON.M{
+		"broker_data." + field + "." + target.Value + ".state": bind.State,
+	}
+	bind.State = string(resp.State)
+	bind.Description = ""
+	if resp.Description != nil {
+		bind.Description = *resp.Description
+	}
+	binds[target.Value] = bind
+	updated, err := si.updateBrokerOperation(ctx, filter)
+	if err != nil || !updated {
+		return err
+	}
+	var envErr error
+	if resp.State == osb.StateSucceeded {
+		envErr = setBindingEnvs(ctx, client, si, bind, target)
+	}
+	return recordBrokerOperation(ctx, si, &target, brokerOperationBind, bind.State, bind.Description, envErr)
+}
+
+// setBindingEnvs injects the credentials of a binding completed
+// asynchronously, which are not available when the binding is requested.
+func setBindingEnvs(ctx context.Context, client *brokerClient, si *ServiceInstance, bind BrokerInstanceBind, target eventTypes.Target) error {
+	credentials, err := client.bindingCredentials(si, bind)
+	if err != nil {
+		return err
+	}
+	envs := serviceEnvVars(si, credentials)
+	if target.Type == eventTypes.TargetTypeJob {
+		var job *jobTypes.Job
+		job, err = servicemanager.Job.GetByName(ctx, target.Value)
+		if err != nil {
+			return err
+		}
+		err = servicemanager.Job.AddServiceEnv(ctx, job, jobTypes.AddInstanceArgs{Envs: envs})
+		if err != nil {
+			return err
+		}
+		return servicemanager.Job.UpdateJobProv(ctx, job)
+	}
+	a, err := servicemanager.App.GetByName(ctx, target.Value)
+	if err != nil {
+		return err
+	}
+	return servicemanager.App.AddInstance(ctx, a, bindTypes.AddInstanceArgs{
+		Envs:          envs,
+		ShouldRestart: true,
+	})
+}
+
+// updateBrokerOperation stores the broker data of the instance only when
+// filter still matches, so each state transition is handled once.
+func (si *ServiceInstance) updateBrokerOperation(ctx context.Context, filter mongoBSON.M) (bool, error) {
+	collection, err := storagev2.ServiceInstancesCollection()
+	if err != nil {
+		return false, err
+	}
+	si.BrokerData.Pending = si.BrokerData.hasOperationInProgress()
+	filter["name"] = si.Name
+	filter["service_name"] = si.ServiceName
+	result, err := collection.UpdateOne(ctx, filter, mongoBSON.M{"$set": mongoBSON.M{"broker_data": si.BrokerData}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func recordBrokerOperation(ctx context.Context, si *ServiceInstance, extraTarget *eventTypes.Target, opType, state, description string, opErr error) error {
+	opts := &event.Opts{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeServiceInstance, Value: si.ServiceName + "/" + si.Name},
+		InternalKind: brokerOperationKind,
+		CustomData: map[string]interface{}{
+			"operation":   opType,
+			"state":       state,
+			"description": description,
+		},
+		Allowed: event.Allowed(permission.PermServiceInstanceReadEvents, append(
+			permission.Contexts(permTypes.CtxTeam, si.Teams),
+			permission.Context(permTypes.CtxServiceInstance, si.ServiceName+"/"+si.Name),
+		)...),
+		DisableLock: true,
+	}
+	if extraTarget != nil {
+		opts.ExtraTargets = []eventTypes.ExtraTarget{{Target: *extraTarget}}
+	}
+	evt, err := event.NewInternal(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if opErr == nil && state == string(osb.StateFailed) {
+		opErr = errors.Errorf("%s operation failed: %s", opType, description)
+	}
+	return evt.Done(ctx, opErr)
+}
@@ -0,0 +1,231 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+
+	osb "github.com/pmorie/go-open-service-broker-client/v2"
+	osbfake "github.com/pmorie/go-open-service-broker-client/v2/fake"
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/event/eventtest"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	serviceTypes "github.com/tsuru/tsuru/types/service"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) setupFakeBroker(config osbfake.FakeClientConfiguration) {
+	config.CatalogReaction = &osbfake.CatalogReaction{Response: &osb.CatalogResponse{
+		Services: []osb.Service{
+			{ID: "s1", Name: "service", Plans: []osb.Plan{{ID: "p1", Name: "plan1"}}},
+		},
+	}}
+	ClientFactory = osbfake.NewFakeClientFunc(config)
+	s.mockService.ServiceBroker.OnFind = func(name string) (serviceTypes.Broker, error) {
+		return serviceTypes.Broker{Name: name}, nil
+	}
+}
+
+func createPendingInstance(c *check.C, data BrokerInstanceData) ServiceInstance {
+	instance := createTestInstance()
+	instance.ServiceName = "broker::service"
+	instance.Teams = []string{"teamOwner"}
+	data.UUID = instance.BrokerData.UUID
+	data.ServiceID = instance.BrokerData.ServiceID
+	data.PlanID = instance.BrokerData.PlanID
+	data.Pending = true
+	instance.BrokerData = &data
+	collection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = collection.InsertOne(context.TODO(), &instance)
+	c.Assert(err, check.IsNil)
+	return instance
+}
+
+func (s *S) TestBrokerClientCreateAsync(c *check.C) {
+	opKey := osb.OperationKey("provisioning")
+	config := osbfake.FakeClientConfiguration{
+		ProvisionReaction: &osbfake.ProvisionReaction{Response: &osb.ProvisionResponse{Async: true, OperationKey: &opKey}},
+	}
+	s.setupFakeBroker(config)
+	client, err := newClient(serviceTypes.Broker{Name: "broker"}, "service")
+	c.Assert(err, check.IsNil)
+	instance := createTestInstance()
+	err = client.Create(context.TODO(), &instance, createEvt(c), "request-id")
+	c.Assert(err, check.IsNil)
+	c.Assert(instance.BrokerData.LastOperationKey, check.Equals, "provisioning")
+	c.Assert(instance.BrokerData.LastOperationType, check.Equals, "provision")
+	c.Assert(instance.BrokerData.LastOperationState, check.Equals, "in progress")
+	c.Assert(instance.BrokerData.Pending, check.Equals, true)
+}
+
+func (s *S) TestBindAppWhileProvisioning(c *check.C) {
+	instance := createPendingInstance(c, BrokerInstanceData{
+		LastOperationType:  "provision",
+		LastOperationState: "in progress",
+	})
+	a := &appTypes.App{Name: "myapp"}
+	err := instance.BindApp(context.TODO(), a, nil, false, nil, createEvt(c), "")
+	c.Assert(err, check.Equals, ErrInstanceProvisionInProgress)
+	err = instance.BindJob(context.TODO(), &jobTypes.Job{Name: "myjob"}, nil, createEvt(c), "")
+	c.Assert(err, check.Equals, ErrInstanceProvisionInProgress)
+}
+
+func (s *S) TestBindAppAfterProvisionFailed(c *check.C) {
+	instance := createPendingInstance(c, BrokerInstanceData{
+		LastOperationType:        "provision",
+		LastOperationState:       "failed",
+		LastOperationDescription: "quota exceeded",
+	})
+	a := &appTypes.App{Name: "myapp"}
+	err := instance.BindApp(context.TODO(), a, nil, false, nil, createEvt(c), "")
+	c.Assert(err, check.ErrorMatches, "service instance provisioning failed: quota exceeded")
+}
+
+func (s *S) TestPollBrokerOperationsInstanceSucceeded(c *check.C) {
+	var requests []*osb.LastOperationRequest
+	config := osbfake.FakeClientConfiguration{
+		PollLastOperationReaction: osbfake.DynamicPollLastOperationReaction(func(req *osb.LastOperationRequest) (*osb.LastOperationResponse, error) {
+			requests = append(requests, req)
+			description := "ready to use"
+			return &osb.LastOperationResponse{State: osb.StateSucceeded, Description: &description}, nil
+		}),
+	}
+	s.setupFakeBroker(config)
+	instance := createPendingInstance(c, BrokerInstanceData{
+		LastOperationKey:   "provisioning",
+		LastOperationType:  "provision",
+		LastOperationState: "in progress",
+	})
+	err := pollBrokerOperations(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(requests, check.HasLen, 1)
+	c.Assert(string(*requests[0].OperationKey), check.Equals, "provisioning")
+	stored, err := GetServiceInstance(context.TODO(), instance.ServiceName, instance.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(stored.BrokerData.LastOperationState, check.Equals, "succeeded")
+	c.Assert(stored.BrokerData.LastOperationDescription, check.Equals, "ready to use")
+	c.Assert(stored.BrokerData.Pending, check.Equals, false)
+	info, err := stored.ToInfo(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(info.OperationType, check.Equals, "provision")
+	c.Assert(info.OperationState, check.Equals, "succeeded")
+	c.Assert(info.OperationDescription, check.Equals, "ready to use")
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeServiceInstance, Value: "broker::service/instance"},
+		Kind:   brokerOperationKind,
+	}, eventtest.HasEvent)
+	err = pollBrokerOperations(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(requests, check.HasLen, 1)
+}
+
+func (s *S) TestPollBrokerOperationsInstanceFailed(c *check.C) {
+	config := osbfake.FakeClientConfiguration{
+		PollLastOperationReaction: osbfake.DynamicPollLastOperationReaction(func(req *osb.LastOperationRequest) (*osb.LastOperationResponse, error) {
+			description := "no capacity"
+			return &osb.LastOperationResponse{State: osb.StateFailed, Description: &description}, nil
+		}),
+	}
+	s.setupFakeBroker(config)
+	instance := createPendingInstance(c, BrokerInstanceData{
+		LastOperationType:  "provision",
+		LastOperationState: "in progress",
+	})
+	err := pollBrokerOperations(context.TODO())
+	c.Assert(err, check.IsNil)
+	stored, err := GetServiceInstance(context.TODO(), instance.ServiceName, instance.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(stored.BrokerData.LastOperationState, check.Equals, "failed")
+	c.Assert(stored.BrokerData.Pending, check.Equals, false)
+	c.Assert(eventtest.EventDesc{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeServiceInstance, Value: "broker::service/instance"},
+		Kind:         brokerOperationKind,
+		ErrorMatches: "provision operation failed: no capacity",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestPollBrokerOperationsInstanceInProgress(c *check.C) {
+	config := osbfake.FakeClientConfiguration{
+		PollLastOperationReaction: &osbfake.PollLastOperationReaction{
+			Response: &osb.LastOperationResponse{State: osb.StateInProgress},
+		},
+	}
+	s.setupFakeBroker(config)
+	instance := createPendingInstance(c, BrokerInstanceData{
+		LastOperationType:  "provision",
+		LastOperationState: "in progress",
+	})
+	err := pollBrokerOperations(context.TODO())
+	c.Assert(err, check.IsNil)
+	stored, err := GetServiceInstance(context.TODO(), instance.ServiceName, instance.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(stored.BrokerData.LastOperationState, check.Equals, "in progress")
+	c.Assert(stored.BrokerData.Pending, check.Equals, true)
+}
+
+func (s *S) TestPollBrokerOperationsBindingSucceeded(c *check.C) {
+	config := osbfake.FakeClientConfiguration{
+		PollBindingLastOperationReaction: &osbfake.PollBindingLastOperationReaction{
+			Response: &osb.LastOperationResponse{State: osb.StateSucceeded},
+		},
+		GetBindingReaction: &osbfake.GetBindingReaction{
+			Response: &osb.GetBindingResponse{Credentials: map[string]interface{}{"DATABASE_URL": "db://host"}},
+		},
+	}
+	s.setupFakeBroker(config)
+	a := &appTypes.App{Name: "myapp"}
+	s.mockService.App.Apps = []*appTypes.App{a}
+	var jobEnvs jobTypes.AddInstanceArgs
+	var reloadedJob string
+	job := &jobTypes.Job{Name: "myjob"}
+	s.mockService.JobService.OnGetByName = func(name string) (*jobTypes.Job, error) {
+		c.Assert(name, check.Equals, "myjob")
+		return job, nil
+	}
+	s.mockService.JobService.OnAddServiceEnv = func(j *jobTypes.Job, args jobTypes.AddInstanceArgs) error {
+		jobEnvs = args
+		return nil
+	}
+	s.mockService.JobService.OnUpdateJobProv = func(j *jobTypes.Job) error {
+		reloadedJob = j.Name
+		return nil
+	}
+	instance := createPendingInstance(c, BrokerInstanceData{
+		LastOperationState: "succeeded",
+		Binds: map[string]BrokerInstanceBind{
+			"myapp": {UUID: "bind-app", State: "in progress"},
+		},
+		JobBinds: map[string]BrokerInstanceBind{
+			"myjob": {UUID: "bind-job", State: "in progress"},
+		},
+	})
+	err := pollBrokerOperations(context.TODO())
+	c.Assert(err, check.IsNil)
+	stored, err := GetServiceInstance(context.TODO(), instance.ServiceName, instance.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(stored.BrokerData.Binds["myapp"].State, check.Equals, "succeeded")
+	c.Assert(stored.BrokerData.JobBinds["myjob"].State, check.Equals, "succeeded")
+	c.Assert(stored.BrokerData.Pending, check.Equals, false)
+	c.Assert(a.ServiceEnvs, check.HasLen, 1)
+	c.Assert(a.ServiceEnvs[0].Name, check.Equals, "DATABASE_URL")
+	c.Assert(a.ServiceEnvs[0].Value, check.Equals, "db://host")
+	c.Assert(a.ServiceEnvs[0].InstanceName, check.Equals, "instance")
+	c.Assert(jobEnvs.Envs, check.HasLen, 1)
+	c.Assert(jobEnvs.Envs[0].Name, check.Equals, "DATABASE_URL")
+	c.Assert(reloadedJob, check.Equals, "myjob")
+	c.Assert(eventtest.EventDesc{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeServiceInstance, Value: "broker::service/instance"},
+		ExtraTargets: []eventTypes.ExtraTarget{{Target: eventTypes.Target{Type: eventTypes.TargetTypeApp, Value: "myapp"}}},
+		Kind:         brokerOperationKind,
+	}, eventtest.HasEvent)
+	c.Assert(eventtest.EventDesc{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeServiceInstance, Value: "broker::service/instance"},
+		ExtraTargets: []eventTypes.ExtraTarget{{Target: eventTypes.Target{Type: eventTypes.TargetTypeJob, Value: "myjob"}}},
+		Kind:         brokerOperationKind,
+	}, eventtest.HasEvent)
+}
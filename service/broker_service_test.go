@@ -110,6 +110,34 @@ func (s *BrokerSuite) TestServiceBrokerUpdateDefaultCache(c *check.C) {
 	c.Assert(broker.Config.CacheExpirationSeconds, check.Equals, 0)
 }
 
+func (s *BrokerSuite) TestServiceBrokerUpdatePlanVisibility(c *check.C) {
+	visibility := []service.PlanVisibility{{Service: "mysql", Plan: "large", Teams: []string{"team1"}}}
+	err := s.service.Create(context.TODO(), service.Broker{
+		Name:           "broker-name",
+		URL:            "https://localhost:8080",
+		PlanVisibility: visibility,
+	})
+	c.Assert(err, check.IsNil)
+	newVisibility := []service.PlanVisibility{{Service: "mysql", Plan: "large", Pools: []string{"pool1"}}}
+	err = s.service.Update(context.TODO(), "broker-name", service.Broker{
+		Name:           "broker-name",
+		URL:            "https://localhost:9090",
+		PlanVisibility: newVisibility,
+	})
+	c.Assert(err, check.IsNil)
+	broker, err := s.service.Find(context.TODO(), "broker-name")
+	c.Assert(err, check.IsNil)
+	c.Assert(broker.PlanVisibility, check.DeepEquals, newVisibility)
+	err = s.service.Update(context.TODO(), "broker-name", service.Broker{
+		Name: "broker-name",
+		URL:  "https://localhost:9090",
+	})
+	c.Assert(err, check.IsNil)
+	broker, err = s.service.Find(context.TODO(), "broker-name")
+	c.Assert(err, check.IsNil)
+	c.Assert(broker.PlanVisibility, check.HasLen, 0)
+}
+
 func (s *BrokerSuite) TestServiceBrokerDelete(c *check.C) {
 	err := s.service.Create(context.TODO(), service.Broker{
 		Name: "broker-name",
@@ -3,12 +3,14 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"sort"
 
 	osb "github.com/pmorie/go-open-service-broker-client/v2"
 	osbfake "github.com/pmorie/go-open-service-broker-client/v2/fake"
 	"github.com/tsuru/tsuru/db/storagev2"
 	"github.com/tsuru/tsuru/provision/provisiontest"
+	jobTypes "github.com/tsuru/tsuru/types/job"
 	serviceTypes "github.com/tsuru/tsuru/types/service"
 	check "gopkg.in/check.v1"
 )
@@ -368,6 +370,168 @@ func (s *S) TestBrokerClientUnbindApp(c *check.C) {
 	})
 }
 
+func (s *S) TestBrokerClientBindJob(c *check.C) {
+	ev := createEvt(c)
+	job := &jobTypes.Job{Name: "myjob", Pool: "mypool"}
+	var bindIDs []string
+	reaction := func(req *osb.BindRequest) (*osb.BindResponse, error) {
+		exID, errMarshal := json.Marshal(map[string]interface{}{
+			"user": "my@user",
+		})
+		c.Assert(errMarshal, check.IsNil)
+		bindIDs = append(bindIDs, req.BindingID)
+		req.BindingID = ""
+		c.Assert(req, check.DeepEquals, &osb.BindRequest{
+			AcceptsIncomplete: true,
+			InstanceID:        "e7252f14-54be-45df-bd40-e988a0e41059",
+			ServiceID:         "s1",
+			PlanID:            "p1",
+			OriginatingIdentity: &osb.OriginatingIdentity{
+				Platform: "tsuru",
+				Value:    string(exID),
+			},
+			Context: map[string]interface{}{
+				"request_id": "request-id",
+				"event_id":   ev.UniqueID.Hex(),
+				"job_name":   "myjob",
+				"Namespace":  "broker-namespace",
+			},
+		})
+		return &osb.BindResponse{
+			Credentials: map[string]interface{}{
+				"env1": "val1",
+				"env2": 2,
+			}}, nil
+	}
+	config := osbfake.FakeClientConfiguration{
+		BindReaction: osbfake.DynamicBindReaction(reaction),
+	}
+	ClientFactory = osbfake.NewFakeClientFunc(config)
+	client, err := newClient(serviceTypes.Broker{
+		Name: "broker",
+		Config: serviceTypes.BrokerConfig{
+			Context: map[string]interface{}{
+				"Namespace": "broker-namespace",
+			},
+		},
+	}, "service")
+	c.Assert(err, check.IsNil)
+	instance := createTestInstance()
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(context.TODO(), &instance)
+	c.Assert(err, check.IsNil)
+	envs, err := client.BindJob(context.TODO(), &instance, job, ev, "request-id")
+	c.Assert(err, check.IsNil)
+	c.Assert(envs, check.DeepEquals, map[string]string{
+		"env1": "val1",
+		"env2": "2",
+	})
+	_, err = client.BindJob(context.TODO(), &instance, job, ev, "request-id")
+	c.Assert(err, check.IsNil)
+	c.Assert(bindIDs, check.HasLen, 2)
+	c.Assert(bindIDs[0], check.Not(check.Equals), "")
+	c.Assert(bindIDs[1], check.Equals, bindIDs[0])
+	storedInstance, err := GetServiceInstance(context.TODO(), instance.ServiceName, instance.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(storedInstance.BrokerData, check.DeepEquals, &BrokerInstanceData{
+		UUID:      "e7252f14-54be-45df-bd40-e988a0e41059",
+		ServiceID: "s1",
+		PlanID:    "p1",
+		JobBinds: map[string]BrokerInstanceBind{
+			"myjob": {UUID: bindIDs[0]},
+		},
+	})
+}
+
+func (s *S) TestBrokerClientBindJobDifferentJobs(c *check.C) {
+	ev := createEvt(c)
+	var bindIDs []string
+	reaction := func(req *osb.BindRequest) (*osb.BindResponse, error) {
+		bindIDs = append(bindIDs, req.BindingID)
+		return &osb.BindResponse{}, nil
+	}
+	config := osbfake.FakeClientConfiguration{
+		BindReaction: osbfake.DynamicBindReaction(reaction),
+	}
+	ClientFactory = osbfake.NewFakeClientFunc(config)
+	client, err := newClient(serviceTypes.Broker{Name: "broker"}, "service")
+	c.Assert(err, check.IsNil)
+	instance := createTestInstance()
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(context.TODO(), &instance)
+	c.Assert(err, check.IsNil)
+	_, err = client.BindJob(context.TODO(), &instance, &jobTypes.Job{Name: "job1"}, ev, "request-id")
+	c.Assert(err, check.IsNil)
+	_, err = client.BindJob(context.TODO(), &instance, &jobTypes.Job{Name: "job2"}, ev, "request-id")
+	c.Assert(err, check.IsNil)
+	c.Assert(bindIDs, check.HasLen, 2)
+	c.Assert(bindIDs[0], check.Not(check.Equals), bindIDs[1])
+	c.Assert(instance.BrokerData.JobBinds, check.HasLen, 2)
+}
+
+func (s *S) TestBrokerClientUnbindJob(c *check.C) {
+	ev := createEvt(c)
+	reaction := func(req *osb.UnbindRequest) (*osb.UnbindResponse, error) {
+		exID, err := json.Marshal(map[string]interface{}{
+			"user": "my@user",
+		})
+		c.Assert(err, check.IsNil)
+		c.Assert(req, check.DeepEquals, &osb.UnbindRequest{
+			InstanceID:        "e7252f14-54be-45df-bd40-e988a0e41059",
+			ServiceID:         "s1",
+			PlanID:            "p1",
+			BindingID:         "xxxx-xxxx",
+			AcceptsIncomplete: true,
+			OriginatingIdentity: &osb.OriginatingIdentity{
+				Platform: "tsuru",
+				Value:    string(exID),
+			},
+		})
+		opKey := osb.OperationKey("Unbinding")
+		return &osb.UnbindResponse{OperationKey: &opKey}, nil
+	}
+	config := osbfake.FakeClientConfiguration{
+		UnbindReaction: osbfake.DynamicUnbindReaction(reaction),
+	}
+	ClientFactory = osbfake.NewFakeClientFunc(config)
+	client, err := newClient(serviceTypes.Broker{Name: "broker"}, "service")
+	c.Assert(err, check.IsNil)
+	instance := createTestInstance()
+	instance.BrokerData.JobBinds = map[string]BrokerInstanceBind{
+		"myjob": {UUID: "xxxx-xxxx"},
+	}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(context.TODO(), &instance)
+	c.Assert(err, check.IsNil)
+	err = client.UnbindJob(context.TODO(), &instance, &jobTypes.Job{Name: "myjob"}, ev, "request-id")
+	c.Assert(err, check.IsNil)
+	storedInstance, err := GetServiceInstance(context.TODO(), instance.ServiceName, instance.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(storedInstance.BrokerData, check.DeepEquals, &BrokerInstanceData{
+		UUID:             "e7252f14-54be-45df-bd40-e988a0e41059",
+		ServiceID:        "s1",
+		PlanID:           "p1",
+		LastOperationKey: "Unbinding",
+		JobBinds:         map[string]BrokerInstanceBind{},
+	})
+}
+
+func (s *S) TestBrokerClientUnbindJobWithoutBinding(c *check.C) {
+	ev := createEvt(c)
+	config := osbfake.FakeClientConfiguration{
+		UnbindReaction: &osbfake.UnbindReaction{Error: errors.New("should not be called")},
+	}
+	ClientFactory = osbfake.NewFakeClientFunc(config)
+	client, err := newClient(serviceTypes.Broker{Name: "broker"}, "service")
+	c.Assert(err, check.IsNil)
+	instance := createTestInstance()
+	err = client.UnbindJob(context.TODO(), &instance, &jobTypes.Job{Name: "myjob"}, ev, "request-id")
+	c.Assert(err, check.IsNil)
+}
+
 func (s *S) TestBrokerClientUpdate(c *check.C) {
 	ev := createEvt(c)
 	planID := "planid"
@@ -429,11 +593,13 @@ func (s *S) TestBrokerClientUpdate(c *check.C) {
 	storedInstance, err := GetServiceInstance(context.TODO(), instance.ServiceName, instance.Name)
 	c.Assert(err, check.IsNil)
 	c.Assert(storedInstance.BrokerData, check.DeepEquals, &BrokerInstanceData{
-		UUID:             "e7252f14-54be-45df-bd40-e988a0e41059",
-		ServiceID:        "serviceid",
-		PlanID:           "planid",
-		LastOperationKey: "Provisioning",
-		Binds:            map[string]BrokerInstanceBind{},
+		UUID:               "e7252f14-54be-45df-bd40-e988a0e41059",
+		ServiceID:          "serviceid",
+		PlanID:             "planid",
+		LastOperationKey:   "Provisioning",
+		LastOperationType:  "update",
+		LastOperationState: "succeeded",
+		Binds:              map[string]BrokerInstanceBind{},
 	})
 }
 
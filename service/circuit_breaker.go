@@ -0,0 +1,130 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultCircuitBreakerCooldown = 30 * time.Second
+
+var (
+	ErrCircuitOpen = errors.New("service API is unavailable: too many consecutive failures, try again later")
+
+	breakersMu sync.Mutex
+	breakers   = map[string]*circuitBreaker{}
+)
+
+// CircuitBreakerConfig controls when requests to a service API are
+// short-circuited after consecutive failures.
+type CircuitBreakerConfig struct {
+	// Threshold is the number of consecutive failures opening the circuit.
+	// Zero disables the circuit breaker.
+	Threshold int `bson:"threshold,omitempty"`
+	// CooldownSeconds is how long the circuit stays open before a trial
+	// request is let through. Defaults to 30 seconds.
+	CooldownSeconds int `bson:"cooldown_seconds,omitempty"`
+}
+
+// circuitBreaker tracks consecutive failures of a service endpoint. Once the
+// threshold is reached the circuit opens and requests fail fast until the
+// cooldown is over, when a single request is allowed to probe the endpoint.
+type circuitBreaker struct {
+	sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	probing   bool
+}
+
+func breakerFor(serviceName, endpoint string, config CircuitBreakerConfig) *circuitBreaker {
+	if config.Threshold <= 0 {
+		return nil
+	}
+	cooldown := time.Duration(config.CooldownSeconds) * time.Second
+	if cooldown <= 0 {
+		cooldown = defaultCircuitBreakerCooldown
+	}
+	key := serviceName + " " + endpoint
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	b, ok := breakers[key]
+	if !ok {
+		b = &circuitBreaker{}
+		breakers[key] = b
+	}
+	b.Lock()
+	b.threshold = config.Threshold
+	b.cooldown = cooldown
+	b.Unlock()
+	return b
+}
+
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.Lock()
+	defer b.Unlock()
+	if b.failures < b.threshold {
+		return nil
+	}
+	if b.probing || time.Since(b.openedAt) < b.cooldown {
+		return ErrCircuitOpen
+	}
+	b.probing = true
+	return nil
+}
+
+func (b *circuitBreaker) record(resp *http.Response, err error) {
+	if b == nil {
+		return
+	}
+	b.Lock()
+	defer b.Unlock()
+	b.probing = false
+	if err == nil && !isUnavailableStatus(resp.StatusCode) {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
+
+// isUnavailableStatus reports whether the status code means the service API
+// itself is unavailable. Other 5xx codes are part of the service API
+// contract, e.g. a 500 on the status route means the instance is down.
+func isUnavailableStatus(code int) bool {
+	return code == http.StatusBadGateway || code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
+
+// requestOperation returns a low cardinality name for a request to a service
+// API, replacing instance, plan, job and action names with placeholders.
+func requestOperation(method, path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) > 1 && parts[0] == "resources" {
+		if parts[1] == "plans" {
+			if len(parts) > 2 {
+				parts[2] = "{plan}"
+			}
+		} else {
+			parts[1] = "{instance}"
+			if len(parts) > 4 && parts[2] == "binds" {
+				parts[4] = "{job}"
+			}
+			if len(parts) > 3 && parts[2] == "actions" {
+				parts[3] = "{action}"
+			}
+		}
+	}
+	return method + " /" + strings.Join(parts, "/")
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/tsuru/tsuru/db/storagev2"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestRequestOperation(c *check.C) {
+	tests := map[string]string{
+		"/resources":                           "POST /resources",
+		"/resources/plans":                     "POST /resources/plans",
+		"/resources/plans/small/schemas":       "POST /resources/plans/{plan}/schemas",
+		"/resources/mydb/bind-app":             "POST /resources/{instance}/bind-app",
+		"/resources/mydb/binds/jobs/myjob":     "POST /resources/{instance}/binds/jobs/{job}",
+		"/resources/mydb/actions/restart":      "POST /resources/{instance}/actions/{action}",
+		"/resources/mydb/credentials/previous": "POST /resources/{instance}/credentials/previous",
+	}
+	for path, expected := range tests {
+		c.Check(requestOperation(http.MethodPost, path), check.Equals, expected)
+	}
+}
+
+func (s *S) TestCircuitBreakerOpensAfterThreshold(c *check.C) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+	srv := Service{Name: "breaker-opens", Endpoint: map[string]string{"production": ts.URL}, CircuitBreaker: CircuitBreakerConfig{Threshold: 2}}
+	cli, err := srv.getClient("production")
+	c.Assert(err, check.IsNil)
+	client := cli.(*endpointClient)
+	for i := 0; i < 2; i++ {
+		resp, reqErr := client.issueRequest(context.TODO(), "/resources/plans", http.MethodGet, nil, make(http.Header))
+		c.Assert(reqErr, check.IsNil)
+		resp.Body.Close()
+	}
+	_, err = client.issueRequest(context.TODO(), "/resources/plans", http.MethodGet, nil, make(http.Header))
+	c.Assert(err, check.Equals, ErrCircuitOpen)
+	c.Assert(atomic.LoadInt32(&requests), check.Equals, int32(2))
+}
+
+func (s *S) TestCircuitBreakerClosesAfterSuccessfulTrial(c *check.C) {
+	var fail int32 = 1
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&fail) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer ts.Close()
+	srv := Service{Name: "breaker-closes", Endpoint: map[string]string{"production": ts.URL}, CircuitBreaker: CircuitBreakerConfig{Threshold: 1, CooldownSeconds: 60}}
+	cli, err := srv.getClient("production")
+	c.Assert(err, check.IsNil)
+	client := cli.(*endpointClient)
+	resp, err := client.issueRequest(context.TODO(), "/resources/plans", http.MethodGet, nil, make(http.Header))
+	c.Assert(err, check.IsNil)
+	resp.Body.Close()
+	_, err = client.issueRequest(context.TODO(), "/resources/plans", http.MethodGet, nil, make(http.Header))
+	c.Assert(err, check.Equals, ErrCircuitOpen)
+	client.breaker.Lock()
+	client.breaker.openedAt = time.Now().Add(-time.Minute)
+	client.breaker.Unlock()
+	atomic.StoreInt32(&fail, 0)
+	resp, err = client.issueRequest(context.TODO(), "/resources/plans", http.MethodGet, nil, make(http.Header))
+	c.Assert(err, check.IsNil)
+	resp.Body.Close()
+	c.Assert(client.breaker.failures, check.Equals, 0)
+}
+
+func (s *S) TestServiceRequestTimeout(c *check.C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Second)
+	}))
+	defer ts.Close()
+	srv := Service{Name: "slow", Endpoint: map[string]string{"production": ts.URL}, Timeout: 1}
+	cli, err := srv.getClient("production")
+	c.Assert(err, check.IsNil)
+	_, err = cli.(*endpointClient).issueRequest(context.TODO(), "/resources/plans", http.MethodGet, nil, make(http.Header))
+	c.Assert(err, check.ErrorMatches, `.*Client.Timeout exceeded.*`)
+}
+
+func (s *S) TestProbeServices(c *check.C) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer healthy.Close()
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unhealthy.Close()
+	collection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	for _, srv := range []Service{
+		{Name: "healthy", Endpoint: map[string]string{"production": healthy.URL}},
+		{Name: "unhealthy", Endpoint: map[string]string{"production": unhealthy.URL}},
+	} {
+		_, err = collection.InsertOne(context.TODO(), srv)
+		c.Assert(err, check.IsNil)
+	}
+	err = probeServices(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(testutil.ToFloat64(serviceAPIUp.WithLabelValues("healthy", "production")), check.Equals, 1.0)
+	c.Assert(testutil.ToFloat64(serviceAPIUp.WithLabelValues("unhealthy", "production")), check.Equals, 0.0)
+	err = healthProbeCheck(context.TODO())
+	c.Assert(err, check.ErrorMatches, `unhealthy \(production\): unexpected status code 503`)
+}
+
+func (s *S) TestProbeServicesBypassesCircuitBreaker(c *check.C) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`[]`))
+	}))
+	defer ts.Close()
+	srv := Service{
+		Name:           "breaking",
+		Endpoint:       map[string]string{"production": ts.URL},
+		CircuitBreaker: CircuitBreakerConfig{Threshold: 1, CooldownSeconds: 60},
+	}
+	collection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = collection.InsertOne(context.TODO(), srv)
+	c.Assert(err, check.IsNil)
+	breaker := breakerFor(srv.Name, ts.URL, srv.CircuitBreaker)
+	breaker.record(nil, errors.New("connection refused"))
+	c.Assert(breaker.allow(), check.Equals, ErrCircuitOpen)
+	err = probeServices(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(atomic.LoadInt32(&calls), check.Equals, int32(1))
+	c.Assert(testutil.ToFloat64(serviceAPIUp.WithLabelValues("breaking", "production")), check.Equals, 1.0)
+	c.Assert(breaker.allow(), check.Equals, ErrCircuitOpen)
+	err = healthProbeCheck(context.TODO())
+	c.Assert(err, check.IsNil)
+}
@@ -0,0 +1,395 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	bindTypes "github.com/tsuru/tsuru/types/bind"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultRotationGracePeriod    = time.Minute
+	defaultMaxRotationGracePeriod = 24 * time.Hour
+	defaultRevocationInterval     = 30 * time.Second
+	revocationRetryInterval       = 5 * time.Minute
+	revocationTimeout             = time.Minute
+	credentialsRevocationKind     = "credentials revocation"
+)
+
+var ErrRotationGracePeriodTooLong = errors.New("grace period is longer than the maximum allowed")
+
+// CredentialRotation holds the credentials issued by a service when the
+// credentials of an instance are rotated.
+type CredentialRotation struct {
+	// Envs are the credentials of every app and job bound to the instance,
+	// used when the service issues credentials per instance.
+	Envs map[string]string
+	// AppEnvs and JobEnvs are the credentials issued per app and job, used
+	// when the service issues credentials per binding.
+	AppEnvs map[string]map[string]string
+	JobEnvs map[string]map[string]string
+
+	previousBinds []BrokerInstanceBind
+}
+
+func (r *CredentialRotation) envsForApp(appName string) map[string]string {
+	if envs, ok := r.AppEnvs[appName]; ok {
+		return envs
+	}
+	return r.Envs
+}
+
+func (r *CredentialRotation) envsForJob(jobName string) map[string]string {
+	if envs, ok := r.JobEnvs[jobName]; ok {
+		return envs
+	}
+	return r.Envs
+}
+
+type RotateCredentialsArgs struct {
+	// GracePeriod is how long the previous credentials remain valid after
+	// every app and job received the new ones. The revocation is scheduled
+	// and performed in background, it must not be longer than
+	// MaxRotationGracePeriod.
+	GracePeriod time.Duration
+	Writer      io.Writer
+	Event       *event.Event
+	RequestID   string
+}
+
+// RotateCredentials requests new credentials for the service instance,
+// replaces the service envs of every bound app and job, restarting apps one
+// at a time, and schedules the revocation of the previous credentials after
+// the grace period. args.Event must lock the instance, serializing rotations
+// and revocations of its credentials. When some of the bindings fail, the
+// others keep the new credentials, the previous ones are not revoked and a
+// *RotationError describing every binding is returned.
+func (si *ServiceInstance) RotateCredentials(ctx context.Context, args RotateCredentialsArgs) error {
+	if args.GracePeriod > MaxRotationGracePeriod() {
+		return ErrRotationGracePeriodTooLong
+	}
+	if err := si.checkProvisioned(); err != nil {
+		return err
+	}
+	s, err := Get(ctx, si.ServiceName)
+	if err != nil {
+		return err
+	}
+	endpoint, err := s.getClientForPool(ctx, si.Pool)
+	if err != nil {
+		return err
+	}
+	w := args.Writer
+	if w == nil {
+		w = io.Discard
+	}
+	fmt.Fprintf(w, "---- Requesting new credentials for instance %q ----\n", si.Name)
+	rotation, err := endpoint.RotateCredentials(ctx, si, args.Event, args.RequestID)
+	if err != nil {
+		return err
+	}
+	rotationErr := &RotationError{}
+	for _, appName := range si.Apps {
+		envs := rotation.envsForApp(appName)
+		if len(envs) == 0 {
+			continue
+		}
+		binding := fmt.Sprintf("app %q", appName)
+		fmt.Fprintf(w, "---- Updating credentials of %s ----\n", binding)
+		rotationErr.add(binding, si.replaceAppEnvs(ctx, appName, envs, w), w)
+	}
+	for _, jobName := range si.Jobs {
+		envs := rotation.envsForJob(jobName)
+		if len(envs) == 0 {
+			continue
+		}
+		binding := fmt.Sprintf("job %q", jobName)
+		fmt.Fprintf(w, "---- Updating credentials of %s ----\n", binding)
+		rotationErr.add(binding, si.replaceJobEnvs(ctx, jobName, envs, w), w)
+	}
+	if len(rotationErr.Failed) > 0 {
+		return rotationErr
+	}
+	if args.GracePeriod <= 0 {
+		fmt.Fprintf(w, "---- Revoking previous credentials ----\n")
+		return endpoint.RevokeCredentials(ctx, si, rotation, args.Event, args.RequestID)
+	}
+	revokeAt := time.Now().UTC().Add(args.GracePeriod)
+	if err = scheduleRevocation(ctx, si, rotation, revokeAt); err != nil {
+		return errors.Wrap(err, "unable to schedule the revocation of previous credentials")
+	}
+	fmt.Fprintf(w, "---- Previous credentials will be revoked at %s ----\n", revokeAt.Format(time.RFC3339))
+	return nil
+}
+
+// RotationError is returned when the new credentials could not be set on
+// some of the apps and jobs bound to the instance. The previous credentials
+// are not revoked, as the failed bindings may still depend on them.
+type RotationError struct {
+	Updated []string
+	Failed  []RotationFailure
+}
+
+type RotationFailure struct {
+	Binding string
+	Err     error
+}
+
+func (e *RotationError) add(binding string, err error, w io.Writer) {
+	if err == nil {
+		e.Updated = append(e.Updated, binding)
+		return
+	}
+	fmt.Fprintf(w, "---- Unable to update credentials of %s: %v ----\n", binding, err)
+	e.Failed = append(e.Failed, RotationFailure{Binding: binding, Err: err})
+}
+
+func (e *RotationError) Error() string {
+	failed := make([]string, len(e.Failed))
+	for i, f := range e.Failed {
+		failed[i] = fmt.Sprintf("%s: %v", f.Binding, f.Err)
+	}
+	msg := fmt.Sprintf("unable to update credentials of %s", strings.Join(failed, "; "))
+	if len(e.Updated) > 0 {
+		msg += fmt.Sprintf(". New credentials were set on %s", strings.Join(e.Updated, ", "))
+	}
+	return msg + ". Previous credentials were not revoked"
+}
+
+func (si *ServiceInstance) replaceAppEnvs(ctx context.Context, appName string, envs map[string]string, w io.Writer) error {
+	a, err := servicemanager.App.GetByName(ctx, appName)
+	if err != nil {
+		return err
+	}
+	err = servicemanager.App.RemoveInstance(ctx, a, bindTypes.RemoveInstanceArgs{
+		ServiceName:  si.ServiceName,
+		InstanceName: si.Name,
+		Writer:       w,
+	})
+	if err != nil {
+		return errors.Wrap(err, "previous credentials were kept")
+	}
+	err = servicemanager.App.AddInstance(ctx, a, bindTypes.AddInstanceArgs{
+		Envs:          serviceEnvVars(si, envs),
+		ShouldRestart: true,
+		Writer:        w,
+	})
+	return errors.Wrap(err, "previous credentials were removed but the new ones were not set")
+}
+
+func (si *ServiceInstance) replaceJobEnvs(ctx context.Context, jobName string, envs map[string]string, w io.Writer) error {
+	job, err := servicemanager.Job.GetByName(ctx, jobName)
+	if err != nil {
+		return err
+	}
+	err = servicemanager.Job.RemoveServiceEnv(ctx, job, jobTypes.RemoveInstanceArgs{
+		ServiceName:  si.ServiceName,
+		InstanceName: si.Name,
+		Writer:       w,
+	})
+	if err != nil {
+		return errors.Wrap(err, "previous credentials were kept")
+	}
+	err = servicemanager.Job.AddServiceEnv(ctx, job, jobTypes.AddInstanceArgs{
+		Envs:   serviceEnvVars(si, envs),
+		Writer: w,
+	})
+	if err != nil {
+		return errors.Wrap(err, "previous credentials were removed but the new ones were not set")
+	}
+	return errors.Wrap(servicemanager.Job.UpdateJobProv(ctx, job), "new credentials were set but the job was not updated in the provisioner")
+}
+
+// MaxRotationGracePeriod returns the longest grace period accepted when
+// rotating credentials, from the service:credentials-rotation-max-grace-period
+// setting.
+func MaxRotationGracePeriod() time.Duration {
+	seconds, err := config.GetInt("service:credentials-rotation-max-grace-period")
+	if err != nil || seconds <= 0 {
+		return defaultMaxRotationGracePeriod
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// RotationGracePeriod returns the grace period used when rotating
+// credentials, from the service:credentials-rotation-grace-period setting.
+func RotationGracePeriod() time.Duration {
+	seconds, err := config.GetInt("service:credentials-rotation-grace-period")
+	if err != nil || seconds <= 0 {
+		return defaultRotationGracePeriod
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// credentialsRevocation is a pending revocation of the credentials replaced
+// by a rotation, stored so it survives restarts of the API.
+type credentialsRevocation struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	ServiceName   string
+	InstanceName  string
+	PreviousBinds []BrokerInstanceBind
+	RevokeAt      time.Time
+	Attempts      int
+	LastError     string
+}
+
+func scheduleRevocation(ctx context.Context, si *ServiceInstance, rotation *CredentialRotation, revokeAt time.Time) error {
+	collection, err := storagev2.ServiceCredentialsRevocationsCollection()
+	if err != nil {
+		return err
+	}
+	_, err = collection.InsertOne(ctx, credentialsRevocation{
+		ID:            primitive.NewObjectID(),
+		ServiceName:   si.ServiceName,
+		InstanceName:  si.Name,
+		PreviousBinds: rotation.previousBinds,
+		RevokeAt:      revokeAt,
+	})
+	return err
+}
+
+// InitializeCredentialsRevoker starts the worker revoking the previous
+// credentials of rotations once their grace period is over.
+func InitializeCredentialsRevoker() error {
+	interval := defaultRevocationInterval
+	if seconds, err := config.GetInt("service:credentials-revocation-interval"); err == nil && seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	}
+	r := &credentialsRevoker{once: &sync.Once{}, interval: interval}
+	r.start()
+	shutdown.Register(r)
+	return nil
+}
+
+type credentialsRevoker struct {
+	once     *sync.Once
+	stopCh   chan struct{}
+	interval time.Duration
+}
+
+func (r *credentialsRevoker) start() {
+	r.once.Do(func() {
+		r.stopCh = make(chan struct{})
+		go r.spin()
+	})
+}
+
+func (r *credentialsRevoker) Shutdown(ctx context.Context) error {
+	if r.stopCh == nil {
+		return nil
+	}
+	r.stopCh <- struct{}{}
+	r.stopCh = nil
+	r.once = &sync.Once{}
+	return nil
+}
+
+func (r *credentialsRevoker) spin() {
+	for {
+		if err := revokePendingCredentials(context.Background()); err != nil {
+			log.Errorf("[credentials revoker] %v", err)
+		}
+
+		select {
+		case <-r.stopCh:
+			return
+		case <-time.After(r.interval):
+		}
+	}
+}
+
+// revokePendingCredentials revokes every credential whose grace period is
+// over. Each revocation is claimed by postponing it, so API replicas don't
+// revoke the same credentials and failures are retried later.
+func revokePendingCredentials(ctx context.Context) error {
+	collection, err := storagev2.ServiceCredentialsRevocationsCollection()
+	if err != nil {
+		return err
+	}
+	for {
+		now := time.Now().UTC()
+		var revocation credentialsRevocation
+		err = collection.FindOneAndUpdate(ctx,
+			mongoBSON.M{"revokeat": mongoBSON.M{"$lte": now}},
+			mongoBSON.M{
+				"$set": mongoBSON.M{"revokeat": now.Add(revocationRetryInterval)},
+				"$inc": mongoBSON.M{"attempts": 1},
+			},
+			options.FindOneAndUpdate().SetSort(mongoBSON.M{"revokeat": 1}),
+		).Decode(&revocation)
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "unable to find pending revocations")
+		}
+		revokeErr := revokeCredentials(ctx, &revocation)
+		if _, isLocked := revokeErr.(event.ErrEventLocked); isLocked {
+			log.Debugf("[credentials revoker] instance %s/%s is locked, postponing revocation", revocation.ServiceName, revocation.InstanceName)
+			continue
+		}
+		if revokeErr == nil || revokeErr == ErrServiceInstanceNotFound {
+			_, err = collection.DeleteOne(ctx, mongoBSON.M{"_id": revocation.ID})
+		} else {
+			log.Errorf("[credentials revoker] unable to revoke previous credentials of instance %s/%s: %v", revocation.ServiceName, revocation.InstanceName, revokeErr)
+			_, err = collection.UpdateOne(ctx, mongoBSON.M{"_id": revocation.ID}, mongoBSON.M{"$set": mongoBSON.M{"lasterror": revokeErr.Error()}})
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func revokeCredentials(ctx context.Context, revocation *credentialsRevocation) error {
+	ctx, cancel := context.WithTimeout(ctx, revocationTimeout)
+	defer cancel()
+	si, err := GetServiceInstance(ctx, revocation.ServiceName, revocation.InstanceName)
+	if err != nil {
+		return err
+	}
+	s, err := Get(ctx, si.ServiceName)
+	if err != nil {
+		return err
+	}
+	endpoint, err := s.getClientForPool(ctx, si.Pool)
+	if err != nil {
+		return err
+	}
+	evt, err := event.NewInternal(ctx, &event.Opts{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeServiceInstance, Value: si.ServiceName + "/" + si.Name},
+		InternalKind: credentialsRevocationKind,
+		Allowed: event.Allowed(permission.PermServiceInstanceReadEvents, append(
+			permission.Contexts(permTypes.CtxTeam, si.Teams),
+			permission.Context(permTypes.CtxServiceInstance, si.ServiceName+"/"+si.Name),
+		)...),
+	})
+	if err != nil {
+		return err
+	}
+	err = endpoint.RevokeCredentials(ctx, si, &CredentialRotation{previousBinds: revocation.PreviousBinds}, evt, "")
+	evt.Done(ctx, err)
+	return err
+}
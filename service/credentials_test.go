@@ -0,0 +1,339 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	osb "github.com/pmorie/go-open-service-broker-client/v2"
+	osbfake "github.com/pmorie/go-open-service-broker-client/v2/fake"
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	bindTypes "github.com/tsuru/tsuru/types/bind"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	serviceTypes "github.com/tsuru/tsuru/types/service"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestEndpointRotateCredentials(c *check.C) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"DATABASE_PASSWORD":"new-secret"}`))
+		}
+	}))
+	defer ts.Close()
+	instance := ServiceInstance{Name: "his-redis", ServiceName: "redis"}
+	client := &endpointClient{endpoint: ts.URL, username: "user", password: "abcde"}
+	evt := createEvt(c)
+	rotation, err := client.RotateCredentials(context.TODO(), &instance, evt, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(rotation.Envs, check.DeepEquals, map[string]string{"DATABASE_PASSWORD": "new-secret"})
+	err = client.RevokeCredentials(context.TODO(), &instance, rotation, evt, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(requests, check.DeepEquals, []string{
+		"POST /resources/his-redis/credentials",
+		"DELETE /resources/his-redis/credentials/previous",
+	})
+}
+
+func (s *S) TestEndpointRotateCredentialsNotSupported(c *check.C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+	instance := ServiceInstance{Name: "his-redis", ServiceName: "redis"}
+	client := &endpointClient{endpoint: ts.URL, username: "user", password: "abcde"}
+	_, err := client.RotateCredentials(context.TODO(), &instance, createEvt(c), "")
+	c.Assert(err, check.Equals, ErrRotationNotSupported)
+}
+
+func (s *S) TestEndpointRotateCredentialsFailure(c *check.C) {
+	ts := httptest.NewServer(http.HandlerFunc(failHandler))
+	defer ts.Close()
+	instance := ServiceInstance{Name: "his-redis", ServiceName: "redis"}
+	client := &endpointClient{endpoint: ts.URL, username: "user", password: "abcde"}
+	_, err := client.RotateCredentials(context.TODO(), &instance, createEvt(c), "")
+	c.Assert(err, check.ErrorMatches, `Failed to rotate credentials of service instance "redis/his-redis": invalid response: Server failed to do its job. \(code: 500\)$`)
+}
+
+func (s *S) TestBrokerClientRotateCredentials(c *check.C) {
+	var bindRequests []*osb.BindRequest
+	var unbindRequests []*osb.UnbindRequest
+	config := osbfake.FakeClientConfiguration{
+		BindReaction: osbfake.DynamicBindReaction(func(req *osb.BindRequest) (*osb.BindResponse, error) {
+			bindRequests = append(bindRequests, req)
+			return &osb.BindResponse{Credentials: map[string]interface{}{"TOKEN": "token-" + req.BindingID}}, nil
+		}),
+		UnbindReaction: osbfake.DynamicUnbindReaction(func(req *osb.UnbindRequest) (*osb.UnbindResponse, error) {
+			unbindRequests = append(unbindRequests, req)
+			return &osb.UnbindResponse{}, nil
+		}),
+	}
+	s.setupFakeBroker(config)
+	s.mockService.App.Apps = []*appTypes.App{{Name: "myapp", UUID: "app-uuid"}}
+	instance := createTestInstance()
+	instance.BrokerData.Binds = map[string]BrokerInstanceBind{
+		"myapp": {UUID: "old-app-bind", Parameters: map[string]interface{}{"role": "rw"}},
+	}
+	instance.BrokerData.JobBinds = map[string]BrokerInstanceBind{
+		"myjob": {UUID: "old-job-bind"},
+	}
+	collection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = collection.InsertOne(context.TODO(), &instance)
+	c.Assert(err, check.IsNil)
+	client, err := newClient(serviceTypes.Broker{Name: "broker"}, "service")
+	c.Assert(err, check.IsNil)
+	evt := createEvt(c)
+	rotation, err := client.RotateCredentials(context.TODO(), &instance, evt, "request-id")
+	c.Assert(err, check.IsNil)
+	c.Assert(bindRequests, check.HasLen, 2)
+	appBind := instance.BrokerData.Binds["myapp"]
+	jobBind := instance.BrokerData.JobBinds["myjob"]
+	c.Assert(appBind.UUID, check.Not(check.Equals), "old-app-bind")
+	c.Assert(appBind.Parameters, check.DeepEquals, map[string]interface{}{"role": "rw"})
+	c.Assert(jobBind.UUID, check.Not(check.Equals), "old-job-bind")
+	c.Assert(rotation.AppEnvs, check.DeepEquals, map[string]map[string]string{"myapp": {"TOKEN": "token-" + appBind.UUID}})
+	c.Assert(rotation.JobEnvs, check.DeepEquals, map[string]map[string]string{"myjob": {"TOKEN": "token-" + jobBind.UUID}})
+	c.Assert(unbindRequests, check.HasLen, 0)
+	stored, err := GetServiceInstance(context.TODO(), instance.ServiceName, instance.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(stored.BrokerData.Binds["myapp"].UUID, check.Equals, appBind.UUID)
+	c.Assert(stored.BrokerData.JobBinds["myjob"].UUID, check.Equals, jobBind.UUID)
+	err = client.RevokeCredentials(context.TODO(), &instance, rotation, evt, "request-id")
+	c.Assert(err, check.IsNil)
+	c.Assert(unbindRequests, check.HasLen, 2)
+	unbound := []string{unbindRequests[0].BindingID, unbindRequests[1].BindingID}
+	c.Assert(unbound, check.DeepEquals, []string{"old-app-bind", "old-job-bind"})
+}
+
+func (s *S) TestBrokerClientRotateCredentialsFailureRemovesNewBindings(c *check.C) {
+	var unbindRequests []*osb.UnbindRequest
+	calls := 0
+	config := osbfake.FakeClientConfiguration{
+		BindReaction: osbfake.DynamicBindReaction(func(req *osb.BindRequest) (*osb.BindResponse, error) {
+			calls++
+			if calls > 1 {
+				return nil, osb.HTTPStatusCodeError{StatusCode: http.StatusInternalServerError}
+			}
+			return &osb.BindResponse{Credentials: map[string]interface{}{"TOKEN": "new"}}, nil
+		}),
+		UnbindReaction: osbfake.DynamicUnbindReaction(func(req *osb.UnbindRequest) (*osb.UnbindResponse, error) {
+			unbindRequests = append(unbindRequests, req)
+			return &osb.UnbindResponse{}, nil
+		}),
+	}
+	s.setupFakeBroker(config)
+	s.mockService.App.Apps = []*appTypes.App{{Name: "myapp", UUID: "app-uuid"}}
+	instance := createTestInstance()
+	instance.BrokerData.Binds = map[string]BrokerInstanceBind{"myapp": {UUID: "old-app-bind"}}
+	instance.BrokerData.JobBinds = map[string]BrokerInstanceBind{"myjob": {UUID: "old-job-bind"}}
+	client, err := newClient(serviceTypes.Broker{Name: "broker"}, "service")
+	c.Assert(err, check.IsNil)
+	_, err = client.RotateCredentials(context.TODO(), &instance, createEvt(c), "request-id")
+	c.Assert(err, check.NotNil)
+	c.Assert(unbindRequests, check.HasLen, 1)
+	c.Assert(unbindRequests[0].BindingID, check.Not(check.Equals), "old-app-bind")
+	c.Assert(instance.BrokerData.Binds["myapp"].UUID, check.Equals, "old-app-bind")
+	c.Assert(instance.BrokerData.JobBinds["myjob"].UUID, check.Equals, "old-job-bind")
+}
+
+func (s *S) TestServiceInstanceRotateCredentials(c *check.C) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"DATABASE_PASSWORD":"new-secret"}`))
+		}
+	}))
+	defer ts.Close()
+	srvc := Service{Name: "mysql", Endpoint: map[string]string{"production": ts.URL}, Password: "s3cr3t"}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srvc)
+	c.Assert(err, check.IsNil)
+	oldEnv := bindTypes.ServiceEnvVar{
+		ServiceName:  "mysql",
+		InstanceName: "db",
+		EnvVar:       bindTypes.EnvVar{Name: "DATABASE_PASSWORD", Value: "old-secret"},
+	}
+	a := &appTypes.App{Name: "myapp", ServiceEnvs: []bindTypes.ServiceEnvVar{oldEnv}}
+	s.mockService.App.Apps = []*appTypes.App{a}
+	job := &jobTypes.Job{Name: "myjob", Spec: jobTypes.JobSpec{ServiceEnvs: []bindTypes.ServiceEnvVar{oldEnv}}}
+	var jobCalls []string
+	s.mockService.JobService.OnGetByName = func(name string) (*jobTypes.Job, error) {
+		c.Assert(name, check.Equals, "myjob")
+		return job, nil
+	}
+	s.mockService.JobService.OnRemoveServiceEnv = func(j *jobTypes.Job, args jobTypes.RemoveInstanceArgs) error {
+		jobCalls = append(jobCalls, "remove "+args.ServiceName+"/"+args.InstanceName)
+		j.Spec.ServiceEnvs = nil
+		return nil
+	}
+	s.mockService.JobService.OnAddServiceEnv = func(j *jobTypes.Job, args jobTypes.AddInstanceArgs) error {
+		jobCalls = append(jobCalls, "add")
+		j.Spec.ServiceEnvs = append(j.Spec.ServiceEnvs, args.Envs...)
+		return nil
+	}
+	s.mockService.JobService.OnUpdateJobProv = func(j *jobTypes.Job) error {
+		jobCalls = append(jobCalls, "update")
+		return nil
+	}
+	si := ServiceInstance{Name: "db", ServiceName: "mysql", Apps: []string{"myapp"}, Jobs: []string{"myjob"}}
+	var buf bytes.Buffer
+	err = si.RotateCredentials(context.TODO(), RotateCredentialsArgs{
+		Writer: &buf,
+		Event:  createEvt(c),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(requests, check.DeepEquals, []string{
+		"POST /resources/db/credentials",
+		"DELETE /resources/db/credentials/previous",
+	})
+	newEnv := oldEnv
+	newEnv.Value = "new-secret"
+	c.Assert(a.ServiceEnvs, check.DeepEquals, []bindTypes.ServiceEnvVar{newEnv})
+	c.Assert(job.Spec.ServiceEnvs, check.DeepEquals, []bindTypes.ServiceEnvVar{newEnv})
+	c.Assert(jobCalls, check.DeepEquals, []string{"remove mysql/db", "add", "update"})
+	c.Assert(buf.String(), check.Matches, `(?s).*Updating credentials of app "myapp".*Updating credentials of job "myjob".*Revoking previous credentials.*`)
+}
+
+func (s *S) TestServiceInstanceRotateCredentialsKeepsPreviousOnFailure(c *check.C) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`{"DATABASE_PASSWORD":"new-secret"}`))
+	}))
+	defer ts.Close()
+	srvc := Service{Name: "mysql", Endpoint: map[string]string{"production": ts.URL}, Password: "s3cr3t"}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srvc)
+	c.Assert(err, check.IsNil)
+	oldEnv := bindTypes.ServiceEnvVar{
+		ServiceName:  "mysql",
+		InstanceName: "db",
+		EnvVar:       bindTypes.EnvVar{Name: "DATABASE_PASSWORD", Value: "old-secret"},
+	}
+	a := &appTypes.App{Name: "myapp", ServiceEnvs: []bindTypes.ServiceEnvVar{oldEnv}}
+	s.mockService.App.Apps = []*appTypes.App{a}
+	si := ServiceInstance{Name: "db", ServiceName: "mysql", Apps: []string{"unknown-app", "myapp"}}
+	var buf bytes.Buffer
+	err = si.RotateCredentials(context.TODO(), RotateCredentialsArgs{Writer: &buf, Event: createEvt(c)})
+	c.Assert(err, check.FitsTypeOf, &RotationError{})
+	rotationErr := err.(*RotationError)
+	c.Assert(rotationErr.Updated, check.DeepEquals, []string{`app "myapp"`})
+	c.Assert(rotationErr.Failed, check.HasLen, 1)
+	c.Assert(rotationErr.Failed[0].Binding, check.Equals, `app "unknown-app"`)
+	c.Assert(err, check.ErrorMatches, `unable to update credentials of app "unknown-app": .*\. New credentials were set on app "myapp"\. Previous credentials were not revoked`)
+	c.Assert(buf.String(), check.Matches, `(?s).*Unable to update credentials of app "unknown-app".*Updating credentials of app "myapp".*`)
+	newEnv := oldEnv
+	newEnv.Value = "new-secret"
+	c.Assert(a.ServiceEnvs, check.DeepEquals, []bindTypes.ServiceEnvVar{newEnv})
+	c.Assert(requests, check.DeepEquals, []string{"POST /resources/db/credentials"})
+}
+
+func (s *S) TestRevokePendingCredentialsPostponesLockedInstance(c *check.C) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+	}))
+	defer ts.Close()
+	srvc := Service{Name: "mysql", Endpoint: map[string]string{"production": ts.URL}, Password: "s3cr3t"}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srvc)
+	c.Assert(err, check.IsNil)
+	si := ServiceInstance{Name: "db", ServiceName: "mysql"}
+	instancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = instancesCollection.InsertOne(context.TODO(), &si)
+	c.Assert(err, check.IsNil)
+	err = scheduleRevocation(context.TODO(), &si, &CredentialRotation{}, time.Now().UTC().Add(-time.Second))
+	c.Assert(err, check.IsNil)
+	evt, err := event.New(context.TODO(), &event.Opts{
+		Target:   eventTypes.Target{Type: eventTypes.TargetTypeServiceInstance, Value: "mysql/db"},
+		Kind:     permission.PermServiceInstanceUpdateCredentials,
+		RawOwner: eventTypes.Owner{Type: eventTypes.OwnerTypeUser, Name: "my@user"},
+		Allowed:  event.Allowed(permission.PermServiceInstanceReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	defer evt.Done(context.TODO(), nil)
+	err = revokePendingCredentials(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(requests, check.HasLen, 0)
+	collection, err := storagev2.ServiceCredentialsRevocationsCollection()
+	c.Assert(err, check.IsNil)
+	var revocation credentialsRevocation
+	err = collection.FindOne(context.TODO(), mongoBSON.M{}).Decode(&revocation)
+	c.Assert(err, check.IsNil)
+	c.Assert(revocation.RevokeAt.After(time.Now().UTC()), check.Equals, true)
+	c.Assert(revocation.LastError, check.Equals, "")
+}
+
+func (s *S) TestServiceInstanceRotateCredentialsSchedulesRevocation(c *check.C) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"DATABASE_PASSWORD":"new-secret"}`))
+		}
+	}))
+	defer ts.Close()
+	srvc := Service{Name: "mysql", Endpoint: map[string]string{"production": ts.URL}, Password: "s3cr3t"}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srvc)
+	c.Assert(err, check.IsNil)
+	si := ServiceInstance{Name: "db", ServiceName: "mysql"}
+	instancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = instancesCollection.InsertOne(context.TODO(), &si)
+	c.Assert(err, check.IsNil)
+	var buf bytes.Buffer
+	err = si.RotateCredentials(context.TODO(), RotateCredentialsArgs{
+		GracePeriod: time.Hour,
+		Writer:      &buf,
+		Event:       createEvt(c),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(requests, check.DeepEquals, []string{"POST /resources/db/credentials"})
+	c.Assert(buf.String(), check.Matches, `(?s).*Previous credentials will be revoked at .*`)
+	err = revokePendingCredentials(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(requests, check.HasLen, 1)
+	collection, err := storagev2.ServiceCredentialsRevocationsCollection()
+	c.Assert(err, check.IsNil)
+	_, err = collection.UpdateMany(context.TODO(), mongoBSON.M{}, mongoBSON.M{"$set": mongoBSON.M{"revokeat": time.Now().UTC().Add(-time.Second)}})
+	c.Assert(err, check.IsNil)
+	err = revokePendingCredentials(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(requests, check.DeepEquals, []string{
+		"POST /resources/db/credentials",
+		"DELETE /resources/db/credentials/previous",
+	})
+	count, err := collection.CountDocuments(context.TODO(), mongoBSON.M{})
+	c.Assert(err, check.IsNil)
+	c.Assert(count, check.Equals, int64(0))
+}
+
+func (s *S) TestServiceInstanceRotateCredentialsGracePeriodTooLong(c *check.C) {
+	si := ServiceInstance{Name: "db", ServiceName: "mysql"}
+	err := si.RotateCredentials(context.TODO(), RotateCredentialsArgs{
+		GracePeriod: MaxRotationGracePeriod() + time.Second,
+		Event:       createEvt(c),
+	})
+	c.Assert(err, check.Equals, ErrRotationGracePeriodTooLong)
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/cezarsa/form"
 	"github.com/pkg/errors"
+	osb "github.com/pmorie/go-open-service-broker-client/v2"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/event"
@@ -33,6 +34,7 @@ var (
 	ErrInstanceAlreadyExistsInAPI = errors.New("instance already exists in the service API")
 	ErrInstanceNotFoundInAPI      = errors.New("instance does not exist in the service API")
 	ErrInstanceNotReady           = errors.New("instance is not ready yet")
+	ErrRotationNotSupported       = errors.New("service API does not support credential rotation")
 
 	requestLatencies = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Name: "tsuru_service_request_duration_seconds",
@@ -42,18 +44,39 @@ var (
 		Name: "tsuru_service_request_errors_total",
 		Help: "The total number of service request errors.",
 	}, []string{"service"})
-
+	operationLatencies = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "tsuru_service_operation_duration_seconds",
+		Help: "The service requests latency distributions by operation.",
+	}, []string{"service", "operation"})
+	operationErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tsuru_service_operation_errors_total",
+		Help: "The total number of failed service requests by operation, including server errors and requests rejected by the circuit breaker.",
+	}, []string{"service", "operation"})
+
+	// reservedProxyPaths are the instance paths tsuru itself calls with
+	// side effects, they can't be reached through the proxy with methods
+	// other than GET.
 	reservedProxyPaths = []string{
 		"",
 		"bind-app",
 		"bind-job",
 		"bind",
+		"credentials",
+		"credentials/previous",
+	}
+	// reservedProxyPrefixes are reserved like reservedProxyPaths, for every
+	// path under them. Only the action calls are reserved, a service route
+	// listing or describing actions with GET is still proxied.
+	reservedProxyPrefixes = []string{
+		"actions/",
 	}
 )
 
 func init() {
 	prometheus.MustRegister(requestLatencies)
 	prometheus.MustRegister(requestErrors)
+	prometheus.MustRegister(operationLatencies)
+	prometheus.MustRegister(operationErrors)
 }
 
 var _ ServiceClient = &endpointClient{}
@@ -68,6 +91,8 @@ type endpointClient struct {
 	endpoint    string
 	username    string
 	password    string
+	timeout     time.Duration
+	breaker     *circuitBreaker
 }
 
 func (c *endpointClient) Create(ctx context.Context, instance *ServiceInstance, evt *event.Event, requestID string) error {
@@ -162,7 +187,7 @@ func (c *endpointClient) Destroy(ctx context.Context, instance *ServiceInstance,
 func (c *endpointClient) BindApp(ctx context.Context, instance *ServiceInstance, app *appTypes.App, bindParams BindAppParameters, evt *event.Event, requestID string) (map[string]string, error) {
 	log.Debugf("Calling bind of instance %q and %q app at %q API",
 		instance.Name, app.Name, instance.ServiceName)
-	params, err := buildBindAppParams(ctx, evt, app, bindParams)
+	params, err := buildBindAppParams(ctx, evt, instance, app, bindParams)
 	if err != nil {
 		log.Errorf("Ignoring some errors found while building the bind app parameters: %v", err)
 		return nil, err
@@ -205,7 +230,7 @@ func (c *endpointClient) BindJob(ctx context.Context, instance *ServiceInstance,
 	log.Debugf("Calling bind of instance %q and %q job at %q API",
 		instance.Name, job.Name, instance.ServiceName)
 
-	params, err := buildBindJobParams(ctx, evt, job)
+	params, err := buildBindJobParams(ctx, evt, instance, job)
 	if err != nil {
 		log.Errorf("Errors found while building the bind job parameters: %v", err)
 		return nil, err
@@ -303,6 +328,133 @@ func (c *endpointClient) UnbindJob(ctx context.Context, instance *ServiceInstanc
 	return nil
 }
 
+// RotateCredentials asks the service API for new credentials, which are
+// used by all apps and jobs bound to the instance. The previous credentials
+// must remain valid until RevokeCredentials is called.
+// The api should be prepared to receive the request,
+// like below:
+// POST /resources/<name>/credentials
+func (c *endpointClient) RotateCredentials(ctx context.Context, instance *ServiceInstance, evt *event.Event, requestID string) (*CredentialRotation, error) {
+	log.Debugf("Attempting to call credential rotation of service instance %q at %q api", instance.Name, instance.ServiceName)
+	params := map[string][]string{
+		"user":    {evt.Owner.Name},
+		"eventid": {evt.UniqueID.Hex()},
+	}
+	header, err := baseHeader(ctx, evt, instance, requestID)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.issueRequest(ctx, "/resources/"+instance.GetIdentifier()+"/credentials", http.MethodPost, params, header)
+	if err != nil {
+		return nil, log.WrapError(errors.Wrapf(err, `Failed to rotate credentials of service instance "%s/%s"`, instance.ServiceName, instance.Name))
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return nil, ErrRotationNotSupported
+	case http.StatusPreconditionFailed:
+		return nil, ErrInstanceNotReady
+	}
+	if resp.StatusCode >= 300 {
+		err = errors.Wrapf(c.buildErrorMessage(err, resp), `Failed to rotate credentials of service instance "%s/%s"`, instance.ServiceName, instance.Name)
+		return nil, log.WrapError(err)
+	}
+	var envs map[string]string
+	err = c.jsonFromResponse(resp, &envs)
+	if err != nil {
+		return nil, err
+	}
+	return &CredentialRotation{Envs: envs}, nil
+}
+
+// RevokeCredentials asks the service API to revoke the credentials replaced
+// by the last call to RotateCredentials.
+// The api should be prepared to receive the request,
+// like below:
+// DELETE /resources/<name>/credentials/previous
+func (c *endpointClient) RevokeCredentials(ctx context.Context, instance *ServiceInstance, rotation *CredentialRotation, evt *event.Event, requestID string) error {
+	log.Debugf("Attempting to call revocation of previous credentials of service instance %q at %q api", instance.Name, instance.ServiceName)
+	params := map[string][]string{
+		"user":    {evt.Owner.Name},
+		"eventid": {evt.UniqueID.Hex()},
+	}
+	header, err := baseHeader(ctx, evt, instance, requestID)
+	if err != nil {
+		return err
+	}
+	resp, err := c.issueRequest(ctx, "/resources/"+instance.GetIdentifier()+"/credentials/previous", http.MethodDelete, params, header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode > 299 && resp.StatusCode != http.StatusNotFound {
+		err = errors.Wrapf(c.buildErrorMessage(err, resp), `Failed to revoke previous credentials of service instance "%s/%s"`, instance.ServiceName, instance.Name)
+		return log.WrapError(err)
+	}
+	return nil
+}
+
+// Actions returns the actions available for the instance.
+// The api should be prepared to receive the request,
+// like below:
+// GET /resources/<name>/actions
+func (c *endpointClient) Actions(ctx context.Context, instance *ServiceInstance, requestID string) ([]InstanceAction, error) {
+	header, err := baseHeader(ctx, nil, instance, requestID)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.issueRequest(ctx, "/resources/"+instance.GetIdentifier()+"/actions", http.MethodGet, nil, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		err = errors.Wrapf(c.buildErrorMessage(err, resp), "Failed to list actions of instance %s", instance.Name)
+		return nil, log.WrapError(err)
+	}
+	var actions []InstanceAction
+	err = c.jsonFromResponse(resp, &actions)
+	if err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
+
+// ExecuteAction runs an action on the instance, copying the response body
+// to w as the action output.
+// The api should be prepared to receive the request,
+// like below:
+// POST /resources/<name>/actions/<action>
+func (c *endpointClient) ExecuteAction(ctx context.Context, instance *ServiceInstance, action string, params map[string]interface{}, w io.Writer, evt *event.Event, requestID string) error {
+	log.Debugf("Attempting to execute action %q of service instance %q at %q api", action, instance.Name, instance.ServiceName)
+	values := map[string][]string{
+		"user":    {evt.Owner.Name},
+		"eventid": {evt.UniqueID.Hex()},
+	}
+	addParameters(values, params)
+	header, err := baseHeader(ctx, evt, instance, requestID)
+	if err != nil {
+		return err
+	}
+	resp, err := c.issueRequest(ctx, "/resources/"+instance.GetIdentifier()+"/actions/"+action, http.MethodPost, values, header)
+	if err != nil {
+		return log.WrapError(errors.Wrapf(err, `Failed to execute action %q on service instance "%s/%s"`, action, instance.ServiceName, instance.Name))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrInstanceActionNotFound
+	}
+	if resp.StatusCode > 299 {
+		err = errors.Wrapf(c.buildErrorMessage(err, resp), `Failed to execute action %q on service instance "%s/%s"`, action, instance.ServiceName, instance.Name)
+		return log.WrapError(err)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
 func (c *endpointClient) Status(ctx context.Context, instance *ServiceInstance, requestID string) (string, error) {
 	log.Debugf("Attempting to call status of service instance %q at %q api", instance.Name, instance.ServiceName)
 	var (
@@ -405,6 +557,46 @@ func (c *endpointClient) Plans(ctx context.Context, pool, requestID string) ([]P
 	return result, nil
 }
 
+// PlanSchemas returns the JSON schemas of the parameters accepted by the
+// plan, caching the result. Services that don't describe their parameters
+// may not implement the call, failed or empty responses are cached for a
+// short time so they are requested again soon.
+// The api should be prepared to receive the request,
+// like below:
+// GET /resources/plans/<plan>/schemas
+func (c *endpointClient) PlanSchemas(ctx context.Context, pool, plan, requestID string) (*osb.Schemas, error) {
+	cacheKey := schemaCacheKey(c.serviceName, pool, plan)
+	if schemas, ok := loadCachedSchemas(ctx, cacheKey); ok {
+		return schemas, nil
+	}
+	header, err := baseHeader(ctx, nil, nil, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if pool != "" {
+		header, err = poolMultiCluster.Header(ctx, pool, header)
+		if err != nil {
+			return nil, err
+		}
+	}
+	resp, err := c.issueRequest(ctx, "/resources/plans/"+url.PathEscape(plan)+"/schemas", http.MethodGet, nil, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		saveCachedSchemas(ctx, cacheKey, nil)
+		return nil, nil
+	}
+	var schemas *osb.Schemas
+	err = c.jsonFromResponse(resp, &schemas)
+	if err != nil {
+		return nil, err
+	}
+	saveCachedSchemas(ctx, cacheKey, schemas)
+	return schemas, nil
+}
+
 // Proxy is a proxy between tsuru and the service.
 // This method allow customized service methods.
 func (c *endpointClient) Proxy(ctx context.Context, opts *ProxyOpts) error {
@@ -490,12 +682,29 @@ func (c *endpointClient) issueRequest(ctx context.Context, path, method string,
 	}
 	req.SetBasicAuth(c.username, c.password)
 	req.Close = true
+	operation := requestOperation(method, path)
+	if err = c.breaker.allow(); err != nil {
+		operationErrors.WithLabelValues(c.serviceName, operation).Inc()
+		return nil, err
+	}
+	client := net.Dial15Full300ClientWithPool
+	if c.timeout > 0 {
+		clientWithTimeout := *client
+		clientWithTimeout.Timeout = c.timeout
+		client = &clientWithTimeout
+	}
 	t0 := time.Now()
-	resp, err := net.Dial15Full300ClientWithPool.Do(req)
-	requestLatencies.WithLabelValues(c.serviceName).Observe(time.Since(t0).Seconds())
+	resp, err := client.Do(req)
+	elapsed := time.Since(t0).Seconds()
+	requestLatencies.WithLabelValues(c.serviceName).Observe(elapsed)
+	operationLatencies.WithLabelValues(c.serviceName, operation).Observe(elapsed)
 	if err != nil {
 		requestErrors.WithLabelValues(c.serviceName).Inc()
 	}
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		operationErrors.WithLabelValues(c.serviceName, operation).Inc()
+	}
+	c.breaker.record(resp, err)
 	return resp, err
 }
 
@@ -539,7 +748,7 @@ func baseHeader(ctx context.Context, evt *event.Event, si *ServiceInstance, requ
 	return poolMultiCluster.Header(ctx, si.Pool, header)
 }
 
-func buildBindAppParams(ctx context.Context, evt *event.Event, app *appTypes.App, bindParams BindAppParameters) (url.Values, error) {
+func buildBindAppParams(ctx context.Context, evt *event.Event, instance *ServiceInstance, app *appTypes.App, bindParams BindAppParameters) (url.Values, error) {
 	if app == nil {
 		return nil, errors.New("app cannot be nil")
 	}
@@ -550,6 +759,10 @@ func buildBindAppParams(ctx context.Context, evt *event.Event, app *appTypes.App
 		params.Set("user", evt.Owner.Name)
 		params.Set("eventid", evt.UniqueID.Hex())
 	}
+	err := addInstancePoolParams(ctx, params, instance)
+	if err != nil {
+		return nil, err
+	}
 	appAddrs, err := servicemanager.App.GetAddresses(ctx, app)
 	if err != nil {
 		return nil, err
@@ -588,7 +801,36 @@ func buildBindAppParams(ctx context.Context, evt *event.Event, app *appTypes.App
 	return params, nil
 }
 
-func buildBindJobParams(ctx context.Context, evt *event.Event, job *jobTypes.Job) (url.Values, error) {
+// addInstancePoolParams tells the service API where a multi-cluster
+// instance lives, which may differ from the pool of the bound app or job when
+// the instance is shared across pools.
+func addInstancePoolParams(ctx context.Context, params url.Values, instance *ServiceInstance) error {
+	if instance == nil || instance.Pool == "" {
+		return nil
+	}
+	p, err := servicemanager.Pool.FindByName(ctx, instance.Pool)
+	if err != nil {
+		if err == provTypes.ErrPoolNotFound {
+			return nil
+		}
+		return err
+	}
+	if p == nil {
+		return nil
+	}
+	params.Set("instance-pool-name", p.Name)
+	c, err := servicemanager.Cluster.FindByPool(ctx, p.Provisioner, p.Name)
+	if err != nil || c == nil {
+		return nil
+	}
+	params.Set("instance-cluster-name", c.Name)
+	for _, addr := range c.Addresses {
+		params.Add("instance-cluster-addresses", addr)
+	}
+	return nil
+}
+
+func buildBindJobParams(ctx context.Context, evt *event.Event, instance *ServiceInstance, job *jobTypes.Job) (url.Values, error) {
 	if job == nil {
 		return nil, errors.New("job cannot be nil")
 	}
@@ -599,6 +841,10 @@ func buildBindJobParams(ctx context.Context, evt *event.Event, job *jobTypes.Job
 		params.Set("user", evt.Owner.Name)
 		params.Set("eventid", evt.UniqueID.Hex())
 	}
+	err := addInstancePoolParams(ctx, params, instance)
+	if err != nil {
+		return nil, err
+	}
 
 	p, err := servicemanager.Pool.FindByName(ctx, job.Pool)
 	if err != nil {
@@ -0,0 +1,162 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/hc"
+	"github.com/tsuru/tsuru/log"
+)
+
+const (
+	defaultHealthProbeInterval = time.Minute
+	defaultHealthProbeTimeout  = 10 * time.Second
+)
+
+var serviceAPIUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "tsuru_service_api_up",
+	Help: "Whether the last health probe of the service API endpoint succeeded.",
+}, []string{"service", "endpoint"})
+
+func init() {
+	prometheus.MustRegister(serviceAPIUp)
+}
+
+// healthProbeResults holds the failures found by the last health probe,
+// indexed by "<service> (<endpoint>)".
+var healthProbeResults = struct {
+	sync.RWMutex
+	failures map[string]string
+}{failures: map[string]string{}}
+
+// InitializeHealthProbe starts the worker that periodically probes every
+// service API endpoint and registers its results as a health checker.
+func InitializeHealthProbe() error {
+	interval := defaultHealthProbeInterval
+	if seconds, err := config.GetInt("service:health-probe:interval"); err == nil && seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	}
+	hc.AddChecker("Service APIs", healthProbeCheck)
+	probe := &healthProbe{once: &sync.Once{}, interval: interval}
+	probe.start()
+	shutdown.Register(probe)
+	return nil
+}
+
+type healthProbe struct {
+	once     *sync.Once
+	stopCh   chan struct{}
+	interval time.Duration
+}
+
+func (p *healthProbe) start() {
+	p.once.Do(func() {
+		p.stopCh = make(chan struct{})
+		go p.spin()
+	})
+}
+
+func (p *healthProbe) Shutdown(ctx context.Context) error {
+	if p.stopCh == nil {
+		return nil
+	}
+	p.stopCh <- struct{}{}
+	p.stopCh = nil
+	p.once = &sync.Once{}
+	return nil
+}
+
+func (p *healthProbe) spin() {
+	for {
+		if err := probeServices(context.Background()); err != nil {
+			log.Errorf("[service health probe] %v", err)
+		}
+
+		select {
+		case <-p.stopCh:
+			return
+		case <-time.After(p.interval):
+		}
+	}
+}
+
+func probeServices(ctx context.Context) error {
+	services, err := GetServices(ctx)
+	if err != nil {
+		return err
+	}
+	timeout := defaultHealthProbeTimeout
+	if seconds, err := config.GetInt("service:health-probe:timeout"); err == nil && seconds > 0 {
+		timeout = time.Duration(seconds) * time.Second
+	}
+	failures := map[string]string{}
+	serviceAPIUp.Reset()
+	for i := range services {
+		s := &services[i]
+		for endpointName := range s.Endpoint {
+			err := probeEndpoint(ctx, s, endpointName, timeout)
+			up := 1.0
+			if err != nil {
+				up = 0
+				failures[fmt.Sprintf("%s (%s)", s.Name, endpointName)] = err.Error()
+			}
+			serviceAPIUp.WithLabelValues(s.Name, endpointName).Set(up)
+		}
+	}
+	healthProbeResults.Lock()
+	healthProbeResults.failures = failures
+	healthProbeResults.Unlock()
+	return nil
+}
+
+func probeEndpoint(ctx context.Context, s *Service, endpointName string, timeout time.Duration) error {
+	cli, err := s.getClient(endpointName)
+	if err != nil {
+		return err
+	}
+	client, ok := cli.(*endpointClient)
+	if !ok {
+		return nil
+	}
+	// probes bypass the circuit breaker, they must neither be rejected while
+	// it's open nor count as failures of the service requests
+	probeClient := *client
+	probeClient.breaker = nil
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resp, err := probeClient.issueRequest(ctx, "/resources/plans", http.MethodGet, nil, make(http.Header))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func healthProbeCheck(ctx context.Context) error {
+	healthProbeResults.RLock()
+	defer healthProbeResults.RUnlock()
+	if len(healthProbeResults.failures) == 0 {
+		return nil
+	}
+	var msgs []string
+	for name, failure := range healthProbeResults.failures {
+		msgs = append(msgs, name+": "+failure)
+	}
+	sort.Strings(msgs)
+	return fmt.Errorf("%s", strings.Join(msgs, "; "))
+}
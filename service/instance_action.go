@@ -0,0 +1,114 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+)
+
+var ErrInstanceActionNotFound = errors.New("action not available for this service instance")
+
+// InstanceAction is an operation advertised by a service for its
+// instances, like backup, restore or snapshot.
+type InstanceAction struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Schema is the JSON schema of the action parameters.
+	Schema map[string]interface{} `json:"schema,omitempty"`
+}
+
+type ExecuteActionArgs struct {
+	Action     string
+	Parameters map[string]interface{}
+	Writer     io.Writer
+	Event      *event.Event
+	RequestID  string
+}
+
+// Actions returns the actions the service advertises for the instance.
+func (si *ServiceInstance) Actions(ctx context.Context, requestID string) ([]InstanceAction, error) {
+	s, err := Get(ctx, si.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := s.getClientForPool(ctx, si.Pool)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := endpoint.Actions(ctx, si, requestID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		return actions[i].Name < actions[j].Name
+	})
+	return actions, nil
+}
+
+// ExecuteAction runs one of the actions advertised for the instance,
+// writing the output of the service to args.Writer.
+func (si *ServiceInstance) ExecuteAction(ctx context.Context, args ExecuteActionArgs) error {
+	if err := si.checkProvisioned(); err != nil {
+		return err
+	}
+	s, err := Get(ctx, si.ServiceName)
+	if err != nil {
+		return err
+	}
+	endpoint, err := s.getClientForPool(ctx, si.Pool)
+	if err != nil {
+		return err
+	}
+	actions, err := endpoint.Actions(ctx, si, args.RequestID)
+	if err != nil {
+		return err
+	}
+	var action *InstanceAction
+	for i := range actions {
+		if actions[i].Name == args.Action {
+			action = &actions[i]
+			break
+		}
+	}
+	if action == nil {
+		return ErrInstanceActionNotFound
+	}
+	if err = validateActionParameters(action, args.Parameters); err != nil {
+		return err
+	}
+	w := args.Writer
+	if w == nil {
+		w = io.Discard
+	}
+	fmt.Fprintf(w, "---- Executing action %q on instance %q ----\n", action.Name, si.Name)
+	return endpoint.ExecuteAction(ctx, si, action.Name, args.Parameters, w, args.Event, args.RequestID)
+}
+
+// validateActionParameters checks the parameters against the schema
+// advertised with the action, the same way instance parameters are checked
+// against the plan schemas.
+func validateActionParameters(action *InstanceAction, params map[string]interface{}) error {
+	if len(action.Schema) == 0 {
+		return nil
+	}
+	msgs, err := validateParameters(action.Schema, params)
+	if err != nil {
+		return errors.Wrapf(err, "invalid schema for action %q", action.Name)
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return &tsuruErrors.ValidationError{
+		Message: fmt.Sprintf("invalid parameters for action %q: %s", action.Name, strings.Join(msgs, "; ")),
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/tsuru/db/storagev2"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	serviceTypes "github.com/tsuru/tsuru/types/service"
+	check "gopkg.in/check.v1"
+)
+
+const actionsJSON = `[
+	{"name": "snapshot", "description": "take a snapshot"},
+	{"name": "backup", "description": "backup to a bucket", "schema": {"type": "object", "required": ["bucket"], "properties": {"retention": {"type": "integer"}}}}
+]`
+
+func actionsHandler(c *check.C, executed *http.Request) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/resources/db/actions":
+			w.Write([]byte(actionsJSON))
+		case r.Method == http.MethodPost && r.URL.Path == "/resources/db/actions/backup":
+			err := r.ParseForm()
+			c.Assert(err, check.IsNil)
+			*executed = *r
+			w.Write([]byte("backup started\nbackup finished\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func (s *S) TestEndpointActions(c *check.C) {
+	var executed http.Request
+	ts := httptest.NewServer(actionsHandler(c, &executed))
+	defer ts.Close()
+	instance := ServiceInstance{Name: "db", ServiceName: "mysql"}
+	client := &endpointClient{endpoint: ts.URL, username: "user", password: "abcde"}
+	actions, err := client.Actions(context.TODO(), &instance, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(actions, check.DeepEquals, []InstanceAction{
+		{Name: "snapshot", Description: "take a snapshot"},
+		{Name: "backup", Description: "backup to a bucket", Schema: map[string]interface{}{
+			"type":       "object",
+			"required":   []interface{}{"bucket"},
+			"properties": map[string]interface{}{"retention": map[string]interface{}{"type": "integer"}},
+		}},
+	})
+}
+
+func (s *S) TestEndpointActionsNotSupported(c *check.C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+	instance := ServiceInstance{Name: "db", ServiceName: "mysql"}
+	client := &endpointClient{endpoint: ts.URL, username: "user", password: "abcde"}
+	actions, err := client.Actions(context.TODO(), &instance, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(actions, check.HasLen, 0)
+}
+
+func (s *S) TestEndpointExecuteAction(c *check.C) {
+	var executed http.Request
+	ts := httptest.NewServer(actionsHandler(c, &executed))
+	defer ts.Close()
+	instance := ServiceInstance{Name: "db", ServiceName: "mysql"}
+	client := &endpointClient{endpoint: ts.URL, username: "user", password: "abcde"}
+	evt := createEvt(c)
+	var buf bytes.Buffer
+	err := client.ExecuteAction(context.TODO(), &instance, "backup", map[string]interface{}{"bucket": "my-bucket"}, &buf, evt, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "backup started\nbackup finished\n")
+	c.Assert(executed.Form.Get("parameters.bucket"), check.Equals, "my-bucket")
+	c.Assert(executed.Form.Get("user"), check.Equals, "my@user")
+	c.Assert(executed.Form.Get("eventid"), check.Equals, evt.UniqueID.Hex())
+	err = client.ExecuteAction(context.TODO(), &instance, "restore", nil, &buf, evt, "")
+	c.Assert(err, check.Equals, ErrInstanceActionNotFound)
+}
+
+func (s *S) TestServiceInstanceExecuteAction(c *check.C) {
+	var executed http.Request
+	ts := httptest.NewServer(actionsHandler(c, &executed))
+	defer ts.Close()
+	srvc := Service{Name: "mysql", Endpoint: map[string]string{"production": ts.URL}, Password: "s3cr3t"}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srvc)
+	c.Assert(err, check.IsNil)
+	si := ServiceInstance{Name: "db", ServiceName: "mysql"}
+	actions, err := si.Actions(context.TODO(), "")
+	c.Assert(err, check.IsNil)
+	c.Assert(actions, check.HasLen, 2)
+	c.Assert(actions[0].Name, check.Equals, "backup")
+	c.Assert(actions[1].Name, check.Equals, "snapshot")
+	var buf bytes.Buffer
+	err = si.ExecuteAction(context.TODO(), ExecuteActionArgs{
+		Action:     "backup",
+		Parameters: map[string]interface{}{"bucket": "my-bucket"},
+		Writer:     &buf,
+		Event:      createEvt(c),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "---- Executing action \"backup\" on instance \"db\" ----\nbackup started\nbackup finished\n")
+}
+
+func (s *S) TestServiceInstanceExecuteActionValidation(c *check.C) {
+	var executed http.Request
+	ts := httptest.NewServer(actionsHandler(c, &executed))
+	defer ts.Close()
+	srvc := Service{Name: "mysql", Endpoint: map[string]string{"production": ts.URL}, Password: "s3cr3t"}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srvc)
+	c.Assert(err, check.IsNil)
+	si := ServiceInstance{Name: "db", ServiceName: "mysql"}
+	err = si.ExecuteAction(context.TODO(), ExecuteActionArgs{Action: "restore", Event: createEvt(c)})
+	c.Assert(err, check.Equals, ErrInstanceActionNotFound)
+	err = si.ExecuteAction(context.TODO(), ExecuteActionArgs{Action: "backup", Event: createEvt(c)})
+	c.Assert(err, check.FitsTypeOf, &tsuruErrors.ValidationError{})
+	c.Assert(err, check.ErrorMatches, `invalid parameters for action "backup": bucket is required`)
+	err = si.ExecuteAction(context.TODO(), ExecuteActionArgs{
+		Action:     "backup",
+		Parameters: map[string]interface{}{"bucket": "my-bucket", "retention": "forever"},
+		Event:      createEvt(c),
+	})
+	c.Assert(err, check.ErrorMatches, `invalid parameters for action "backup": retention must be of type integer: "string"`)
+	c.Assert(executed.URL, check.IsNil)
+}
+
+func (s *S) TestBrokerClientActions(c *check.C) {
+	var body map[string]interface{}
+	var header http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v2/service_instances/my-uuid/actions":
+			w.Write([]byte(`{"actions": [{"name": "snapshot"}]}`))
+		case "POST /v2/service_instances/my-uuid/actions/snapshot":
+			err := json.NewDecoder(r.Body).Decode(&body)
+			c.Assert(err, check.IsNil)
+			w.Write([]byte("snapshot created\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	broker := serviceTypes.Broker{
+		Name: "broker",
+		URL:  ts.URL,
+		Config: serviceTypes.BrokerConfig{
+			AuthConfig: &serviceTypes.AuthConfig{
+				BearerConfig: &serviceTypes.BearerConfig{Token: "my-token"},
+			},
+			Context: map[string]interface{}{"platform": "tsuru"},
+		},
+	}
+	client, err := newClient(broker, "service")
+	c.Assert(err, check.IsNil)
+	instance := ServiceInstance{
+		Name:        "db",
+		ServiceName: "broker::service",
+		BrokerData:  &BrokerInstanceData{UUID: "my-uuid", ServiceID: "s1", PlanID: "p1"},
+	}
+	actions, err := client.Actions(context.TODO(), &instance, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(actions, check.DeepEquals, []InstanceAction{{Name: "snapshot"}})
+	c.Assert(header.Get("Authorization"), check.Equals, "Bearer my-token")
+	c.Assert(header.Get("X-Broker-API-Version"), check.Not(check.Equals), "")
+	evt := createEvt(c)
+	var buf bytes.Buffer
+	err = client.ExecuteAction(context.TODO(), &instance, "snapshot", map[string]interface{}{"name": "daily"}, &buf, evt, "request-id")
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "snapshot created\n")
+	c.Assert(body, check.DeepEquals, map[string]interface{}{
+		"service_id": "s1",
+		"plan_id":    "p1",
+		"parameters": map[string]interface{}{"name": "daily"},
+		"context": map[string]interface{}{
+			"request_id": "request-id",
+			"event_id":   evt.UniqueID.Hex(),
+			"platform":   "tsuru",
+		},
+	})
+	err = client.ExecuteAction(context.TODO(), &instance, "restore", nil, &buf, evt, "request-id")
+	c.Assert(err, check.Equals, ErrInstanceActionNotFound)
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 
 	osb "github.com/pmorie/go-open-service-broker-client/v2"
+	"github.com/tsuru/tsuru/log"
 )
 
 // Plan represents a service plan
@@ -29,6 +30,25 @@ func GetPlansByService(ctx context.Context, svc Service, pool, requestID string)
 	return plans, nil
 }
 
+// LoadPlanSchemas sets the parameter schemas of the plans not carrying them
+// in the service catalog. Schemas that can't be fetched are left empty.
+func LoadPlanSchemas(ctx context.Context, svc Service, pool string, plans []Plan, requestID string) error {
+	endpoint, err := svc.getClientForPool(ctx, pool)
+	if err != nil {
+		return err
+	}
+	for i := range plans {
+		if plans[i].Schemas != nil {
+			continue
+		}
+		plans[i].Schemas, err = endpoint.PlanSchemas(ctx, pool, plans[i].Name, requestID)
+		if err != nil {
+			log.Errorf("[service %s] unable to get schemas of plan %q: %v", svc.Name, plans[i].Name, err)
+		}
+	}
+	return nil
+}
+
 func GetPlanByServiceAndPlanName(ctx context.Context, svc Service, pool, planName, requestID string) (Plan, error) {
 	plans, err := GetPlansByService(ctx, svc, pool, requestID)
 	if err != nil {
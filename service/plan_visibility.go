@@ -0,0 +1,111 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/servicemanager"
+	serviceTypes "github.com/tsuru/tsuru/types/service"
+)
+
+// PlanAudience describes who is trying to use a plan. Global audiences see
+// every plan regardless of team rules. An empty Pool means the pool is not
+// known yet, in which case pool rules are checked when binding.
+type PlanAudience struct {
+	Global bool
+	Teams  []string
+	Pool   string
+}
+
+// VisiblePlans filters the plans of a brokered service according to the
+// visibility rules of its broker. Plans of regular services are returned
+// untouched.
+func VisiblePlans(ctx context.Context, svc Service, plans []Plan, audience PlanAudience) ([]Plan, error) {
+	rules, serviceName, err := planVisibilityRules(ctx, svc.Name)
+	if err != nil || len(rules) == 0 {
+		return plans, err
+	}
+	result := []Plan{}
+	for _, plan := range plans {
+		if planVisible(rules, serviceName, plan.Name, audience) {
+			result = append(result, plan)
+		}
+	}
+	return result, nil
+}
+
+func planVisibilityRules(ctx context.Context, name string) ([]serviceTypes.PlanVisibility, string, error) {
+	if !isBrokeredService(name) {
+		return nil, "", nil
+	}
+	brokerName, serviceName, err := splitBrokerService(name)
+	if err != nil {
+		return nil, "", err
+	}
+	broker, err := servicemanager.ServiceBroker.Find(ctx, brokerName)
+	if err != nil {
+		return nil, "", err
+	}
+	return broker.PlanVisibility, serviceName, nil
+}
+
+func planVisible(rules []serviceTypes.PlanVisibility, serviceName, plan string, audience PlanAudience) bool {
+	matched := false
+	for _, rule := range rules {
+		if !rule.Matches(serviceName, plan) {
+			continue
+		}
+		matched = true
+		teamAllowed := audience.Global || len(rule.Teams) == 0 || hasAnyString(rule.Teams, audience.Teams)
+		poolAllowed := audience.Pool == "" || len(rule.Pools) == 0 || hasString(rule.Pools, audience.Pool)
+		if teamAllowed && poolAllowed {
+			return true
+		}
+	}
+	return !matched
+}
+
+func hasAnyString(slice, elements []string) bool {
+	for _, e := range elements {
+		if hasString(slice, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePlanVisibility ensures the plan of the instance is available to its
+// team owner and pool.
+func validatePlanVisibility(ctx context.Context, si ServiceInstance) error {
+	if si.PlanName == "" {
+		return nil
+	}
+	return checkPlanAudience(ctx, si, PlanAudience{Teams: []string{si.TeamOwner}, Pool: si.Pool},
+		fmt.Sprintf("plan %q is not available for team %q", si.PlanName, si.TeamOwner))
+}
+
+// validatePlanPool ensures the plan of the instance may be used by apps and
+// jobs running in the given pool.
+func validatePlanPool(ctx context.Context, si *ServiceInstance, pool string) error {
+	if si.PlanName == "" || pool == "" {
+		return nil
+	}
+	return checkPlanAudience(ctx, *si, PlanAudience{Global: true, Pool: pool},
+		fmt.Sprintf("plan %q is not available in pool %q", si.PlanName, pool))
+}
+
+func checkPlanAudience(ctx context.Context, si ServiceInstance, audience PlanAudience, msg string) error {
+	rules, serviceName, err := planVisibilityRules(ctx, si.ServiceName)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+	if planVisible(rules, serviceName, si.PlanName, audience) {
+		return nil
+	}
+	return &tsuruErrors.ValidationError{Message: msg}
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+
+	appTypes "github.com/tsuru/tsuru/types/app"
+	serviceTypes "github.com/tsuru/tsuru/types/service"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) mockPlanVisibility(rules ...serviceTypes.PlanVisibility) {
+	s.mockService.ServiceBroker.OnFind = func(name string) (serviceTypes.Broker, error) {
+		return serviceTypes.Broker{Name: name, PlanVisibility: rules}, nil
+	}
+}
+
+func (s *S) TestVisiblePlans(c *check.C) {
+	s.mockPlanVisibility(
+		serviceTypes.PlanVisibility{Service: "mysql", Plan: "large", Teams: []string{"team1"}},
+		serviceTypes.PlanVisibility{Service: "mysql", Plan: "regional", Pools: []string{"pool1"}},
+		serviceTypes.PlanVisibility{Service: "redis", Teams: []string{"nobody"}},
+	)
+	plans := []Plan{{Name: "small"}, {Name: "large"}, {Name: "regional"}}
+	svc := Service{Name: "aws::mysql"}
+	visible, err := VisiblePlans(context.TODO(), svc, plans, PlanAudience{Teams: []string{"team2"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(visible, check.DeepEquals, []Plan{{Name: "small"}, {Name: "regional"}})
+	visible, err = VisiblePlans(context.TODO(), svc, plans, PlanAudience{Teams: []string{"team2", "team1"}, Pool: "pool2"})
+	c.Assert(err, check.IsNil)
+	c.Assert(visible, check.DeepEquals, []Plan{{Name: "small"}, {Name: "large"}})
+	visible, err = VisiblePlans(context.TODO(), svc, plans, PlanAudience{Global: true, Pool: "pool1"})
+	c.Assert(err, check.IsNil)
+	c.Assert(visible, check.DeepEquals, plans)
+	visible, err = VisiblePlans(context.TODO(), Service{Name: "mysql"}, plans, PlanAudience{})
+	c.Assert(err, check.IsNil)
+	c.Assert(visible, check.DeepEquals, plans)
+}
+
+func (s *S) TestCreateServiceInstanceWithHiddenPlan(c *check.C) {
+	s.mockPlanVisibility(serviceTypes.PlanVisibility{Plan: "large", Teams: []string{"team1"}})
+	srv := Service{Name: "aws::mysql"}
+	instance := ServiceInstance{Name: "db", PlanName: "large", TeamOwner: s.team.Name}
+	err := CreateServiceInstance(context.TODO(), instance, &srv, createEvt(c), "")
+	c.Assert(err, check.ErrorMatches, `plan "large" is not available for team "raul"`)
+}
+
+func (s *S) TestUpdateServiceInstanceWithHiddenPlan(c *check.C) {
+	s.mockPlanVisibility(serviceTypes.PlanVisibility{Plan: "large", Teams: []string{"team1"}})
+	srv := Service{Name: "aws::mysql"}
+	instance := ServiceInstance{Name: "db", ServiceName: "aws::mysql", PlanName: "small", TeamOwner: s.team.Name}
+	updateData := instance
+	updateData.PlanName = "large"
+	err := instance.Update(context.TODO(), srv, updateData, createEvt(c), "")
+	c.Assert(err, check.ErrorMatches, `plan "large" is not available for team "raul"`)
+}
+
+func (s *S) TestUpdateServiceInstanceKeepingHiddenPlan(c *check.C) {
+	s.mockPlanVisibility(serviceTypes.PlanVisibility{Plan: "large", Teams: []string{"team1"}})
+	srv := Service{Name: "aws::mysql"}
+	instance := ServiceInstance{Name: "db", ServiceName: "aws::mysql", PlanName: "large", TeamOwner: s.team.Name}
+	updateData := instance
+	updateData.Description = "new description"
+	err := instance.Update(context.TODO(), srv, updateData, createEvt(c), "")
+	c.Assert(err, check.Not(check.ErrorMatches), `plan .* is not available .*`)
+}
+
+func (s *S) TestBindAppPlanNotAvailableInPool(c *check.C) {
+	s.mockPlanVisibility(serviceTypes.PlanVisibility{Plan: "regional", Pools: []string{"pool1"}})
+	instance := ServiceInstance{Name: "db", ServiceName: "aws::mysql", PlanName: "regional"}
+	err := instance.BindApp(context.TODO(), &appTypes.App{Name: "myapp", Pool: "pool2"}, nil, false, nil, createEvt(c), "")
+	c.Assert(err, check.ErrorMatches, `plan "regional" is not available in pool "pool2"`)
+}
@@ -0,0 +1,222 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	osb "github.com/pmorie/go-open-service-broker-client/v2"
+	"github.com/tsuru/config"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/storage"
+	"github.com/tsuru/tsuru/types/cache"
+	openapiErrors "k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+const (
+	defaultSchemaCacheExpiration         = time.Hour
+	defaultSchemaNegativeCacheExpiration = time.Minute
+)
+
+// validateInstanceParameters checks the parameters of the instance against
+// the schema published by the service for its plan, either on creation or
+// on update. Services that don't publish schemas accept any parameter.
+func validateInstanceParameters(ctx context.Context, s *Service, si ServiceInstance, update bool, requestID string) error {
+	if si.PlanName == "" {
+		return nil
+	}
+	endpoint, err := s.getClientForPool(ctx, si.Pool)
+	if err != nil {
+		return err
+	}
+	schemas, err := endpoint.PlanSchemas(ctx, si.Pool, si.PlanName, requestID)
+	if err != nil {
+		return err
+	}
+	parameters := instanceParametersSchema(schemas, update)
+	if parameters == nil {
+		return nil
+	}
+	msgs, err := validateParameters(parameters, si.Parameters)
+	if err != nil {
+		return errors.Wrapf(err, "invalid schema for plan %q", si.PlanName)
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return &tsuruErrors.ValidationError{
+		Message: fmt.Sprintf("invalid parameters for plan %q: %s", si.PlanName, strings.Join(msgs, "; ")),
+	}
+}
+
+// validateParameters checks the parameters against a JSON schema, returning
+// the violations found. The error is only set when the schema itself is
+// invalid.
+func validateParameters(rawSchema interface{}, params map[string]interface{}) ([]string, error) {
+	data, err := json.Marshal(rawSchema)
+	if err != nil {
+		return nil, err
+	}
+	var schema spec.Schema
+	if err = json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	err = validate.AgainstSchema(&schema, coerceParameters(&schema, params), strfmt.Default)
+	if err == nil {
+		return nil, nil
+	}
+	return schemaErrors(err), nil
+}
+
+func instanceParametersSchema(schemas *osb.Schemas, update bool) interface{} {
+	if schemas == nil || schemas.ServiceInstance == nil {
+		return nil
+	}
+	input := schemas.ServiceInstance.Create
+	if update {
+		input = schemas.ServiceInstance.Update
+	}
+	if input == nil {
+		return nil
+	}
+	return input.Parameters
+}
+
+// coerceParameters converts string parameters to the type declared in the
+// schema properties. Parameters sent as form values are always strings, and
+// the service API receives them the same way, so the conversion is only
+// used for validation.
+func coerceParameters(schema *spec.Schema, params map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(params))
+	for name, value := range params {
+		result[name] = value
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		property, ok := schema.Properties[name]
+		if !ok {
+			continue
+		}
+		switch {
+		case property.Type.Contains("integer"):
+			if v, err := strconv.ParseInt(str, 10, 64); err == nil {
+				result[name] = v
+			}
+		case property.Type.Contains("number"):
+			if v, err := strconv.ParseFloat(str, 64); err == nil {
+				result[name] = v
+			}
+		case property.Type.Contains("boolean"):
+			if v, err := strconv.ParseBool(str); err == nil {
+				result[name] = v
+			}
+		}
+	}
+	return result
+}
+
+func schemaErrors(err error) []string {
+	composite, ok := err.(*openapiErrors.CompositeError)
+	if !ok {
+		msg := strings.Replace(err.Error(), " in body", "", 1)
+		return []string{strings.TrimPrefix(msg, ".")}
+	}
+	var msgs []string
+	for _, e := range composite.Errors {
+		msgs = append(msgs, schemaErrors(e)...)
+	}
+	return msgs
+}
+
+func schemaCacheKey(serviceName, pool, plan string) string {
+	return serviceName + "/" + pool + "/" + plan
+}
+
+func schemaCacheStorage() cache.CacheStorage {
+	dbDriver, err := storage.GetCurrentDbDriver()
+	if err != nil {
+		dbDriver, err = storage.GetDefaultDbDriver()
+		if err != nil {
+			return nil
+		}
+	}
+	return dbDriver.ServiceSchemaCacheStorage
+}
+
+func loadCachedSchemas(ctx context.Context, key string) (*osb.Schemas, bool) {
+	cacheStorage := schemaCacheStorage()
+	if cacheStorage == nil {
+		return nil, false
+	}
+	entry, err := cacheStorage.Get(ctx, key)
+	if err != nil {
+		if err != cache.ErrEntryNotFound {
+			log.Errorf("unable to load cached schemas %q: %v", key, err)
+		}
+		return nil, false
+	}
+	if !entry.ExpireAt.IsZero() && entry.ExpireAt.Before(time.Now()) {
+		return nil, false
+	}
+	var schemas *osb.Schemas
+	if err = json.Unmarshal([]byte(entry.Value), &schemas); err != nil {
+		return nil, false
+	}
+	return schemas, true
+}
+
+// saveCachedSchemas caches the schemas published by the service. Missing or
+// empty schemas are cached for a shorter time, as the service may not be
+// ready to describe the plan yet.
+func saveCachedSchemas(ctx context.Context, key string, schemas *osb.Schemas) {
+	cacheStorage := schemaCacheStorage()
+	if cacheStorage == nil {
+		return
+	}
+	expiration := schemaCacheExpiration()
+	if schemas == nil || (schemas.ServiceInstance == nil && schemas.ServiceBinding == nil) {
+		schemas = nil
+		expiration = schemaNegativeCacheExpiration()
+	}
+	data, err := json.Marshal(schemas)
+	if err != nil {
+		return
+	}
+	err = cacheStorage.Put(ctx, cache.CacheEntry{
+		Key:      key,
+		Value:    string(data),
+		ExpireAt: time.Now().Add(expiration),
+	})
+	if err != nil {
+		log.Errorf("unable to cache schemas %q: %v", key, err)
+	}
+}
+
+func schemaCacheExpiration() time.Duration {
+	seconds, err := config.GetInt("service:schema-cache-expiration")
+	if err != nil || seconds <= 0 {
+		return defaultSchemaCacheExpiration
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func schemaNegativeCacheExpiration() time.Duration {
+	seconds, err := config.GetInt("service:schema-negative-cache-expiration")
+	if err != nil || seconds <= 0 {
+		return defaultSchemaNegativeCacheExpiration
+	}
+	return time.Duration(seconds) * time.Second
+}
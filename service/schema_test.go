@@ -0,0 +1,255 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	osb "github.com/pmorie/go-open-service-broker-client/v2"
+	osbfake "github.com/pmorie/go-open-service-broker-client/v2/fake"
+	"github.com/tsuru/tsuru/db/storagev2"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	serviceTypes "github.com/tsuru/tsuru/types/service"
+	check "gopkg.in/check.v1"
+)
+
+const planSchemasJSON = `{
+	"service_instance": {
+		"create": {
+			"parameters": {
+				"type": "object",
+				"required": ["size"],
+				"properties": {
+					"size": {"type": "integer", "minimum": 1},
+					"engine": {"type": "string", "enum": ["mysql", "postgres"]}
+				}
+			}
+		},
+		"update": {
+			"parameters": {
+				"type": "object",
+				"properties": {
+					"size": {"type": "integer", "minimum": 1}
+				},
+				"additionalProperties": false
+			}
+		}
+	}
+}`
+
+func schemasHandler(schemaRequests *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/resources/plans":
+			w.Write([]byte(`[{"name": "small", "description": "small plan"}, {"name": "free", "description": "free plan"}]`))
+		case "/resources/plans/small/schemas":
+			atomic.AddInt32(schemaRequests, 1)
+			w.Write([]byte(planSchemasJSON))
+		case "/resources/plans/free/schemas":
+			atomic.AddInt32(schemaRequests, 1)
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}
+}
+
+func (s *S) TestLoadPlanSchemas(c *check.C) {
+	var schemaRequests int32
+	ts := httptest.NewServer(schemasHandler(&schemaRequests))
+	defer ts.Close()
+	srv := Service{Name: "mysql", Endpoint: map[string]string{"production": ts.URL}, Password: "abcde"}
+	plans, err := GetPlansByService(context.TODO(), srv, "", "")
+	c.Assert(err, check.IsNil)
+	c.Assert(plans, check.HasLen, 2)
+	c.Assert(plans[0].Schemas, check.IsNil)
+	c.Assert(plans[1].Schemas, check.IsNil)
+	c.Assert(atomic.LoadInt32(&schemaRequests), check.Equals, int32(0))
+	err = LoadPlanSchemas(context.TODO(), srv, "", plans, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(plans[0].Schemas, check.NotNil)
+	c.Assert(plans[0].Schemas.ServiceInstance.Create.Parameters, check.DeepEquals, map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"size"},
+		"properties": map[string]interface{}{
+			"size":   map[string]interface{}{"type": "integer", "minimum": float64(1)},
+			"engine": map[string]interface{}{"type": "string", "enum": []interface{}{"mysql", "postgres"}},
+		},
+	})
+	c.Assert(plans[1].Schemas, check.IsNil)
+	c.Assert(atomic.LoadInt32(&schemaRequests), check.Equals, int32(2))
+	plans, err = GetPlansByService(context.TODO(), srv, "", "")
+	c.Assert(err, check.IsNil)
+	err = LoadPlanSchemas(context.TODO(), srv, "", plans, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(plans[0].Schemas, check.NotNil)
+	c.Assert(plans[1].Schemas, check.IsNil)
+	// the not found response of the free plan is cached as well
+	c.Assert(atomic.LoadInt32(&schemaRequests), check.Equals, int32(2))
+}
+
+func (s *S) TestEndpointPlanSchemasCachesFailuresBriefly(c *check.C) {
+	var schemaRequests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&schemaRequests, 1) {
+		case 1:
+			w.WriteHeader(http.StatusInternalServerError)
+		case 2:
+			w.Write([]byte(`{}`))
+		default:
+			w.Write([]byte(planSchemasJSON))
+		}
+	}))
+	defer ts.Close()
+	client := &endpointClient{serviceName: "mysql", endpoint: ts.URL, username: "user", password: "abcde"}
+	cacheKey := schemaCacheKey("mysql", "", "small")
+	expireCached := func() {
+		entry, err := schemaCacheStorage().Get(context.TODO(), cacheKey)
+		c.Assert(err, check.IsNil)
+		c.Assert(time.Until(entry.ExpireAt) <= defaultSchemaNegativeCacheExpiration, check.Equals, true)
+		entry.ExpireAt = time.Now().Add(-time.Second)
+		err = schemaCacheStorage().Put(context.TODO(), entry)
+		c.Assert(err, check.IsNil)
+	}
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			schemas, err := client.PlanSchemas(context.TODO(), "", "small", "")
+			c.Assert(err, check.IsNil)
+			c.Assert(schemas, check.IsNil)
+		}
+		c.Assert(atomic.LoadInt32(&schemaRequests), check.Equals, int32(i+1))
+		expireCached()
+	}
+	for i := 0; i < 2; i++ {
+		schemas, err := client.PlanSchemas(context.TODO(), "", "small", "")
+		c.Assert(err, check.IsNil)
+		c.Assert(schemas.ServiceInstance, check.NotNil)
+	}
+	c.Assert(atomic.LoadInt32(&schemaRequests), check.Equals, int32(3))
+}
+
+func (s *S) TestCreateServiceInstanceValidatesParameters(c *check.C) {
+	var schemaRequests int32
+	ts := httptest.NewServer(schemasHandler(&schemaRequests))
+	defer ts.Close()
+	srv := Service{Name: "mysql", Endpoint: map[string]string{"production": ts.URL}, Password: "s3cr3t"}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srv)
+	c.Assert(err, check.IsNil)
+	instance := ServiceInstance{
+		Name:       "db",
+		PlanName:   "small",
+		TeamOwner:  s.team.Name,
+		Parameters: map[string]interface{}{"size": "0", "engine": "oracle"},
+	}
+	err = CreateServiceInstance(context.TODO(), instance, &srv, createEvt(c), "")
+	c.Assert(err, check.FitsTypeOf, &tsuruErrors.ValidationError{})
+	c.Assert(err, check.ErrorMatches, `invalid parameters for plan "small": .*size should be greater than or equal to 1.*`)
+	c.Assert(err, check.ErrorMatches, `.*engine should be one of \[mysql postgres\].*`)
+	_, err = GetServiceInstance(context.TODO(), "mysql", "db")
+	c.Assert(err, check.Equals, ErrServiceInstanceNotFound)
+	instance.Parameters = nil
+	err = CreateServiceInstance(context.TODO(), instance, &srv, createEvt(c), "")
+	c.Assert(err, check.ErrorMatches, `invalid parameters for plan "small": size is required`)
+	instance.Parameters = map[string]interface{}{"size": "10", "engine": "mysql"}
+	err = CreateServiceInstance(context.TODO(), instance, &srv, createEvt(c), "")
+	c.Assert(err, check.IsNil)
+	si, err := GetServiceInstance(context.TODO(), "mysql", "db")
+	c.Assert(err, check.IsNil)
+	c.Assert(si.Parameters, check.DeepEquals, map[string]interface{}{"size": "10", "engine": "mysql"})
+	c.Assert(atomic.LoadInt32(&schemaRequests), check.Equals, int32(1))
+}
+
+func (s *S) TestCreateServiceInstanceWithoutSchema(c *check.C) {
+	var schemaRequests int32
+	ts := httptest.NewServer(schemasHandler(&schemaRequests))
+	defer ts.Close()
+	srv := Service{Name: "mysql", Endpoint: map[string]string{"production": ts.URL}, Password: "s3cr3t"}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srv)
+	c.Assert(err, check.IsNil)
+	instance := ServiceInstance{
+		Name:       "db",
+		PlanName:   "free",
+		TeamOwner:  s.team.Name,
+		Parameters: map[string]interface{}{"anything": "goes"},
+	}
+	err = CreateServiceInstance(context.TODO(), instance, &srv, createEvt(c), "")
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestUpdateServiceInstanceValidatesParameters(c *check.C) {
+	var schemaRequests int32
+	ts := httptest.NewServer(schemasHandler(&schemaRequests))
+	defer ts.Close()
+	srv := Service{Name: "mysql", Endpoint: map[string]string{"production": ts.URL}, Password: "s3cr3t"}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srv)
+	c.Assert(err, check.IsNil)
+	instance := ServiceInstance{
+		Name:        "db",
+		ServiceName: "mysql",
+		PlanName:    "small",
+		TeamOwner:   s.team.Name,
+		Teams:       []string{s.team.Name},
+		Parameters:  map[string]interface{}{"size": "10"},
+	}
+	collection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = collection.InsertOne(context.TODO(), instance)
+	c.Assert(err, check.IsNil)
+	updateData := instance
+	updateData.Parameters = map[string]interface{}{"engine": "postgres"}
+	err = instance.Update(context.TODO(), srv, updateData, createEvt(c), "")
+	c.Assert(err, check.ErrorMatches, `invalid parameters for plan "small": engine is a forbidden property`)
+	updateData.Parameters = map[string]interface{}{"size": 20}
+	err = instance.Update(context.TODO(), srv, updateData, createEvt(c), "")
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestBrokerClientPlanSchemas(c *check.C) {
+	schemas := &osb.Schemas{
+		ServiceInstance: &osb.ServiceInstanceSchema{
+			Create: &osb.InputParametersSchema{
+				Parameters: map[string]interface{}{"type": "object", "required": []interface{}{"region"}},
+			},
+		},
+	}
+	config := osbfake.FakeClientConfiguration{
+		CatalogReaction: &osbfake.CatalogReaction{Response: &osb.CatalogResponse{
+			Services: []osb.Service{
+				{Name: "service", Plans: []osb.Plan{{Name: "plan1", Schemas: schemas}, {Name: "plan2"}}},
+			},
+		}},
+	}
+	ClientFactory = osbfake.NewFakeClientFunc(config)
+	s.mockService.ServiceBroker.OnFind = func(name string) (serviceTypes.Broker, error) {
+		return serviceTypes.Broker{Name: name}, nil
+	}
+	client, err := newClient(serviceTypes.Broker{Name: "broker"}, "service")
+	c.Assert(err, check.IsNil)
+	result, err := client.PlanSchemas(context.TODO(), "", "plan1", "")
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, schemas)
+	result, err = client.PlanSchemas(context.TODO(), "", "plan2", "")
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.IsNil)
+	_, err = client.PlanSchemas(context.TODO(), "", "plan3", "")
+	c.Assert(err, check.ErrorMatches, "invalid plan: plan3")
+	srv := Service{Name: "broker::service"}
+	instance := ServiceInstance{Name: "db", ServiceName: "broker::service", PlanName: "plan1", TeamOwner: s.team.Name}
+	err = validateInstanceParameters(context.TODO(), &srv, instance, false, "")
+	c.Assert(err, check.ErrorMatches, `invalid parameters for plan "plan1": region is required`)
+	instance.Parameters = map[string]interface{}{"region": "us-east-1"}
+	err = validateInstanceParameters(context.TODO(), &srv, instance, false, "")
+	c.Assert(err, check.IsNil)
+}
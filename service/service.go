@@ -7,10 +7,13 @@ package service
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"regexp"
+	"time"
 
 	"github.com/pkg/errors"
+	osb "github.com/pmorie/go-open-service-broker-client/v2"
 	"github.com/tsuru/tsuru/db/storagev2"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/event"
@@ -39,6 +42,12 @@ type Service struct {
 	//
 	// This field is immutable (after creating Service).
 	IsMultiCluster bool `bson:"is_multi_cluster"`
+	// Timeout is the maximum duration, in seconds, of each request to the
+	// service API. Zero keeps the default HTTP client timeouts.
+	Timeout int `bson:"timeout,omitempty"`
+	// CircuitBreaker configures when requests to the service API fail fast
+	// after consecutive failures.
+	CircuitBreaker CircuitBreakerConfig `bson:"circuit_breaker,omitempty"`
 }
 
 type BindAppParameters map[string]interface{}
@@ -61,9 +70,14 @@ type ServiceClient interface {
 	BindJob(ctx context.Context, instance *ServiceInstance, job *jobTypes.Job, evt *event.Event, requestID string) (map[string]string, error)
 	UnbindApp(ctx context.Context, instance *ServiceInstance, app *appTypes.App, evt *event.Event, requestID string) error
 	UnbindJob(ctx context.Context, instance *ServiceInstance, job *jobTypes.Job, evt *event.Event, requestID string) error
+	RotateCredentials(ctx context.Context, instance *ServiceInstance, evt *event.Event, requestID string) (*CredentialRotation, error)
+	RevokeCredentials(ctx context.Context, instance *ServiceInstance, rotation *CredentialRotation, evt *event.Event, requestID string) error
+	Actions(ctx context.Context, instance *ServiceInstance, requestID string) ([]InstanceAction, error)
+	ExecuteAction(ctx context.Context, instance *ServiceInstance, action string, params map[string]interface{}, w io.Writer, evt *event.Event, requestID string) error
 	Status(ctx context.Context, instance *ServiceInstance, requestID string) (string, error)
 	Info(ctx context.Context, instance *ServiceInstance, requestID string) ([]map[string]string, error)
 	Plans(ctx context.Context, pool, requestID string) ([]Plan, error)
+	PlanSchemas(ctx context.Context, pool, plan, requestID string) (*osb.Schemas, error)
 	Proxy(ctx context.Context, opts *ProxyOpts) error
 }
 
@@ -334,7 +348,14 @@ func (s *Service) getClient(endpoints ...string) (ServiceClient, error) {
 			if p := schemeRegexp.MatchString(e); !p {
 				e = "http://" + e
 			}
-			cli := &endpointClient{serviceName: s.Name, endpoint: e, username: s.getUsername(), password: s.Password}
+			cli := &endpointClient{
+				serviceName: s.Name,
+				endpoint:    e,
+				username:    s.getUsername(),
+				password:    s.Password,
+				timeout:     time.Duration(s.Timeout) * time.Second,
+				breaker:     breakerFor(s.Name, e, s.CircuitBreaker),
+			}
 			return cli, nil
 		} else {
 			err = errors.New("Unknown endpoint: " + endpoint)
@@ -366,6 +387,12 @@ func (s *Service) validate(ctx context.Context, skipName bool) (err error) {
 	if len(s.Endpoint) == 0 {
 		return fmt.Errorf("At least one endpoint is required")
 	}
+	if s.Timeout < 0 {
+		return fmt.Errorf("Service timeout cannot be negative")
+	}
+	if s.CircuitBreaker.Threshold < 0 || s.CircuitBreaker.CooldownSeconds < 0 {
+		return fmt.Errorf("Circuit breaker threshold and cooldown cannot be negative")
+	}
 	return s.validateOwnerTeams(ctx)
 }
 
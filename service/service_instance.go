@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	osb "github.com/pmorie/go-open-service-broker-client/v2"
 	"github.com/tsuru/tsuru/action"
 	"github.com/tsuru/tsuru/db/storagev2"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
@@ -43,7 +44,9 @@ var (
 	ErrMultiClusterViolatingConstraint          = errors.New("multi-cluster service instance is not allowed in this pool")
 	ErrMultiClusterPoolDoesNotMatch             = errors.New("pools between app and multi-cluster service instance does not match")
 	ErrRegularServiceInstanceCannotBelongToPool = errors.New("regular (non-multi-cluster) service instance cannot belong to a pool")
+	ErrRegularServiceInstanceCannotBeShared     = errors.New("regular (non-multi-cluster) service instance cannot be shared with other pools")
 	ErrRevokeInstanceTeamOwnerAccess            = errors.New("cannot revoke the instance's team owner access")
+	ErrInstanceProvisionInProgress              = errors.New("service instance is still being provisioned, try again later")
 	instanceNameRegexp                          = regexp.MustCompile(`^[A-Za-z][-a-zA-Z0-9_]+$`)
 )
 
@@ -66,6 +69,10 @@ type ServiceInstance struct {
 	// NOTE: after the service instance is created, this field turns immutable.
 	Pool string `json:"pool,omitempty"`
 
+	// SharedPools is an allowlist of pools, other than Pool, whose apps and
+	// jobs may bind to this multi-cluster service instance.
+	SharedPools []string `bson:"shared_pools,omitempty" json:"shared_pools,omitempty"`
+
 	// BrokerData stores data used by Instances provisioned by Brokers
 	BrokerData *BrokerInstanceData `json:"broker_data,omitempty" bson:"broker_data"`
 
@@ -83,7 +90,20 @@ type BrokerInstanceData struct {
 	SpaceID          string
 	LastOperationKey string
 
+	// LastOperationType, LastOperationState and LastOperationDescription
+	// track the last asynchronous operation requested to the broker
+	LastOperationType        string
+	LastOperationState       string
+	LastOperationDescription string
+
+	// Pending indicates that the instance or one of its bindings has an
+	// operation in progress in the broker
+	Pending bool
+
 	Binds map[string]BrokerInstanceBind
+
+	// JobBinds holds the bindings created for jobs, indexed by job name
+	JobBinds map[string]BrokerInstanceBind
 }
 
 type BrokerInstanceBind struct {
@@ -91,6 +111,8 @@ type BrokerInstanceBind struct {
 	UUID         string
 	OperationKey string
 	Parameters   map[string]interface{}
+	State        string
+	Description  string
 }
 
 // DeleteInstance deletes the service instance from the database.
@@ -133,6 +155,7 @@ type ServiceInstanceWithInfo struct {
 	Id          int
 	Name        string
 	Pool        string
+	SharedPools []string
 	Teams       []string
 	PlanName    string
 	Apps        []string
@@ -140,6 +163,10 @@ type ServiceInstanceWithInfo struct {
 	ServiceName string
 	Info        map[string]string
 	TeamOwner   string
+
+	OperationType        string
+	OperationState       string
+	OperationDescription string
 }
 
 // ToInfo returns the service instance as a struct compatible with the return
@@ -149,10 +176,11 @@ func (si *ServiceInstance) ToInfo(ctx context.Context) (ServiceInstanceWithInfo,
 	if err != nil {
 		info = nil
 	}
-	return ServiceInstanceWithInfo{
+	result := ServiceInstanceWithInfo{
 		Id:          si.Id,
 		Name:        si.Name,
 		Pool:        si.Pool,
+		SharedPools: si.SharedPools,
 		Teams:       si.Teams,
 		PlanName:    si.PlanName,
 		Apps:        si.Apps,
@@ -160,7 +188,42 @@ func (si *ServiceInstance) ToInfo(ctx context.Context) (ServiceInstanceWithInfo,
 		ServiceName: si.ServiceName,
 		Info:        info,
 		TeamOwner:   si.TeamOwner,
-	}, nil
+	}
+	if si.BrokerData != nil {
+		result.OperationType = si.BrokerData.LastOperationType
+		result.OperationState = si.BrokerData.LastOperationState
+		result.OperationDescription = si.BrokerData.LastOperationDescription
+	}
+	return result, nil
+}
+
+// checkProvisioned returns an error when the instance is not ready to be
+// bound, which happens while the broker provisions it asynchronously or
+// after the provisioning failed.
+func (si *ServiceInstance) checkProvisioned() error {
+	if si.BrokerData == nil || si.BrokerData.LastOperationType != brokerOperationProvision {
+		return nil
+	}
+	switch si.BrokerData.LastOperationState {
+	case string(osb.StateInProgress):
+		return ErrInstanceProvisionInProgress
+	case string(osb.StateFailed):
+		msg := "service instance provisioning failed"
+		if si.BrokerData.LastOperationDescription != "" {
+			msg += ": " + si.BrokerData.LastOperationDescription
+		}
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// CheckPool returns ErrMultiClusterPoolDoesNotMatch when a multi-cluster
+// instance is neither owned by nor shared with the given pool.
+func (si *ServiceInstance) CheckPool(pool string) error {
+	if si.Pool == "" || si.Pool == pool || hasString(si.SharedPools, pool) {
+		return nil
+	}
+	return ErrMultiClusterPoolDoesNotMatch
 }
 
 func (si *ServiceInstance) Info(ctx context.Context, requestID string) (map[string]string, error) {
@@ -207,6 +270,26 @@ func (si *ServiceInstance) Update(ctx context.Context, service Service, updateDa
 	if err != nil {
 		return err
 	}
+	updateData.Pool = si.Pool
+	updateData.ServiceName = si.ServiceName
+	err = validateSharedPools(ctx, &service, updateData)
+	if err != nil {
+		return err
+	}
+	err = validateRemovedSharedPools(ctx, si.ServiceName, si.Name, updateData.SharedPools)
+	if err != nil {
+		return err
+	}
+	if updateData.PlanName != si.PlanName {
+		err = validatePlanVisibility(ctx, updateData)
+		if err != nil {
+			return err
+		}
+	}
+	err = validateInstanceParameters(ctx, &service, updateData, true, requestID)
+	if err != nil {
+		return err
+	}
 	tags := processTags(updateData.Tags)
 	if tags == nil {
 		updateData.Tags = si.Tags
@@ -229,6 +312,15 @@ func (si *ServiceInstance) updateData(ctx context.Context, update mongoBSON.M) e
 
 // BindApp makes the bind between the service instance and an app.
 func (si *ServiceInstance) BindApp(ctx context.Context, app *appTypes.App, params BindAppParameters, shouldRestart bool, writer io.Writer, evt *event.Event, requestID string) error {
+	if err := si.checkProvisioned(); err != nil {
+		return err
+	}
+	if err := si.CheckPool(app.Pool); err != nil {
+		return err
+	}
+	if err := validatePlanPool(ctx, si, app.Pool); err != nil {
+		return err
+	}
 	args := bindAppPipelineArgs{
 		serviceInstance: si,
 		app:             app,
@@ -249,6 +341,15 @@ func (si *ServiceInstance) BindApp(ctx context.Context, app *appTypes.App, param
 
 // BindJob makes the bind between the service instance and a job.
 func (si *ServiceInstance) BindJob(ctx context.Context, job *jobTypes.Job, writer io.Writer, evt *event.Event, requestID string) error {
+	if err := si.checkProvisioned(); err != nil {
+		return err
+	}
+	if err := si.CheckPool(job.Pool); err != nil {
+		return err
+	}
+	if err := validatePlanPool(ctx, si, job.Pool); err != nil {
+		return err
+	}
 	args := bindJobPipelineArgs{
 		serviceInstance: si,
 		job:             job,
@@ -422,6 +523,14 @@ func CreateServiceInstance(ctx context.Context, instance ServiceInstance, servic
 		return err
 	}
 	instance.ServiceName = service.Name
+	err = validatePlanVisibility(ctx, instance)
+	if err != nil {
+		return err
+	}
+	err = validateInstanceParameters(ctx, service, instance, false, requestID)
+	if err != nil {
+		return err
+	}
 	instance.Teams = []string{instance.TeamOwner}
 	instance.Tags = processTags(instance.Tags)
 	actions := []*action.Action{&notifyCreateServiceInstance, &createServiceInstance}
@@ -547,6 +656,23 @@ func GetServiceInstancesBoundToJob(ctx context.Context, jobName string) ([]Servi
 	return instances, nil
 }
 
+func GetServiceInstancesByPool(ctx context.Context, pool string) ([]ServiceInstance, error) {
+	collection, err := storagev2.ServiceInstancesCollection()
+	if err != nil {
+		return nil, err
+	}
+	var instances []ServiceInstance
+	cursor, err := collection.Find(ctx, mongoBSON.M{"pool": pool})
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &instances)
+	if err != nil {
+		return nil, err
+	}
+	return instances, nil
+}
+
 func processTags(tags []string) []string {
 	if tags == nil {
 		return nil
@@ -588,6 +714,20 @@ func RenameServiceInstanceTeam(ctx context.Context, oldName, newName string) err
 }
 
 // ProxyInstance is a proxy between tsuru and the service instance.
+func isReservedProxyPath(path string) bool {
+	for _, reserved := range reservedProxyPaths {
+		if path == reserved {
+			return true
+		}
+	}
+	for _, prefix := range reservedProxyPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // This method allow customized service instance methods.
 func ProxyInstance(ctx context.Context, instance *ServiceInstance, path string, evt *event.Event, requestID string, w http.ResponseWriter, r *http.Request) error {
 	service, err := Get(ctx, instance.ServiceName)
@@ -600,11 +740,9 @@ func ProxyInstance(ctx context.Context, instance *ServiceInstance, path string,
 	}
 	prefix := fmt.Sprintf("/resources/%s/", instance.GetIdentifier())
 	path = strings.Trim(strings.TrimPrefix(path+"/", prefix), "/")
-	for _, reserved := range reservedProxyPaths {
-		if path == reserved && r.Method != "GET" {
-			return &tsuruErrors.ValidationError{
-				Message: fmt.Sprintf("proxy request %s %q is forbidden", r.Method, path),
-			}
+	if r.Method != "GET" && isReservedProxyPath(path) {
+		return &tsuruErrors.ValidationError{
+			Message: fmt.Sprintf("proxy request %s %q is forbidden", r.Method, path),
 		}
 	}
 
@@ -623,6 +761,9 @@ func validateMultiCluster(ctx context.Context, s *Service, si ServiceInstance) e
 		if si.Pool != "" {
 			return ErrRegularServiceInstanceCannotBelongToPool
 		}
+		if len(si.SharedPools) > 0 {
+			return ErrRegularServiceInstanceCannotBeShared
+		}
 		return nil
 	}
 	if si.Pool == "" {
@@ -642,6 +783,74 @@ func validateMultiCluster(ctx context.Context, s *Service, si ServiceInstance) e
 		return ErrMultiClusterViolatingConstraint
 	}
 
+	return validateSharedPools(ctx, s, si)
+}
+
+func validateSharedPools(ctx context.Context, s *Service, si ServiceInstance) error {
+	if len(si.SharedPools) == 0 {
+		return nil
+	}
+	if !s.IsMultiCluster {
+		return ErrRegularServiceInstanceCannotBeShared
+	}
+	for _, pool := range si.SharedPools {
+		if pool == si.Pool {
+			return &tsuruErrors.ValidationError{Message: fmt.Sprintf("pool %q already owns the service instance", pool)}
+		}
+		_, err := servicemanager.Pool.FindByName(ctx, pool)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRemovedSharedPools rejects removing a shared pool while apps or
+// jobs from that pool are still bound to the service instance.
+func validateRemovedSharedPools(ctx context.Context, serviceName, instanceName string, sharedPools []string) error {
+	current, err := GetServiceInstance(ctx, serviceName, instanceName)
+	if err != nil {
+		return err
+	}
+	var removed []string
+	for _, pool := range current.SharedPools {
+		if !hasString(sharedPools, pool) {
+			removed = append(removed, pool)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	var bound []string
+	if len(current.Apps) > 0 {
+		apps, err := servicemanager.App.List(ctx, &appTypes.Filter{
+			Pools: removed,
+			Extra: map[string][]string{"name": current.Apps},
+		})
+		if err != nil {
+			return err
+		}
+		for _, a := range apps {
+			bound = append(bound, fmt.Sprintf("app %q (pool %q)", a.Name, a.Pool))
+		}
+	}
+	if len(current.Jobs) > 0 {
+		jobs, err := servicemanager.Job.List(ctx, &jobTypes.Filter{
+			Pools: removed,
+			Extra: map[string][]string{"name": current.Jobs},
+		})
+		if err != nil {
+			return err
+		}
+		for _, j := range jobs {
+			bound = append(bound, fmt.Sprintf("job %q (pool %q)", j.Name, j.Pool))
+		}
+	}
+	if len(bound) > 0 {
+		return &tsuruErrors.ValidationError{
+			Message: fmt.Sprintf("unable to remove shared pools, unbind them first: %s", strings.Join(bound, ", ")),
+		}
+	}
 	return nil
 }
 
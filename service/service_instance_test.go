@@ -777,7 +777,7 @@ func (s *InstanceSuite) TestCreateServiceInstance(c *check.C) {
 	c.Assert(err, check.IsNil)
 	si, err := GetServiceInstance(context.TODO(), "mongodb", "instance")
 	c.Assert(err, check.IsNil)
-	c.Assert(atomic.LoadInt32(&requests), check.Equals, int32(1))
+	c.Assert(atomic.LoadInt32(&requests), check.Equals, int32(2))
 	c.Assert(si.PlanName, check.Equals, "small")
 	c.Assert(si.TeamOwner, check.Equals, s.team.Name)
 	c.Assert(si.Teams, check.DeepEquals, []string{s.team.Name})
@@ -824,7 +824,7 @@ func (s *InstanceSuite) TestCreateServiceInstanceWithSameInstanceName(c *check.C
 	}
 	si, err := GetServiceInstance(context.TODO(), "mongodb3", "instance")
 	c.Assert(err, check.IsNil)
-	c.Assert(atomic.LoadInt32(&requests), check.Equals, int32(3))
+	c.Assert(atomic.LoadInt32(&requests), check.Equals, int32(6))
 	c.Assert(si.PlanName, check.Equals, "small")
 	c.Assert(si.TeamOwner, check.Equals, s.team.Name)
 	c.Assert(si.Teams, check.DeepEquals, []string{s.team.Name})
@@ -857,7 +857,7 @@ func (s *InstanceSuite) TestCreateSpecifyOwner(c *check.C) {
 	c.Assert(err, check.IsNil)
 	si, err := GetServiceInstance(context.TODO(), "mongodb", "instance")
 	c.Assert(err, check.IsNil)
-	c.Assert(atomic.LoadInt32(&requests), check.Equals, int32(1))
+	c.Assert(atomic.LoadInt32(&requests), check.Equals, int32(2))
 	c.Assert(si.TeamOwner, check.Equals, team.Name)
 }
 
@@ -967,7 +967,7 @@ func (s *InstanceSuite) TestCreateServiceInstanceRemovesDuplicatedAndEmptyTags(c
 	c.Assert(err, check.IsNil)
 	si, err := GetServiceInstance(context.TODO(), "mongodb", "instance")
 	c.Assert(err, check.IsNil)
-	c.Assert(atomic.LoadInt32(&requests), check.Equals, int32(1))
+	c.Assert(atomic.LoadInt32(&requests), check.Equals, int32(2))
 	c.Assert(si.Tags, check.DeepEquals, []string{"tag1"})
 }
 
@@ -1233,7 +1233,7 @@ func (s *InstanceSuite) TestUpdateServiceInstanceRemovesDuplicatedAndEmptyTags(c
 	evt := createEvt(c)
 	err = instance.Update(context.TODO(), srv, instance, evt, "")
 	c.Assert(err, check.IsNil)
-	c.Assert(atomic.LoadInt32(&requests), check.Equals, int32(1))
+	c.Assert(atomic.LoadInt32(&requests), check.Equals, int32(2))
 	var si ServiceInstance
 	err = serviceInstancesCollection.FindOne(context.TODO(), mongoBSON.M{"name": "instance"}).Decode(&si)
 	c.Assert(err, check.IsNil)
@@ -1830,6 +1830,12 @@ func (s *S) TestProxyInstance(c *check.C) {
 		{method: "POST", path: "bind-app", err: "proxy request POST \"bind-app\" is forbidden"},
 		{method: "POST", path: "/bind-app", err: "proxy request POST \"bind-app\" is forbidden"},
 		{method: "GET", path: "/bind-app", expectedPath: "/resources/noflow/bind-app"},
+		{method: "POST", path: "credentials", err: "proxy request POST \"credentials\" is forbidden"},
+		{method: "DELETE", path: "credentials/previous", err: "proxy request DELETE \"credentials/previous\" is forbidden"},
+		{method: "POST", path: "actions/restart", err: "proxy request POST \"actions/restart\" is forbidden"},
+		{method: "POST", path: "actions", expectedPath: "/resources/noflow/actions"},
+		{method: "GET", path: "actions/restart", expectedPath: "/resources/noflow/actions/restart"},
+		{method: "POST", path: "credentials-report", expectedPath: "/resources/noflow/credentials-report"},
 		{method: "GET", path: "/resources/noflow/bind-app", expectedPath: "/resources/noflow/bind-app"},
 		{method: "POST", path: "/resources/noflow/otherpath", expectedPath: "/resources/noflow/otherpath"},
 		{method: "POST", path: "/resources/otherinstance/otherpath", expectedPath: "/resources/noflow/resources/otherinstance/otherpath"},
@@ -0,0 +1,151 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestCheckPool(c *check.C) {
+	regular := ServiceInstance{Name: "regular"}
+	c.Assert(regular.CheckPool("any-pool"), check.IsNil)
+	instance := ServiceInstance{Name: "regional", Pool: "pool-a", SharedPools: []string{"pool-b"}}
+	c.Assert(instance.CheckPool("pool-a"), check.IsNil)
+	c.Assert(instance.CheckPool("pool-b"), check.IsNil)
+	c.Assert(instance.CheckPool("pool-c"), check.Equals, ErrMultiClusterPoolDoesNotMatch)
+}
+
+func (s *S) TestBindAppPoolDoesNotMatch(c *check.C) {
+	instance := ServiceInstance{Name: "regional", ServiceName: "mysql", Pool: "pool-a", SharedPools: []string{"pool-b"}}
+	a := &appTypes.App{Name: "myapp", Pool: "pool-c"}
+	err := instance.BindApp(context.TODO(), a, nil, false, nil, createEvt(c), "")
+	c.Assert(err, check.Equals, ErrMultiClusterPoolDoesNotMatch)
+	err = instance.BindJob(context.TODO(), &jobTypes.Job{Name: "myjob", Pool: "pool-c"}, nil, createEvt(c), "")
+	c.Assert(err, check.Equals, ErrMultiClusterPoolDoesNotMatch)
+}
+
+func (s *S) TestBindAppSharedInstanceSendsBothPools(c *check.C) {
+	h := TestHandler{}
+	ts := httptest.NewServer(&h)
+	defer ts.Close()
+	s.mockService.Pool.OnFindByName = func(name string) (*provTypes.Pool, error) {
+		return &provTypes.Pool{Name: name, Provisioner: "kubernetes"}, nil
+	}
+	s.mockService.Cluster.OnFindByPool = func(provisioner, pool string) (*provTypes.Cluster, error) {
+		return &provTypes.Cluster{
+			Name:        pool + "-cluster",
+			Provisioner: "kubernetes",
+			Addresses:   []string{"https://" + pool + ".example.com"},
+		}, nil
+	}
+	instance := ServiceInstance{Name: "regional", ServiceName: "mysql", Pool: "pool-a", SharedPools: []string{"pool-b"}}
+	a := provisiontest.NewFakeAppWithPool("myapp", "python", "pool-b", 1)
+	client := &endpointClient{endpoint: ts.URL, username: "user", password: "abcde"}
+	_, err := client.BindApp(context.TODO(), &instance, a, nil, createEvt(c), "")
+	c.Assert(err, check.IsNil)
+	h.Lock()
+	defer h.Unlock()
+	v, err := url.ParseQuery(string(h.body))
+	c.Assert(err, check.IsNil)
+	c.Assert(v.Get("app-pool-name"), check.Equals, "pool-b")
+	c.Assert(v.Get("app-cluster-name"), check.Equals, "pool-b-cluster")
+	c.Assert(v["app-cluster-addresses"], check.DeepEquals, []string{"https://pool-b.example.com"})
+	c.Assert(v.Get("instance-pool-name"), check.Equals, "pool-a")
+	c.Assert(v.Get("instance-cluster-name"), check.Equals, "pool-a-cluster")
+	c.Assert(v["instance-cluster-addresses"], check.DeepEquals, []string{"https://pool-a.example.com"})
+	c.Assert(h.request.Header.Get("X-Tsuru-Pool-Name"), check.Equals, "pool-a")
+}
+
+func (s *S) TestCreateServiceInstanceRegularServiceWithSharedPools(c *check.C) {
+	srv := Service{Name: "mysql", Endpoint: map[string]string{"production": "http://localhost:1234"}, Password: "abcde"}
+	instance := ServiceInstance{Name: "instance", TeamOwner: s.team.Name, SharedPools: []string{"pool-b"}}
+	err := CreateServiceInstance(context.TODO(), instance, &srv, createEvt(c), "")
+	c.Assert(err, check.Equals, ErrRegularServiceInstanceCannotBeShared)
+}
+
+func (s *S) TestCreateServiceInstanceWithSharedPools(c *check.C) {
+	ts := httptest.NewServer(&TestHandler{})
+	defer ts.Close()
+	s.mockService.Pool.OnFindByName = func(name string) (*provTypes.Pool, error) {
+		if name == "unknown" {
+			return nil, provTypes.ErrPoolNotFound
+		}
+		return &provTypes.Pool{Name: name, Provisioner: "kubernetes"}, nil
+	}
+	s.mockService.Pool.OnServices = func(pool string) ([]string, error) {
+		return []string{"regional"}, nil
+	}
+	srv := Service{Name: "regional", Endpoint: map[string]string{"production": ts.URL}, Password: "abcde", IsMultiCluster: true}
+	instance := ServiceInstance{Name: "instance", TeamOwner: s.team.Name, Pool: "pool-a", SharedPools: []string{"pool-a"}}
+	err := CreateServiceInstance(context.TODO(), instance, &srv, createEvt(c), "")
+	c.Assert(err, check.ErrorMatches, `pool "pool-a" already owns the service instance`)
+	instance.SharedPools = []string{"unknown"}
+	err = CreateServiceInstance(context.TODO(), instance, &srv, createEvt(c), "")
+	c.Assert(err, check.Equals, provTypes.ErrPoolNotFound)
+	instance.SharedPools = []string{"pool-b"}
+	err = CreateServiceInstance(context.TODO(), instance, &srv, createEvt(c), "")
+	c.Assert(err, check.IsNil)
+	collection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	var si ServiceInstance
+	err = collection.FindOne(context.TODO(), mongoBSON.M{"name": "instance"}).Decode(&si)
+	c.Assert(err, check.IsNil)
+	c.Assert(si.SharedPools, check.DeepEquals, []string{"pool-b"})
+}
+
+func (s *S) TestUpdateServiceInstanceRemoveSharedPoolWithBinds(c *check.C) {
+	ts := httptest.NewServer(&TestHandler{})
+	defer ts.Close()
+	s.mockService.Pool.OnFindByName = func(name string) (*provTypes.Pool, error) {
+		return &provTypes.Pool{Name: name, Provisioner: "kubernetes"}, nil
+	}
+	s.mockService.Pool.OnServices = func(pool string) ([]string, error) {
+		return []string{"regional"}, nil
+	}
+	var listFilter *appTypes.Filter
+	s.mockService.App.OnList = func(f *appTypes.Filter) ([]*appTypes.App, error) {
+		listFilter = f
+		return []*appTypes.App{{Name: "myapp", Pool: "pool-b"}}, nil
+	}
+	srv := Service{Name: "regional", Endpoint: map[string]string{"production": ts.URL}, Password: "abcde", IsMultiCluster: true}
+	instance := ServiceInstance{
+		Name:        "instance",
+		ServiceName: "regional",
+		TeamOwner:   s.team.Name,
+		Teams:       []string{s.team.Name},
+		Pool:        "pool-a",
+		SharedPools: []string{"pool-b", "pool-c"},
+		Apps:        []string{"myapp"},
+	}
+	collection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = collection.InsertOne(context.TODO(), instance)
+	c.Assert(err, check.IsNil)
+	updateData := instance
+	updateData.SharedPools = []string{"pool-c"}
+	err = instance.Update(context.TODO(), srv, updateData, createEvt(c), "")
+	c.Assert(err, check.ErrorMatches, `unable to remove shared pools, unbind them first: app "myapp" \(pool "pool-b"\)`)
+	c.Assert(listFilter.Pools, check.DeepEquals, []string{"pool-b"})
+	c.Assert(listFilter.Extra, check.DeepEquals, map[string][]string{"name": {"myapp"}})
+	s.mockService.App.OnList = func(f *appTypes.Filter) ([]*appTypes.App, error) {
+		return nil, nil
+	}
+	err = instance.Update(context.TODO(), srv, updateData, createEvt(c), "")
+	c.Assert(err, check.IsNil)
+	var si ServiceInstance
+	err = collection.FindOne(context.TODO(), mongoBSON.M{"name": "instance"}).Decode(&si)
+	c.Assert(err, check.IsNil)
+	c.Assert(si.SharedPools, check.DeepEquals, []string{"pool-c"})
+}
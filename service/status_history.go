@@ -0,0 +1,311 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultStatusCollectInterval = 5 * time.Minute
+	defaultStatusCollectTimeout  = 30 * time.Second
+	defaultStatusRetention       = 7 * 24 * time.Hour
+	defaultStatusHistoryLimit    = 100
+	MaxStatusHistoryLimit        = 1000
+
+	statusCollectKind = "service-instance-status-collect"
+
+	statusValueUnknown = -1
+	statusValueDown    = 0
+	statusValuePending = 0.5
+	statusValueUp      = 1
+)
+
+var serviceInstanceStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "tsuru_service_instance_status",
+	Help: "The last collected status of service instances: 1 when up, 0.5 while pending, 0 when down and -1 when the service does not report a known status",
+}, []string{"service", "instance", "team"})
+
+// reportedStatus holds the label values set on serviceInstanceStatus by the
+// last export, so removed instances can be dropped from the metric.
+var (
+	reportedStatusMu sync.Mutex
+	reportedStatus   = map[[3]string]struct{}{}
+)
+
+func init() {
+	prometheus.MustRegister(serviceInstanceStatus)
+}
+
+// StatusSnapshot is the status and info of a service instance recorded by
+// the status collector at a given time.
+type StatusSnapshot struct {
+	ServiceName  string            `bson:"service_name" json:"service"`
+	InstanceName string            `bson:"instance_name" json:"instance"`
+	TeamOwner    string            `json:"team_owner"`
+	Status       string            `json:"status"`
+	Info         map[string]string `json:"info,omitempty"`
+	Error        string            `json:"error,omitempty"`
+	Timestamp    time.Time         `json:"timestamp"`
+	ExpireAt     time.Time         `json:"-"`
+}
+
+// StatusHistoryFilter restricts the snapshots returned by StatusHistory.
+type StatusHistoryFilter struct {
+	Since time.Time
+	Until time.Time
+	Limit int
+}
+
+// InitializeStatusCollector starts the worker that periodically records the
+// status of every service instance.
+func InitializeStatusCollector() error {
+	interval := defaultStatusCollectInterval
+	if seconds, err := config.GetInt("service:status-collector:interval"); err == nil && seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	}
+	collector := &statusCollector{once: &sync.Once{}, interval: interval}
+	collector.start()
+	shutdown.Register(collector)
+	return nil
+}
+
+type statusCollector struct {
+	once     *sync.Once
+	stopCh   chan struct{}
+	interval time.Duration
+}
+
+func (sc *statusCollector) start() {
+	sc.once.Do(func() {
+		sc.stopCh = make(chan struct{})
+		go sc.spin()
+	})
+}
+
+func (sc *statusCollector) Shutdown(ctx context.Context) error {
+	if sc.stopCh == nil {
+		return nil
+	}
+	sc.stopCh <- struct{}{}
+	sc.stopCh = nil
+	sc.once = &sync.Once{}
+	return nil
+}
+
+func (sc *statusCollector) spin() {
+	for {
+		if err := runStatusCollection(context.Background()); err != nil {
+			log.Errorf("[service instance status collector] %v", err)
+		}
+		if err := exportInstancesStatus(context.Background()); err != nil {
+			log.Errorf("[service instance status collector] %v", err)
+		}
+
+		select {
+		case <-sc.stopCh:
+			return
+		case <-time.After(sc.interval):
+		}
+	}
+}
+
+// runStatusCollection collects the status of service instances holding a
+// global lock, so only one API replica calls the service APIs at a time.
+func runStatusCollection(ctx context.Context) error {
+	evt, err := event.NewInternal(ctx, &event.Opts{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeGlobal, Value: statusCollectKind},
+		InternalKind: statusCollectKind,
+		Allowed:      event.Allowed(permission.PermServiceInstanceReadEvents, permission.Context(permTypes.CtxGlobal, "")),
+	})
+	if err != nil {
+		if _, isLocked := err.(event.ErrEventLocked); isLocked {
+			return nil
+		}
+		return errors.Wrap(err, "could not create event")
+	}
+	err = collectInstancesStatus(ctx)
+	if err != nil {
+		evt.Done(ctx, err)
+		return err
+	}
+	return evt.Abort(ctx)
+}
+
+func collectInstancesStatus(ctx context.Context) error {
+	collection, err := storagev2.ServiceInstancesCollection()
+	if err != nil {
+		return err
+	}
+	cursor, err := collection.Find(ctx, mongoBSON.M{})
+	if err != nil {
+		return errors.Wrap(err, "unable to list service instances")
+	}
+	var instances []ServiceInstance
+	err = cursor.All(ctx, &instances)
+	if err != nil {
+		return errors.Wrap(err, "unable to list service instances")
+	}
+	now := time.Now().UTC()
+	snapshots := make([]interface{}, 0, len(instances))
+	for i := range instances {
+		snapshots = append(snapshots, collectInstanceStatus(ctx, &instances[i], now))
+	}
+	if len(snapshots) == 0 {
+		return nil
+	}
+	historyCollection, err := storagev2.ServiceInstanceStatusCollection()
+	if err != nil {
+		return err
+	}
+	_, err = historyCollection.InsertMany(ctx, snapshots)
+	return errors.Wrap(err, "unable to store service instance status")
+}
+
+// exportInstancesStatus sets serviceInstanceStatus from the snapshots stored
+// by the last collection. It runs on every API replica, not only on the one
+// holding the collection lock, so the metric doesn't depend on which replica
+// is scraped.
+func exportInstancesStatus(ctx context.Context) error {
+	collection, err := storagev2.ServiceInstanceStatusCollection()
+	if err != nil {
+		return err
+	}
+	var last StatusSnapshot
+	err = collection.FindOne(ctx, mongoBSON.M{}, options.FindOne().SetSort(mongoBSON.M{"timestamp": -1})).Decode(&last)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return errors.Wrap(err, "unable to find the last service instance status")
+	}
+	var snapshots []StatusSnapshot
+	if err == nil {
+		cursor, err := collection.Find(ctx, mongoBSON.M{"timestamp": last.Timestamp})
+		if err != nil {
+			return errors.Wrap(err, "unable to list the last service instance status")
+		}
+		if err = cursor.All(ctx, &snapshots); err != nil {
+			return errors.Wrap(err, "unable to list the last service instance status")
+		}
+	}
+	reported := make(map[[3]string]struct{}, len(snapshots))
+	for _, snapshot := range snapshots {
+		labels := [3]string{snapshot.ServiceName, snapshot.InstanceName, snapshot.TeamOwner}
+		serviceInstanceStatus.WithLabelValues(labels[:]...).Set(statusValue(snapshot))
+		reported[labels] = struct{}{}
+	}
+	reportedStatusMu.Lock()
+	defer reportedStatusMu.Unlock()
+	for labels := range reportedStatus {
+		if _, ok := reported[labels]; !ok {
+			serviceInstanceStatus.DeleteLabelValues(labels[:]...)
+		}
+	}
+	reportedStatus = reported
+	return nil
+}
+
+func collectInstanceStatus(ctx context.Context, si *ServiceInstance, now time.Time) StatusSnapshot {
+	snapshot := StatusSnapshot{
+		ServiceName:  si.ServiceName,
+		InstanceName: si.Name,
+		TeamOwner:    si.TeamOwner,
+		Timestamp:    now,
+		ExpireAt:     now.Add(statusRetention()),
+	}
+	ctx, cancel := context.WithTimeout(ctx, statusCollectTimeout())
+	defer cancel()
+	status, err := si.Status(ctx, "")
+	if err != nil {
+		snapshot.Status = "down"
+		snapshot.Error = err.Error()
+		return snapshot
+	}
+	snapshot.Status = status
+	info, err := si.Info(ctx, "")
+	if err != nil {
+		log.Errorf("[service instance status collector] unable to get info of instance %s/%s: %v", si.ServiceName, si.Name, err)
+	}
+	snapshot.Info = info
+	return snapshot
+}
+
+func statusValue(snapshot StatusSnapshot) float64 {
+	switch snapshot.Status {
+	case "up":
+		return statusValueUp
+	case "down":
+		return statusValueDown
+	case "pending":
+		return statusValuePending
+	}
+	return statusValueUnknown
+}
+
+func statusCollectTimeout() time.Duration {
+	if seconds, err := config.GetInt("service:status-collector:timeout"); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultStatusCollectTimeout
+}
+
+func statusRetention() time.Duration {
+	if hours, err := config.GetInt("service:status-collector:retention-hours"); err == nil && hours > 0 {
+		return time.Duration(hours) * time.Hour
+	}
+	return defaultStatusRetention
+}
+
+// StatusHistory returns the recorded snapshots of a service instance, most
+// recent first.
+func StatusHistory(ctx context.Context, si *ServiceInstance, filter StatusHistoryFilter) ([]StatusSnapshot, error) {
+	collection, err := storagev2.ServiceInstanceStatusCollection()
+	if err != nil {
+		return nil, err
+	}
+	query := mongoBSON.M{"service_name": si.ServiceName, "instance_name": si.Name}
+	timestamp := mongoBSON.M{}
+	if !filter.Since.IsZero() {
+		timestamp["$gte"] = filter.Since
+	}
+	if !filter.Until.IsZero() {
+		timestamp["$lte"] = filter.Until
+	}
+	if len(timestamp) > 0 {
+		query["timestamp"] = timestamp
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultStatusHistoryLimit
+	}
+	if limit > MaxStatusHistoryLimit {
+		limit = MaxStatusHistoryLimit
+	}
+	opts := options.Find().SetSort(mongoBSON.M{"timestamp": -1}).SetLimit(int64(limit))
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []StatusSnapshot
+	err = cursor.All(ctx, &snapshots)
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) createStatusService(c *check.C) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/up-db/status"):
+			w.WriteHeader(http.StatusNoContent)
+		case strings.HasSuffix(r.URL.Path, "/down-db/status"):
+			w.WriteHeader(http.StatusInternalServerError)
+		case strings.HasSuffix(r.URL.Path, "/up-db"):
+			w.Write([]byte(`[{"label": "Connections", "value": "42"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	srv := Service{Name: "mysql", Endpoint: map[string]string{"production": ts.URL}, Password: "abcde"}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srv)
+	c.Assert(err, check.IsNil)
+	collection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	for _, name := range []string{"up-db", "down-db", "unknown-db"} {
+		_, err = collection.InsertOne(context.TODO(), ServiceInstance{Name: name, ServiceName: "mysql", TeamOwner: s.team.Name})
+		c.Assert(err, check.IsNil)
+	}
+	return ts
+}
+
+func (s *S) TestCollectInstancesStatus(c *check.C) {
+	ts := s.createStatusService(c)
+	defer ts.Close()
+	err := collectInstancesStatus(context.TODO())
+	c.Assert(err, check.IsNil)
+	err = exportInstancesStatus(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(testutil.ToFloat64(serviceInstanceStatus.WithLabelValues("mysql", "up-db", s.team.Name)), check.Equals, float64(statusValueUp))
+	c.Assert(testutil.ToFloat64(serviceInstanceStatus.WithLabelValues("mysql", "down-db", s.team.Name)), check.Equals, float64(statusValueDown))
+	c.Assert(testutil.ToFloat64(serviceInstanceStatus.WithLabelValues("mysql", "unknown-db", s.team.Name)), check.Equals, float64(statusValueUnknown))
+	history, err := StatusHistory(context.TODO(), &ServiceInstance{Name: "up-db", ServiceName: "mysql"}, StatusHistoryFilter{})
+	c.Assert(err, check.IsNil)
+	c.Assert(history, check.HasLen, 1)
+	c.Assert(history[0].Status, check.Equals, "up")
+	c.Assert(history[0].TeamOwner, check.Equals, s.team.Name)
+	c.Assert(history[0].Info, check.DeepEquals, map[string]string{"Connections": "42"})
+	c.Assert(history[0].ExpireAt.Sub(history[0].Timestamp), check.Equals, defaultStatusRetention)
+}
+
+func (s *S) TestStatusHistoryFilter(c *check.C) {
+	collection, err := storagev2.ServiceInstanceStatusCollection()
+	c.Assert(err, check.IsNil)
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		_, err = collection.InsertOne(context.TODO(), StatusSnapshot{
+			ServiceName:  "mysql",
+			InstanceName: "db",
+			Status:       "up",
+			Timestamp:    base.Add(time.Duration(i) * time.Hour),
+			ExpireAt:     time.Now().Add(time.Hour),
+		})
+		c.Assert(err, check.IsNil)
+	}
+	si := &ServiceInstance{Name: "db", ServiceName: "mysql"}
+	history, err := StatusHistory(context.TODO(), si, StatusHistoryFilter{})
+	c.Assert(err, check.IsNil)
+	c.Assert(history, check.HasLen, 3)
+	c.Assert(history[0].Timestamp.Equal(base.Add(2*time.Hour)), check.Equals, true)
+	history, err = StatusHistory(context.TODO(), si, StatusHistoryFilter{Since: base.Add(time.Hour), Limit: 1})
+	c.Assert(err, check.IsNil)
+	c.Assert(history, check.HasLen, 1)
+	c.Assert(history[0].Timestamp.Equal(base.Add(2*time.Hour)), check.Equals, true)
+	history, err = StatusHistory(context.TODO(), si, StatusHistoryFilter{Until: base})
+	c.Assert(err, check.IsNil)
+	c.Assert(history, check.HasLen, 1)
+}
+
+func (s *S) TestCollectInstancesStatusRemovesDeletedInstances(c *check.C) {
+	ts := s.createStatusService(c)
+	defer ts.Close()
+	err := collectInstancesStatus(context.TODO())
+	c.Assert(err, check.IsNil)
+	err = exportInstancesStatus(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(testutil.CollectAndCount(serviceInstanceStatus), check.Equals, 3)
+	collection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = collection.DeleteOne(context.TODO(), mongoBSON.M{"name": "down-db"})
+	c.Assert(err, check.IsNil)
+	err = collectInstancesStatus(context.TODO())
+	c.Assert(err, check.IsNil)
+	err = exportInstancesStatus(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(testutil.CollectAndCount(serviceInstanceStatus), check.Equals, 2)
+}
+
+func (s *S) TestRunStatusCollectionLocked(c *check.C) {
+	ts := s.createStatusService(c)
+	defer ts.Close()
+	evt, err := event.NewInternal(context.TODO(), &event.Opts{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeGlobal, Value: statusCollectKind},
+		InternalKind: statusCollectKind,
+		Allowed:      event.Allowed(permission.PermServiceInstanceReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	defer evt.Abort(context.TODO())
+	err = runStatusCollection(context.TODO())
+	c.Assert(err, check.IsNil)
+	history, err := StatusHistory(context.TODO(), &ServiceInstance{Name: "up-db", ServiceName: "mysql"}, StatusHistoryFilter{})
+	c.Assert(err, check.IsNil)
+	c.Assert(history, check.HasLen, 0)
+}
+
+func (s *S) TestExportInstancesStatusFromStoredSnapshots(c *check.C) {
+	collection, err := storagev2.ServiceInstanceStatusCollection()
+	c.Assert(err, check.IsNil)
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for _, snapshot := range []StatusSnapshot{
+		{ServiceName: "mysql", InstanceName: "db1", TeamOwner: s.team.Name, Status: "down", Timestamp: base},
+		{ServiceName: "mysql", InstanceName: "db2", TeamOwner: s.team.Name, Status: "down", Timestamp: base},
+		{ServiceName: "mysql", InstanceName: "db1", TeamOwner: s.team.Name, Status: "up", Timestamp: base.Add(time.Minute)},
+	} {
+		snapshot.ExpireAt = time.Now().Add(time.Hour)
+		_, err = collection.InsertOne(context.TODO(), snapshot)
+		c.Assert(err, check.IsNil)
+	}
+	err = exportInstancesStatus(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(testutil.CollectAndCount(serviceInstanceStatus), check.Equals, 1)
+	c.Assert(testutil.ToFloat64(serviceInstanceStatus.WithLabelValues("mysql", "db1", s.team.Name)), check.Equals, float64(statusValueUp))
+}
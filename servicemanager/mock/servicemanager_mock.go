@@ -30,6 +30,8 @@ type MockService struct {
 	UserQuota                 *quota.MockQuotaService[quota.QuotaItem]
 	AppQuota                  *quota.MockQuotaService[*app.App]
 	TeamQuota                 *quota.MockQuotaService[*auth.Team]
+	TeamJobQuota              *quota.MockQuotaService[*auth.Team]
+	TeamJobRunQuota           *quota.MockQuotaService[*auth.Team]
 	Cluster                   *provision.MockClusterService
 	ServiceBroker             *service.MockServiceBrokerService
 	ServiceBrokerCatalogCache *service.MockServiceBrokerCatalogCacheService
@@ -52,6 +54,8 @@ func SetMockService(m *MockService) {
 	m.UserQuota = &quota.MockQuotaService[quota.QuotaItem]{}
 	m.AppQuota = &quota.MockQuotaService[*app.App]{}
 	m.TeamQuota = &quota.MockQuotaService[*auth.Team]{}
+	m.TeamJobQuota = &quota.MockQuotaService[*auth.Team]{}
+	m.TeamJobRunQuota = &quota.MockQuotaService[*auth.Team]{}
 	m.Cluster = &provision.MockClusterService{}
 	m.ServiceBroker = &service.MockServiceBrokerService{}
 	m.ServiceBrokerCatalogCache = &service.MockServiceBrokerCatalogCacheService{}
@@ -73,6 +77,8 @@ func SetMockService(m *MockService) {
 	servicemanager.UserQuota = m.UserQuota
 	servicemanager.AppQuota = m.AppQuota
 	servicemanager.TeamQuota = m.TeamQuota
+	servicemanager.TeamJobQuota = m.TeamJobQuota
+	servicemanager.TeamJobRunQuota = m.TeamJobRunQuota
 	servicemanager.Cluster = m.Cluster
 	servicemanager.ServiceBroker = m.ServiceBroker
 	servicemanager.ServiceBrokerCatalogCache = m.ServiceBrokerCatalogCache
@@ -29,10 +29,14 @@ var (
 	Team                      auth.TeamService
 	TeamToken                 auth.TeamTokenService
 	Job                       job.JobService
+	JobWorkflow               job.WorkflowService
+	JobTemplate               job.TemplateService
 	Webhook                   event.WebhookService
 	AppQuota                  quota.QuotaService[*app.App]
 	UserQuota                 quota.LegacyQuotaService
 	TeamQuota                 quota.QuotaService[*auth.Team]
+	TeamJobQuota              quota.QuotaService[*auth.Team]
+	TeamJobRunQuota           quota.QuotaService[*auth.Team]
 	Cluster                   provision.ClusterService
 	ServiceBroker             service.ServiceBrokerService
 	ServiceBrokerCatalogCache service.ServiceBrokerCatalogCacheService
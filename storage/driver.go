@@ -31,10 +31,13 @@ type DbDriver struct {
 	UserQuotaStorage                 quota.QuotaStorage
 	AppQuotaStorage                  quota.QuotaStorage
 	TeamQuotaStorage                 quota.QuotaStorage
+	TeamJobQuotaStorage              quota.QuotaStorage
+	TeamJobRunQuotaStorage           quota.QuotaStorage
 	WebhookStorage                   event.WebhookStorage
 	ClusterStorage                   provision.ClusterStorage
 	ServiceBrokerStorage             service.ServiceBrokerStorage
 	ServiceBrokerCatalogCacheStorage cache.CacheStorage
+	ServiceSchemaCacheStorage        cache.CacheStorage
 	PlatformImageStorage             image.PlatformImageStorage
 	InstanceTrackerStorage           tracker.InstanceStorage
 	AppVersionStorage                app.AppVersionStorage
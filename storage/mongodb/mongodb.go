@@ -19,10 +19,13 @@ func init() {
 		UserQuotaStorage:                 authQuotaStorage(),
 		AppQuotaStorage:                  appQuotaStorage(),
 		TeamQuotaStorage:                 teamQuotaStorage(),
+		TeamJobQuotaStorage:              teamJobQuotaStorage(),
+		TeamJobRunQuotaStorage:           teamJobRunQuotaStorage(),
 		WebhookStorage:                   &webhookStorage{},
 		ClusterStorage:                   &clusterStorage{},
 		ServiceBrokerStorage:             &serviceBrokerStorage{},
 		ServiceBrokerCatalogCacheStorage: serviceBrokerCatalogCacheStorage(),
+		ServiceSchemaCacheStorage:        serviceSchemaCacheStorage(),
 		InstanceTrackerStorage:           &instanceTrackerStorage{},
 		AppVersionStorage:                &appVersionStorage{},
 		DynamicRouterStorage:             &dynamicRouterStorage{},
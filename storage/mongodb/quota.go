@@ -18,47 +18,42 @@ var _ quota.QuotaStorage = &quotaStorage{}
 type quotaStorage struct {
 	collection string
 	query      func(string) mongoBSON.M
+	// field is the document field holding the quota, defaults to quota.
+	field string
+	// unset is the quota of documents stored before the field existed.
+	unset quota.Quota
 }
 
-type quotaObject struct {
-	Quota quota.Quota
+func (s *quotaStorage) fieldName() string {
+	if s.field == "" {
+		return "quota"
+	}
+	return s.field
 }
 
 func (s *quotaStorage) SetLimit(ctx context.Context, name string, limit int) error {
-	_, err := s.Get(ctx, name)
-	if err != nil {
-		return err
-	}
-
-	query := s.query(name)
-	span := newMongoDBSpan(ctx, mongoSpanUpdate, s.collection)
-	span.SetQueryStatement(query)
-	defer span.Finish()
-
-	collection, err := storagev2.Collection(s.collection)
-	if err != nil {
-		span.SetError(err)
-		return err
-	}
+	return s.update(ctx, name, "limit", limit)
+}
 
-	_, err = collection.UpdateOne(
-		ctx,
-		query,
-		mongoBSON.M{"$set": mongoBSON.M{"quota.limit": limit}},
-	)
+func (s *quotaStorage) Set(ctx context.Context, name string, inUse int) error {
+	return s.update(ctx, name, "inuse", inUse)
+}
 
+func (s *quotaStorage) update(ctx context.Context, name, key string, value int) error {
+	q, found, err := s.get(ctx, name)
 	if err != nil {
-		span.SetError(err)
 		return err
 	}
 
-	return nil
-}
-
-func (s *quotaStorage) Set(ctx context.Context, name string, inUse int) error {
-	_, err := s.Get(ctx, name)
-	if err != nil {
-		return err
+	set := mongoBSON.M{s.fieldName() + "." + key: value}
+	if !found {
+		// the whole quota is stored so the unset limit is kept
+		if key == "limit" {
+			q.Limit = value
+		} else {
+			q.InUse = value
+		}
+		set = mongoBSON.M{s.fieldName(): q}
 	}
 
 	query := s.query(name)
@@ -72,11 +67,7 @@ func (s *quotaStorage) Set(ctx context.Context, name string, inUse int) error {
 		return err
 	}
 
-	_, err = collection.UpdateOne(
-		ctx,
-		query,
-		mongoBSON.M{"$set": mongoBSON.M{"quota.inuse": inUse}},
-	)
+	_, err = collection.UpdateOne(ctx, query, mongoBSON.M{"$set": set})
 
 	if err != nil {
 		span.SetError(err)
@@ -87,6 +78,11 @@ func (s *quotaStorage) Set(ctx context.Context, name string, inUse int) error {
 }
 
 func (s *quotaStorage) Get(ctx context.Context, name string) (*quota.Quota, error) {
+	q, _, err := s.get(ctx, name)
+	return q, err
+}
+
+func (s *quotaStorage) get(ctx context.Context, name string) (*quota.Quota, bool, error) {
 	query := s.query(name)
 	span := newMongoDBSpan(ctx, mongoSpanFind, s.collection)
 	span.SetQueryStatement(query)
@@ -95,17 +91,26 @@ func (s *quotaStorage) Get(ctx context.Context, name string) (*quota.Quota, erro
 	collection, err := storagev2.Collection(s.collection)
 	if err != nil {
 		span.SetError(err)
-		return nil, err
+		return nil, false, err
 	}
 
-	var obj quotaObject
+	var obj mongoBSON.Raw
 	err = collection.FindOne(ctx, query).Decode(&obj)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, quota.ErrQuotaNotFound
+			return nil, false, quota.ErrQuotaNotFound
 		}
 		span.SetError(err)
-		return nil, err
+		return nil, false, err
+	}
+	q := s.unset
+	value, err := obj.LookupErr(s.fieldName())
+	if err != nil {
+		return &q, false, nil
+	}
+	if err = value.Unmarshal(&q); err != nil {
+		span.SetError(err)
+		return nil, false, err
 	}
-	return &obj.Quota, nil
+	return &q, true, nil
 }
@@ -0,0 +1,13 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mongodb
+
+import "github.com/tsuru/tsuru/types/cache"
+
+func serviceSchemaCacheStorage() cache.CacheStorage {
+	return &cacheStorage{
+		collection: "service_schema_cache",
+	}
+}
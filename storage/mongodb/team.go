@@ -24,6 +24,8 @@ type team struct {
 	CreatingUser string
 	Tags         []string
 	Quota        quota.Quota
+	JobQuota     quota.Quota
+	JobRunQuota  quota.Quota
 }
 
 func (s *TeamStorage) Insert(ctx context.Context, t auth.Team) error {
@@ -53,8 +55,9 @@ func (s *TeamStorage) Update(ctx context.Context, t auth.Team) error {
 
 	defer span.Finish()
 
-	_, err = collection.ReplaceOne(ctx, mongoBSON.M{"_id": t.Name}, t)
-	if err == mongo.ErrNoDocuments {
+	// quotas are owned by the quota storages, only the tags are updated
+	result, err := collection.UpdateOne(ctx, mongoBSON.M{"_id": t.Name}, mongoBSON.M{"$set": mongoBSON.M{"tags": t.Tags}})
+	if err == nil && result.MatchedCount == 0 {
 		err = auth.ErrTeamNotFound
 	}
 	span.SetError(err)
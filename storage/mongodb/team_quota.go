@@ -17,3 +17,25 @@ func teamQuotaStorage() quota.QuotaStorage {
 		},
 	}
 }
+
+func teamJobQuotaStorage() quota.QuotaStorage {
+	return &quotaStorage{
+		collection: "teams",
+		field:      "jobquota",
+		unset:      quota.UnlimitedQuota,
+		query: func(name string) mongoBSON.M {
+			return mongoBSON.M{"_id": name}
+		},
+	}
+}
+
+func teamJobRunQuotaStorage() quota.QuotaStorage {
+	return &quotaStorage{
+		collection: "teams",
+		field:      "jobrunquota",
+		unset:      quota.UnlimitedQuota,
+		query: func(name string) mongoBSON.M {
+			return mongoBSON.M{"_id": name}
+		},
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 
 	"github.com/tsuru/tsuru/types/auth"
+	"github.com/tsuru/tsuru/types/quota"
 	check "gopkg.in/check.v1"
 )
 
@@ -50,6 +51,26 @@ func (s *TeamSuite) TeamUpdate(c *check.C) {
 	c.Assert(team.Tags, check.DeepEquals, t.Tags)
 }
 
+func (s *TeamSuite) TestTeamUpdateKeepsQuotas(c *check.C) {
+	t := auth.Team{
+		Name:        "teamname",
+		Tags:        []string{"tag1"},
+		Quota:       quota.Quota{Limit: 3, InUse: 1},
+		JobQuota:    quota.Quota{Limit: 5, InUse: 2},
+		JobRunQuota: quota.UnlimitedQuota,
+	}
+	err := s.TeamStorage.Insert(context.TODO(), t)
+	c.Assert(err, check.IsNil)
+	err = s.TeamStorage.Update(context.TODO(), auth.Team{Name: t.Name, Tags: []string{"tag2"}})
+	c.Assert(err, check.IsNil)
+	team, err := s.TeamStorage.FindByName(context.TODO(), t.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(team.Tags, check.DeepEquals, []string{"tag2"})
+	c.Assert(team.Quota, check.DeepEquals, t.Quota)
+	c.Assert(team.JobQuota, check.DeepEquals, t.JobQuota)
+	c.Assert(team.JobRunQuota, check.DeepEquals, t.JobRunQuota)
+}
+
 func (s *TeamSuite) TeamUpdateNotFound(c *check.C) {
 	t := auth.Team{Name: "teamname", CreatingUser: "me@example.com", Tags: []string{"tag1"}}
 	err := s.TeamStorage.Update(context.TODO(), t)
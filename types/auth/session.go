@@ -0,0 +1,25 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// Session describes an active login of a user. The ID is derived from the
+// token value, which is never exposed.
+type Session struct {
+	ID        string     `json:"id"`
+	Engine    string     `json:"engine"`
+	CreatedAt time.Time  `json:"createdAt"`
+	LastUsed  *time.Time `json:"lastUsed,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	ClientIP  string     `json:"clientIP,omitempty"`
+	UserAgent string     `json:"userAgent,omitempty"`
+	Current   bool       `json:"current"`
+}
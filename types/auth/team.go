@@ -20,6 +20,11 @@ type Team struct {
 	CreatingUser string      `json:"creatingUser"`
 	Tags         []string    `json:"tags"`
 	Quota        quota.Quota `json:"quota"`
+	// JobQuota limits the number of jobs owned by the team.
+	JobQuota quota.Quota `json:"jobQuota"`
+	// JobRunQuota limits the number of job units running at the same time
+	// for the team in each pool, its usage is taken from the provisioner.
+	JobRunQuota quota.Quota `json:"jobRunQuota"`
 }
 
 func (t Team) GetName() string {
@@ -27,11 +27,51 @@ type User struct {
 
 	APIKeyLastAccess   time.Time
 	APIKeyUsageCounter int64
+
+	MFA *MFA `json:"-"`
+}
+
+// MFA holds the TOTP based multi-factor authentication enrolment of an
+// user. The secret is only taken into account after the enrolment is
+// confirmed with a valid code.
+type MFA struct {
+	Secret  string
+	Enabled bool
+	// RecoveryCodes contains hashes of single use codes which may replace a
+	// TOTP code when the user loses access to the authenticator.
+	RecoveryCodes []string
+	// LastStep is the last TOTP time step accepted, used to prevent the same
+	// code from being used twice.
+	LastStep int64
+	// FailedAttempts counts the invalid codes since the last accepted one,
+	// the verification is locked until LockedUntil once it reaches the
+	// limit.
+	FailedAttempts int
+	LockedUntil    time.Time
+}
+
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URL    string `json:"url"`
+}
+
+func (u *User) MFAEnabled() bool {
+	return u.MFA != nil && u.MFA.Enabled
 }
 
 type RoleInstance struct {
 	Name         string
 	ContextValue string
+	// ExpiresAt, when set, denotes a just-in-time role assignment which is
+	// no longer valid after the given time.
+	ExpiresAt     *time.Time `json:",omitempty" bson:",omitempty"`
+	Justification string     `json:",omitempty" bson:",omitempty"`
+}
+
+// Expired returns whether the role instance has an expiration time and it
+// has already been reached.
+func (r RoleInstance) Expired(now time.Time) bool {
+	return r.ExpiresAt != nil && !now.Before(*r.ExpiresAt)
 }
 
 type ErrTeamStillUsed struct {
@@ -44,6 +84,11 @@ var (
 	ErrInvalidKey         = errors.New("invalid key")
 	ErrKeyDisabled        = errors.New("key management is disabled")
 	ErrEmailFromTeamToken = errors.New("email from team token")
+	ErrMFARequired        = errors.New("multi-factor authentication code required")
+	ErrInvalidMFACode     = errors.New("invalid multi-factor authentication code")
+	ErrMFANotEnrolled     = errors.New("multi-factor authentication is not enrolled")
+	ErrMFAAlreadyEnabled  = errors.New("multi-factor authentication is already enabled")
+	ErrMFALocked          = errors.New("too many invalid multi-factor authentication codes, try again later")
 )
 
 func (e *ErrTeamStillUsed) Error() string {
@@ -160,6 +160,8 @@ var (
 	TargetTypeWebhook         = TargetType("webhook")
 	TargetTypeGC              = TargetType("gc")
 	TargetTypeRouter          = TargetType("router")
+	TargetTypeJobWorkflow     = TargetType("job-workflow")
+	TargetTypeJobTemplate     = TargetType("job-template")
 
 	ErrInvalidTargetType = errors.New("invalid event target type")
 )
@@ -206,6 +208,10 @@ func GetTargetType(t string) (TargetType, error) {
 		return TargetTypeWebhook, nil
 	case "router":
 		return TargetTypeRouter, nil
+	case "job-workflow":
+		return TargetTypeJobWorkflow, nil
+	case "job-template":
+		return TargetTypeJobTemplate, nil
 	}
 	return TargetType(""), ErrInvalidTargetType
 }
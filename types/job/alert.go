@@ -0,0 +1,37 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+// AlertEventKind is the internal kind of the events emitted when an alert
+// rule of a job is violated, webhooks may filter on it to notify the team.
+const AlertEventKind = "job.alert"
+
+type AlertType string
+
+const (
+	AlertConsecutiveFailures = AlertType("consecutive-failures")
+	AlertMaxDuration         = AlertType("max-duration")
+	AlertMissedSchedule      = AlertType("missed-schedule")
+)
+
+// AlertRules describes when the executions of a job must raise an alert,
+// zero values disable the respective rule.
+type AlertRules struct {
+	// ConsecutiveFailures alerts once the job fails this many times in a
+	// row, the alert is raised again only after a successful run.
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
+	// MaxDurationSeconds alerts when a run takes longer than this to finish.
+	MaxDurationSeconds int64 `json:"maxDurationSeconds,omitempty"`
+	// MissedSchedule alerts when a scheduled execution is not started,
+	// usually because the startingDeadlineSeconds has passed.
+	MissedSchedule bool `json:"missedSchedule,omitempty"`
+}
+
+func (r *AlertRules) Validate() error {
+	if r.ConsecutiveFailures < 0 || r.MaxDurationSeconds < 0 {
+		return ErrInvalidAlertRules
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import "github.com/tsuru/tsuru/types/provision"
+
+// AutoScaleSpec makes the executions of a job follow the depth of a queue
+// instead of a schedule. The prometheus queries are polled and as many
+// executions as they report, bounded by the parallelism limits, are spawned.
+type AutoScaleSpec struct {
+	MinParallelism int32 `json:"minParallelism"`
+	MaxParallelism int32 `json:"maxParallelism"`
+	// PollingInterval is how often, in seconds, the queries are evaluated.
+	PollingInterval int32                           `json:"pollingInterval,omitempty"`
+	Prometheus      []provision.AutoScalePrometheus `json:"prometheus"`
+}
+
+func (s *AutoScaleSpec) Validate() error {
+	if s.MaxParallelism <= 0 || s.MinParallelism < 0 || s.MinParallelism > s.MaxParallelism {
+		return ErrInvalidAutoScaleParallelism
+	}
+	if s.PollingInterval < 0 {
+		return ErrInvalidAutoScalePolling
+	}
+	if len(s.Prometheus) == 0 {
+		return ErrAutoScaleNoTriggers
+	}
+	for _, p := range s.Prometheus {
+		if p.Name == "" || p.Query == "" {
+			return ErrInvalidAutoScaleTrigger
+		}
+	}
+	return nil
+}
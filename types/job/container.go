@@ -0,0 +1,62 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"regexp"
+
+	appTypes "github.com/tsuru/tsuru/types/app"
+	bindTypes "github.com/tsuru/tsuru/types/bind"
+)
+
+// MainContainerName is the name of the container running the job itself.
+const MainContainerName = "job"
+
+var containerNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// Container is an additional container of the job executions. Init
+// containers run to completion, in order, before the job container starts.
+// Sidecars run along the job container and are stopped once it finishes.
+type Container struct {
+	Name    string             `json:"name"`
+	Image   string             `json:"image"`
+	Command []string           `json:"command,omitempty"`
+	Envs    []bindTypes.EnvVar `json:"envs,omitempty"`
+	// Plan holds the resources of the container, the default plan of the
+	// pool is used when it's not set.
+	Plan appTypes.Plan `json:"plan"`
+}
+
+// ValidateContainers checks the init containers and sidecars of the job
+// spec, their names must be unique across the pod.
+func (s *JobSpec) ValidateContainers() error {
+	names := map[string]struct{}{MainContainerName: {}}
+	for _, containers := range [][]Container{s.InitContainers, s.Sidecars} {
+		for _, c := range containers {
+			if !containerNameRegexp.MatchString(c.Name) {
+				return ErrInvalidContainerName
+			}
+			if _, ok := names[c.Name]; ok {
+				return ErrDuplicatedContainerName
+			}
+			names[c.Name] = struct{}{}
+			if c.Image == "" {
+				return ErrContainerImageRequired
+			}
+		}
+	}
+	return nil
+}
+
+// ContainerImages returns the images of the init containers and sidecars.
+func (s *JobSpec) ContainerImages() []string {
+	var images []string
+	for _, containers := range [][]Container{s.InitContainers, s.Sidecars} {
+		for _, c := range containers {
+			images = append(images, c.Image)
+		}
+	}
+	return images
+}
@@ -7,17 +7,52 @@ package job
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
 	ErrJobNotFound              = errors.New("Job not found")
 	ErrJobUnitNotFound          = errors.New("Job unit not found")
+	ErrJobRunNotFound           = errors.New("Job run not found")
 	MaxAttempts                 = 5
 	ErrMaxAttemptsReached       = fmt.Errorf("Unable to generate unique job name: max attempts reached (%d)", MaxAttempts)
 	ErrJobAlreadyExists         = errors.New("a job with the same name already exists")
 	ErrInvalidSchedule          = errors.New("invalid schedule")
 	ErrInvalidConcurrencyPolicy = errors.New("invalid concurrency policy, allowed values are: Allow, Forbid, Replace")
 	ErrInvalidDeployKind        = errors.New("invalid deploy kind")
+
+	ErrInvalidTTLSecondsAfterFinished = errors.New("ttlSecondsAfterFinished must not be negative")
+	ErrInvalidStartingDeadline        = errors.New("startingDeadlineSeconds must not be negative")
+	ErrInvalidHistoryLimit            = errors.New("jobs history limits must not be negative")
+	ErrInvalidTimeZone                = errors.New("invalid time zone, it must be an IANA time zone name")
+	ErrSuspendManualJob               = errors.New("manual jobs have no schedule to suspend or resume")
+	ErrInvalidAlertRules              = errors.New("alert rules thresholds must not be negative")
+
+	ErrInvalidAutoScaleParallelism = errors.New("autoscale maxParallelism must be positive and greater than or equal to minParallelism")
+	ErrInvalidAutoScalePolling     = errors.New("autoscale pollingInterval must not be negative")
+	ErrAutoScaleNoTriggers         = errors.New("autoscale requires at least one prometheus trigger")
+	ErrInvalidAutoScaleTrigger     = errors.New("autoscale prometheus triggers require a name and a query")
+	ErrAutoScaleScheduledJob       = errors.New("autoscale is only available to manual jobs, they must not have a schedule")
+
+	ErrTemplateNotFound      = errors.New("Job template not found")
+	ErrTemplateAlreadyExists = errors.New("a job template with the same name already exists")
+	ErrTemplateNoImage       = errors.New("job template requires an image")
+	ErrTemplateInvalidParam  = errors.New("invalid template param name")
+
+	ErrInvalidContainerName    = errors.New("invalid container name, it must be a lowercase RFC 1123 label")
+	ErrDuplicatedContainerName = errors.New("container names must be unique and must not be \"job\"")
+	ErrContainerImageRequired  = errors.New("init containers and sidecars require an image")
+	ErrSidecarsNotSupported    = errors.New("sidecars require native sidecar containers, available on Kubernetes 1.29 or newer")
+
+	ErrWorkflowNotFound         = errors.New("Workflow not found")
+	ErrWorkflowAlreadyExists    = errors.New("a workflow with the same name already exists")
+	ErrWorkflowRunNotFound      = errors.New("Workflow run not found")
+	ErrWorkflowRunNotRunning    = errors.New("workflow run is not running")
+	ErrWorkflowRunStillRunning  = errors.New("workflow run is still running")
+	ErrWorkflowNodeNotFound     = errors.New("node is not part of the workflow")
+	ErrWorkflowCycle            = errors.New("workflow edges must not form a cycle")
+	ErrWorkflowNoNodes          = errors.New("workflow must have at least one node")
+	ErrInvalidWorkflowCondition = errors.New("invalid edge condition, allowed values are: success, failure")
 )
 
 type JobCreationError struct {
@@ -28,3 +63,12 @@ type JobCreationError struct {
 func (e *JobCreationError) Error() string {
 	return fmt.Sprintf("tsuru failed to create job %q: %s", e.Job, e.Err)
 }
+
+type ErrJobInWorkflows struct {
+	Job       string
+	Workflows []string
+}
+
+func (e *ErrJobInWorkflows) Error() string {
+	return fmt.Sprintf("job %q is used by the workflows: %s", e.Job, strings.Join(e.Workflows, ", "))
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/tsuru/tsuru/types/bind"
 	bindTypes "github.com/tsuru/tsuru/types/bind"
 	"github.com/tsuru/tsuru/types/provision"
+	"github.com/tsuru/tsuru/types/volume"
 )
 
 // Job is another main type in tsuru as of version 1.13
@@ -32,6 +33,12 @@ type Job struct {
 	DeployOptions *DeployOptions `json:"deployOptions"`
 
 	Spec JobSpec `json:"spec"`
+
+	Alerts *AlertRules `json:"alerts,omitempty"`
+
+	// Template is set on jobs created from a template, they are updated
+	// along with it.
+	Template *TemplateRef `json:"template,omitempty"`
 }
 
 func (job *Job) GetName() string {
@@ -59,6 +66,26 @@ type JobSpec struct {
 	Container             ContainerInfo             `json:"container"`
 	ServiceEnvs           []bindTypes.ServiceEnvVar `json:"-"`
 	Envs                  []bindTypes.EnvVar        `json:"envs"`
+	// TTLSecondsAfterFinished is how long finished executions are kept in
+	// the cluster. Defaults to one day.
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// Suspend pauses the scheduled executions of a cron job, manual
+	// triggers still work.
+	Suspend bool `json:"suspend"`
+	// StartingDeadlineSeconds is how late a missed execution may still
+	// start, executions missed for longer are skipped.
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+	// TimeZone is the IANA time zone of the schedule, defaults to the time
+	// zone of the cluster.
+	TimeZone                   string `json:"timeZone,omitempty"`
+	SuccessfulJobsHistoryLimit *int32 `json:"successfulJobsHistoryLimit,omitempty"`
+	FailedJobsHistoryLimit     *int32 `json:"failedJobsHistoryLimit,omitempty"`
+	// AutoScale spawns executions of a manual job following prometheus
+	// queries, usually the depth of the queue the job consumes.
+	AutoScale *AutoScaleSpec `json:"autoscale,omitempty"`
+
+	InitContainers []Container `json:"initContainers,omitempty"`
+	Sidecars       []Container `json:"sidecars,omitempty"`
 }
 
 type Filter struct {
@@ -67,7 +94,29 @@ type Filter struct {
 	UserOwner string
 	Pool      string
 	Pools     []string
-	Extra     map[string][]string
+	// Template filters the jobs created from the given template.
+	Template string
+	Extra    map[string][]string
+}
+
+// TriggerOptions holds the parameters of a single job execution. They are
+// applied only to the job spawned by the trigger and never change the job
+// spec.
+type TriggerOptions struct {
+	Envs    []bindTypes.EnvVar `json:"envs,omitempty"`
+	Args    []string           `json:"args,omitempty"`
+	Command []string           `json:"command,omitempty"`
+	// TriggeredBy is the user requesting the execution, recorded in the
+	// job run history.
+	TriggeredBy string `json:"-"`
+	// WorkflowRun is the workflow run that spawned the execution, used to
+	// trigger the downstream jobs once it finishes.
+	WorkflowRun string `json:"-"`
+}
+
+// IsEmpty reports whether the execution runs the job as it is.
+func (o TriggerOptions) IsEmpty() bool {
+	return len(o.Envs) == 0 && len(o.Args) == 0 && len(o.Command) == 0
 }
 
 type AddInstanceArgs struct {
@@ -87,7 +136,7 @@ type JobService interface {
 	GetByName(ctx context.Context, name string) (*Job, error)
 	List(ctx context.Context, filter *Filter) ([]Job, error)
 	RemoveJob(ctx context.Context, job *Job) error
-	Trigger(ctx context.Context, job *Job) error
+	Trigger(ctx context.Context, job *Job, opts TriggerOptions) error
 	UpdateJob(ctx context.Context, newJob, oldJob *Job, user *authTypes.User) error
 	AddServiceEnv(ctx context.Context, job *Job, addArgs AddInstanceArgs) error
 	RemoveServiceEnv(ctx context.Context, job *Job, removeArgs RemoveInstanceArgs) error
@@ -96,6 +145,13 @@ type JobService interface {
 	BaseImageName(ctx context.Context, job *Job) (string, error)
 	KillUnit(ctx context.Context, job *Job, unitName string, force bool) error
 	Deploy(ctx context.Context, opts DeployOptions, job *Job, output io.Writer) (string, error)
+	RecordRun(ctx context.Context, run Run) error
+	ListRuns(ctx context.Context, job *Job, filter RunFilter) ([]Run, error)
+	GetRun(ctx context.Context, job *Job, runName string) (*Run, error)
+	Suspend(ctx context.Context, job *Job) error
+	Resume(ctx context.Context, job *Job) error
+	SetAutoScale(ctx context.Context, job *Job, spec AutoScaleSpec) error
+	RemoveAutoScale(ctx context.Context, job *Job) error
 }
 
 type JobInfo struct {
@@ -103,5 +159,6 @@ type JobInfo struct {
 	Job                  *Job                       `json:"job,omitempty"`
 	Units                []provision.Unit           `json:"units,omitempty"`
 	ServiceInstanceBinds []bind.ServiceInstanceBind `json:"serviceInstanceBinds,omitempty"`
+	VolumeBinds          []volume.VolumeBind        `json:"volumeBinds,omitempty"`
 	DashboardURL         string                     `json:"dashboardURL,omitempty"`
 }
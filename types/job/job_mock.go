@@ -20,7 +20,7 @@ type MockJobService struct {
 	OnList             func(*Filter) ([]Job, error)
 	OnRemoveJob        func(*Job) error
 	OnRemoveJobProv    func(*Job) error
-	OnTrigger          func(*Job) error
+	OnTrigger          func(*Job, TriggerOptions) error
 	OnAddServiceEnv    func(*Job, AddInstanceArgs) error
 	OnRemoveServiceEnv func(*Job, RemoveInstanceArgs) error
 	OnUpdateJob        func(*Job, *Job, *authTypes.User) error
@@ -29,6 +29,13 @@ type MockJobService struct {
 	OnBaseImageName    func(context.Context, *Job) (string, error)
 	OnKillUnit         func(*Job, string) error
 	OnDeploy           func(context.Context, DeployOptions, *Job, io.Writer) (string, error)
+	OnRecordRun        func(Run) error
+	OnListRuns         func(*Job, RunFilter) ([]Run, error)
+	OnGetRun           func(*Job, string) (*Run, error)
+	OnSuspend          func(*Job) error
+	OnResume           func(*Job) error
+	OnSetAutoScale     func(*Job, AutoScaleSpec) error
+	OnRemoveAutoScale  func(*Job) error
 }
 
 func (m *MockJobService) CreateJob(ctx context.Context, job *Job, user *authTypes.User) error {
@@ -66,11 +73,11 @@ func (m *MockJobService) RemoveJob(ctx context.Context, job *Job) error {
 	return m.OnRemoveJob(job)
 }
 
-func (m *MockJobService) Trigger(ctx context.Context, job *Job) error {
+func (m *MockJobService) Trigger(ctx context.Context, job *Job, opts TriggerOptions) error {
 	if m.OnTrigger == nil {
 		return nil
 	}
-	return m.OnTrigger(job)
+	return m.OnTrigger(job, opts)
 }
 
 func (m *MockJobService) UpdateJob(ctx context.Context, newJob, oldJob *Job, user *authTypes.User) error {
@@ -128,3 +135,52 @@ func (m *MockJobService) Deploy(ctx context.Context, opts DeployOptions, job *Jo
 	}
 	return m.OnDeploy(ctx, opts, job, output)
 }
+
+func (m *MockJobService) RecordRun(ctx context.Context, run Run) error {
+	if m.OnRecordRun == nil {
+		return nil
+	}
+	return m.OnRecordRun(run)
+}
+
+func (m *MockJobService) ListRuns(ctx context.Context, job *Job, filter RunFilter) ([]Run, error) {
+	if m.OnListRuns == nil {
+		return nil, nil
+	}
+	return m.OnListRuns(job, filter)
+}
+
+func (m *MockJobService) GetRun(ctx context.Context, job *Job, runName string) (*Run, error) {
+	if m.OnGetRun == nil {
+		return nil, nil
+	}
+	return m.OnGetRun(job, runName)
+}
+
+func (m *MockJobService) Suspend(ctx context.Context, job *Job) error {
+	if m.OnSuspend == nil {
+		return nil
+	}
+	return m.OnSuspend(job)
+}
+
+func (m *MockJobService) Resume(ctx context.Context, job *Job) error {
+	if m.OnResume == nil {
+		return nil
+	}
+	return m.OnResume(job)
+}
+
+func (m *MockJobService) SetAutoScale(ctx context.Context, job *Job, spec AutoScaleSpec) error {
+	if m.OnSetAutoScale == nil {
+		return nil
+	}
+	return m.OnSetAutoScale(job, spec)
+}
+
+func (m *MockJobService) RemoveAutoScale(ctx context.Context, job *Job) error {
+	if m.OnRemoveAutoScale == nil {
+		return nil
+	}
+	return m.OnRemoveAutoScale(job)
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import "time"
+
+type RunStatus string
+
+const (
+	RunStatusRunning   = RunStatus("running")
+	RunStatusSucceeded = RunStatus("succeeded")
+	RunStatusFailed    = RunStatus("failed")
+)
+
+// Run is a single execution of a job, either scheduled or manually
+// triggered. Runs are recorded by the provisioner and outlive the objects
+// created in the cluster.
+type Run struct {
+	// Name is the name of the execution in the provisioner, the same name
+	// used by the job units.
+	Name        string     `json:"name"`
+	Job         string     `json:"job"`
+	Pool        string     `json:"pool"`
+	Manual      bool       `json:"manual"`
+	TriggeredBy string     `json:"triggeredBy,omitempty"`
+	WorkflowRun string     `json:"workflowRun,omitempty"`
+	Status      RunStatus  `json:"status"`
+	StartTime   time.Time  `json:"startTime"`
+	EndTime     *time.Time `json:"endTime,omitempty"`
+	ExitCode    *int32     `json:"exitCode,omitempty"`
+	Attempts    int32      `json:"attempts"`
+	Reason      string     `json:"reason,omitempty"`
+	Message     string     `json:"message,omitempty"`
+	// Units holds the names of the units spawned by the run, used to filter
+	// the logs of a single run.
+	Units []string `json:"units,omitempty"`
+}
+
+// IsFinished reports whether the execution is over.
+func (r Run) IsFinished() bool {
+	return r.Status == RunStatusSucceeded || r.Status == RunStatusFailed
+}
+
+type RunFilter struct {
+	Status RunStatus
+	Limit  int
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"context"
+
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	bindTypes "github.com/tsuru/tsuru/types/bind"
+)
+
+// TemplateParam declares a placeholder of a job template, referenced as
+// {{.name}} in the image, command, schedule and env values of the template.
+type TemplateParam struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// TemplateSpec is the part of the job definition provided by a template.
+type TemplateSpec struct {
+	Image    string             `json:"image"`
+	Command  []string           `json:"command,omitempty"`
+	Schedule string             `json:"schedule,omitempty"`
+	Manual   bool               `json:"manual,omitempty"`
+	Plan     string             `json:"plan,omitempty"`
+	Envs     []bindTypes.EnvVar `json:"envs,omitempty"`
+}
+
+// Template is a reusable job definition. Templates without a team owner are
+// managed by admins and available to every team, the remaining ones are
+// available to the jobs of their team owner.
+type Template struct {
+	Name        string          `json:"name"`
+	TeamOwner   string          `json:"teamOwner,omitempty"`
+	Owner       string          `json:"owner"`
+	Description string          `json:"description"`
+	Params      []TemplateParam `json:"params,omitempty"`
+	Spec        TemplateSpec    `json:"spec"`
+}
+
+// TemplateRef records the template, and the params, a job was created from.
+type TemplateRef struct {
+	Name   string            `json:"name"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+type TemplateFilter struct {
+	// TeamOwners filters the team templates, templates managed by admins
+	// are always listed.
+	TeamOwners []string
+}
+
+// TemplateApplyResult is the outcome of updating a job after a change in its
+// template.
+type TemplateApplyResult struct {
+	Job   string `json:"job"`
+	Error string `json:"error,omitempty"`
+}
+
+type TemplateService interface {
+	CreateTemplate(ctx context.Context, tmpl *Template) error
+	UpdateTemplate(ctx context.Context, tmpl *Template) error
+	GetTemplate(ctx context.Context, name string) (*Template, error)
+	ListTemplates(ctx context.Context, filter *TemplateFilter) ([]Template, error)
+	RemoveTemplate(ctx context.Context, tmpl *Template) error
+	// RenderJob fills the job with the template definition, replacing the
+	// placeholders by the given params.
+	RenderJob(ctx context.Context, tmpl *Template, params map[string]string, job *Job) error
+	// ApplyTemplate renders every job created from the template again,
+	// with the params each one of them was created with. Only the jobs the
+	// token is allowed to update are changed.
+	ApplyTemplate(ctx context.Context, tmpl *Template, t authTypes.Token) ([]TemplateApplyResult, error)
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"context"
+	"time"
+)
+
+type WorkflowCondition string
+
+const (
+	WorkflowOnSuccess = WorkflowCondition("success")
+	WorkflowOnFailure = WorkflowCondition("failure")
+)
+
+// WorkflowEdge links two nodes of a workflow, the To job is triggered once
+// the From job finishes with the outcome described by On.
+type WorkflowEdge struct {
+	From string            `json:"from"`
+	To   string            `json:"to"`
+	On   WorkflowCondition `json:"on"`
+}
+
+// Workflow is a directed acyclic graph of jobs. Nodes without incoming edges
+// are triggered when the workflow runs, the remaining ones are triggered as
+// their upstream jobs finish.
+type Workflow struct {
+	Name        string         `json:"name"`
+	TeamOwner   string         `json:"teamOwner"`
+	Owner       string         `json:"owner"`
+	Description string         `json:"description"`
+	Nodes       []string       `json:"nodes"`
+	Edges       []WorkflowEdge `json:"edges"`
+}
+
+type WorkflowRunStatus string
+
+const (
+	WorkflowRunRunning   = WorkflowRunStatus("running")
+	WorkflowRunSucceeded = WorkflowRunStatus("succeeded")
+	WorkflowRunFailed    = WorkflowRunStatus("failed")
+	WorkflowRunCanceled  = WorkflowRunStatus("canceled")
+)
+
+type WorkflowNodeStatus string
+
+const (
+	WorkflowNodePending   = WorkflowNodeStatus("pending")
+	WorkflowNodeRunning   = WorkflowNodeStatus("running")
+	WorkflowNodeSucceeded = WorkflowNodeStatus("succeeded")
+	WorkflowNodeFailed    = WorkflowNodeStatus("failed")
+	WorkflowNodeSkipped   = WorkflowNodeStatus("skipped")
+	WorkflowNodeCanceled  = WorkflowNodeStatus("canceled")
+)
+
+// IsFinished reports whether the node will not change its status anymore.
+func (s WorkflowNodeStatus) IsFinished() bool {
+	return s != WorkflowNodePending && s != WorkflowNodeRunning
+}
+
+// WorkflowRun is a single execution of a workflow.
+type WorkflowRun struct {
+	ID        string            `json:"id" bson:"_id"`
+	Workflow  string            `json:"workflow"`
+	Status    WorkflowRunStatus `json:"status"`
+	Owner     string            `json:"owner"`
+	StartTime time.Time         `json:"startTime"`
+	EndTime   *time.Time        `json:"endTime,omitempty"`
+	// EventID is the root of the event tree of the run, every node
+	// triggered by the run has an event pointing to it.
+	EventID string `json:"eventID,omitempty"`
+	// RerunOf is the run this one was created from, nodes outside the
+	// rerun keep their outcome from the original run.
+	RerunOf string            `json:"rerunOf,omitempty"`
+	Edges   []WorkflowEdge    `json:"edges"`
+	Nodes   []WorkflowRunNode `json:"nodes"`
+}
+
+type WorkflowRunNode struct {
+	Job    string             `json:"job"`
+	Status WorkflowNodeStatus `json:"status"`
+	// Run is the name of the job run spawned for the node.
+	Run       string     `json:"run,omitempty"`
+	EventID   string     `json:"eventID,omitempty"`
+	StartTime *time.Time `json:"startTime,omitempty"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+	Message   string     `json:"message,omitempty"`
+}
+
+type WorkflowFilter struct {
+	TeamOwners []string
+	// Job filters the workflows using the given job as a node.
+	Job string
+}
+
+type WorkflowService interface {
+	CreateWorkflow(ctx context.Context, wf *Workflow) error
+	UpdateWorkflow(ctx context.Context, wf *Workflow) error
+	GetWorkflow(ctx context.Context, name string) (*Workflow, error)
+	ListWorkflows(ctx context.Context, filter *WorkflowFilter) ([]Workflow, error)
+	RemoveWorkflow(ctx context.Context, wf *Workflow) error
+	RunWorkflow(ctx context.Context, wf *Workflow, owner string) (*WorkflowRun, error)
+	ListWorkflowRuns(ctx context.Context, wf *Workflow, limit int) ([]WorkflowRun, error)
+	GetWorkflowRun(ctx context.Context, wf *Workflow, id string) (*WorkflowRun, error)
+	CancelWorkflowRun(ctx context.Context, run *WorkflowRun, owner string) error
+	RerunWorkflow(ctx context.Context, wf *Workflow, run *WorkflowRun, fromNode, owner string) (*WorkflowRun, error)
+}
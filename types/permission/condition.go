@@ -0,0 +1,78 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package permission
+
+import (
+	"fmt"
+	"path"
+)
+
+// Condition restricts a permission granted by a role to objects matching all
+// of its attributes: every tag must be present in the object and its pool
+// must match at least one of the pool patterns.
+//
+// Attributes are read from the object when the permission is checked. Tags
+// and metadata labels may be changed by anyone allowed to update the app or
+// job, and the pool by anyone allowed to change it, so a condition only
+// limits users who are not able to edit those attributes themselves.
+type Condition struct {
+	Permission string   `json:"permission"`
+	Tags       []string `json:"tags,omitempty"`
+	Pools      []string `json:"pools,omitempty"`
+}
+
+func (c *Condition) Validate() error {
+	if len(c.Tags) == 0 && len(c.Pools) == 0 {
+		return ErrEmptyCondition
+	}
+	for _, pattern := range c.Pools {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return &ErrInvalidCondition{Permission: c.Permission, Reason: fmt.Sprintf("invalid pool pattern %q", pattern)}
+		}
+	}
+	return nil
+}
+
+// Matches returns whether the checked contexts satisfy the condition. The
+// pool is read from the CtxPool contexts and tags from the CtxTag ones, a
+// condition is never satisfied when the attributes are not available.
+func (c *Condition) Matches(contexts []PermissionContext) bool {
+	if len(c.Pools) > 0 && !c.matchPool(contexts) {
+		return false
+	}
+	for _, tag := range c.Tags {
+		if !hasContext(contexts, CtxTag, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Condition) String() string {
+	return fmt.Sprintf("tags=%v pools=%v", c.Tags, c.Pools)
+}
+
+func (c *Condition) matchPool(contexts []PermissionContext) bool {
+	for _, ctx := range contexts {
+		if ctx.CtxType != CtxPool {
+			continue
+		}
+		for _, pattern := range c.Pools {
+			if ok, _ := path.Match(pattern, ctx.Value); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasContext(contexts []PermissionContext, ctxType ContextType, value string) bool {
+	for _, ctx := range contexts {
+		if ctx.CtxType == ctxType && ctx.Value == value {
+			return true
+		}
+	}
+	return false
+}
@@ -25,6 +25,11 @@ var (
 	CtxVolume          = ContextType("volume")
 	CtxRouter          = ContextType("router")
 
+	// CtxTag is never used as a role context type, it only carries tags and
+	// labels of the checked object so that permission conditions can be
+	// evaluated.
+	CtxTag = ContextType("tag")
+
 	ContextTypes = []ContextType{
 		CtxGlobal, CtxApp, CtxTeam, CtxUser, CtxPool, CtxService, CtxServiceInstance, CtxVolume, CtxRouter, CtxJob,
 	}
@@ -38,8 +43,9 @@ type PermissionContext struct {
 }
 
 type Permission struct {
-	Scheme  *PermissionScheme
-	Context PermissionContext
+	Scheme    *PermissionScheme
+	Context   PermissionContext
+	Condition *Condition
 }
 
 func (p *Permission) String() string {
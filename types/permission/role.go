@@ -16,6 +16,7 @@ var (
 	ErrInvalidRoleName       = errors.New("invalid role name")
 	ErrInvalidPermissionName = errors.New("invalid permission name")
 	ErrRemoveRoleWithUsers   = errors.New("role has users assigned. you must dissociate them before remove the role.")
+	ErrEmptyCondition        = errors.New("condition must define at least one tag or pool")
 
 	RoleEventUserCreate = &RoleEvent{
 		Name:        "user-create",
@@ -69,3 +70,12 @@ type ErrPermissionNotAllowed struct {
 func (e ErrPermissionNotAllowed) Error() string {
 	return fmt.Sprintf("permission %q not allowed with context of type %q", e.Permission, e.ContextType)
 }
+
+type ErrInvalidCondition struct {
+	Permission string
+	Reason     string
+}
+
+func (e ErrInvalidCondition) Error() string {
+	return fmt.Sprintf("invalid condition for permission %q: %s", e.Permission, e.Reason)
+}
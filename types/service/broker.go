@@ -23,6 +23,32 @@ type Broker struct {
 	URL string
 	// Config is the configuration used to setup a client for the broker
 	Config BrokerConfig
+	// PlanVisibility holds the rules restricting which teams and pools may
+	// use the plans offered by the broker.
+	PlanVisibility []PlanVisibility
+}
+
+// PlanVisibility restricts the usage of plans offered by a broker. Plans
+// without any matching rule are visible to everyone allowed to see the
+// service; when more than one rule matches a plan, it is enough for one of
+// them to allow it.
+type PlanVisibility struct {
+	// Service is the name of the service in the broker catalog. An empty
+	// value matches every service.
+	Service string
+	// Plan is the name of the plan. An empty value matches every plan.
+	Plan string
+	// Teams lists the teams allowed to use the plan. An empty list allows
+	// every team.
+	Teams []string
+	// Pools lists the pools where the plan is allowed. An empty list allows
+	// every pool.
+	Pools []string
+}
+
+// Matches reports whether the rule applies to the given service and plan.
+func (v PlanVisibility) Matches(service, plan string) bool {
+	return (v.Service == "" || v.Service == service) && (v.Plan == "" || v.Plan == plan)
 }
 
 // BrokerConfig exposes configuration used to talk to the broker API.
@@ -29,6 +29,7 @@ type VolumeBindID struct {
 	App        string
 	MountPoint string
 	Volume     string
+	Job        string `bson:",omitempty" json:",omitempty"`
 }
 
 type VolumeBind struct {
@@ -57,6 +58,7 @@ func (v *Volume) UnmarshalPlan(result interface{}) error {
 type BindOpts struct {
 	Volume     *Volume
 	AppName    string
+	JobName    string
 	MountPoint string
 	ReadOnly   bool
 }
@@ -81,6 +83,11 @@ type VolumeService interface {
 	UnbindApp(ctx context.Context, opts *BindOpts) error
 	BindsForApp(ctx context.Context, v *Volume, appName string) ([]VolumeBind, error)
 	Binds(ctx context.Context, v *Volume) ([]VolumeBind, error)
+
+	ListByJob(ctx context.Context, jobName string) ([]Volume, error)
+	BindJob(ctx context.Context, opts *BindOpts) error
+	UnbindJob(ctx context.Context, opts *BindOpts) error
+	BindsForJob(ctx context.Context, v *Volume, jobName string) ([]VolumeBind, error)
 }
 
 type VolumeStorage interface {
@@ -93,6 +100,7 @@ type VolumeStorage interface {
 	RemoveBind(ctx context.Context, id VolumeBindID) error
 	Binds(ctx context.Context, volumeName string) ([]VolumeBind, error)
 	BindsForApp(ctx context.Context, volumeName, appName string) ([]VolumeBind, error)
+	BindsForJob(ctx context.Context, volumeName, jobName string) ([]VolumeBind, error)
 
 	RenameTeam(ctx context.Context, oldName, newName string) error
 }
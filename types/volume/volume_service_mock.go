@@ -21,6 +21,10 @@ type MockVolumeService struct {
 	OnBindsForApp                func(ctx context.Context, v *Volume, appName string) ([]VolumeBind, error)
 	OnListPlans                  func(ctx context.Context) (map[string][]VolumePlan, error)
 	OnCheckPoolVolumeConstraints func(ctx context.Context, volume Volume) error
+	OnListByJob                  func(ctx context.Context, jobName string) ([]Volume, error)
+	OnBindJob                    func(ctx context.Context, opts *BindOpts) error
+	OnUnbindJob                  func(ctx context.Context, opts *BindOpts) error
+	OnBindsForJob                func(ctx context.Context, v *Volume, jobName string) ([]VolumeBind, error)
 }
 
 func (m *MockVolumeService) VolumeService() (Volume, error) {
@@ -120,3 +124,31 @@ func (m *MockVolumeService) CheckPoolVolumeConstraints(ctx context.Context, volu
 	}
 	return nil
 }
+
+func (m *MockVolumeService) ListByJob(ctx context.Context, jobName string) ([]Volume, error) {
+	if m.OnListByJob != nil {
+		return m.OnListByJob(ctx, jobName)
+	}
+	return nil, nil
+}
+
+func (m *MockVolumeService) BindJob(ctx context.Context, opts *BindOpts) error {
+	if m.OnBindJob != nil {
+		return m.OnBindJob(ctx, opts)
+	}
+	return nil
+}
+
+func (m *MockVolumeService) UnbindJob(ctx context.Context, opts *BindOpts) error {
+	if m.OnUnbindJob != nil {
+		return m.OnUnbindJob(ctx, opts)
+	}
+	return nil
+}
+
+func (m *MockVolumeService) BindsForJob(ctx context.Context, v *Volume, jobName string) ([]VolumeBind, error) {
+	if m.OnBindsForJob != nil {
+		return m.OnBindsForJob(ctx, v, jobName)
+	}
+	return nil, nil
+}
@@ -433,6 +433,60 @@ func (s *S) TestVolumeBindAppMultipleMounts(c *check.C) {
 	c.Assert(binds, check.DeepEquals, expected)
 }
 
+func (s *S) TestVolumeBindJob(c *check.C) {
+	vs := &volumeService{
+		storage: &volumeTypes.MockVolumeStorage{},
+	}
+	v := volumeTypes.Volume{
+		Name:      "v1",
+		Plan:      volumeTypes.VolumePlan{Name: "p1"},
+		Pool:      "mypool",
+		TeamOwner: "myteam",
+	}
+	err := vs.Create(context.TODO(), &v)
+	c.Assert(err, check.IsNil)
+	err = vs.BindApp(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &v,
+		AppName:    "myapp",
+		MountPoint: "/mnt1",
+	})
+	c.Assert(err, check.IsNil)
+	err = vs.BindJob(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &v,
+		JobName:    "myjob",
+		MountPoint: "/mnt1",
+		ReadOnly:   true,
+	})
+	c.Assert(err, check.IsNil)
+	err = vs.BindJob(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &v,
+		JobName:    "myjob",
+		MountPoint: "/mnt1",
+	})
+	c.Assert(err, check.Equals, volumeTypes.ErrVolumeAlreadyBound)
+	binds, err := vs.BindsForJob(context.TODO(), &v, "myjob")
+	c.Assert(err, check.IsNil)
+	c.Assert(binds, check.DeepEquals, []volumeTypes.VolumeBind{
+		{ID: volumeTypes.VolumeBindID{Job: "myjob", MountPoint: "/mnt1", Volume: "v1"}, ReadOnly: true},
+	})
+	jobVolumes, err := vs.ListByJob(context.TODO(), "myjob")
+	c.Assert(err, check.IsNil)
+	c.Assert(jobVolumes, check.HasLen, 1)
+	c.Assert(jobVolumes[0].Name, check.Equals, "v1")
+	err = vs.UnbindJob(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &v,
+		JobName:    "myjob",
+		MountPoint: "/mnt1",
+	})
+	c.Assert(err, check.IsNil)
+	binds, err = vs.BindsForJob(context.TODO(), nil, "myjob")
+	c.Assert(err, check.IsNil)
+	c.Assert(binds, check.HasLen, 0)
+	binds, err = vs.BindsForApp(context.TODO(), &v, "myapp")
+	c.Assert(err, check.IsNil)
+	c.Assert(binds, check.HasLen, 1)
+}
+
 func (s *S) TestLoadBindsForApp(c *check.C) {
 	vs := &volumeService{
 		storage: &volumeTypes.MockVolumeStorage{},